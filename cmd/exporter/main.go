@@ -3,18 +3,23 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"log/slog"
 	"os"
 	"os/signal"
+	"slices"
+	"strings"
 	"syscall"
-	"time"
 
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
 
+	"github.com/kanzifucius/xp-tracker/pkg/auth"
 	"github.com/kanzifucius/xp-tracker/pkg/config"
 	"github.com/kanzifucius/xp-tracker/pkg/kube"
+	"github.com/kanzifucius/xp-tracker/pkg/metrics"
+	"github.com/kanzifucius/xp-tracker/pkg/rpc"
 	"github.com/kanzifucius/xp-tracker/pkg/server"
 	"github.com/kanzifucius/xp-tracker/pkg/store"
 )
@@ -62,44 +67,179 @@ func run() error {
 	if err := discoverAndApplyGVRs(ctx, client, cfg); err != nil {
 		return err
 	}
+	applyResourceOverrides(cfg)
 
 	slog.Info("configuration loaded",
+		"config_file", cfg.File,
 		"claim_gvrs", formatGVRs(cfg.ClaimGVRs),
 		"xr_gvrs", formatGVRs(cfg.XRGVRs),
 		"mr_gvrs", formatGVRs(cfg.MRGVRs),
+		"gvr_include", cfg.GVRInclude,
+		"gvr_exclude", cfg.GVRExclude,
 		"namespaces", cfg.Namespaces,
+		"namespace_selector", cfg.NamespaceSelector,
 		"creator_annotation", cfg.CreatorAnnotationKey,
 		"team_annotation", cfg.TeamAnnotationKey,
 		"composition_label", cfg.CompositionLabelKey,
 		"composite_label", cfg.CompositeLabelKey,
 		"poll_interval_seconds", cfg.PollIntervalSeconds,
+		"claim_poll_interval_seconds", cfg.ClaimPollIntervalSeconds,
+		"xr_poll_interval_seconds", cfg.XRPollIntervalSeconds,
+		"mr_poll_interval_seconds", cfg.MRPollIntervalSeconds,
+		"adaptive_poll_factor", cfg.AdaptivePollFactor,
+		"poll_jitter", cfg.PollJitter,
+		"table_listing", cfg.TableListing,
+		"resource_overrides", len(cfg.Resources),
+		"readiness_stale_polls", cfg.ReadinessStalePolls,
 		"metrics_addr", cfg.MetricsAddr,
 		"store_backend", cfg.StoreBackend,
+		"auth_paths", cfg.AuthPaths,
+		"tls", cfg.TLSCertFile != "",
+		"health_addr", cfg.HealthAddr,
+		"grpc_addr", cfg.GRPCAddr,
+		"shard_namespace", cfg.ShardNamespace,
 	)
 
 	// Initialise the store based on STORE_BACKEND.
 	mem := store.New()
+	mem.SetTombstoneRetention(cfg.TombstoneRetention)
+	mem.SetChangeBufferSize(cfg.EventBufferSize)
 	var s store.Store = mem
+	var s3s *store.S3Store
 
 	if cfg.StoreBackend == "s3" {
 		s3Client, err := store.NewS3Client(ctx, cfg.S3Region, cfg.S3Endpoint)
 		if err != nil {
 			return fmt.Errorf("create S3 client: %w", err)
 		}
-		s3s := store.NewS3Store(mem, s3Client, cfg.S3Bucket, cfg.S3KeyPrefix)
+		s3s = store.NewS3Store(mem, s3Client, cfg.S3Bucket, cfg.S3KeyPrefix)
 
-		slog.Info("restoring store snapshot from S3",
-			"bucket", cfg.S3Bucket,
-			"key_prefix", cfg.S3KeyPrefix,
-		)
-		if err := s3s.Restore(ctx); err != nil {
-			slog.Warn("failed to restore S3 snapshot, starting with empty store", "error", err)
+		if cfg.EncryptionKeyPath != "" {
+			keyring, err := store.LoadKeyring(cfg.EncryptionKeyPath, cfg.EncryptionKeyID)
+			if err != nil {
+				return fmt.Errorf("load snapshot encryption keys: %w", err)
+			}
+			s3s.SetKeyring(keyring)
+			slog.Info("snapshot encryption enabled",
+				"active_key", keyring.ActiveKeyID(),
+				"keys", keyring.KeyIDs(),
+			)
+		}
+
+		// Each shard persists its own object. The shards are restored once
+		// the members are known, below.
+		if cfg.ShardNamespace != "" {
+			s3s.SetShard(cfg.ShardIdentity)
+		} else {
+			slog.Info("restoring store snapshot from S3",
+				"bucket", cfg.S3Bucket,
+				"key_prefix", cfg.S3KeyPrefix,
+			)
+			if err := s3s.Restore(ctx); err != nil {
+				slog.Warn("failed to restore S3 snapshot, starting with empty store", "error", err)
+			}
 		}
 		s = s3s
+	} else if cfg.EncryptionKeyPath != "" {
+		slog.Warn("SNAPSHOT_ENCRYPTION_KEY_PATH ignored: store backend does not persist snapshots",
+			"store_backend", cfg.StoreBackend,
+		)
 	}
 
 	// Start the HTTP metrics server.
 	srv := server.New(cfg.MetricsAddr, s)
+
+	// The gRPC server, when enabled, shares readiness, TLS and auth with the
+	// HTTP server.
+	var grpcSrv *rpc.Server
+	if cfg.GRPCAddr != "" {
+		grpcSrv = rpc.New(cfg.GRPCAddr, s)
+		srv.OnReadinessChange(grpcSrv.SetReady)
+	}
+	if len(cfg.AuthPaths) > 0 {
+		restCfg, err := kube.RESTConfig()
+		if err != nil {
+			return fmt.Errorf("create Kubernetes client: %w", err)
+		}
+		kr, err := auth.NewKubeReviewer(restCfg)
+		if err != nil {
+			return fmt.Errorf("create access reviewer: %w", err)
+		}
+		var reviewer auth.Reviewer = kr
+		if cfg.AuthCacheTTL > 0 {
+			reviewer = auth.NewCachingReviewer(kr, cfg.AuthCacheTTL)
+		}
+		filter := auth.NewFilter(reviewer, cfg.AuthPaths)
+		srv.SetAuth(filter)
+		if grpcSrv != nil {
+			grpcSrv.SetAuth(filter)
+		}
+		slog.Info("HTTP API authentication enabled",
+			"paths", cfg.AuthPaths,
+			"cache_ttl", cfg.AuthCacheTTL.String(),
+		)
+
+		if len(cfg.TenantScope) > 0 {
+			// Scoped endpoints need the caller's identity; unprotected
+			// ones would reject every request.
+			for _, path := range server.ScopedPaths {
+				if !filter.Protects(path) {
+					return fmt.Errorf("TENANT_SCOPE requires AUTH_PATHS to protect %s", path)
+				}
+			}
+			if grpcSrv != nil {
+				for _, method := range rpc.InventoryMethods() {
+					if !filter.Protects(method) {
+						return fmt.Errorf("TENANT_SCOPE requires AUTH_PATHS to protect %s", method)
+					}
+				}
+			}
+			resource, group, _ := strings.Cut(cfg.TenantNamespaceResource, ".")
+			tenancy := auth.NewTenancy(reviewer, auth.TenancyOptions{
+				ByNamespace:     slices.Contains(cfg.TenantScope, "namespace"),
+				NamespaceAccess: auth.ResourceAttributes{Verb: "list", Group: group, Resource: resource},
+				ByTeam:          slices.Contains(cfg.TenantScope, "team"),
+				TeamGroupPrefix: cfg.TenantTeamGroupPrefix,
+			})
+			srv.SetTenancy(tenancy)
+			if grpcSrv != nil {
+				grpcSrv.SetTenancy(tenancy)
+			}
+			slog.Info("tenant scoping enabled",
+				"scope", cfg.TenantScope,
+				"namespace_resource", cfg.TenantNamespaceResource,
+				"team_group_prefix", cfg.TenantTeamGroupPrefix,
+			)
+		}
+	}
+	if cfg.TLSCertFile != "" {
+		tlsCfg := server.TLSConfig{
+			CertFile:     cfg.TLSCertFile,
+			KeyFile:      cfg.TLSKeyFile,
+			ClientCAFile: cfg.TLSClientCAFile,
+		}
+		if cfg.TLSClientAuth == "optional" {
+			tlsCfg.ClientAuth = tls.VerifyClientCertIfGiven
+		}
+		if err := srv.SetTLS(tlsCfg); err != nil {
+			return fmt.Errorf("configure TLS: %w", err)
+		}
+		if grpcSrv != nil {
+			grpcTLS, err := server.NewTLSConfig(tlsCfg)
+			if err != nil {
+				return fmt.Errorf("configure gRPC TLS: %w", err)
+			}
+			grpcSrv.SetTLS(grpcTLS)
+		}
+		slog.Info("TLS enabled",
+			"cert_file", cfg.TLSCertFile,
+			"client_ca_file", cfg.TLSClientCAFile,
+			"client_auth", cfg.TLSClientAuth,
+		)
+	}
+	if cfg.HealthAddr != "" {
+		srv.SetHealthAddr(cfg.HealthAddr)
+	}
 	go func() {
 		if err := srv.Run(ctx); err != nil {
 			slog.Error("metrics server error", "error", err)
@@ -107,28 +247,115 @@ func run() error {
 		}
 	}()
 
+	if grpcSrv != nil {
+		go func() {
+			if err := grpcSrv.Run(ctx); err != nil {
+				slog.Error("gRPC server error", "error", err)
+				cancel()
+			}
+		}()
+	}
+
 	// Start the polling loop.
 	poller := kube.NewPoller(client, cfg, s)
+	srv.SetBuildInfo(server.BuildInfo{Version: version, Commit: commit, Date: date})
+	srv.SetPollStatus(poller.Status)
+
+	// The table client is created even when TABLE_LISTING is empty, since a
+	// config reload can enable it.
+	restCfg, err := kube.RESTConfig()
+	if err != nil {
+		return fmt.Errorf("create Kubernetes client: %w", err)
+	}
+	tables, err := kube.NewTableClient(restCfg)
+	if err != nil {
+		return fmt.Errorf("create table client: %w", err)
+	}
+	poller.SetTableClient(tables)
+
+	// In sharded polling, join the members before the first cycle so that
+	// it only lists this replica's share of the GVRs, and restore the
+	// members' shards. Config validation ensures the S3 backend.
+	var shardsDone chan struct{}
+	if cfg.ShardNamespace != "" {
+		shards := kube.NewShards(client, cfg.ShardNamespace, cfg.ShardIdentity, cfg.ShardLeaseDuration)
+		if err := shards.Sync(ctx); err != nil {
+			return fmt.Errorf("join shard members: %w", err)
+		}
+		slog.Info("restoring shards from S3",
+			"bucket", cfg.S3Bucket,
+			"key_prefix", cfg.S3KeyPrefix,
+			"members", shards.Members(),
+		)
+		if err := s3s.RestoreShards(ctx, shards.Members()); err != nil {
+			slog.Warn("failed to restore some S3 shards, their GVRs start empty", "error", err)
+		}
+		poller.SetShards(shards)
+		shardsDone = make(chan struct{})
+		go func() {
+			shards.Run(ctx)
+			close(shardsDone)
+		}()
+		slog.Info("sharded polling enabled",
+			"identity", cfg.ShardIdentity,
+			"namespace", cfg.ShardNamespace,
+			"lease_duration", cfg.ShardLeaseDuration.String(),
+			"members", shards.Members(),
+		)
+	}
+	srv.SetStaleness(poller.Staleness)
 	go func() {
 		slog.Info("starting poller")
 		poller.Run(ctx)
 	}()
 
-	// Mark the server as ready after the first poll cycle completes.
-	// The poller runs an initial poll synchronously before entering the
-	// ticker loop, so by the time Run returns control we can signal readiness.
-	// We use a small goroutine to avoid blocking if the first poll is slow.
+	// Reload the config file when it changes. Poller settings are applied
+	// live; other changes are reported as needing a restart.
+	if cfg.File != "" {
+		watcher := config.NewWatcher(cfg.File)
+		go watcher.Run(ctx, func(next *config.Config, err error) {
+			if err == nil {
+				err = discoverAndApplyGVRs(ctx, client, next)
+			}
+			if err != nil {
+				metrics.ConfigReloads.WithLabelValues("failure").Inc()
+				slog.Error("config reload rejected, keeping the previous configuration", "error", err)
+				return
+			}
+			applyResourceOverrides(next)
+			if changed := cfg.RestartRequired(next); len(changed) > 0 {
+				slog.Warn("config reload: changed settings take effect on restart", "settings", changed)
+			}
+			poller.Reconfigure(next)
+			metrics.ConfigReloads.WithLabelValues("success").Inc()
+			slog.Info("configuration reloaded", "config_file", next.File)
+		})
+	}
+
+	// Mark the server as ready once the first poll cycle has committed its
+	// results, so scrapes after a restart never see an empty store.
 	go func() {
-		// Wait briefly for the first poll to finish (Run does an immediate poll).
-		time.Sleep(100 * time.Millisecond)
+		select {
+		case <-poller.FirstPoll():
+		case <-ctx.Done():
+			return
+		}
+		if poller.FirstPollSucceeded() {
+			slog.Info("server marked as ready")
+		} else {
+			slog.Warn("server marked as ready, but the first poll cycle had errors; serving partial data")
+		}
 		srv.SetReady()
-		slog.Info("server marked as ready")
 	}()
 
 	slog.Info("exporter running", "metrics_addr", cfg.MetricsAddr)
 
 	// Block until context is cancelled.
 	<-ctx.Done()
+	if shardsDone != nil {
+		// Wait for the shard Lease to be released.
+		<-shardsDone
+	}
 	slog.Info("shutdown complete")
 	return nil
 }
@@ -142,6 +369,22 @@ func formatGVRs(gvrs []schema.GroupVersionResource) []string {
 	return out
 }
 
+// applyResourceOverrides drops the GVRs rejected by the include/exclude
+// filter or disabled in the config file, and warns about overrides that
+// match no tracked GVR.
+func applyResourceOverrides(cfg *config.Config) {
+	if filtered := cfg.ApplyGVRFilter(); len(filtered) > 0 {
+		slog.Info("GVRs excluded by filter", "count", len(filtered))
+		slog.Debug("GVRs excluded by filter", "gvrs", filtered)
+	}
+	if dropped := cfg.DropDisabled(); len(dropped) > 0 {
+		slog.Info("GVRs disabled in config file", "gvrs", dropped)
+	}
+	if unmatched := cfg.UnmatchedResources(); len(unmatched) > 0 {
+		slog.Warn("config file overrides match no tracked GVR", "gvrs", unmatched)
+	}
+}
+
 func discoverAndApplyGVRs(ctx context.Context, client dynamic.Interface, cfg *config.Config) error {
 	claimGVRs, xrGVRs, err := kube.DiscoverFromXRD(ctx, client)
 	if err != nil {
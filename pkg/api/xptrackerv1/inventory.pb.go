@@ -0,0 +1,1606 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: xptracker/v1/inventory.proto
+
+package xptrackerv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type WatchEvent_Type int32
+
+const (
+	WatchEvent_TYPE_UNSPECIFIED WatchEvent_Type = 0
+	WatchEvent_TYPE_ADDED       WatchEvent_Type = 1
+	WatchEvent_TYPE_UPDATED     WatchEvent_Type = 2
+	WatchEvent_TYPE_REMOVED     WatchEvent_Type = 3
+	WatchEvent_TYPE_RESET       WatchEvent_Type = 4
+)
+
+// Enum value maps for WatchEvent_Type.
+var (
+	WatchEvent_Type_name = map[int32]string{
+		0: "TYPE_UNSPECIFIED",
+		1: "TYPE_ADDED",
+		2: "TYPE_UPDATED",
+		3: "TYPE_REMOVED",
+		4: "TYPE_RESET",
+	}
+	WatchEvent_Type_value = map[string]int32{
+		"TYPE_UNSPECIFIED": 0,
+		"TYPE_ADDED":       1,
+		"TYPE_UPDATED":     2,
+		"TYPE_REMOVED":     3,
+		"TYPE_RESET":       4,
+	}
+)
+
+func (x WatchEvent_Type) Enum() *WatchEvent_Type {
+	p := new(WatchEvent_Type)
+	*p = x
+	return p
+}
+
+func (x WatchEvent_Type) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (WatchEvent_Type) Descriptor() protoreflect.EnumDescriptor {
+	return file_xptracker_v1_inventory_proto_enumTypes[0].Descriptor()
+}
+
+func (WatchEvent_Type) Type() protoreflect.EnumType {
+	return &file_xptracker_v1_inventory_proto_enumTypes[0]
+}
+
+func (x WatchEvent_Type) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use WatchEvent_Type.Descriptor instead.
+func (WatchEvent_Type) EnumDescriptor() ([]byte, []int) {
+	return file_xptracker_v1_inventory_proto_rawDescGZIP(), []int{8, 0}
+}
+
+// ListRequest filters, orders and paginates a listing. Empty fields do not
+// filter. Namespace, team and creator of XRs and MRs are resolved through
+// their claim.
+type ListRequest struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Gvr         string                 `protobuf:"bytes,1,opt,name=gvr,proto3" json:"gvr,omitempty"` // "group/version/resource"
+	Namespace   string                 `protobuf:"bytes,2,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Kind        string                 `protobuf:"bytes,3,opt,name=kind,proto3" json:"kind,omitempty"`
+	Team        string                 `protobuf:"bytes,4,opt,name=team,proto3" json:"team,omitempty"`
+	Creator     string                 `protobuf:"bytes,5,opt,name=creator,proto3" json:"creator,omitempty"`
+	Composition string                 `protobuf:"bytes,6,opt,name=composition,proto3" json:"composition,omitempty"`
+	Provider    string                 `protobuf:"bytes,7,opt,name=provider,proto3" json:"provider,omitempty"`
+	Ready       *bool                  `protobuf:"varint,8,opt,name=ready,proto3,oneof" json:"ready,omitempty"`
+	Synced      *bool                  `protobuf:"varint,9,opt,name=synced,proto3,oneof" json:"synced,omitempty"`
+	Paused      *bool                  `protobuf:"varint,10,opt,name=paused,proto3,oneof" json:"paused,omitempty"`
+	Deleting    *bool                  `protobuf:"varint,11,opt,name=deleting,proto3,oneof" json:"deleting,omitempty"`
+	// sort_by is one of name, namespace, kind, creator, team, composition,
+	// provider or createdAt; the default orders by namespace and name.
+	SortBy     string `protobuf:"bytes,12,opt,name=sort_by,json=sortBy,proto3" json:"sort_by,omitempty"`
+	Descending bool   `protobuf:"varint,13,opt,name=descending,proto3" json:"descending,omitempty"`
+	// page_size caps the number of items returned; zero returns all.
+	PageSize int32 `protobuf:"varint,14,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// page_token resumes after a previous page (next_page_token).
+	PageToken     string `protobuf:"bytes,15,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRequest) Reset() {
+	*x = ListRequest{}
+	mi := &file_xptracker_v1_inventory_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRequest) ProtoMessage() {}
+
+func (x *ListRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_xptracker_v1_inventory_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRequest.ProtoReflect.Descriptor instead.
+func (*ListRequest) Descriptor() ([]byte, []int) {
+	return file_xptracker_v1_inventory_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ListRequest) GetGvr() string {
+	if x != nil {
+		return x.Gvr
+	}
+	return ""
+}
+
+func (x *ListRequest) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *ListRequest) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *ListRequest) GetTeam() string {
+	if x != nil {
+		return x.Team
+	}
+	return ""
+}
+
+func (x *ListRequest) GetCreator() string {
+	if x != nil {
+		return x.Creator
+	}
+	return ""
+}
+
+func (x *ListRequest) GetComposition() string {
+	if x != nil {
+		return x.Composition
+	}
+	return ""
+}
+
+func (x *ListRequest) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *ListRequest) GetReady() bool {
+	if x != nil && x.Ready != nil {
+		return *x.Ready
+	}
+	return false
+}
+
+func (x *ListRequest) GetSynced() bool {
+	if x != nil && x.Synced != nil {
+		return *x.Synced
+	}
+	return false
+}
+
+func (x *ListRequest) GetPaused() bool {
+	if x != nil && x.Paused != nil {
+		return *x.Paused
+	}
+	return false
+}
+
+func (x *ListRequest) GetDeleting() bool {
+	if x != nil && x.Deleting != nil {
+		return *x.Deleting
+	}
+	return false
+}
+
+func (x *ListRequest) GetSortBy() string {
+	if x != nil {
+		return x.SortBy
+	}
+	return ""
+}
+
+func (x *ListRequest) GetDescending() bool {
+	if x != nil {
+		return x.Descending
+	}
+	return false
+}
+
+func (x *ListRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type ListClaimsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Claims        []*Claim               `protobuf:"bytes,1,rep,name=claims,proto3" json:"claims,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"` // empty on the last page
+	Total         int64                  `protobuf:"varint,3,opt,name=total,proto3" json:"total,omitempty"`                                       // matches across all pages
+	Generation    uint64                 `protobuf:"varint,4,opt,name=generation,proto3" json:"generation,omitempty"`                             // store generation the page was read from
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListClaimsResponse) Reset() {
+	*x = ListClaimsResponse{}
+	mi := &file_xptracker_v1_inventory_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListClaimsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListClaimsResponse) ProtoMessage() {}
+
+func (x *ListClaimsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_xptracker_v1_inventory_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListClaimsResponse.ProtoReflect.Descriptor instead.
+func (*ListClaimsResponse) Descriptor() ([]byte, []int) {
+	return file_xptracker_v1_inventory_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ListClaimsResponse) GetClaims() []*Claim {
+	if x != nil {
+		return x.Claims
+	}
+	return nil
+}
+
+func (x *ListClaimsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+func (x *ListClaimsResponse) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *ListClaimsResponse) GetGeneration() uint64 {
+	if x != nil {
+		return x.Generation
+	}
+	return 0
+}
+
+type ListXRsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Xrs           []*XR                  `protobuf:"bytes,1,rep,name=xrs,proto3" json:"xrs,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	Total         int64                  `protobuf:"varint,3,opt,name=total,proto3" json:"total,omitempty"`
+	Generation    uint64                 `protobuf:"varint,4,opt,name=generation,proto3" json:"generation,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListXRsResponse) Reset() {
+	*x = ListXRsResponse{}
+	mi := &file_xptracker_v1_inventory_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListXRsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListXRsResponse) ProtoMessage() {}
+
+func (x *ListXRsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_xptracker_v1_inventory_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListXRsResponse.ProtoReflect.Descriptor instead.
+func (*ListXRsResponse) Descriptor() ([]byte, []int) {
+	return file_xptracker_v1_inventory_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ListXRsResponse) GetXrs() []*XR {
+	if x != nil {
+		return x.Xrs
+	}
+	return nil
+}
+
+func (x *ListXRsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+func (x *ListXRsResponse) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *ListXRsResponse) GetGeneration() uint64 {
+	if x != nil {
+		return x.Generation
+	}
+	return 0
+}
+
+type ListMRsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Mrs           []*MR                  `protobuf:"bytes,1,rep,name=mrs,proto3" json:"mrs,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	Total         int64                  `protobuf:"varint,3,opt,name=total,proto3" json:"total,omitempty"`
+	Generation    uint64                 `protobuf:"varint,4,opt,name=generation,proto3" json:"generation,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListMRsResponse) Reset() {
+	*x = ListMRsResponse{}
+	mi := &file_xptracker_v1_inventory_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListMRsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListMRsResponse) ProtoMessage() {}
+
+func (x *ListMRsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_xptracker_v1_inventory_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListMRsResponse.ProtoReflect.Descriptor instead.
+func (*ListMRsResponse) Descriptor() ([]byte, []int) {
+	return file_xptracker_v1_inventory_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ListMRsResponse) GetMrs() []*MR {
+	if x != nil {
+		return x.Mrs
+	}
+	return nil
+}
+
+func (x *ListMRsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+func (x *ListMRsResponse) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *ListMRsResponse) GetGeneration() uint64 {
+	if x != nil {
+		return x.Generation
+	}
+	return 0
+}
+
+type GetClaimRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Namespace     string                 `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetClaimRequest) Reset() {
+	*x = GetClaimRequest{}
+	mi := &file_xptracker_v1_inventory_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetClaimRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetClaimRequest) ProtoMessage() {}
+
+func (x *GetClaimRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_xptracker_v1_inventory_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetClaimRequest.ProtoReflect.Descriptor instead.
+func (*GetClaimRequest) Descriptor() ([]byte, []int) {
+	return file_xptracker_v1_inventory_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetClaimRequest) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *GetClaimRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type GetXRRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Namespace     string                 `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"` // empty for cluster-scoped XRs
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetXRRequest) Reset() {
+	*x = GetXRRequest{}
+	mi := &file_xptracker_v1_inventory_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetXRRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetXRRequest) ProtoMessage() {}
+
+func (x *GetXRRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_xptracker_v1_inventory_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetXRRequest.ProtoReflect.Descriptor instead.
+func (*GetXRRequest) Descriptor() ([]byte, []int) {
+	return file_xptracker_v1_inventory_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetXRRequest) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *GetXRRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type GetMRRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Gvr           string                 `protobuf:"bytes,1,opt,name=gvr,proto3" json:"gvr,omitempty"`             // "group/version/resource"
+	Namespace     string                 `protobuf:"bytes,2,opt,name=namespace,proto3" json:"namespace,omitempty"` // empty for cluster-scoped MRs
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetMRRequest) Reset() {
+	*x = GetMRRequest{}
+	mi := &file_xptracker_v1_inventory_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMRRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMRRequest) ProtoMessage() {}
+
+func (x *GetMRRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_xptracker_v1_inventory_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMRRequest.ProtoReflect.Descriptor instead.
+func (*GetMRRequest) Descriptor() ([]byte, []int) {
+	return file_xptracker_v1_inventory_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetMRRequest) GetGvr() string {
+	if x != nil {
+		return x.Gvr
+	}
+	return ""
+}
+
+func (x *GetMRRequest) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *GetMRRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+// WatchRequest selects the changes to stream.
+type WatchRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Only changes in this namespace (resolved through the claim).
+	Namespace string `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	// Only changes for this team.
+	Team string `protobuf:"bytes,2,opt,name=team,proto3" json:"team,omitempty"`
+	// Resume after this sequence number; without it the stream starts with
+	// the next change.
+	AfterSeq      *uint64 `protobuf:"varint,3,opt,name=after_seq,json=afterSeq,proto3,oneof" json:"after_seq,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchRequest) Reset() {
+	*x = WatchRequest{}
+	mi := &file_xptracker_v1_inventory_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchRequest) ProtoMessage() {}
+
+func (x *WatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_xptracker_v1_inventory_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchRequest.ProtoReflect.Descriptor instead.
+func (*WatchRequest) Descriptor() ([]byte, []int) {
+	return file_xptracker_v1_inventory_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *WatchRequest) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *WatchRequest) GetTeam() string {
+	if x != nil {
+		return x.Team
+	}
+	return ""
+}
+
+func (x *WatchRequest) GetAfterSeq() uint64 {
+	if x != nil && x.AfterSeq != nil {
+		return *x.AfterSeq
+	}
+	return 0
+}
+
+// WatchEvent is one change, or a RESET telling the client that changes were
+// missed and it must list the inventory again.
+type WatchEvent struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Seq        uint64                 `protobuf:"varint,1,opt,name=seq,proto3" json:"seq,omitempty"`
+	Type       WatchEvent_Type        `protobuf:"varint,2,opt,name=type,proto3,enum=xptracker.v1.WatchEvent_Type" json:"type,omitempty"`
+	Generation uint64                 `protobuf:"varint,3,opt,name=generation,proto3" json:"generation,omitempty"`
+	At         *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=at,proto3" json:"at,omitempty"` // commit time of the generation
+	Namespace  string                 `protobuf:"bytes,5,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Name       string                 `protobuf:"bytes,6,opt,name=name,proto3" json:"name,omitempty"`
+	Team       string                 `protobuf:"bytes,7,opt,name=team,proto3" json:"team,omitempty"`
+	// The object after the change; the last known state for removals. Unset
+	// for RESET events.
+	//
+	// Types that are valid to be assigned to Object:
+	//
+	//	*WatchEvent_Claim
+	//	*WatchEvent_Xr
+	//	*WatchEvent_Mr
+	Object        isWatchEvent_Object `protobuf_oneof:"object"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchEvent) Reset() {
+	*x = WatchEvent{}
+	mi := &file_xptracker_v1_inventory_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchEvent) ProtoMessage() {}
+
+func (x *WatchEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_xptracker_v1_inventory_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchEvent.ProtoReflect.Descriptor instead.
+func (*WatchEvent) Descriptor() ([]byte, []int) {
+	return file_xptracker_v1_inventory_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *WatchEvent) GetSeq() uint64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+func (x *WatchEvent) GetType() WatchEvent_Type {
+	if x != nil {
+		return x.Type
+	}
+	return WatchEvent_TYPE_UNSPECIFIED
+}
+
+func (x *WatchEvent) GetGeneration() uint64 {
+	if x != nil {
+		return x.Generation
+	}
+	return 0
+}
+
+func (x *WatchEvent) GetAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.At
+	}
+	return nil
+}
+
+func (x *WatchEvent) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *WatchEvent) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *WatchEvent) GetTeam() string {
+	if x != nil {
+		return x.Team
+	}
+	return ""
+}
+
+func (x *WatchEvent) GetObject() isWatchEvent_Object {
+	if x != nil {
+		return x.Object
+	}
+	return nil
+}
+
+func (x *WatchEvent) GetClaim() *Claim {
+	if x != nil {
+		if x, ok := x.Object.(*WatchEvent_Claim); ok {
+			return x.Claim
+		}
+	}
+	return nil
+}
+
+func (x *WatchEvent) GetXr() *XR {
+	if x != nil {
+		if x, ok := x.Object.(*WatchEvent_Xr); ok {
+			return x.Xr
+		}
+	}
+	return nil
+}
+
+func (x *WatchEvent) GetMr() *MR {
+	if x != nil {
+		if x, ok := x.Object.(*WatchEvent_Mr); ok {
+			return x.Mr
+		}
+	}
+	return nil
+}
+
+type isWatchEvent_Object interface {
+	isWatchEvent_Object()
+}
+
+type WatchEvent_Claim struct {
+	Claim *Claim `protobuf:"bytes,8,opt,name=claim,proto3,oneof"`
+}
+
+type WatchEvent_Xr struct {
+	Xr *XR `protobuf:"bytes,9,opt,name=xr,proto3,oneof"`
+}
+
+type WatchEvent_Mr struct {
+	Mr *MR `protobuf:"bytes,10,opt,name=mr,proto3,oneof"`
+}
+
+func (*WatchEvent_Claim) isWatchEvent_Object() {}
+
+func (*WatchEvent_Xr) isWatchEvent_Object() {}
+
+func (*WatchEvent_Mr) isWatchEvent_Object() {}
+
+// Claim mirrors store.ClaimInfo.
+type Claim struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Gvr           string                 `protobuf:"bytes,1,opt,name=gvr,proto3" json:"gvr,omitempty"`
+	Group         string                 `protobuf:"bytes,2,opt,name=group,proto3" json:"group,omitempty"`
+	Version       string                 `protobuf:"bytes,3,opt,name=version,proto3" json:"version,omitempty"`
+	Kind          string                 `protobuf:"bytes,4,opt,name=kind,proto3" json:"kind,omitempty"`
+	Namespace     string                 `protobuf:"bytes,5,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Name          string                 `protobuf:"bytes,6,opt,name=name,proto3" json:"name,omitempty"`
+	Creator       string                 `protobuf:"bytes,7,opt,name=creator,proto3" json:"creator,omitempty"`
+	Team          string                 `protobuf:"bytes,8,opt,name=team,proto3" json:"team,omitempty"`
+	Composition   string                 `protobuf:"bytes,9,opt,name=composition,proto3" json:"composition,omitempty"`
+	Paused        bool                   `protobuf:"varint,10,opt,name=paused,proto3" json:"paused,omitempty"`
+	Synced        bool                   `protobuf:"varint,11,opt,name=synced,proto3" json:"synced,omitempty"`
+	Ready         bool                   `protobuf:"varint,12,opt,name=ready,proto3" json:"ready,omitempty"`
+	Reason        string                 `protobuf:"bytes,13,opt,name=reason,proto3" json:"reason,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,14,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	DeletedAt     *timestamppb.Timestamp `protobuf:"bytes,15,opt,name=deleted_at,json=deletedAt,proto3" json:"deleted_at,omitempty"` // unset when not deleting
+	XrRef         string                 `protobuf:"bytes,16,opt,name=xr_ref,json=xrRef,proto3" json:"xr_ref,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Claim) Reset() {
+	*x = Claim{}
+	mi := &file_xptracker_v1_inventory_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Claim) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Claim) ProtoMessage() {}
+
+func (x *Claim) ProtoReflect() protoreflect.Message {
+	mi := &file_xptracker_v1_inventory_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Claim.ProtoReflect.Descriptor instead.
+func (*Claim) Descriptor() ([]byte, []int) {
+	return file_xptracker_v1_inventory_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Claim) GetGvr() string {
+	if x != nil {
+		return x.Gvr
+	}
+	return ""
+}
+
+func (x *Claim) GetGroup() string {
+	if x != nil {
+		return x.Group
+	}
+	return ""
+}
+
+func (x *Claim) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *Claim) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *Claim) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *Claim) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Claim) GetCreator() string {
+	if x != nil {
+		return x.Creator
+	}
+	return ""
+}
+
+func (x *Claim) GetTeam() string {
+	if x != nil {
+		return x.Team
+	}
+	return ""
+}
+
+func (x *Claim) GetComposition() string {
+	if x != nil {
+		return x.Composition
+	}
+	return ""
+}
+
+func (x *Claim) GetPaused() bool {
+	if x != nil {
+		return x.Paused
+	}
+	return false
+}
+
+func (x *Claim) GetSynced() bool {
+	if x != nil {
+		return x.Synced
+	}
+	return false
+}
+
+func (x *Claim) GetReady() bool {
+	if x != nil {
+		return x.Ready
+	}
+	return false
+}
+
+func (x *Claim) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *Claim) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Claim) GetDeletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DeletedAt
+	}
+	return nil
+}
+
+func (x *Claim) GetXrRef() string {
+	if x != nil {
+		return x.XrRef
+	}
+	return ""
+}
+
+// XR mirrors store.XRInfo.
+type XR struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Gvr            string                 `protobuf:"bytes,1,opt,name=gvr,proto3" json:"gvr,omitempty"`
+	Group          string                 `protobuf:"bytes,2,opt,name=group,proto3" json:"group,omitempty"`
+	Version        string                 `protobuf:"bytes,3,opt,name=version,proto3" json:"version,omitempty"`
+	Kind           string                 `protobuf:"bytes,4,opt,name=kind,proto3" json:"kind,omitempty"`
+	Namespace      string                 `protobuf:"bytes,5,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Name           string                 `protobuf:"bytes,6,opt,name=name,proto3" json:"name,omitempty"`
+	ClaimName      string                 `protobuf:"bytes,7,opt,name=claim_name,json=claimName,proto3" json:"claim_name,omitempty"`
+	ClaimNamespace string                 `protobuf:"bytes,8,opt,name=claim_namespace,json=claimNamespace,proto3" json:"claim_namespace,omitempty"`
+	Composition    string                 `protobuf:"bytes,9,opt,name=composition,proto3" json:"composition,omitempty"`
+	Paused         bool                   `protobuf:"varint,10,opt,name=paused,proto3" json:"paused,omitempty"`
+	Synced         bool                   `protobuf:"varint,11,opt,name=synced,proto3" json:"synced,omitempty"`
+	Ready          bool                   `protobuf:"varint,12,opt,name=ready,proto3" json:"ready,omitempty"`
+	Reason         string                 `protobuf:"bytes,13,opt,name=reason,proto3" json:"reason,omitempty"`
+	CreatedAt      *timestamppb.Timestamp `protobuf:"bytes,14,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	DeletedAt      *timestamppb.Timestamp `protobuf:"bytes,15,opt,name=deleted_at,json=deletedAt,proto3" json:"deleted_at,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *XR) Reset() {
+	*x = XR{}
+	mi := &file_xptracker_v1_inventory_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *XR) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*XR) ProtoMessage() {}
+
+func (x *XR) ProtoReflect() protoreflect.Message {
+	mi := &file_xptracker_v1_inventory_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use XR.ProtoReflect.Descriptor instead.
+func (*XR) Descriptor() ([]byte, []int) {
+	return file_xptracker_v1_inventory_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *XR) GetGvr() string {
+	if x != nil {
+		return x.Gvr
+	}
+	return ""
+}
+
+func (x *XR) GetGroup() string {
+	if x != nil {
+		return x.Group
+	}
+	return ""
+}
+
+func (x *XR) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *XR) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *XR) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *XR) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *XR) GetClaimName() string {
+	if x != nil {
+		return x.ClaimName
+	}
+	return ""
+}
+
+func (x *XR) GetClaimNamespace() string {
+	if x != nil {
+		return x.ClaimNamespace
+	}
+	return ""
+}
+
+func (x *XR) GetComposition() string {
+	if x != nil {
+		return x.Composition
+	}
+	return ""
+}
+
+func (x *XR) GetPaused() bool {
+	if x != nil {
+		return x.Paused
+	}
+	return false
+}
+
+func (x *XR) GetSynced() bool {
+	if x != nil {
+		return x.Synced
+	}
+	return false
+}
+
+func (x *XR) GetReady() bool {
+	if x != nil {
+		return x.Ready
+	}
+	return false
+}
+
+func (x *XR) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *XR) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *XR) GetDeletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DeletedAt
+	}
+	return nil
+}
+
+// MR mirrors store.MRInfo.
+type MR struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Gvr                string                 `protobuf:"bytes,1,opt,name=gvr,proto3" json:"gvr,omitempty"`
+	Group              string                 `protobuf:"bytes,2,opt,name=group,proto3" json:"group,omitempty"`
+	Version            string                 `protobuf:"bytes,3,opt,name=version,proto3" json:"version,omitempty"`
+	Kind               string                 `protobuf:"bytes,4,opt,name=kind,proto3" json:"kind,omitempty"`
+	Namespace          string                 `protobuf:"bytes,5,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Name               string                 `protobuf:"bytes,6,opt,name=name,proto3" json:"name,omitempty"`
+	XrName             string                 `protobuf:"bytes,7,opt,name=xr_name,json=xrName,proto3" json:"xr_name,omitempty"`
+	ClaimName          string                 `protobuf:"bytes,8,opt,name=claim_name,json=claimName,proto3" json:"claim_name,omitempty"`
+	ClaimNamespace     string                 `protobuf:"bytes,9,opt,name=claim_namespace,json=claimNamespace,proto3" json:"claim_namespace,omitempty"`
+	Provider           string                 `protobuf:"bytes,10,opt,name=provider,proto3" json:"provider,omitempty"`
+	ProviderConfig     string                 `protobuf:"bytes,11,opt,name=provider_config,json=providerConfig,proto3" json:"provider_config,omitempty"`
+	ExternalName       string                 `protobuf:"bytes,12,opt,name=external_name,json=externalName,proto3" json:"external_name,omitempty"`
+	ManagementPolicies string                 `protobuf:"bytes,13,opt,name=management_policies,json=managementPolicies,proto3" json:"management_policies,omitempty"`
+	Paused             bool                   `protobuf:"varint,14,opt,name=paused,proto3" json:"paused,omitempty"`
+	Synced             bool                   `protobuf:"varint,15,opt,name=synced,proto3" json:"synced,omitempty"`
+	Ready              bool                   `protobuf:"varint,16,opt,name=ready,proto3" json:"ready,omitempty"`
+	Reason             string                 `protobuf:"bytes,17,opt,name=reason,proto3" json:"reason,omitempty"`
+	CreatedAt          *timestamppb.Timestamp `protobuf:"bytes,18,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	DeletedAt          *timestamppb.Timestamp `protobuf:"bytes,19,opt,name=deleted_at,json=deletedAt,proto3" json:"deleted_at,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *MR) Reset() {
+	*x = MR{}
+	mi := &file_xptracker_v1_inventory_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MR) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MR) ProtoMessage() {}
+
+func (x *MR) ProtoReflect() protoreflect.Message {
+	mi := &file_xptracker_v1_inventory_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MR.ProtoReflect.Descriptor instead.
+func (*MR) Descriptor() ([]byte, []int) {
+	return file_xptracker_v1_inventory_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *MR) GetGvr() string {
+	if x != nil {
+		return x.Gvr
+	}
+	return ""
+}
+
+func (x *MR) GetGroup() string {
+	if x != nil {
+		return x.Group
+	}
+	return ""
+}
+
+func (x *MR) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *MR) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *MR) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *MR) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *MR) GetXrName() string {
+	if x != nil {
+		return x.XrName
+	}
+	return ""
+}
+
+func (x *MR) GetClaimName() string {
+	if x != nil {
+		return x.ClaimName
+	}
+	return ""
+}
+
+func (x *MR) GetClaimNamespace() string {
+	if x != nil {
+		return x.ClaimNamespace
+	}
+	return ""
+}
+
+func (x *MR) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *MR) GetProviderConfig() string {
+	if x != nil {
+		return x.ProviderConfig
+	}
+	return ""
+}
+
+func (x *MR) GetExternalName() string {
+	if x != nil {
+		return x.ExternalName
+	}
+	return ""
+}
+
+func (x *MR) GetManagementPolicies() string {
+	if x != nil {
+		return x.ManagementPolicies
+	}
+	return ""
+}
+
+func (x *MR) GetPaused() bool {
+	if x != nil {
+		return x.Paused
+	}
+	return false
+}
+
+func (x *MR) GetSynced() bool {
+	if x != nil {
+		return x.Synced
+	}
+	return false
+}
+
+func (x *MR) GetReady() bool {
+	if x != nil {
+		return x.Ready
+	}
+	return false
+}
+
+func (x *MR) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *MR) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *MR) GetDeletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DeletedAt
+	}
+	return nil
+}
+
+var File_xptracker_v1_inventory_proto protoreflect.FileDescriptor
+
+const file_xptracker_v1_inventory_proto_rawDesc = "" +
+	"\n" +
+	"\x1cxptracker/v1/inventory.proto\x12\fxptracker.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xd5\x03\n" +
+	"\vListRequest\x12\x10\n" +
+	"\x03gvr\x18\x01 \x01(\tR\x03gvr\x12\x1c\n" +
+	"\tnamespace\x18\x02 \x01(\tR\tnamespace\x12\x12\n" +
+	"\x04kind\x18\x03 \x01(\tR\x04kind\x12\x12\n" +
+	"\x04team\x18\x04 \x01(\tR\x04team\x12\x18\n" +
+	"\acreator\x18\x05 \x01(\tR\acreator\x12 \n" +
+	"\vcomposition\x18\x06 \x01(\tR\vcomposition\x12\x1a\n" +
+	"\bprovider\x18\a \x01(\tR\bprovider\x12\x19\n" +
+	"\x05ready\x18\b \x01(\bH\x00R\x05ready\x88\x01\x01\x12\x1b\n" +
+	"\x06synced\x18\t \x01(\bH\x01R\x06synced\x88\x01\x01\x12\x1b\n" +
+	"\x06paused\x18\n" +
+	" \x01(\bH\x02R\x06paused\x88\x01\x01\x12\x1f\n" +
+	"\bdeleting\x18\v \x01(\bH\x03R\bdeleting\x88\x01\x01\x12\x17\n" +
+	"\asort_by\x18\f \x01(\tR\x06sortBy\x12\x1e\n" +
+	"\n" +
+	"descending\x18\r \x01(\bR\n" +
+	"descending\x12\x1b\n" +
+	"\tpage_size\x18\x0e \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x0f \x01(\tR\tpageTokenB\b\n" +
+	"\x06_readyB\t\n" +
+	"\a_syncedB\t\n" +
+	"\a_pausedB\v\n" +
+	"\t_deleting\"\x9f\x01\n" +
+	"\x12ListClaimsResponse\x12+\n" +
+	"\x06claims\x18\x01 \x03(\v2\x13.xptracker.v1.ClaimR\x06claims\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\x12\x14\n" +
+	"\x05total\x18\x03 \x01(\x03R\x05total\x12\x1e\n" +
+	"\n" +
+	"generation\x18\x04 \x01(\x04R\n" +
+	"generation\"\x93\x01\n" +
+	"\x0fListXRsResponse\x12\"\n" +
+	"\x03xrs\x18\x01 \x03(\v2\x10.xptracker.v1.XRR\x03xrs\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\x12\x14\n" +
+	"\x05total\x18\x03 \x01(\x03R\x05total\x12\x1e\n" +
+	"\n" +
+	"generation\x18\x04 \x01(\x04R\n" +
+	"generation\"\x93\x01\n" +
+	"\x0fListMRsResponse\x12\"\n" +
+	"\x03mrs\x18\x01 \x03(\v2\x10.xptracker.v1.MRR\x03mrs\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\x12\x14\n" +
+	"\x05total\x18\x03 \x01(\x03R\x05total\x12\x1e\n" +
+	"\n" +
+	"generation\x18\x04 \x01(\x04R\n" +
+	"generation\"C\n" +
+	"\x0fGetClaimRequest\x12\x1c\n" +
+	"\tnamespace\x18\x01 \x01(\tR\tnamespace\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\"@\n" +
+	"\fGetXRRequest\x12\x1c\n" +
+	"\tnamespace\x18\x01 \x01(\tR\tnamespace\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\"R\n" +
+	"\fGetMRRequest\x12\x10\n" +
+	"\x03gvr\x18\x01 \x01(\tR\x03gvr\x12\x1c\n" +
+	"\tnamespace\x18\x02 \x01(\tR\tnamespace\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\"p\n" +
+	"\fWatchRequest\x12\x1c\n" +
+	"\tnamespace\x18\x01 \x01(\tR\tnamespace\x12\x12\n" +
+	"\x04team\x18\x02 \x01(\tR\x04team\x12 \n" +
+	"\tafter_seq\x18\x03 \x01(\x04H\x00R\bafterSeq\x88\x01\x01B\f\n" +
+	"\n" +
+	"_after_seq\"\xc4\x03\n" +
+	"\n" +
+	"WatchEvent\x12\x10\n" +
+	"\x03seq\x18\x01 \x01(\x04R\x03seq\x121\n" +
+	"\x04type\x18\x02 \x01(\x0e2\x1d.xptracker.v1.WatchEvent.TypeR\x04type\x12\x1e\n" +
+	"\n" +
+	"generation\x18\x03 \x01(\x04R\n" +
+	"generation\x12*\n" +
+	"\x02at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x02at\x12\x1c\n" +
+	"\tnamespace\x18\x05 \x01(\tR\tnamespace\x12\x12\n" +
+	"\x04name\x18\x06 \x01(\tR\x04name\x12\x12\n" +
+	"\x04team\x18\a \x01(\tR\x04team\x12+\n" +
+	"\x05claim\x18\b \x01(\v2\x13.xptracker.v1.ClaimH\x00R\x05claim\x12\"\n" +
+	"\x02xr\x18\t \x01(\v2\x10.xptracker.v1.XRH\x00R\x02xr\x12\"\n" +
+	"\x02mr\x18\n" +
+	" \x01(\v2\x10.xptracker.v1.MRH\x00R\x02mr\"`\n" +
+	"\x04Type\x12\x14\n" +
+	"\x10TYPE_UNSPECIFIED\x10\x00\x12\x0e\n" +
+	"\n" +
+	"TYPE_ADDED\x10\x01\x12\x10\n" +
+	"\fTYPE_UPDATED\x10\x02\x12\x10\n" +
+	"\fTYPE_REMOVED\x10\x03\x12\x0e\n" +
+	"\n" +
+	"TYPE_RESET\x10\x04B\b\n" +
+	"\x06object\"\xca\x03\n" +
+	"\x05Claim\x12\x10\n" +
+	"\x03gvr\x18\x01 \x01(\tR\x03gvr\x12\x14\n" +
+	"\x05group\x18\x02 \x01(\tR\x05group\x12\x18\n" +
+	"\aversion\x18\x03 \x01(\tR\aversion\x12\x12\n" +
+	"\x04kind\x18\x04 \x01(\tR\x04kind\x12\x1c\n" +
+	"\tnamespace\x18\x05 \x01(\tR\tnamespace\x12\x12\n" +
+	"\x04name\x18\x06 \x01(\tR\x04name\x12\x18\n" +
+	"\acreator\x18\a \x01(\tR\acreator\x12\x12\n" +
+	"\x04team\x18\b \x01(\tR\x04team\x12 \n" +
+	"\vcomposition\x18\t \x01(\tR\vcomposition\x12\x16\n" +
+	"\x06paused\x18\n" +
+	" \x01(\bR\x06paused\x12\x16\n" +
+	"\x06synced\x18\v \x01(\bR\x06synced\x12\x14\n" +
+	"\x05ready\x18\f \x01(\bR\x05ready\x12\x16\n" +
+	"\x06reason\x18\r \x01(\tR\x06reason\x129\n" +
+	"\n" +
+	"created_at\x18\x0e \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"deleted_at\x18\x0f \x01(\v2\x1a.google.protobuf.TimestampR\tdeletedAt\x12\x15\n" +
+	"\x06xr_ref\x18\x10 \x01(\tR\x05xrRef\"\xca\x03\n" +
+	"\x02XR\x12\x10\n" +
+	"\x03gvr\x18\x01 \x01(\tR\x03gvr\x12\x14\n" +
+	"\x05group\x18\x02 \x01(\tR\x05group\x12\x18\n" +
+	"\aversion\x18\x03 \x01(\tR\aversion\x12\x12\n" +
+	"\x04kind\x18\x04 \x01(\tR\x04kind\x12\x1c\n" +
+	"\tnamespace\x18\x05 \x01(\tR\tnamespace\x12\x12\n" +
+	"\x04name\x18\x06 \x01(\tR\x04name\x12\x1d\n" +
+	"\n" +
+	"claim_name\x18\a \x01(\tR\tclaimName\x12'\n" +
+	"\x0fclaim_namespace\x18\b \x01(\tR\x0eclaimNamespace\x12 \n" +
+	"\vcomposition\x18\t \x01(\tR\vcomposition\x12\x16\n" +
+	"\x06paused\x18\n" +
+	" \x01(\bR\x06paused\x12\x16\n" +
+	"\x06synced\x18\v \x01(\bR\x06synced\x12\x14\n" +
+	"\x05ready\x18\f \x01(\bR\x05ready\x12\x16\n" +
+	"\x06reason\x18\r \x01(\tR\x06reason\x129\n" +
+	"\n" +
+	"created_at\x18\x0e \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"deleted_at\x18\x0f \x01(\v2\x1a.google.protobuf.TimestampR\tdeletedAt\"\xdc\x04\n" +
+	"\x02MR\x12\x10\n" +
+	"\x03gvr\x18\x01 \x01(\tR\x03gvr\x12\x14\n" +
+	"\x05group\x18\x02 \x01(\tR\x05group\x12\x18\n" +
+	"\aversion\x18\x03 \x01(\tR\aversion\x12\x12\n" +
+	"\x04kind\x18\x04 \x01(\tR\x04kind\x12\x1c\n" +
+	"\tnamespace\x18\x05 \x01(\tR\tnamespace\x12\x12\n" +
+	"\x04name\x18\x06 \x01(\tR\x04name\x12\x17\n" +
+	"\axr_name\x18\a \x01(\tR\x06xrName\x12\x1d\n" +
+	"\n" +
+	"claim_name\x18\b \x01(\tR\tclaimName\x12'\n" +
+	"\x0fclaim_namespace\x18\t \x01(\tR\x0eclaimNamespace\x12\x1a\n" +
+	"\bprovider\x18\n" +
+	" \x01(\tR\bprovider\x12'\n" +
+	"\x0fprovider_config\x18\v \x01(\tR\x0eproviderConfig\x12#\n" +
+	"\rexternal_name\x18\f \x01(\tR\fexternalName\x12/\n" +
+	"\x13management_policies\x18\r \x01(\tR\x12managementPolicies\x12\x16\n" +
+	"\x06paused\x18\x0e \x01(\bR\x06paused\x12\x16\n" +
+	"\x06synced\x18\x0f \x01(\bR\x06synced\x12\x14\n" +
+	"\x05ready\x18\x10 \x01(\bR\x05ready\x12\x16\n" +
+	"\x06reason\x18\x11 \x01(\tR\x06reason\x129\n" +
+	"\n" +
+	"created_at\x18\x12 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"deleted_at\x18\x13 \x01(\v2\x1a.google.protobuf.TimestampR\tdeletedAt2\xd6\x03\n" +
+	"\x10InventoryService\x12I\n" +
+	"\n" +
+	"ListClaims\x12\x19.xptracker.v1.ListRequest\x1a .xptracker.v1.ListClaimsResponse\x12C\n" +
+	"\aListXRs\x12\x19.xptracker.v1.ListRequest\x1a\x1d.xptracker.v1.ListXRsResponse\x12C\n" +
+	"\aListMRs\x12\x19.xptracker.v1.ListRequest\x1a\x1d.xptracker.v1.ListMRsResponse\x12>\n" +
+	"\bGetClaim\x12\x1d.xptracker.v1.GetClaimRequest\x1a\x13.xptracker.v1.Claim\x125\n" +
+	"\x05GetXR\x12\x1a.xptracker.v1.GetXRRequest\x1a\x10.xptracker.v1.XR\x125\n" +
+	"\x05GetMR\x12\x1a.xptracker.v1.GetMRRequest\x1a\x10.xptracker.v1.MR\x12?\n" +
+	"\x05Watch\x12\x1a.xptracker.v1.WatchRequest\x1a\x18.xptracker.v1.WatchEvent0\x01BCZAgithub.com/kanzifucius/xp-tracker/pkg/api/xptrackerv1;xptrackerv1b\x06proto3"
+
+var (
+	file_xptracker_v1_inventory_proto_rawDescOnce sync.Once
+	file_xptracker_v1_inventory_proto_rawDescData []byte
+)
+
+func file_xptracker_v1_inventory_proto_rawDescGZIP() []byte {
+	file_xptracker_v1_inventory_proto_rawDescOnce.Do(func() {
+		file_xptracker_v1_inventory_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_xptracker_v1_inventory_proto_rawDesc), len(file_xptracker_v1_inventory_proto_rawDesc)))
+	})
+	return file_xptracker_v1_inventory_proto_rawDescData
+}
+
+var file_xptracker_v1_inventory_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_xptracker_v1_inventory_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
+var file_xptracker_v1_inventory_proto_goTypes = []any{
+	(WatchEvent_Type)(0),          // 0: xptracker.v1.WatchEvent.Type
+	(*ListRequest)(nil),           // 1: xptracker.v1.ListRequest
+	(*ListClaimsResponse)(nil),    // 2: xptracker.v1.ListClaimsResponse
+	(*ListXRsResponse)(nil),       // 3: xptracker.v1.ListXRsResponse
+	(*ListMRsResponse)(nil),       // 4: xptracker.v1.ListMRsResponse
+	(*GetClaimRequest)(nil),       // 5: xptracker.v1.GetClaimRequest
+	(*GetXRRequest)(nil),          // 6: xptracker.v1.GetXRRequest
+	(*GetMRRequest)(nil),          // 7: xptracker.v1.GetMRRequest
+	(*WatchRequest)(nil),          // 8: xptracker.v1.WatchRequest
+	(*WatchEvent)(nil),            // 9: xptracker.v1.WatchEvent
+	(*Claim)(nil),                 // 10: xptracker.v1.Claim
+	(*XR)(nil),                    // 11: xptracker.v1.XR
+	(*MR)(nil),                    // 12: xptracker.v1.MR
+	(*timestamppb.Timestamp)(nil), // 13: google.protobuf.Timestamp
+}
+var file_xptracker_v1_inventory_proto_depIdxs = []int32{
+	10, // 0: xptracker.v1.ListClaimsResponse.claims:type_name -> xptracker.v1.Claim
+	11, // 1: xptracker.v1.ListXRsResponse.xrs:type_name -> xptracker.v1.XR
+	12, // 2: xptracker.v1.ListMRsResponse.mrs:type_name -> xptracker.v1.MR
+	0,  // 3: xptracker.v1.WatchEvent.type:type_name -> xptracker.v1.WatchEvent.Type
+	13, // 4: xptracker.v1.WatchEvent.at:type_name -> google.protobuf.Timestamp
+	10, // 5: xptracker.v1.WatchEvent.claim:type_name -> xptracker.v1.Claim
+	11, // 6: xptracker.v1.WatchEvent.xr:type_name -> xptracker.v1.XR
+	12, // 7: xptracker.v1.WatchEvent.mr:type_name -> xptracker.v1.MR
+	13, // 8: xptracker.v1.Claim.created_at:type_name -> google.protobuf.Timestamp
+	13, // 9: xptracker.v1.Claim.deleted_at:type_name -> google.protobuf.Timestamp
+	13, // 10: xptracker.v1.XR.created_at:type_name -> google.protobuf.Timestamp
+	13, // 11: xptracker.v1.XR.deleted_at:type_name -> google.protobuf.Timestamp
+	13, // 12: xptracker.v1.MR.created_at:type_name -> google.protobuf.Timestamp
+	13, // 13: xptracker.v1.MR.deleted_at:type_name -> google.protobuf.Timestamp
+	1,  // 14: xptracker.v1.InventoryService.ListClaims:input_type -> xptracker.v1.ListRequest
+	1,  // 15: xptracker.v1.InventoryService.ListXRs:input_type -> xptracker.v1.ListRequest
+	1,  // 16: xptracker.v1.InventoryService.ListMRs:input_type -> xptracker.v1.ListRequest
+	5,  // 17: xptracker.v1.InventoryService.GetClaim:input_type -> xptracker.v1.GetClaimRequest
+	6,  // 18: xptracker.v1.InventoryService.GetXR:input_type -> xptracker.v1.GetXRRequest
+	7,  // 19: xptracker.v1.InventoryService.GetMR:input_type -> xptracker.v1.GetMRRequest
+	8,  // 20: xptracker.v1.InventoryService.Watch:input_type -> xptracker.v1.WatchRequest
+	2,  // 21: xptracker.v1.InventoryService.ListClaims:output_type -> xptracker.v1.ListClaimsResponse
+	3,  // 22: xptracker.v1.InventoryService.ListXRs:output_type -> xptracker.v1.ListXRsResponse
+	4,  // 23: xptracker.v1.InventoryService.ListMRs:output_type -> xptracker.v1.ListMRsResponse
+	10, // 24: xptracker.v1.InventoryService.GetClaim:output_type -> xptracker.v1.Claim
+	11, // 25: xptracker.v1.InventoryService.GetXR:output_type -> xptracker.v1.XR
+	12, // 26: xptracker.v1.InventoryService.GetMR:output_type -> xptracker.v1.MR
+	9,  // 27: xptracker.v1.InventoryService.Watch:output_type -> xptracker.v1.WatchEvent
+	21, // [21:28] is the sub-list for method output_type
+	14, // [14:21] is the sub-list for method input_type
+	14, // [14:14] is the sub-list for extension type_name
+	14, // [14:14] is the sub-list for extension extendee
+	0,  // [0:14] is the sub-list for field type_name
+}
+
+func init() { file_xptracker_v1_inventory_proto_init() }
+func file_xptracker_v1_inventory_proto_init() {
+	if File_xptracker_v1_inventory_proto != nil {
+		return
+	}
+	file_xptracker_v1_inventory_proto_msgTypes[0].OneofWrappers = []any{}
+	file_xptracker_v1_inventory_proto_msgTypes[7].OneofWrappers = []any{}
+	file_xptracker_v1_inventory_proto_msgTypes[8].OneofWrappers = []any{
+		(*WatchEvent_Claim)(nil),
+		(*WatchEvent_Xr)(nil),
+		(*WatchEvent_Mr)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_xptracker_v1_inventory_proto_rawDesc), len(file_xptracker_v1_inventory_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   12,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_xptracker_v1_inventory_proto_goTypes,
+		DependencyIndexes: file_xptracker_v1_inventory_proto_depIdxs,
+		EnumInfos:         file_xptracker_v1_inventory_proto_enumTypes,
+		MessageInfos:      file_xptracker_v1_inventory_proto_msgTypes,
+	}.Build()
+	File_xptracker_v1_inventory_proto = out.File
+	file_xptracker_v1_inventory_proto_goTypes = nil
+	file_xptracker_v1_inventory_proto_depIdxs = nil
+}
@@ -0,0 +1,373 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: xptracker/v1/inventory.proto
+
+package xptrackerv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	InventoryService_ListClaims_FullMethodName = "/xptracker.v1.InventoryService/ListClaims"
+	InventoryService_ListXRs_FullMethodName    = "/xptracker.v1.InventoryService/ListXRs"
+	InventoryService_ListMRs_FullMethodName    = "/xptracker.v1.InventoryService/ListMRs"
+	InventoryService_GetClaim_FullMethodName   = "/xptracker.v1.InventoryService/GetClaim"
+	InventoryService_GetXR_FullMethodName      = "/xptracker.v1.InventoryService/GetXR"
+	InventoryService_GetMR_FullMethodName      = "/xptracker.v1.InventoryService/GetMR"
+	InventoryService_Watch_FullMethodName      = "/xptracker.v1.InventoryService/Watch"
+)
+
+// InventoryServiceClient is the client API for InventoryService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// InventoryService exposes the inventory of Crossplane claims, composite
+// resources (XRs) and managed resources (MRs) that xp-tracker polls.
+type InventoryServiceClient interface {
+	// ListClaims returns one page of claims matching the request.
+	ListClaims(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListClaimsResponse, error)
+	// ListXRs returns one page of composite resources matching the request.
+	ListXRs(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListXRsResponse, error)
+	// ListMRs returns one page of managed resources matching the request.
+	ListMRs(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListMRsResponse, error)
+	// GetClaim returns a single claim, or NOT_FOUND.
+	GetClaim(ctx context.Context, in *GetClaimRequest, opts ...grpc.CallOption) (*Claim, error)
+	// GetXR returns a single composite resource, or NOT_FOUND.
+	GetXR(ctx context.Context, in *GetXRRequest, opts ...grpc.CallOption) (*XR, error)
+	// GetMR returns a single managed resource, or NOT_FOUND.
+	GetMR(ctx context.Context, in *GetMRRequest, opts ...grpc.CallOption) (*MR, error)
+	// Watch streams changes to claims, XRs and MRs as poll cycles commit them.
+	Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchEvent], error)
+}
+
+type inventoryServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewInventoryServiceClient(cc grpc.ClientConnInterface) InventoryServiceClient {
+	return &inventoryServiceClient{cc}
+}
+
+func (c *inventoryServiceClient) ListClaims(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListClaimsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListClaimsResponse)
+	err := c.cc.Invoke(ctx, InventoryService_ListClaims_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *inventoryServiceClient) ListXRs(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListXRsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListXRsResponse)
+	err := c.cc.Invoke(ctx, InventoryService_ListXRs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *inventoryServiceClient) ListMRs(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListMRsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListMRsResponse)
+	err := c.cc.Invoke(ctx, InventoryService_ListMRs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *inventoryServiceClient) GetClaim(ctx context.Context, in *GetClaimRequest, opts ...grpc.CallOption) (*Claim, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Claim)
+	err := c.cc.Invoke(ctx, InventoryService_GetClaim_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *inventoryServiceClient) GetXR(ctx context.Context, in *GetXRRequest, opts ...grpc.CallOption) (*XR, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(XR)
+	err := c.cc.Invoke(ctx, InventoryService_GetXR_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *inventoryServiceClient) GetMR(ctx context.Context, in *GetMRRequest, opts ...grpc.CallOption) (*MR, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MR)
+	err := c.cc.Invoke(ctx, InventoryService_GetMR_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *inventoryServiceClient) Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &InventoryService_ServiceDesc.Streams[0], InventoryService_Watch_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchRequest, WatchEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type InventoryService_WatchClient = grpc.ServerStreamingClient[WatchEvent]
+
+// InventoryServiceServer is the server API for InventoryService service.
+// All implementations must embed UnimplementedInventoryServiceServer
+// for forward compatibility.
+//
+// InventoryService exposes the inventory of Crossplane claims, composite
+// resources (XRs) and managed resources (MRs) that xp-tracker polls.
+type InventoryServiceServer interface {
+	// ListClaims returns one page of claims matching the request.
+	ListClaims(context.Context, *ListRequest) (*ListClaimsResponse, error)
+	// ListXRs returns one page of composite resources matching the request.
+	ListXRs(context.Context, *ListRequest) (*ListXRsResponse, error)
+	// ListMRs returns one page of managed resources matching the request.
+	ListMRs(context.Context, *ListRequest) (*ListMRsResponse, error)
+	// GetClaim returns a single claim, or NOT_FOUND.
+	GetClaim(context.Context, *GetClaimRequest) (*Claim, error)
+	// GetXR returns a single composite resource, or NOT_FOUND.
+	GetXR(context.Context, *GetXRRequest) (*XR, error)
+	// GetMR returns a single managed resource, or NOT_FOUND.
+	GetMR(context.Context, *GetMRRequest) (*MR, error)
+	// Watch streams changes to claims, XRs and MRs as poll cycles commit them.
+	Watch(*WatchRequest, grpc.ServerStreamingServer[WatchEvent]) error
+	mustEmbedUnimplementedInventoryServiceServer()
+}
+
+// UnimplementedInventoryServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedInventoryServiceServer struct{}
+
+func (UnimplementedInventoryServiceServer) ListClaims(context.Context, *ListRequest) (*ListClaimsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListClaims not implemented")
+}
+func (UnimplementedInventoryServiceServer) ListXRs(context.Context, *ListRequest) (*ListXRsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListXRs not implemented")
+}
+func (UnimplementedInventoryServiceServer) ListMRs(context.Context, *ListRequest) (*ListMRsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListMRs not implemented")
+}
+func (UnimplementedInventoryServiceServer) GetClaim(context.Context, *GetClaimRequest) (*Claim, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetClaim not implemented")
+}
+func (UnimplementedInventoryServiceServer) GetXR(context.Context, *GetXRRequest) (*XR, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetXR not implemented")
+}
+func (UnimplementedInventoryServiceServer) GetMR(context.Context, *GetMRRequest) (*MR, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMR not implemented")
+}
+func (UnimplementedInventoryServiceServer) Watch(*WatchRequest, grpc.ServerStreamingServer[WatchEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method Watch not implemented")
+}
+func (UnimplementedInventoryServiceServer) mustEmbedUnimplementedInventoryServiceServer() {}
+func (UnimplementedInventoryServiceServer) testEmbeddedByValue()                          {}
+
+// UnsafeInventoryServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to InventoryServiceServer will
+// result in compilation errors.
+type UnsafeInventoryServiceServer interface {
+	mustEmbedUnimplementedInventoryServiceServer()
+}
+
+func RegisterInventoryServiceServer(s grpc.ServiceRegistrar, srv InventoryServiceServer) {
+	// If the following call pancis, it indicates UnimplementedInventoryServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&InventoryService_ServiceDesc, srv)
+}
+
+func _InventoryService_ListClaims_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InventoryServiceServer).ListClaims(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InventoryService_ListClaims_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InventoryServiceServer).ListClaims(ctx, req.(*ListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InventoryService_ListXRs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InventoryServiceServer).ListXRs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InventoryService_ListXRs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InventoryServiceServer).ListXRs(ctx, req.(*ListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InventoryService_ListMRs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InventoryServiceServer).ListMRs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InventoryService_ListMRs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InventoryServiceServer).ListMRs(ctx, req.(*ListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InventoryService_GetClaim_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetClaimRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InventoryServiceServer).GetClaim(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InventoryService_GetClaim_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InventoryServiceServer).GetClaim(ctx, req.(*GetClaimRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InventoryService_GetXR_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetXRRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InventoryServiceServer).GetXR(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InventoryService_GetXR_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InventoryServiceServer).GetXR(ctx, req.(*GetXRRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InventoryService_GetMR_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMRRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InventoryServiceServer).GetMR(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InventoryService_GetMR_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InventoryServiceServer).GetMR(ctx, req.(*GetMRRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InventoryService_Watch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(InventoryServiceServer).Watch(m, &grpc.GenericServerStream[WatchRequest, WatchEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type InventoryService_WatchServer = grpc.ServerStreamingServer[WatchEvent]
+
+// InventoryService_ServiceDesc is the grpc.ServiceDesc for InventoryService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var InventoryService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "xptracker.v1.InventoryService",
+	HandlerType: (*InventoryServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListClaims",
+			Handler:    _InventoryService_ListClaims_Handler,
+		},
+		{
+			MethodName: "ListXRs",
+			Handler:    _InventoryService_ListXRs_Handler,
+		},
+		{
+			MethodName: "ListMRs",
+			Handler:    _InventoryService_ListMRs_Handler,
+		},
+		{
+			MethodName: "GetClaim",
+			Handler:    _InventoryService_GetClaim_Handler,
+		},
+		{
+			MethodName: "GetXR",
+			Handler:    _InventoryService_GetXR_Handler,
+		},
+		{
+			MethodName: "GetMR",
+			Handler:    _InventoryService_GetMR_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Watch",
+			Handler:       _InventoryService_Watch_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "xptracker/v1/inventory.proto",
+}
@@ -0,0 +1,187 @@
+// Package auth authenticates and authorizes API requests against the
+// Kubernetes API server, in the manner of kube-rbac-proxy: bearer tokens are
+// validated with a TokenReview and access is checked with a
+// SubjectAccessReview for the request path as a non-resource URL.
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// User is an authenticated caller of the API.
+type User struct {
+	Name   string
+	UID    string
+	Groups []string
+	Extra  map[string][]string
+}
+
+// Reviewer authenticates bearer tokens and authorizes requests.
+type Reviewer interface {
+	// Authenticate returns the user a token belongs to. ok is false when
+	// the token is not valid.
+	Authenticate(ctx context.Context, token string) (u User, ok bool, err error)
+
+	// Authorize reports whether u may perform verb on the non-resource URL
+	// path. reason explains a denial when the reviewer provides one.
+	Authorize(ctx context.Context, u User, verb, path string) (allowed bool, reason string, err error)
+
+	// AuthorizeResource reports whether u may perform the action described
+	// by attrs on Kubernetes resources.
+	AuthorizeResource(ctx context.Context, u User, attrs ResourceAttributes) (allowed bool, err error)
+}
+
+// ResourceAttributes describes an action on Kubernetes resources. An empty
+// Namespace means all namespaces.
+type ResourceAttributes struct {
+	Namespace string
+	Verb      string
+	Group     string
+	Resource  string
+}
+
+// healthPaths are never protected, so that kubelet probes keep working.
+var healthPaths = []string{"/healthz", "/readyz"}
+
+// Filter requires authentication and authorization for selected endpoints.
+//
+// Each configured path is either an exact request path ("/metrics") or a
+// subtree ending in a slash ("/claims/"); "/" protects every endpoint. The
+// health probes are never protected.
+type Filter struct {
+	reviewer Reviewer
+	paths    []string
+}
+
+// NewFilter returns a Filter that protects paths using r.
+func NewFilter(r Reviewer, paths []string) *Filter {
+	return &Filter{reviewer: r, paths: paths}
+}
+
+// Protects reports whether requests for path require authentication.
+func (f *Filter) Protects(path string) bool {
+	if slices.Contains(healthPaths, path) {
+		return false
+	}
+	for _, p := range f.paths {
+		if p == path || (strings.HasSuffix(p, "/") && strings.HasPrefix(path, p)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Errors returned by Check.
+var (
+	// ErrUnauthenticated means the token is invalid or could not be reviewed.
+	ErrUnauthenticated = errors.New("unauthenticated")
+	// ErrForbidden means the user may not access the path.
+	ErrForbidden = errors.New("forbidden")
+)
+
+// Check authenticates token and authorizes verb on the non-resource URL
+// path. It returns ErrUnauthenticated or ErrForbidden when access is denied,
+// and another error when the access review fails.
+func (f *Filter) Check(ctx context.Context, token, verb, path string) (User, error) {
+	u, ok, err := f.reviewer.Authenticate(ctx, token)
+	if err != nil {
+		slog.Error("token review failed", "path", path, "error", err)
+		return User{}, ErrUnauthenticated
+	}
+	if !ok {
+		return User{}, ErrUnauthenticated
+	}
+
+	allowed, reason, err := f.reviewer.Authorize(ctx, u, verb, path)
+	if err != nil {
+		return User{}, fmt.Errorf("subject access review for %s: %w", u.Name, err)
+	}
+	if !allowed {
+		slog.Debug("request forbidden", "user", u.Name, "verb", verb, "path", path, "reason", reason)
+		return User{}, ErrForbidden
+	}
+	return u, nil
+}
+
+// Wrap returns a handler that checks protected requests before passing them
+// to next. Unauthenticated requests get 401, unauthorized ones 403. The
+// authenticated user is available to next via UserFrom.
+func (f *Filter) Wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !f.Protects(r.URL.Path) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		token, ok := BearerToken(r.Header.Get("Authorization"))
+		if !ok {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="xp-tracker"`)
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		u, err := f.Check(r.Context(), token, requestVerb(r.Method), r.URL.Path)
+		switch {
+		case errors.Is(err, ErrUnauthenticated):
+			w.Header().Set("WWW-Authenticate", `Bearer realm="xp-tracker", error="invalid_token"`)
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		case errors.Is(err, ErrForbidden):
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return
+		case err != nil:
+			slog.Error("access review failed", "path", r.URL.Path, "error", err)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(WithUser(r.Context(), u)))
+	})
+}
+
+// BearerToken extracts the token from an "Authorization: Bearer" header
+// value.
+func BearerToken(header string) (string, bool) {
+	scheme, token, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+// requestVerb maps an HTTP method to the Kubernetes verb used for
+// non-resource URLs.
+func requestVerb(method string) string {
+	switch method {
+	case http.MethodPost:
+		return "create"
+	case http.MethodPut:
+		return "update"
+	case http.MethodPatch:
+		return "patch"
+	case http.MethodDelete:
+		return "delete"
+	default:
+		return "get"
+	}
+}
+
+type userKey struct{}
+
+// WithUser returns a copy of ctx carrying u.
+func WithUser(ctx context.Context, u User) context.Context {
+	return context.WithValue(ctx, userKey{}, u)
+}
+
+// UserFrom returns the authenticated user stored in ctx, if any.
+func UserFrom(ctx context.Context) (User, bool) {
+	u, ok := ctx.Value(userKey{}).(User)
+	return u, ok
+}
@@ -0,0 +1,180 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// stubReviewer accepts the token "good" as alice, who may only get /metrics
+// and list pods in team-a. The admin user may list pods everywhere.
+type stubReviewer struct {
+	authnCalls, authzCalls int
+	resourceCalls          atomic.Int32
+	err                    error
+}
+
+func (s *stubReviewer) Authenticate(_ context.Context, token string) (User, bool, error) {
+	s.authnCalls++
+	if s.err != nil {
+		return User{}, false, s.err
+	}
+	if token != "good" {
+		return User{}, false, nil
+	}
+	return User{Name: "alice", UID: "1", Groups: []string{"devs"}}, true, nil
+}
+
+func (s *stubReviewer) Authorize(_ context.Context, u User, verb, path string) (bool, string, error) {
+	s.authzCalls++
+	if u.Name == "alice" && verb == "get" && path == "/metrics" {
+		return true, "", nil
+	}
+	return false, "no rule", nil
+}
+
+func (s *stubReviewer) AuthorizeResource(_ context.Context, u User, attrs ResourceAttributes) (bool, error) {
+	s.resourceCalls.Add(1)
+	if attrs.Verb != "list" || attrs.Group != "" || attrs.Resource != "pods" {
+		return false, nil
+	}
+	return u.Name == "admin" || (u.Name == "alice" && attrs.Namespace == "team-a"), nil
+}
+
+func TestFilter_Protects(t *testing.T) {
+	f := NewFilter(&stubReviewer{}, []string{"/metrics", "/claims/"})
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"/metrics", true},
+		{"/metricsx", false},
+		{"/claims/team-a/db", true},
+		{"/claims", false},
+		{"/bookkeeping", false},
+		{"/healthz", false},
+	}
+	for _, tt := range tests {
+		if got := f.Protects(tt.path); got != tt.want {
+			t.Errorf("Protects(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+
+	all := NewFilter(&stubReviewer{}, []string{"/"})
+	if !all.Protects("/bookkeeping") || !all.Protects("/events/stream") {
+		t.Error("expected \"/\" to protect every endpoint")
+	}
+	if all.Protects("/healthz") || all.Protects("/readyz") {
+		t.Error("expected health probes to stay unprotected")
+	}
+}
+
+func TestFilter_Wrap(t *testing.T) {
+	var gotUser User
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotUser, _ = UserFrom(r.Context())
+		w.WriteHeader(http.StatusOK)
+	})
+	h := NewFilter(&stubReviewer{}, []string{"/metrics", "/bookkeeping"}).Wrap(next)
+
+	tests := []struct {
+		name   string
+		path   string
+		header string
+		want   int
+	}{
+		{"unprotected", "/healthz", "", http.StatusOK},
+		{"no token", "/metrics", "", http.StatusUnauthorized},
+		{"not bearer", "/metrics", "Basic Zm9vOmJhcg==", http.StatusUnauthorized},
+		{"invalid token", "/metrics", "Bearer bad", http.StatusUnauthorized},
+		{"allowed", "/metrics", "Bearer good", http.StatusOK},
+		{"forbidden", "/bookkeeping", "Bearer good", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Errorf("expected %d, got %d", tt.want, rec.Code)
+			}
+			if rec.Code == http.StatusUnauthorized && rec.Header().Get("WWW-Authenticate") == "" {
+				t.Error("expected WWW-Authenticate header on 401")
+			}
+		})
+	}
+
+	if gotUser.Name != "alice" {
+		t.Errorf("expected authenticated user in context, got %+v", gotUser)
+	}
+}
+
+func TestFilter_ReviewError(t *testing.T) {
+	h := NewFilter(&stubReviewer{err: errors.New("api server down")}, []string{"/metrics"}).
+		Wrap(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			t.Error("handler must not run when the review fails")
+		}))
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	req.Header.Set("Authorization", "Bearer good")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401, got %d", rec.Code)
+	}
+}
+
+func TestCachingReviewer(t *testing.T) {
+	stub := &stubReviewer{}
+	c := NewCachingReviewer(stub, time.Minute)
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	c.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	for range 3 {
+		u, ok, err := c.Authenticate(ctx, "good")
+		if err != nil || !ok {
+			t.Fatalf("Authenticate: ok=%v err=%v", ok, err)
+		}
+		if allowed, _, err := c.Authorize(ctx, u, "get", "/metrics"); err != nil || !allowed {
+			t.Fatalf("Authorize: allowed=%v err=%v", allowed, err)
+		}
+	}
+	if stub.authnCalls != 1 || stub.authzCalls != 1 {
+		t.Errorf("expected one review of each kind, got %d token and %d access reviews", stub.authnCalls, stub.authzCalls)
+	}
+
+	// Invalid tokens are cached too, errors are not.
+	for range 2 {
+		if _, ok, _ := c.Authenticate(ctx, "bad"); ok {
+			t.Fatal("expected invalid token to be rejected")
+		}
+	}
+	if stub.authnCalls != 2 {
+		t.Errorf("expected invalid token to be reviewed once, got %d token reviews", stub.authnCalls)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if _, _, err := c.Authenticate(ctx, "good"); err != nil {
+		t.Fatalf("Authenticate: %v", err)
+	}
+	if stub.authnCalls != 3 {
+		t.Errorf("expected expired entry to be reviewed again, got %d token reviews", stub.authnCalls)
+	}
+
+	stub.err = errors.New("api server down")
+	now = now.Add(2 * time.Minute)
+	if _, _, err := c.Authenticate(ctx, "good"); err == nil {
+		t.Fatal("expected review error")
+	}
+	stub.err = nil
+	if _, ok, err := c.Authenticate(ctx, "good"); err != nil || !ok {
+		t.Errorf("expected error not to be cached: ok=%v err=%v", ok, err)
+	}
+}
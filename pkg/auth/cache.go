@@ -0,0 +1,143 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxCacheEntries bounds each cache; expired entries are swept once it is
+// reached, and the cache is emptied if that is not enough.
+const maxCacheEntries = 4096
+
+// CachingReviewer remembers the results of another Reviewer for a fixed
+// time, so that frequent scrapes and polling clients do not send a
+// TokenReview and a SubjectAccessReview to the API server on every request.
+// Errors are never cached.
+type CachingReviewer struct {
+	next Reviewer
+	ttl  time.Duration
+	now  func() time.Time
+
+	mu        sync.Mutex
+	authn     map[string]authnEntry // sha256 of the token → result
+	authz     map[string]authzEntry // user, verb and path → decision
+	resources map[string]authzEntry // user and resource attributes → decision
+}
+
+type authnEntry struct {
+	user    User
+	ok      bool
+	expires time.Time
+}
+
+type authzEntry struct {
+	allowed bool
+	reason  string
+	expires time.Time
+}
+
+// NewCachingReviewer wraps next with a cache whose entries live for ttl.
+func NewCachingReviewer(next Reviewer, ttl time.Duration) *CachingReviewer {
+	return &CachingReviewer{
+		next:      next,
+		ttl:       ttl,
+		now:       time.Now,
+		authn:     make(map[string]authnEntry),
+		authz:     make(map[string]authzEntry),
+		resources: make(map[string]authzEntry),
+	}
+}
+
+// Authenticate implements Reviewer.
+func (c *CachingReviewer) Authenticate(ctx context.Context, token string) (User, bool, error) {
+	sum := sha256.Sum256([]byte(token))
+	key := hex.EncodeToString(sum[:])
+
+	c.mu.Lock()
+	e, hit := c.authn[key]
+	c.mu.Unlock()
+	if hit && c.now().Before(e.expires) {
+		return e.user, e.ok, nil
+	}
+
+	u, ok, err := c.next.Authenticate(ctx, token)
+	if err != nil {
+		return User{}, false, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	if len(c.authn) >= maxCacheEntries {
+		c.authn = sweep(c.authn, now, func(e authnEntry) time.Time { return e.expires })
+	}
+	c.authn[key] = authnEntry{user: u, ok: ok, expires: now.Add(c.ttl)}
+	return u, ok, nil
+}
+
+// Authorize implements Reviewer.
+func (c *CachingReviewer) Authorize(ctx context.Context, u User, verb, path string) (bool, string, error) {
+	key := strings.Join([]string{u.UID, u.Name, verb, path}, "\x00")
+
+	c.mu.Lock()
+	e, hit := c.authz[key]
+	c.mu.Unlock()
+	if hit && c.now().Before(e.expires) {
+		return e.allowed, e.reason, nil
+	}
+
+	allowed, reason, err := c.next.Authorize(ctx, u, verb, path)
+	if err != nil {
+		return false, "", err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	if len(c.authz) >= maxCacheEntries {
+		c.authz = sweep(c.authz, now, func(e authzEntry) time.Time { return e.expires })
+	}
+	c.authz[key] = authzEntry{allowed: allowed, reason: reason, expires: now.Add(c.ttl)}
+	return allowed, reason, nil
+}
+
+// AuthorizeResource implements Reviewer.
+func (c *CachingReviewer) AuthorizeResource(ctx context.Context, u User, attrs ResourceAttributes) (bool, error) {
+	key := strings.Join([]string{u.UID, u.Name, attrs.Verb, attrs.Group, attrs.Resource, attrs.Namespace}, "\x00")
+
+	c.mu.Lock()
+	e, hit := c.resources[key]
+	c.mu.Unlock()
+	if hit && c.now().Before(e.expires) {
+		return e.allowed, nil
+	}
+
+	allowed, err := c.next.AuthorizeResource(ctx, u, attrs)
+	if err != nil {
+		return false, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	if len(c.resources) >= maxCacheEntries {
+		c.resources = sweep(c.resources, now, func(e authzEntry) time.Time { return e.expires })
+	}
+	c.resources[key] = authzEntry{allowed: allowed, expires: now.Add(c.ttl)}
+	return allowed, nil
+}
+
+// sweep deletes expired entries from m. If every entry is still live it
+// returns an empty map instead, keeping the cache bounded.
+func sweep[E any](m map[string]E, now time.Time, expires func(E) time.Time) map[string]E {
+	for k, e := range m {
+		if !now.Before(expires(e)) {
+			delete(m, k)
+		}
+	}
+	if len(m) >= maxCacheEntries {
+		return make(map[string]E)
+	}
+	return m
+}
@@ -0,0 +1,112 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	authenticationclient "k8s.io/client-go/kubernetes/typed/authentication/v1"
+	authorizationclient "k8s.io/client-go/kubernetes/typed/authorization/v1"
+	"k8s.io/client-go/rest"
+)
+
+// KubeReviewer is a Reviewer backed by the Kubernetes TokenReview and
+// SubjectAccessReview APIs. The exporter's service account needs permission
+// to create both, which the built-in system:auth-delegator ClusterRole grants.
+type KubeReviewer struct {
+	tokens authenticationclient.TokenReviewsGetter
+	access authorizationclient.SubjectAccessReviewsGetter
+}
+
+// NewKubeReviewer creates a KubeReviewer from a client configuration.
+func NewKubeReviewer(cfg *rest.Config) (*KubeReviewer, error) {
+	authn, err := authenticationclient.NewForConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("create authentication client: %w", err)
+	}
+	authz, err := authorizationclient.NewForConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("create authorization client: %w", err)
+	}
+	return &KubeReviewer{tokens: authn, access: authz}, nil
+}
+
+// Authenticate implements Reviewer with a TokenReview.
+func (k *KubeReviewer) Authenticate(ctx context.Context, token string) (User, bool, error) {
+	review, err := k.tokens.TokenReviews().Create(ctx, &authenticationv1.TokenReview{
+		Spec: authenticationv1.TokenReviewSpec{Token: token},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return User{}, false, fmt.Errorf("create token review: %w", err)
+	}
+	if review.Status.Error != "" {
+		return User{}, false, fmt.Errorf("token review: %s", review.Status.Error)
+	}
+	if !review.Status.Authenticated {
+		return User{}, false, nil
+	}
+
+	info := review.Status.User
+	u := User{Name: info.Username, UID: info.UID, Groups: info.Groups}
+	if len(info.Extra) > 0 {
+		u.Extra = make(map[string][]string, len(info.Extra))
+		for k, v := range info.Extra {
+			u.Extra[k] = v
+		}
+	}
+	return u, true, nil
+}
+
+// Authorize implements Reviewer with a SubjectAccessReview for path as a
+// non-resource URL.
+func (k *KubeReviewer) Authorize(ctx context.Context, u User, verb, path string) (bool, string, error) {
+	status, err := k.review(ctx, u, authorizationv1.SubjectAccessReviewSpec{
+		NonResourceAttributes: &authorizationv1.NonResourceAttributes{
+			Path: path,
+			Verb: verb,
+		},
+	})
+	if err != nil {
+		return false, "", err
+	}
+	reason := status.Reason
+	if reason == "" {
+		reason = status.EvaluationError
+	}
+	return status.Allowed, reason, nil
+}
+
+// AuthorizeResource implements Reviewer with a SubjectAccessReview for
+// resource attributes.
+func (k *KubeReviewer) AuthorizeResource(ctx context.Context, u User, attrs ResourceAttributes) (bool, error) {
+	status, err := k.review(ctx, u, authorizationv1.SubjectAccessReviewSpec{
+		ResourceAttributes: &authorizationv1.ResourceAttributes{
+			Namespace: attrs.Namespace,
+			Verb:      attrs.Verb,
+			Group:     attrs.Group,
+			Resource:  attrs.Resource,
+		},
+	})
+	if err != nil {
+		return false, err
+	}
+	return status.Allowed, nil
+}
+
+// review creates a SubjectAccessReview of spec for u.
+func (k *KubeReviewer) review(ctx context.Context, u User, spec authorizationv1.SubjectAccessReviewSpec) (authorizationv1.SubjectAccessReviewStatus, error) {
+	spec.User, spec.UID, spec.Groups = u.Name, u.UID, u.Groups
+	if len(u.Extra) > 0 {
+		spec.Extra = make(map[string]authorizationv1.ExtraValue, len(u.Extra))
+		for k, v := range u.Extra {
+			spec.Extra[k] = v
+		}
+	}
+	review, err := k.access.SubjectAccessReviews().Create(ctx, &authorizationv1.SubjectAccessReview{Spec: spec}, metav1.CreateOptions{})
+	if err != nil {
+		return authorizationv1.SubjectAccessReviewStatus{}, fmt.Errorf("create subject access review: %w", err)
+	}
+	return review.Status, nil
+}
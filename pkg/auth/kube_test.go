@@ -0,0 +1,77 @@
+package auth
+
+import (
+	"context"
+	"testing"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	authenticationfake "k8s.io/client-go/kubernetes/typed/authentication/v1/fake"
+	authorizationfake "k8s.io/client-go/kubernetes/typed/authorization/v1/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func fakeKubeReviewer(t *testing.T) *KubeReviewer {
+	t.Helper()
+
+	authn := &authenticationfake.FakeAuthenticationV1{Fake: &k8stesting.Fake{}}
+	authn.AddReactor("create", "tokenreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authenticationv1.TokenReview)
+		if review.Spec.Token == "good" {
+			review.Status = authenticationv1.TokenReviewStatus{
+				Authenticated: true,
+				User: authenticationv1.UserInfo{
+					Username: "system:serviceaccount:monitoring:prometheus",
+					UID:      "42",
+					Groups:   []string{"system:serviceaccounts"},
+				},
+			}
+		}
+		return true, review, nil
+	})
+
+	authz := &authorizationfake.FakeAuthorizationV1{Fake: &k8stesting.Fake{}}
+	authz.AddReactor("create", "subjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SubjectAccessReview)
+		attrs := review.Spec.NonResourceAttributes
+		if review.Spec.User == "system:serviceaccount:monitoring:prometheus" &&
+			attrs != nil && attrs.Verb == "get" && attrs.Path == "/metrics" {
+			review.Status.Allowed = true
+		} else {
+			review.Status.Reason = "no RBAC rule"
+		}
+		return true, review, nil
+	})
+
+	return &KubeReviewer{tokens: authn, access: authz}
+}
+
+func TestKubeReviewer(t *testing.T) {
+	k := fakeKubeReviewer(t)
+	ctx := context.Background()
+
+	if _, ok, err := k.Authenticate(ctx, "bad"); err != nil || ok {
+		t.Fatalf("expected invalid token to be rejected: ok=%v err=%v", ok, err)
+	}
+
+	u, ok, err := k.Authenticate(ctx, "good")
+	if err != nil || !ok {
+		t.Fatalf("Authenticate: ok=%v err=%v", ok, err)
+	}
+	if u.Name != "system:serviceaccount:monitoring:prometheus" || u.UID != "42" || len(u.Groups) != 1 {
+		t.Errorf("unexpected user %+v", u)
+	}
+
+	allowed, _, err := k.Authorize(ctx, u, "get", "/metrics")
+	if err != nil || !allowed {
+		t.Errorf("expected access to /metrics: allowed=%v err=%v", allowed, err)
+	}
+	allowed, reason, err := k.Authorize(ctx, u, "get", "/bookkeeping")
+	if err != nil || allowed {
+		t.Errorf("expected access to /bookkeeping to be denied: allowed=%v err=%v", allowed, err)
+	}
+	if reason != "no RBAC rule" {
+		t.Errorf("expected denial reason, got %q", reason)
+	}
+}
@@ -0,0 +1,102 @@
+package auth
+
+import (
+	"context"
+	"strings"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/kanzifucius/xp-tracker/pkg/store"
+)
+
+// namespaceReviewConcurrency bounds the access reviews sent in parallel
+// when resolving a caller's namespaces.
+const namespaceReviewConcurrency = 10
+
+// TenancyOptions configures how a caller's share of the inventory is found.
+type TenancyOptions struct {
+	// ByNamespace shows objects in namespaces where the caller may perform
+	// NamespaceAccess (its Namespace is ignored). Callers allowed it in all
+	// namespaces see everything.
+	ByNamespace     bool
+	NamespaceAccess ResourceAttributes
+
+	// ByTeam shows objects whose team is one of the caller's groups. With
+	// TeamGroupPrefix set, only groups with that prefix count, and the
+	// prefix is removed to get the team name.
+	ByTeam          bool
+	TeamGroupPrefix string
+}
+
+// Tenancy restricts inventory results to the objects a caller may see.
+type Tenancy struct {
+	reviewer Reviewer
+	opts     TenancyOptions
+}
+
+// NewTenancy returns a Tenancy that checks namespace access with r.
+func NewTenancy(r Reviewer, opts TenancyOptions) *Tenancy {
+	return &Tenancy{reviewer: r, opts: opts}
+}
+
+// Scope returns the part of the inventory u may see, given the namespaces
+// that currently hold objects. It returns nil when u may see everything.
+func (t *Tenancy) Scope(ctx context.Context, u User, namespaces []string) (*store.Scope, error) {
+	scope := &store.Scope{}
+
+	if t.opts.ByNamespace {
+		attrs := t.opts.NamespaceAccess
+		attrs.Namespace = ""
+		all, err := t.reviewer.AuthorizeResource(ctx, u, attrs)
+		if err != nil {
+			return nil, err
+		}
+		if all {
+			return nil, nil
+		}
+		if scope.Namespaces, err = t.allowedNamespaces(ctx, u, namespaces); err != nil {
+			return nil, err
+		}
+	}
+
+	if t.opts.ByTeam {
+		for _, g := range u.Groups {
+			if team, ok := strings.CutPrefix(g, t.opts.TeamGroupPrefix); ok && team != "" {
+				scope.Teams = append(scope.Teams, team)
+			}
+		}
+	}
+
+	return scope, nil
+}
+
+// allowedNamespaces returns the namespaces in which u may perform the
+// configured namespace access, in the order given.
+func (t *Tenancy) allowedNamespaces(ctx context.Context, u User, namespaces []string) ([]string, error) {
+	allowed := make([]bool, len(namespaces))
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(namespaceReviewConcurrency)
+	for i, ns := range namespaces {
+		g.Go(func() error {
+			attrs := t.opts.NamespaceAccess
+			attrs.Namespace = ns
+			ok, err := t.reviewer.AuthorizeResource(ctx, u, attrs)
+			if err != nil {
+				return err
+			}
+			allowed[i] = ok
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	var out []string
+	for i, ns := range namespaces {
+		if allowed[i] {
+			out = append(out, ns)
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,66 @@
+package auth
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+func TestTenancy_Scope(t *testing.T) {
+	namespaces := []string{"team-a", "team-b", "team-c"}
+	podAccess := ResourceAttributes{Verb: "list", Resource: "pods"}
+	ctx := context.Background()
+
+	t.Run("by namespace", func(t *testing.T) {
+		stub := &stubReviewer{}
+		tn := NewTenancy(stub, TenancyOptions{ByNamespace: true, NamespaceAccess: podAccess})
+		scope, err := tn.Scope(ctx, User{Name: "alice"}, namespaces)
+		if err != nil {
+			t.Fatalf("Scope: %v", err)
+		}
+		if scope == nil || !slices.Equal(scope.Namespaces, []string{"team-a"}) || len(scope.Teams) != 0 {
+			t.Errorf("expected scope limited to team-a, got %+v", scope)
+		}
+		// One cluster-wide check plus one per namespace.
+		if got := stub.resourceCalls.Load(); got != 4 {
+			t.Errorf("expected 4 access reviews, got %d", got)
+		}
+	})
+
+	t.Run("cluster-wide access", func(t *testing.T) {
+		stub := &stubReviewer{}
+		tn := NewTenancy(stub, TenancyOptions{ByNamespace: true, NamespaceAccess: podAccess})
+		scope, err := tn.Scope(ctx, User{Name: "admin"}, namespaces)
+		if err != nil {
+			t.Fatalf("Scope: %v", err)
+		}
+		if scope != nil {
+			t.Errorf("expected no restriction for cluster-wide access, got %+v", scope)
+		}
+		if got := stub.resourceCalls.Load(); got != 1 {
+			t.Errorf("expected a single access review, got %d", got)
+		}
+	})
+
+	t.Run("by team", func(t *testing.T) {
+		tn := NewTenancy(&stubReviewer{}, TenancyOptions{ByTeam: true, TeamGroupPrefix: "team:"})
+		scope, err := tn.Scope(ctx, User{Name: "bob", Groups: []string{"system:authenticated", "team:payments", "team:"}}, namespaces)
+		if err != nil {
+			t.Fatalf("Scope: %v", err)
+		}
+		if scope == nil || !slices.Equal(scope.Teams, []string{"payments"}) || len(scope.Namespaces) != 0 {
+			t.Errorf("expected scope limited to team payments, got %+v", scope)
+		}
+	})
+
+	t.Run("both", func(t *testing.T) {
+		tn := NewTenancy(&stubReviewer{}, TenancyOptions{ByNamespace: true, NamespaceAccess: podAccess, ByTeam: true})
+		scope, err := tn.Scope(ctx, User{Name: "alice", Groups: []string{"payments"}}, namespaces)
+		if err != nil {
+			t.Fatalf("Scope: %v", err)
+		}
+		if scope == nil || !slices.Equal(scope.Namespaces, []string{"team-a"}) || !slices.Equal(scope.Teams, []string{"payments"}) {
+			t.Errorf("expected namespace and team scope, got %+v", scope)
+		}
+	})
+}
@@ -0,0 +1,330 @@
+// Package client is a Go client for the xp-tracker HTTP API.
+//
+// It wraps the /bookkeeping listing, including pagination, and the
+// single-resource lookups. The API itself is described by the OpenAPI
+// document served at /openapi.json. The package depends only on the
+// standard library.
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// maxErrorBody bounds how much of an error response is kept as the message.
+const maxErrorBody = 4096
+
+// Client calls the xp-tracker API. It is safe for concurrent use.
+type Client struct {
+	baseURL    *url.URL
+	httpClient *http.Client
+	token      string
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithHTTPClient sets the HTTP client used for requests, for example one with
+// a TLS configuration or a timeout. The default is http.DefaultClient.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) { c.httpClient = hc }
+}
+
+// WithBearerToken sends token in the Authorization header of every request,
+// for servers that require API authentication.
+func WithBearerToken(token string) Option {
+	return func(c *Client) { c.token = token }
+}
+
+// New returns a Client for the API at baseURL, e.g.
+// "https://xp-tracker.monitoring.svc:8080".
+func New(baseURL string, opts ...Option) (*Client, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid base URL %q: scheme must be http or https", baseURL)
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/")
+	c := &Client{baseURL: u, httpClient: http.DefaultClient}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+// Error is returned for responses with a non-2xx status code.
+type Error struct {
+	StatusCode int
+	Message    string // response body, usually the server's error text
+}
+
+func (e *Error) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("xp-tracker API: %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+	}
+	return fmt.Sprintf("xp-tracker API: %d %s: %s", e.StatusCode, http.StatusText(e.StatusCode), e.Message)
+}
+
+// IsNotFound reports whether err is an Error with status 404.
+func IsNotFound(err error) bool {
+	var apiErr *Error
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// SortField names a field that listings can be ordered by.
+type SortField string
+
+// Sort fields.
+const (
+	SortByName        SortField = "name"
+	SortByNamespace   SortField = "namespace"
+	SortByKind        SortField = "kind"
+	SortByCreator     SortField = "creator"
+	SortByTeam        SortField = "team"
+	SortByComposition SortField = "composition"
+	SortByProvider    SortField = "provider"
+	SortByCreatedAt   SortField = "createdAt"
+)
+
+// ListOptions filters and orders a listing. Zero values are not sent. The
+// boolean filters are pointers so that false can be told apart from unset;
+// see Bool.
+type ListOptions struct {
+	GVR         string
+	Namespace   string
+	Kind        string
+	Team        string
+	Creator     string
+	Composition string
+	Provider    string
+
+	Ready    *bool
+	Synced   *bool
+	Paused   *bool
+	Deleting *bool
+
+	SortBy     SortField
+	Descending bool
+
+	// Limit and Cursor paginate the typed listings (ListClaims and so on);
+	// Inventory ignores them.
+	Limit  int
+	Cursor string
+
+	// IncludeDeleted adds recently removed resources to an Inventory.
+	IncludeDeleted bool
+}
+
+// Bool returns a pointer to b, for the boolean filters of ListOptions.
+func Bool(b bool) *bool { return &b }
+
+func (o ListOptions) values() url.Values {
+	v := url.Values{}
+	for name, s := range map[string]string{
+		"gvr":         o.GVR,
+		"namespace":   o.Namespace,
+		"kind":        o.Kind,
+		"team":        o.Team,
+		"creator":     o.Creator,
+		"composition": o.Composition,
+		"provider":    o.Provider,
+		"sort":        string(o.SortBy),
+	} {
+		if s != "" {
+			v.Set(name, s)
+		}
+	}
+	for name, b := range map[string]*bool{
+		"ready":    o.Ready,
+		"synced":   o.Synced,
+		"paused":   o.Paused,
+		"deleting": o.Deleting,
+	} {
+		if b != nil {
+			v.Set(name, strconv.FormatBool(*b))
+		}
+	}
+	if o.Descending {
+		v.Set("order", "desc")
+	}
+	return v
+}
+
+// Inventory returns all claims, XRs and MRs matching opts from a single
+// store generation.
+func (c *Client) Inventory(ctx context.Context, opts ListOptions) (*Inventory, error) {
+	v := opts.values()
+	if opts.IncludeDeleted {
+		v.Set("includeDeleted", "true")
+	}
+	var inv Inventory
+	if err := c.get(ctx, "/bookkeeping", v, &inv); err != nil {
+		return nil, err
+	}
+	return &inv, nil
+}
+
+// ListClaims returns one page of claims. Pass the returned Next as
+// opts.Cursor to fetch the following page, or use Claims to iterate.
+func (c *Client) ListClaims(ctx context.Context, opts ListOptions) (*Page[Claim], error) {
+	return list(ctx, c, "claims", opts, func(r *listResponse) []Claim { return r.Claims })
+}
+
+// ListXRs returns one page of XRs.
+func (c *Client) ListXRs(ctx context.Context, opts ListOptions) (*Page[XR], error) {
+	return list(ctx, c, "xrs", opts, func(r *listResponse) []XR { return r.XRs })
+}
+
+// ListMRs returns one page of MRs.
+func (c *Client) ListMRs(ctx context.Context, opts ListOptions) (*Page[MR], error) {
+	return list(ctx, c, "mrs", opts, func(r *listResponse) []MR { return r.MRs })
+}
+
+// Claims iterates over every claim matching opts, fetching pages of
+// opts.Limit items (the server default when zero) as needed. Iteration stops
+// at the first error, which is yielded with a zero Claim.
+//
+// Each page comes from the generation that was current when it was fetched.
+func (c *Client) Claims(ctx context.Context, opts ListOptions) iter.Seq2[Claim, error] {
+	return all(ctx, opts, c.ListClaims)
+}
+
+// XRs iterates over every XR matching opts; see Claims.
+func (c *Client) XRs(ctx context.Context, opts ListOptions) iter.Seq2[XR, error] {
+	return all(ctx, opts, c.ListXRs)
+}
+
+// MRs iterates over every MR matching opts; see Claims.
+func (c *Client) MRs(ctx context.Context, opts ListOptions) iter.Seq2[MR, error] {
+	return all(ctx, opts, c.ListMRs)
+}
+
+// Claim returns the claim namespace/name. Use IsNotFound to tell a missing
+// claim from other errors.
+func (c *Client) Claim(ctx context.Context, namespace, name string) (*ClaimRecord, error) {
+	var rec ClaimRecord
+	if err := c.get(ctx, "/claims/"+url.PathEscape(namespace)+"/"+url.PathEscape(name), nil, &rec); err != nil {
+		return nil, err
+	}
+	return &rec, nil
+}
+
+// XR returns an XR. namespace is empty for cluster-scoped XRs.
+func (c *Client) XR(ctx context.Context, namespace, name string) (*XRRecord, error) {
+	var rec XRRecord
+	if err := c.get(ctx, "/xrs/"+scopedPath(namespace, name), nil, &rec); err != nil {
+		return nil, err
+	}
+	return &rec, nil
+}
+
+// MR returns an MR of the given "group/version/resource". namespace is
+// empty for cluster-scoped MRs.
+func (c *Client) MR(ctx context.Context, gvr, namespace, name string) (*MRRecord, error) {
+	var rec MRRecord
+	if err := c.get(ctx, "/mrs/"+url.PathEscape(gvr)+"/"+scopedPath(namespace, name), nil, &rec); err != nil {
+		return nil, err
+	}
+	return &rec, nil
+}
+
+func scopedPath(namespace, name string) string {
+	if namespace == "" {
+		return url.PathEscape(name)
+	}
+	return url.PathEscape(namespace) + "/" + url.PathEscape(name)
+}
+
+// listResponse is the JSON body of a typed /bookkeeping request.
+type listResponse struct {
+	Claims     []Claim `json:"claims"`
+	XRs        []XR    `json:"xrs"`
+	MRs        []MR    `json:"mrs"`
+	Next       string  `json:"next"`
+	Generation uint64  `json:"generation"`
+}
+
+func list[T any](ctx context.Context, c *Client, typ string, opts ListOptions, items func(*listResponse) []T) (*Page[T], error) {
+	v := opts.values()
+	v.Set("type", typ)
+	if opts.Limit > 0 {
+		v.Set("limit", strconv.Itoa(opts.Limit))
+	}
+	if opts.Cursor != "" {
+		v.Set("cursor", opts.Cursor)
+	}
+	var resp listResponse
+	if err := c.get(ctx, "/bookkeeping", v, &resp); err != nil {
+		return nil, err
+	}
+	return &Page[T]{Items: items(&resp), Next: resp.Next, Generation: resp.Generation}, nil
+}
+
+func all[T any](ctx context.Context, opts ListOptions, page func(context.Context, ListOptions) (*Page[T], error)) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for {
+			p, err := page(ctx, opts)
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+			for _, item := range p.Items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+			if p.Next == "" {
+				return
+			}
+			opts.Cursor = p.Next
+		}
+	}
+}
+
+// get sends a GET request for the escaped path with query v and decodes the
+// JSON response into out.
+func (c *Client) get(ctx context.Context, path string, v url.Values, out any) error {
+	u := *c.baseURL
+	u.RawPath = c.baseURL.EscapedPath() + path // keeps %2F in GVR segments
+	unescaped, err := url.PathUnescape(u.RawPath)
+	if err != nil {
+		return err
+	}
+	u.Path = unescaped
+	u.RawQuery = v.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+		return &Error{StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(body))}
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decode %s response: %w", path, err)
+	}
+	return nil
+}
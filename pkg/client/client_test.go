@@ -0,0 +1,202 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kanzifucius/xp-tracker/pkg/server"
+	"github.com/kanzifucius/xp-tracker/pkg/store"
+)
+
+const bucketGVR = "s3.aws.upbound.io/v1beta1/buckets"
+
+// startServer runs the real API server over a small inventory and returns a
+// client for it.
+func startServer(t *testing.T) *Client {
+	t.Helper()
+	s := store.New()
+	s.ReplaceClaims("g/v1/things", []store.ClaimInfo{
+		{GVR: "g/v1/things", Group: "g", Version: "v1", Kind: "Thing", Namespace: "team-a", Name: "db-1", Team: "backend", XRRef: "xr-1", Ready: true},
+		{GVR: "g/v1/things", Group: "g", Version: "v1", Kind: "Thing", Namespace: "team-a", Name: "db-2", Team: "backend"},
+		{GVR: "g/v1/things", Group: "g", Version: "v1", Kind: "Thing", Namespace: "team-b", Name: "db-3", Team: "frontend", Ready: true},
+	})
+	s.ReplaceXRs("g/v1/xthings", []store.XRInfo{
+		{GVR: "g/v1/xthings", Group: "g", Version: "v1", Kind: "XThing", Name: "xr-1", ClaimName: "db-1", ClaimNS: "team-a"},
+	})
+	s.ReplaceMRs(bucketGVR, []store.MRInfo{
+		{GVR: bucketGVR, Group: "s3.aws.upbound.io", Version: "v1beta1", Kind: "Bucket", Name: "bucket-1", XRName: "xr-1", ClaimName: "db-1", ClaimNS: "team-a"},
+	})
+
+	srv := server.New("127.0.0.1:0", s)
+	srv.SetReady()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go func() {
+		_ = srv.Run(ctx)
+	}()
+
+	c, err := New("http://" + srv.Addr())
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return c
+}
+
+func TestClient_Inventory(t *testing.T) {
+	c := startServer(t)
+
+	inv, err := c.Inventory(context.Background(), ListOptions{Team: "backend"})
+	if err != nil {
+		t.Fatalf("Inventory: %v", err)
+	}
+	if len(inv.Claims) != 2 || len(inv.XRs) != 1 || len(inv.MRs) != 1 {
+		t.Errorf("expected 2 claims, 1 XR and 1 MR, got %d, %d and %d", len(inv.Claims), len(inv.XRs), len(inv.MRs))
+	}
+	if inv.Generation == 0 || inv.GeneratedAt.IsZero() {
+		t.Errorf("expected generation metadata, got %d at %v", inv.Generation, inv.GeneratedAt)
+	}
+
+	ready, err := c.Inventory(context.Background(), ListOptions{Ready: Bool(false)})
+	if err != nil {
+		t.Fatalf("Inventory: %v", err)
+	}
+	if len(ready.Claims) != 1 || ready.Claims[0].Name != "db-2" {
+		t.Errorf("expected only db-2 to be not ready, got %+v", ready.Claims)
+	}
+}
+
+func TestClient_Pagination(t *testing.T) {
+	c := startServer(t)
+	ctx := context.Background()
+
+	page, err := c.ListClaims(ctx, ListOptions{Limit: 2, SortBy: SortByName, Descending: true})
+	if err != nil {
+		t.Fatalf("ListClaims: %v", err)
+	}
+	if len(page.Items) != 2 || page.Items[0].Name != "db-3" || page.Next == "" {
+		t.Fatalf("unexpected first page: %+v", page)
+	}
+
+	var names []string
+	for claim, err := range c.Claims(ctx, ListOptions{Limit: 1}) {
+		if err != nil {
+			t.Fatalf("Claims: %v", err)
+		}
+		names = append(names, claim.Name)
+	}
+	if len(names) != 3 {
+		t.Errorf("expected to iterate over 3 claims, got %v", names)
+	}
+
+	var iterErr error
+	for _, err := range c.MRs(ctx, ListOptions{Limit: 1, Cursor: "garbage"}) {
+		iterErr = err
+	}
+	var apiErr *Error
+	if !errors.As(iterErr, &apiErr) || apiErr.StatusCode != http.StatusBadRequest || apiErr.Message == "" {
+		t.Errorf("expected a 400 Error with a message for an invalid cursor, got %v", iterErr)
+	}
+}
+
+func TestClient_Lookups(t *testing.T) {
+	c := startServer(t)
+	ctx := context.Background()
+
+	claim, err := c.Claim(ctx, "team-a", "db-1")
+	if err != nil {
+		t.Fatalf("Claim: %v", err)
+	}
+	if claim.Links.XR != "/xrs/xr-1" || len(claim.Links.MRs) != 1 {
+		t.Errorf("unexpected claim links: %+v", claim.Links)
+	}
+
+	xr, err := c.XR(ctx, "", "xr-1")
+	if err != nil {
+		t.Fatalf("XR: %v", err)
+	}
+	if xr.ClaimNamespace != "team-a" || xr.Links.Claim != "/claims/team-a/db-1" {
+		t.Errorf("unexpected XR: %+v", xr)
+	}
+
+	mr, err := c.MR(ctx, bucketGVR, "", "bucket-1")
+	if err != nil {
+		t.Fatalf("MR: %v", err)
+	}
+	if mr.GVR != bucketGVR || mr.Links.XR != "/xrs/xr-1" {
+		t.Errorf("unexpected MR: %+v", mr)
+	}
+
+	if _, err := c.Claim(ctx, "team-a", "missing"); !IsNotFound(err) {
+		t.Errorf("expected not found, got %v", err)
+	}
+}
+
+func TestClient_BearerToken(t *testing.T) {
+	var got string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get("Authorization")
+		http.Error(w, "forbidden", http.StatusForbidden)
+	}))
+	defer ts.Close()
+
+	c, err := New(ts.URL+"/", WithBearerToken("secret"), WithHTTPClient(ts.Client()))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	_, err = c.Inventory(context.Background(), ListOptions{})
+	if got != "Bearer secret" {
+		t.Errorf("expected bearer token to be sent, got %q", got)
+	}
+	var apiErr *Error
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusForbidden || apiErr.Message != "forbidden" {
+		t.Errorf("expected a 403 Error, got %v", err)
+	}
+}
+
+func TestNew_InvalidBaseURL(t *testing.T) {
+	for _, u := range []string{"", "localhost:8080", "ftp://example.com", "http://[::1"} {
+		if _, err := New(u); err == nil {
+			t.Errorf("expected error for base URL %q", u)
+		}
+	}
+}
+
+// TestTypes_MatchServer decodes real server responses without tolerating
+// unknown fields, so that fields added to the API are added here too.
+func TestTypes_MatchServer(t *testing.T) {
+	c := startServer(t)
+	base := c.baseURL.String()
+
+	strict := func(path string, out any) {
+		t.Helper()
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, base+path, nil)
+		if err != nil {
+			t.Fatalf("failed to create request: %v", err)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("GET %s: %v", path, err)
+		}
+		defer func() { _ = resp.Body.Close() }()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("read %s: %v", path, err)
+		}
+		dec := json.NewDecoder(bytes.NewReader(body))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(out); err != nil {
+			t.Errorf("decode %s: %v", path, err)
+		}
+	}
+
+	strict("/bookkeeping?includeDeleted=true", &Inventory{})
+	strict("/claims/team-a/db-1", &ClaimRecord{})
+	strict("/xrs/xr-1", &XRRecord{})
+	strict("/mrs/s3.aws.upbound.io%2Fv1beta1%2Fbuckets/bucket-1", &MRRecord{})
+}
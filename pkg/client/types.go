@@ -0,0 +1,173 @@
+package client
+
+import "time"
+
+// Claim is a claim as listed by /bookkeeping.
+type Claim struct {
+	Group       string `json:"group"`
+	Version     string `json:"version"`
+	Kind        string `json:"kind"`
+	Namespace   string `json:"namespace"`
+	Name        string `json:"name"`
+	Creator     string `json:"creator"`
+	Team        string `json:"team"`
+	Composition string `json:"composition"`
+	Paused      bool   `json:"paused"`
+	Deleting    bool   `json:"deleting"`
+	Ready       bool   `json:"ready"`
+	Reason      string `json:"reason"`
+	AgeSeconds  int64  `json:"ageSeconds"`
+}
+
+// XR is a composite resource as listed by /bookkeeping.
+type XR struct {
+	Group       string `json:"group"`
+	Version     string `json:"version"`
+	Kind        string `json:"kind"`
+	Namespace   string `json:"namespace"` // empty for cluster-scoped XRs
+	Name        string `json:"name"`
+	Composition string `json:"composition"`
+	Paused      bool   `json:"paused"`
+	Deleting    bool   `json:"deleting"`
+	Ready       bool   `json:"ready"`
+	Reason      string `json:"reason"`
+	AgeSeconds  int64  `json:"ageSeconds"`
+}
+
+// MR is a managed resource as listed by /bookkeeping.
+type MR struct {
+	Group              string `json:"group"`
+	Version            string `json:"version"`
+	Kind               string `json:"kind"`
+	Namespace          string `json:"namespace"` // empty for cluster-scoped MRs
+	Name               string `json:"name"`
+	XRName             string `json:"xrName"`
+	ClaimName          string `json:"claimName"`
+	ClaimNamespace     string `json:"claimNamespace"`
+	Provider           string `json:"provider"`
+	ProviderConfig     string `json:"providerConfig"`
+	ExternalName       string `json:"externalName"`
+	ManagementPolicies string `json:"managementPolicies"`
+	Paused             bool   `json:"paused"`
+	Deleting           bool   `json:"deleting"`
+	Ready              bool   `json:"ready"`
+	Reason             string `json:"reason"`
+	AgeSeconds         int64  `json:"ageSeconds"`
+}
+
+// DeletedClaim is a claim tombstone. AgeSeconds is the claim's age when it
+// was removed.
+type DeletedClaim struct {
+	Claim
+	RemovedAt time.Time `json:"removedAt"`
+}
+
+// DeletedXR is an XR tombstone.
+type DeletedXR struct {
+	XR
+	RemovedAt time.Time `json:"removedAt"`
+}
+
+// DeletedMR is an MR tombstone.
+type DeletedMR struct {
+	MR
+	RemovedAt time.Time `json:"removedAt"`
+}
+
+// Inventory is the response of an unpaginated /bookkeeping request. All
+// items come from the store generation identified by Generation.
+type Inventory struct {
+	Claims                []Claim        `json:"claims"`
+	XRs                   []XR           `json:"xrs"`
+	MRs                   []MR           `json:"mrs"`
+	DeletedClaims         []DeletedClaim `json:"deletedClaims,omitempty"`
+	DeletedXRs            []DeletedXR    `json:"deletedXrs,omitempty"`
+	DeletedMRs            []DeletedMR    `json:"deletedMrs,omitempty"`
+	Generation            uint64         `json:"generation"`
+	GenerationCommittedAt time.Time      `json:"generationCommittedAt"` // zero before the first poll
+	GeneratedAt           time.Time      `json:"generatedAt"`
+}
+
+// Page is one page of a paginated listing. Next is empty on the last page.
+type Page[T any] struct {
+	Items      []T
+	Next       string
+	Generation uint64
+}
+
+// Links points from a single resource to itself and to the related
+// resources present in the same store generation, as API paths.
+type Links struct {
+	Self  string   `json:"self"`
+	Claim string   `json:"claim,omitempty"`
+	XR    string   `json:"xr,omitempty"`
+	MRs   []string `json:"mrs,omitempty"`
+}
+
+// ClaimRecord is the full stored record of a claim.
+type ClaimRecord struct {
+	GVR         string    `json:"gvr"`
+	Group       string    `json:"group"`
+	Version     string    `json:"version"`
+	Kind        string    `json:"kind"`
+	Namespace   string    `json:"namespace"`
+	Name        string    `json:"name"`
+	Creator     string    `json:"creator"`
+	Team        string    `json:"team"`
+	Composition string    `json:"composition"`
+	Paused      bool      `json:"paused"`
+	Synced      bool      `json:"synced"`
+	Ready       bool      `json:"ready"`
+	Reason      string    `json:"reason"`
+	CreatedAt   time.Time `json:"createdAt"`
+	DeletedAt   time.Time `json:"deletedAt"` // zero when not deleting
+	XRRef       string    `json:"xrRef"`
+	Links       Links     `json:"links"`
+	Generation  uint64    `json:"generation"`
+}
+
+// XRRecord is the full stored record of a composite resource.
+type XRRecord struct {
+	GVR            string    `json:"gvr"`
+	Group          string    `json:"group"`
+	Version        string    `json:"version"`
+	Kind           string    `json:"kind"`
+	Namespace      string    `json:"namespace"`
+	Name           string    `json:"name"`
+	ClaimName      string    `json:"claimName"`
+	ClaimNamespace string    `json:"claimNamespace"`
+	Composition    string    `json:"composition"`
+	Paused         bool      `json:"paused"`
+	Synced         bool      `json:"synced"`
+	Ready          bool      `json:"ready"`
+	Reason         string    `json:"reason"`
+	CreatedAt      time.Time `json:"createdAt"`
+	DeletedAt      time.Time `json:"deletedAt"`
+	Links          Links     `json:"links"`
+	Generation     uint64    `json:"generation"`
+}
+
+// MRRecord is the full stored record of a managed resource.
+type MRRecord struct {
+	GVR                string    `json:"gvr"`
+	Group              string    `json:"group"`
+	Version            string    `json:"version"`
+	Kind               string    `json:"kind"`
+	Namespace          string    `json:"namespace"`
+	Name               string    `json:"name"`
+	XRName             string    `json:"xrName"`
+	ClaimName          string    `json:"claimName"`
+	ClaimNamespace     string    `json:"claimNamespace"`
+	Provider           string    `json:"provider"`
+	ProviderConfig     string    `json:"providerConfig"`
+	ExternalName       string    `json:"externalName"`
+	ManagementPolicies string    `json:"managementPolicies"`
+	Paused             bool      `json:"paused"`
+	Synced             bool      `json:"synced"`
+	Ready              bool      `json:"ready"`
+	Reason             string    `json:"reason"`
+	CreatedAt          time.Time `json:"createdAt"`
+	DeletedAt          time.Time `json:"deletedAt"`
+	Links              Links     `json:"links"`
+	Generation         uint64    `json:"generation"`
+}
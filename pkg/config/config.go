@@ -1,4 +1,5 @@
-// Package config loads and validates exporter configuration from environment variables.
+// Package config loads and validates exporter configuration from an optional
+// YAML file and environment variables.
 package config
 
 import (
@@ -7,8 +8,12 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
 )
 
 // Config holds all runtime configuration for the exporter.
@@ -25,9 +30,27 @@ type Config struct {
 	// MRProviderNames maps GVR key (group/version/resource) to provider package name.
 	MRProviderNames map[string]string
 
+	// GVRInclude and GVRExclude are the patterns of GVRFilter, which selects
+	// the discovered and configured GVRs to poll.
+	GVRInclude []string
+	GVRExclude []string
+	GVRFilter  GVRFilter
+
+	// ClaimSelector, XRSelector and MRSelector restrict the claims, XRs and
+	// MRs listed, for every GVR of the class. Per-GVR selectors in Resources
+	// are combined with them.
+	ClaimSelector Selector
+	XRSelector    Selector
+	MRSelector    Selector
+
 	// Namespaces restricts watches to these namespaces. Empty means all.
 	Namespaces []string
 
+	// NamespaceSelector, when set, selects the namespaces to watch by their
+	// labels. It is resolved every poll cycle and narrows Namespaces if both
+	// are set.
+	NamespaceSelector string
+
 	// CreatorAnnotationKey is the annotation key used to identify the claim creator.
 	CreatorAnnotationKey string
 
@@ -43,6 +66,35 @@ type Config struct {
 	// PollIntervalSeconds is the number of seconds between polling cycles.
 	PollIntervalSeconds int
 
+	// ClaimPollIntervalSeconds, XRPollIntervalSeconds and
+	// MRPollIntervalSeconds poll each resource class at its own interval.
+	// Zero uses PollIntervalSeconds. Per-GVR overrides take precedence.
+	ClaimPollIntervalSeconds int
+	XRPollIntervalSeconds    int
+	MRPollIntervalSeconds    int
+
+	// AdaptivePollFactor enables adaptive polling when at least 2: a GVR's
+	// interval halves after a poll that found changes and grows by half
+	// after one that did not, staying between its configured interval
+	// divided and multiplied by the factor. Zero disables it.
+	AdaptivePollFactor int
+
+	// PollJitter moves each GVR's next poll randomly by up to this fraction
+	// of its interval, to spread list calls over time. Between 0 and 0.5;
+	// zero disables jitter.
+	PollJitter float64
+
+	// TableListing lists the resource classes ("claims", "xrs", "mrs")
+	// whose objects are listed as server-side Tables holding only their
+	// metadata and printer columns, instead of full objects. Fields outside
+	// those, such as condition reasons, are then left empty.
+	TableListing []string
+
+	// ReadinessStalePolls makes /readyz report degraded once the last poll
+	// cycle without errors is older than this many poll intervals. Zero
+	// disables the check.
+	ReadinessStalePolls int
+
 	// MetricsAddr is the listen address for the HTTP metrics server.
 	MetricsAddr string
 
@@ -61,58 +113,239 @@ type Config struct {
 
 	// S3Endpoint is an optional custom S3 endpoint URL (for MinIO, LocalStack, etc.).
 	S3Endpoint string
+
+	// EncryptionKeyPath is a key file or directory of key files (e.g. a mounted
+	// Secret) used to encrypt persisted snapshots. Empty disables encryption.
+	EncryptionKeyPath string
+
+	// EncryptionKeyID selects the key used to encrypt new snapshots. Optional
+	// when EncryptionKeyPath holds a single key.
+	EncryptionKeyID string
+
+	// TombstoneRetention is how long removed claims, XRs and MRs are kept as
+	// tombstones. Zero disables tombstones. Default: 24h.
+	TombstoneRetention time.Duration
+
+	// EventBufferSize is how many changes the /events/stream change feed
+	// keeps for resuming clients. Zero disables the feed. Default: 10000.
+	EventBufferSize int
+
+	// AuthPaths lists the HTTP endpoints that require a bearer token checked
+	// with a TokenReview and SubjectAccessReview. Each entry is an exact path
+	// or a subtree ending in "/". Empty disables authentication.
+	AuthPaths []string
+
+	// AuthCacheTTL is how long token and access review results are cached.
+	// Zero disables caching. Default: 1m.
+	AuthCacheTTL time.Duration
+
+	// TenantScope restricts the inventory API per caller: "namespace"
+	// shows namespaces the caller may list TenantNamespaceResource in,
+	// "team" shows objects whose team is one of the caller's groups. Both
+	// may be given. Empty disables tenant scoping. Requires AuthPaths.
+	TenantScope []string
+
+	// TenantNamespaceResource is the resource, as "resource" or
+	// "resource.group", that callers must be allowed to list in a namespace
+	// to see its objects. Default: "pods".
+	TenantNamespaceResource string
+
+	// TenantTeamGroupPrefix selects the caller's groups that name teams; the
+	// prefix is stripped to get the team. Empty uses every group.
+	TenantTeamGroupPrefix string
+
+	// TLSCertFile and TLSKeyFile enable HTTPS on MetricsAddr. Both or
+	// neither must be set. The files are reloaded when they change.
+	TLSCertFile string
+	TLSKeyFile  string
+
+	// TLSClientCAFile is a CA bundle that client certificates are verified
+	// against. Empty disables client certificate verification.
+	TLSClientCAFile string
+
+	// TLSClientAuth is "require" (default) to reject clients without a valid
+	// certificate, or "optional" to verify only certificates that are sent.
+	TLSClientAuth string
+
+	// HealthAddr is an optional separate plain HTTP listen address for
+	// /healthz and /readyz. Empty serves them on MetricsAddr only.
+	HealthAddr string
+
+	// GRPCAddr is an optional listen address for the gRPC inventory API.
+	// Empty disables it. It shares the TLS and auth settings of MetricsAddr.
+	GRPCAddr string
+
+	// ShardNamespace enables sharded polling: replicas hold Leases in this
+	// namespace and each polls a consistent-hash share of the GVRs, merging
+	// the other shards from the store backend. Empty disables sharding.
+	// Requires StoreBackend "s3".
+	ShardNamespace string
+
+	// ShardIdentity names this replica among the shards, and its shard
+	// object in the store backend. It must stay the same across restarts,
+	// like a StatefulSet pod name, for the replica to restore its shard.
+	// Required with ShardNamespace.
+	ShardIdentity string
+
+	// ShardLeaseDuration is how long a replica's Lease is valid without
+	// renewal; a replica whose Lease expires loses its GVRs to the others.
+	// Default: 30s.
+	ShardLeaseDuration time.Duration
+
+	// File is the YAML config file the settings were read from, or empty
+	// when CONFIG_FILE is not set.
+	File string
+
+	// Resources holds the per-GVR overrides from the config file, keyed by
+	// GVR string (group/version/resource). See ForGVR.
+	Resources map[string]ResourceConfig
+}
+
+// Selector is a Kubernetes label selector and field selector, in the
+// string syntax of the list API. Empty selectors match everything.
+type Selector struct {
+	Label string
+	Field string
 }
 
 const (
 	defaultCompositionLabelKey = "crossplane.io/composition-name"
 	defaultCompositeLabelKey   = "crossplane.io/composite"
 	defaultPollInterval        = 30
+	defaultReadinessStalePolls = 5
+	maxPollJitter              = 0.5
 	defaultMetricsAddr         = ":8080"
 	defaultStoreBackend        = "memory"
 	defaultS3KeyPrefix         = "xp-tracker"
 	defaultS3Region            = "us-east-1"
+	defaultTombstoneRetention  = 24 * time.Hour
+	defaultEventBufferSize     = 10000
+	defaultAuthCacheTTL        = time.Minute
+	defaultTenantNSResource    = "pods"
+	defaultTLSClientAuth       = "require"
+	defaultShardLeaseDuration  = 30 * time.Second
 )
 
-// Load reads configuration from environment variables and returns a validated Config.
+// Load reads configuration from the optional file named by CONFIG_FILE and
+// from environment variables, which take precedence over the file, and
+// returns a validated Config. Every problem found is reported at once in a
+// *ValidationError.
 func Load() (*Config, error) {
 	cfg := &Config{
-		CompositionLabelKey: defaultCompositionLabelKey,
-		CompositeLabelKey:   defaultCompositeLabelKey,
-		PollIntervalSeconds: defaultPollInterval,
-		MetricsAddr:         defaultMetricsAddr,
-		MRProviderNames:     make(map[string]string),
+		CompositionLabelKey:     defaultCompositionLabelKey,
+		CompositeLabelKey:       defaultCompositeLabelKey,
+		PollIntervalSeconds:     defaultPollInterval,
+		ReadinessStalePolls:     defaultReadinessStalePolls,
+		MetricsAddr:             defaultMetricsAddr,
+		StoreBackend:            defaultStoreBackend,
+		S3KeyPrefix:             defaultS3KeyPrefix,
+		S3Region:                defaultS3Region,
+		MRProviderNames:         make(map[string]string),
+		TombstoneRetention:      defaultTombstoneRetention,
+		EventBufferSize:         defaultEventBufferSize,
+		AuthCacheTTL:            defaultAuthCacheTTL,
+		TenantNamespaceResource: defaultTenantNSResource,
+		ShardLeaseDuration:      defaultShardLeaseDuration,
 	}
+	var p problems
 
+	// Optional: CONFIG_FILE
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		cfg.File = path
+		f, err := readFile(path)
+		if err != nil {
+			p.add(err.Error())
+		} else {
+			f.apply(cfg, &p)
+		}
+	}
+
+	loadEnv(cfg, &p)
+	validate(cfg, &p)
+
+	if err := p.err(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// loadEnv overrides cfg with the environment variables that are set.
+func loadEnv(cfg *Config, p *problems) {
 	// Optional: CLAIM_GVRS (deprecated in favour of XRD discovery)
-	claimRaw := os.Getenv("CLAIM_GVRS")
-	if claimRaw != "" {
-		claimGVRs, err := ParseGVRs(claimRaw)
+	if v := os.Getenv("CLAIM_GVRS"); v != "" {
+		claimGVRs, err := ParseGVRs(v)
 		if err != nil {
-			return nil, fmt.Errorf("invalid CLAIM_GVRS: %w", err)
+			p.addf("invalid CLAIM_GVRS: %v", err)
 		}
 		cfg.ClaimGVRs = claimGVRs
 	}
 
 	// Optional: XR_GVRS (deprecated in favour of XRD discovery)
-	xrRaw := os.Getenv("XR_GVRS")
-	if xrRaw != "" {
-		xrGVRs, err := ParseGVRs(xrRaw)
+	if v := os.Getenv("XR_GVRS"); v != "" {
+		xrGVRs, err := ParseGVRs(v)
 		if err != nil {
-			return nil, fmt.Errorf("invalid XR_GVRS: %w", err)
+			p.addf("invalid XR_GVRS: %v", err)
 		}
 		cfg.XRGVRs = xrGVRs
 	}
 
+	// Optional: GVR_INCLUDE, GVR_EXCLUDE
+	if v := os.Getenv("GVR_INCLUDE"); v != "" {
+		cfg.GVRInclude = splitAndTrim(v)
+		if _, err := ParseGVRFilter(cfg.GVRInclude, nil); err != nil {
+			p.addf("GVR_INCLUDE: %v", err)
+		}
+	}
+	if v := os.Getenv("GVR_EXCLUDE"); v != "" {
+		cfg.GVRExclude = splitAndTrim(v)
+		if _, err := ParseGVRFilter(nil, cfg.GVRExclude); err != nil {
+			p.addf("GVR_EXCLUDE: %v", err)
+		}
+	}
+
+	// Optional: {CLAIM,XR,MR}_{LABEL,FIELD}_SELECTOR
+	for _, s := range []struct {
+		env string
+		dst *string
+		fn  func(string) error
+	}{
+		{"CLAIM_LABEL_SELECTOR", &cfg.ClaimSelector.Label, validateLabelSelector},
+		{"CLAIM_FIELD_SELECTOR", &cfg.ClaimSelector.Field, validateFieldSelector},
+		{"XR_LABEL_SELECTOR", &cfg.XRSelector.Label, validateLabelSelector},
+		{"XR_FIELD_SELECTOR", &cfg.XRSelector.Field, validateFieldSelector},
+		{"MR_LABEL_SELECTOR", &cfg.MRSelector.Label, validateLabelSelector},
+		{"MR_FIELD_SELECTOR", &cfg.MRSelector.Field, validateFieldSelector},
+	} {
+		if v := os.Getenv(s.env); v != "" {
+			if err := s.fn(v); err != nil {
+				p.addf("%s: %v", s.env, err)
+			}
+			*s.dst = v
+		}
+	}
+
 	// Optional: KUBE_NAMESPACE_SCOPE
 	if ns := os.Getenv("KUBE_NAMESPACE_SCOPE"); ns != "" {
 		cfg.Namespaces = splitAndTrim(ns)
 	}
 
+	// Optional: NAMESPACE_SELECTOR
+	if v := os.Getenv("NAMESPACE_SELECTOR"); v != "" {
+		if err := validateLabelSelector(v); err != nil {
+			p.addf("NAMESPACE_SELECTOR: %v", err)
+		}
+		cfg.NamespaceSelector = v
+	}
+
 	// Optional: CREATOR_ANNOTATION_KEY
-	cfg.CreatorAnnotationKey = os.Getenv("CREATOR_ANNOTATION_KEY")
+	if v := os.Getenv("CREATOR_ANNOTATION_KEY"); v != "" {
+		cfg.CreatorAnnotationKey = v
+	}
 
 	// Optional: TEAM_ANNOTATION_KEY
-	cfg.TeamAnnotationKey = os.Getenv("TEAM_ANNOTATION_KEY")
+	if v := os.Getenv("TEAM_ANNOTATION_KEY"); v != "" {
+		cfg.TeamAnnotationKey = v
+	}
 
 	// Optional: COMPOSITION_LABEL_KEY
 	if v := os.Getenv("COMPOSITION_LABEL_KEY"); v != "" {
@@ -125,10 +358,10 @@ func Load() (*Config, error) {
 	}
 
 	// Optional: MR_GVRS (merged with MRD discovery at startup)
-	if mrRaw := os.Getenv("MR_GVRS"); mrRaw != "" {
-		mrGVRs, err := ParseGVRs(mrRaw)
+	if v := os.Getenv("MR_GVRS"); v != "" {
+		mrGVRs, err := ParseGVRs(v)
 		if err != nil {
-			return nil, fmt.Errorf("invalid MR_GVRS: %w", err)
+			p.addf("invalid MR_GVRS: %v", err)
 		}
 		cfg.MRGVRs = mrGVRs
 	}
@@ -137,9 +370,64 @@ func Load() (*Config, error) {
 	if v := os.Getenv("POLL_INTERVAL_SECONDS"); v != "" {
 		n, err := strconv.Atoi(v)
 		if err != nil || n < 1 {
-			return nil, fmt.Errorf("POLL_INTERVAL_SECONDS must be a positive integer, got %q", v)
+			p.addf("POLL_INTERVAL_SECONDS must be a positive integer, got %q", v)
+		} else {
+			cfg.PollIntervalSeconds = n
+		}
+	}
+
+	// Optional: per-class poll intervals.
+	for _, s := range []struct {
+		key string
+		dst *int
+	}{
+		{"CLAIM_POLL_INTERVAL_SECONDS", &cfg.ClaimPollIntervalSeconds},
+		{"XR_POLL_INTERVAL_SECONDS", &cfg.XRPollIntervalSeconds},
+		{"MR_POLL_INTERVAL_SECONDS", &cfg.MRPollIntervalSeconds},
+	} {
+		if v := os.Getenv(s.key); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 1 {
+				p.addf("%s must be a positive integer, got %q", s.key, v)
+			} else {
+				*s.dst = n
+			}
+		}
+	}
+
+	// Optional: ADAPTIVE_POLL_FACTOR
+	if v := os.Getenv("ADAPTIVE_POLL_FACTOR"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			p.addf("ADAPTIVE_POLL_FACTOR must be a non-negative integer, got %q", v)
+		} else {
+			cfg.AdaptivePollFactor = n
+		}
+	}
+
+	// Optional: POLL_JITTER
+	if v := os.Getenv("POLL_JITTER"); v != "" {
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil || f < 0 || f > maxPollJitter {
+			p.addf("POLL_JITTER must be a number between 0 and %g, got %q", maxPollJitter, v)
+		} else {
+			cfg.PollJitter = f
+		}
+	}
+
+	// Optional: TABLE_LISTING
+	if v := os.Getenv("TABLE_LISTING"); v != "" {
+		cfg.TableListing = splitAndTrim(v)
+	}
+
+	// Optional: READINESS_STALE_POLLS
+	if v := os.Getenv("READINESS_STALE_POLLS"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			p.addf("READINESS_STALE_POLLS must be a non-negative integer, got %q", v)
+		} else {
+			cfg.ReadinessStalePolls = n
 		}
-		cfg.PollIntervalSeconds = n
 	}
 
 	// Optional: METRICS_ADDR
@@ -148,43 +436,234 @@ func Load() (*Config, error) {
 	}
 
 	// Optional: STORE_BACKEND
-	cfg.StoreBackend = defaultStoreBackend
 	if v := os.Getenv("STORE_BACKEND"); v != "" {
 		cfg.StoreBackend = v
 	}
-	switch cfg.StoreBackend {
-	case "memory", "s3":
-		// valid
-	default:
-		return nil, fmt.Errorf("STORE_BACKEND must be \"memory\" or \"s3\", got %q", cfg.StoreBackend)
-	}
 
 	// S3 configuration (required when STORE_BACKEND=s3, ignored otherwise).
-	cfg.S3Bucket = os.Getenv("S3_BUCKET")
-	cfg.S3KeyPrefix = defaultS3KeyPrefix
+	if v := os.Getenv("S3_BUCKET"); v != "" {
+		cfg.S3Bucket = v
+	}
 	if v := os.Getenv("S3_KEY_PREFIX"); v != "" {
 		cfg.S3KeyPrefix = v
 	}
-	cfg.S3Region = defaultS3Region
 	if v := os.Getenv("S3_REGION"); v != "" {
 		cfg.S3Region = v
 	}
-	cfg.S3Endpoint = os.Getenv("S3_ENDPOINT")
+	if v := os.Getenv("S3_ENDPOINT"); v != "" {
+		cfg.S3Endpoint = v
+	}
+
+	// Optional: snapshot encryption (only meaningful with a persistent backend).
+	if v := os.Getenv("SNAPSHOT_ENCRYPTION_KEY_PATH"); v != "" {
+		cfg.EncryptionKeyPath = v
+	}
+	if v := os.Getenv("SNAPSHOT_ENCRYPTION_KEY_ID"); v != "" {
+		cfg.EncryptionKeyID = v
+	}
+
+	// Optional: TOMBSTONE_RETENTION
+	if v := os.Getenv("TOMBSTONE_RETENTION"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d < 0 {
+			p.addf("TOMBSTONE_RETENTION must be a non-negative duration (e.g. \"24h\"), got %q", v)
+		} else {
+			cfg.TombstoneRetention = d
+		}
+	}
+
+	// Optional: EVENT_BUFFER_SIZE
+	if v := os.Getenv("EVENT_BUFFER_SIZE"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			p.addf("EVENT_BUFFER_SIZE must be a non-negative integer, got %q", v)
+		} else {
+			cfg.EventBufferSize = n
+		}
+	}
+
+	// Optional: AUTH_PATHS
+	if v := os.Getenv("AUTH_PATHS"); v != "" {
+		cfg.AuthPaths = splitAndTrim(v)
+	}
+
+	// Optional: AUTH_CACHE_TTL
+	if v := os.Getenv("AUTH_CACHE_TTL"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d < 0 {
+			p.addf("AUTH_CACHE_TTL must be a non-negative duration (e.g. \"1m\"), got %q", v)
+		} else {
+			cfg.AuthCacheTTL = d
+		}
+	}
 
+	// Optional: TENANT_SCOPE
+	if v := os.Getenv("TENANT_SCOPE"); v != "" {
+		cfg.TenantScope = splitAndTrim(v)
+	}
+	if v := os.Getenv("TENANT_NAMESPACE_RESOURCE"); v != "" {
+		cfg.TenantNamespaceResource = v
+	}
+	if v := os.Getenv("TENANT_TEAM_GROUP_PREFIX"); v != "" {
+		cfg.TenantTeamGroupPrefix = v
+	}
+
+	// Optional: TLS
+	if v := os.Getenv("TLS_CERT_FILE"); v != "" {
+		cfg.TLSCertFile = v
+	}
+	if v := os.Getenv("TLS_KEY_FILE"); v != "" {
+		cfg.TLSKeyFile = v
+	}
+	if v := os.Getenv("TLS_CLIENT_CA_FILE"); v != "" {
+		cfg.TLSClientCAFile = v
+	}
+	if v := os.Getenv("TLS_CLIENT_AUTH"); v != "" {
+		cfg.TLSClientAuth = v
+	}
+
+	// Optional: HEALTH_ADDR
+	if v := os.Getenv("HEALTH_ADDR"); v != "" {
+		cfg.HealthAddr = v
+	}
+
+	// Optional: GRPC_ADDR
+	if v := os.Getenv("GRPC_ADDR"); v != "" {
+		cfg.GRPCAddr = v
+	}
+
+	// Optional: sharded polling
+	if v := os.Getenv("SHARD_NAMESPACE"); v != "" {
+		cfg.ShardNamespace = v
+	}
+	if v := os.Getenv("SHARD_IDENTITY"); v != "" {
+		cfg.ShardIdentity = v
+	}
+	if v := os.Getenv("SHARD_LEASE_DURATION"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d < 0 {
+			p.addf("SHARD_LEASE_DURATION must be a non-negative duration (e.g. \"30s\"), got %q", v)
+		} else {
+			cfg.ShardLeaseDuration = d
+		}
+	}
+}
+
+// validate checks the settings that depend on each other, once the file and
+// the environment have been merged. Problems name the environment variable;
+// the config file keys are documented alongside them.
+func validate(cfg *Config, p *problems) {
+	// Invalid patterns were already reported by the loaders.
+	cfg.GVRFilter, _ = ParseGVRFilter(cfg.GVRInclude, cfg.GVRExclude)
+
+	switch cfg.StoreBackend {
+	case "memory", "s3":
+		// valid
+	default:
+		p.addf("STORE_BACKEND must be \"memory\" or \"s3\", got %q", cfg.StoreBackend)
+	}
 	if cfg.StoreBackend == "s3" && cfg.S3Bucket == "" {
-		return nil, fmt.Errorf("S3_BUCKET is required when STORE_BACKEND=s3")
+		p.add("S3_BUCKET is required when STORE_BACKEND=s3")
 	}
 
 	// Validate S3 key prefix.
 	if strings.Contains(cfg.S3KeyPrefix, "..") {
-		return nil, fmt.Errorf("S3_KEY_PREFIX must not contain '..', got %q", cfg.S3KeyPrefix)
+		p.addf("S3_KEY_PREFIX must not contain '..', got %q", cfg.S3KeyPrefix)
 	}
 	cfg.S3KeyPrefix = strings.Trim(cfg.S3KeyPrefix, "/")
 	if cfg.S3KeyPrefix == "" {
 		cfg.S3KeyPrefix = defaultS3KeyPrefix
 	}
 
-	return cfg, nil
+	if cfg.EncryptionKeyID != "" && cfg.EncryptionKeyPath == "" {
+		p.add("SNAPSHOT_ENCRYPTION_KEY_ID requires SNAPSHOT_ENCRYPTION_KEY_PATH")
+	}
+
+	for _, path := range cfg.AuthPaths {
+		if !strings.HasPrefix(path, "/") {
+			p.addf("AUTH_PATHS entries must start with '/', got %q", path)
+		}
+	}
+
+	for _, c := range cfg.TableListing {
+		if c != "claims" && c != "xrs" && c != "mrs" {
+			p.addf("TABLE_LISTING entries must be \"claims\", \"xrs\" or \"mrs\", got %q", c)
+		}
+	}
+
+	for _, m := range cfg.TenantScope {
+		if m != "namespace" && m != "team" {
+			p.addf("TENANT_SCOPE entries must be \"namespace\" or \"team\", got %q", m)
+		}
+	}
+	if len(cfg.TenantScope) > 0 && len(cfg.AuthPaths) == 0 {
+		p.add("TENANT_SCOPE requires AUTH_PATHS")
+	}
+
+	if (cfg.TLSCertFile == "") != (cfg.TLSKeyFile == "") {
+		p.add("TLS_CERT_FILE and TLS_KEY_FILE must be set together")
+	}
+	if cfg.TLSClientCAFile != "" && cfg.TLSCertFile == "" {
+		p.add("TLS_CLIENT_CA_FILE requires TLS_CERT_FILE and TLS_KEY_FILE")
+	}
+	if cfg.TLSClientAuth != "" && cfg.TLSClientCAFile == "" {
+		p.add("TLS_CLIENT_AUTH requires TLS_CLIENT_CA_FILE")
+	} else if cfg.TLSClientAuth == "" && cfg.TLSClientCAFile != "" {
+		cfg.TLSClientAuth = defaultTLSClientAuth
+	}
+	switch cfg.TLSClientAuth {
+	case "", "require", "optional":
+		// valid
+	default:
+		p.addf("TLS_CLIENT_AUTH must be \"require\" or \"optional\", got %q", cfg.TLSClientAuth)
+	}
+
+	if cfg.ShardNamespace != "" {
+		if cfg.StoreBackend != "s3" {
+			p.add("SHARD_NAMESPACE requires STORE_BACKEND=s3")
+		}
+		// The identity names the replica's Lease and shard object. A
+		// Deployment's pod names change on every restart, so there is no
+		// default.
+		if cfg.ShardIdentity == "" {
+			p.add("SHARD_NAMESPACE requires SHARD_IDENTITY, a name that stays the same across restarts such as a StatefulSet pod name")
+		} else if errs := validation.IsDNS1123Subdomain(cfg.ShardIdentity); len(errs) > 0 {
+			p.addf("SHARD_IDENTITY must be a DNS subdomain name, got %q: %s", cfg.ShardIdentity, strings.Join(errs, ", "))
+		}
+		if cfg.ShardLeaseDuration < time.Second {
+			p.addf("SHARD_LEASE_DURATION must be at least 1s, got %s", cfg.ShardLeaseDuration)
+		}
+	}
+}
+
+// ValidationError reports every problem found while loading configuration.
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	if len(e.Problems) == 1 {
+		return e.Problems[0]
+	}
+	return fmt.Sprintf("%d configuration problems: %s", len(e.Problems), strings.Join(e.Problems, "; "))
+}
+
+// problems collects validation problems so they can be reported together.
+type problems []string
+
+func (p *problems) add(msg string) {
+	*p = append(*p, msg)
+}
+
+func (p *problems) addf(format string, args ...any) {
+	*p = append(*p, fmt.Sprintf(format, args...))
+}
+
+func (p problems) err() error {
+	if len(p) == 0 {
+		return nil
+	}
+	return &ValidationError{Problems: p}
 }
 
 // ParseGVRs parses a comma-separated list of GVR strings in the format "group/version/resource".
@@ -232,6 +711,16 @@ func ParseGVR(s string) (schema.GroupVersionResource, error) {
 	}, nil
 }
 
+func validateLabelSelector(s string) error {
+	_, err := labels.Parse(s)
+	return err
+}
+
+func validateFieldSelector(s string) error {
+	_, err := fields.ParseSelector(s)
+	return err
+}
+
 // splitAndTrim splits s by comma and trims whitespace from each part,
 // discarding empty entries.
 func splitAndTrim(s string) []string {
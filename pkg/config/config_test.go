@@ -2,7 +2,9 @@ package config
 
 import (
 	"os"
+	"slices"
 	"testing"
+	"time"
 
 	"k8s.io/apimachinery/pkg/runtime/schema"
 )
@@ -282,6 +284,368 @@ func TestLoad_StoreBackendInvalid(t *testing.T) {
 	}
 }
 
+func TestLoad_SnapshotEncryption(t *testing.T) {
+	setEnvs(t, map[string]string{
+		"STORE_BACKEND":                "s3",
+		"S3_BUCKET":                    "my-snapshots",
+		"SNAPSHOT_ENCRYPTION_KEY_PATH": "/etc/xp-tracker/keys",
+		"SNAPSHOT_ENCRYPTION_KEY_ID":   "2026-01",
+	})
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.EncryptionKeyPath != "/etc/xp-tracker/keys" {
+		t.Errorf("unexpected key path: %q", cfg.EncryptionKeyPath)
+	}
+	if cfg.EncryptionKeyID != "2026-01" {
+		t.Errorf("unexpected key ID: %q", cfg.EncryptionKeyID)
+	}
+}
+
+func TestLoad_TombstoneRetention(t *testing.T) {
+	setEnvs(t, map[string]string{})
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.TombstoneRetention != 24*time.Hour {
+		t.Errorf("expected default retention 24h, got %s", cfg.TombstoneRetention)
+	}
+
+	setEnvs(t, map[string]string{"TOMBSTONE_RETENTION": "0"})
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.TombstoneRetention != 0 {
+		t.Errorf("expected retention 0, got %s", cfg.TombstoneRetention)
+	}
+
+	for _, bad := range []string{"yesterday", "-1h"} {
+		setEnvs(t, map[string]string{"TOMBSTONE_RETENTION": bad})
+		if _, err := Load(); err == nil {
+			t.Errorf("expected error for TOMBSTONE_RETENTION=%q", bad)
+		}
+	}
+}
+
+func TestLoad_EventBufferSize(t *testing.T) {
+	setEnvs(t, map[string]string{})
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.EventBufferSize != 10000 {
+		t.Errorf("expected default buffer size 10000, got %d", cfg.EventBufferSize)
+	}
+
+	setEnvs(t, map[string]string{"EVENT_BUFFER_SIZE": "0"})
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.EventBufferSize != 0 {
+		t.Errorf("expected buffer size 0, got %d", cfg.EventBufferSize)
+	}
+
+	for _, bad := range []string{"lots", "-1"} {
+		setEnvs(t, map[string]string{"EVENT_BUFFER_SIZE": bad})
+		if _, err := Load(); err == nil {
+			t.Errorf("expected error for EVENT_BUFFER_SIZE=%q", bad)
+		}
+	}
+}
+
+func TestLoad_AuthPaths(t *testing.T) {
+	setEnvs(t, map[string]string{})
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(cfg.AuthPaths) != 0 {
+		t.Errorf("expected authentication disabled by default, got %v", cfg.AuthPaths)
+	}
+	if cfg.AuthCacheTTL != time.Minute {
+		t.Errorf("expected default auth cache TTL 1m, got %v", cfg.AuthCacheTTL)
+	}
+
+	setEnvs(t, map[string]string{
+		"AUTH_PATHS":     "/metrics, /bookkeeping,/claims/",
+		"AUTH_CACHE_TTL": "30s",
+	})
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{"/metrics", "/bookkeeping", "/claims/"}
+	if len(cfg.AuthPaths) != len(want) {
+		t.Fatalf("expected %v, got %v", want, cfg.AuthPaths)
+	}
+	for i := range want {
+		if cfg.AuthPaths[i] != want[i] {
+			t.Errorf("AuthPaths[%d] = %q, want %q", i, cfg.AuthPaths[i], want[i])
+		}
+	}
+	if cfg.AuthCacheTTL != 30*time.Second {
+		t.Errorf("expected auth cache TTL 30s, got %v", cfg.AuthCacheTTL)
+	}
+
+	for env, bad := range map[string]string{"AUTH_PATHS": "metrics", "AUTH_CACHE_TTL": "-1m"} {
+		setEnvs(t, map[string]string{env: bad})
+		if _, err := Load(); err == nil {
+			t.Errorf("expected error for %s=%q", env, bad)
+		}
+	}
+}
+
+func TestLoad_TenantScope(t *testing.T) {
+	setEnvs(t, map[string]string{
+		"AUTH_PATHS":               "/bookkeeping",
+		"TENANT_SCOPE":             "namespace, team",
+		"TENANT_TEAM_GROUP_PREFIX": "oidc:team-",
+	})
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(cfg.TenantScope) != 2 || cfg.TenantScope[0] != "namespace" || cfg.TenantScope[1] != "team" {
+		t.Errorf("unexpected tenant scope %v", cfg.TenantScope)
+	}
+	if cfg.TenantNamespaceResource != "pods" {
+		t.Errorf("expected default namespace resource pods, got %q", cfg.TenantNamespaceResource)
+	}
+	if cfg.TenantTeamGroupPrefix != "oidc:team-" {
+		t.Errorf("unexpected team group prefix %q", cfg.TenantTeamGroupPrefix)
+	}
+
+	invalid := []map[string]string{
+		{"TENANT_SCOPE": "namespace"},
+		{"AUTH_PATHS": "/bookkeeping", "TENANT_SCOPE": "cluster"},
+	}
+	for _, envs := range invalid {
+		setEnvs(t, envs)
+		if _, err := Load(); err == nil {
+			t.Errorf("expected error for %v", envs)
+		}
+	}
+}
+
+func TestLoad_TLS(t *testing.T) {
+	setEnvs(t, map[string]string{
+		"TLS_CERT_FILE":      "/etc/tls/tls.crt",
+		"TLS_KEY_FILE":       "/etc/tls/tls.key",
+		"TLS_CLIENT_CA_FILE": "/etc/tls/ca.crt",
+		"HEALTH_ADDR":        ":8081",
+	})
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.TLSCertFile != "/etc/tls/tls.crt" || cfg.TLSKeyFile != "/etc/tls/tls.key" || cfg.TLSClientCAFile != "/etc/tls/ca.crt" {
+		t.Errorf("unexpected TLS files: %q %q %q", cfg.TLSCertFile, cfg.TLSKeyFile, cfg.TLSClientCAFile)
+	}
+	if cfg.TLSClientAuth != "require" {
+		t.Errorf("expected client auth to default to require, got %q", cfg.TLSClientAuth)
+	}
+	if cfg.HealthAddr != ":8081" {
+		t.Errorf("expected health addr :8081, got %q", cfg.HealthAddr)
+	}
+
+	invalid := []map[string]string{
+		{"TLS_CERT_FILE": "/etc/tls/tls.crt"},
+		{"TLS_KEY_FILE": "/etc/tls/tls.key"},
+		{"TLS_CLIENT_CA_FILE": "/etc/tls/ca.crt"},
+		{"TLS_CERT_FILE": "/etc/tls/tls.crt", "TLS_KEY_FILE": "/etc/tls/tls.key", "TLS_CLIENT_AUTH": "optional"},
+		{"TLS_CERT_FILE": "/etc/tls/tls.crt", "TLS_KEY_FILE": "/etc/tls/tls.key", "TLS_CLIENT_CA_FILE": "/etc/tls/ca.crt", "TLS_CLIENT_AUTH": "sometimes"},
+	}
+	for _, envs := range invalid {
+		setEnvs(t, envs)
+		if _, err := Load(); err == nil {
+			t.Errorf("expected error for %v", envs)
+		}
+	}
+}
+
+func TestLoad_ReadinessStalePolls(t *testing.T) {
+	setEnvs(t, map[string]string{})
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.ReadinessStalePolls != 5 {
+		t.Errorf("expected default of 5 poll intervals, got %d", cfg.ReadinessStalePolls)
+	}
+
+	setEnvs(t, map[string]string{"READINESS_STALE_POLLS": "0"})
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.ReadinessStalePolls != 0 {
+		t.Errorf("expected the check to be disabled, got %d", cfg.ReadinessStalePolls)
+	}
+
+	for _, v := range []string{"-1", "often"} {
+		setEnvs(t, map[string]string{"READINESS_STALE_POLLS": v})
+		if _, err := Load(); err == nil {
+			t.Errorf("expected error for READINESS_STALE_POLLS=%q", v)
+		}
+	}
+}
+
+func TestLoad_GRPCAddr(t *testing.T) {
+	setEnvs(t, map[string]string{})
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.GRPCAddr != "" {
+		t.Errorf("expected gRPC to be disabled by default, got %q", cfg.GRPCAddr)
+	}
+
+	setEnvs(t, map[string]string{"GRPC_ADDR": ":9090"})
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.GRPCAddr != ":9090" {
+		t.Errorf("expected gRPC addr :9090, got %q", cfg.GRPCAddr)
+	}
+}
+
+func TestLoad_Selectors(t *testing.T) {
+	setEnvs(t, map[string]string{
+		"CLAIM_LABEL_SELECTOR": "platform=dev",
+		"XR_FIELD_SELECTOR":    "metadata.name!=scratch",
+		"MR_LABEL_SELECTOR":    "shard in (a,b)",
+	})
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.ClaimSelector != (Selector{Label: "platform=dev"}) ||
+		cfg.XRSelector != (Selector{Field: "metadata.name!=scratch"}) ||
+		cfg.MRSelector != (Selector{Label: "shard in (a,b)"}) {
+		t.Errorf("unexpected selectors: claims=%+v xrs=%+v mrs=%+v", cfg.ClaimSelector, cfg.XRSelector, cfg.MRSelector)
+	}
+
+	setEnvs(t, map[string]string{"NAMESPACE_SELECTOR": "xp-tracker/tracked=true"})
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.NamespaceSelector != "xp-tracker/tracked=true" {
+		t.Errorf("unexpected namespace selector %q", cfg.NamespaceSelector)
+	}
+
+	invalid := []map[string]string{
+		{"NAMESPACE_SELECTOR": "tracked in (true"},
+		{"CLAIM_LABEL_SELECTOR": "platform in (dev"},
+		{"MR_FIELD_SELECTOR": "metadata.name"},
+	}
+	for _, envs := range invalid {
+		setEnvs(t, envs)
+		if _, err := Load(); err == nil {
+			t.Errorf("expected error for %v", envs)
+		}
+	}
+}
+
+func TestLoad_Sharding(t *testing.T) {
+	setEnvs(t, map[string]string{
+		"STORE_BACKEND":   "s3",
+		"S3_BUCKET":       "inventory",
+		"SHARD_NAMESPACE": "crossplane-system",
+		"SHARD_IDENTITY":  "xp-tracker-0",
+	})
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.ShardIdentity != "xp-tracker-0" || cfg.ShardLeaseDuration != 30*time.Second {
+		t.Errorf("expected the identity and default lease duration, got %q %v", cfg.ShardIdentity, cfg.ShardLeaseDuration)
+	}
+
+	invalid := []map[string]string{
+		{"SHARD_NAMESPACE": "crossplane-system", "SHARD_IDENTITY": "xp-tracker-0"},
+		{"STORE_BACKEND": "s3", "S3_BUCKET": "b", "SHARD_NAMESPACE": "ns"},
+		{"STORE_BACKEND": "s3", "S3_BUCKET": "b", "SHARD_NAMESPACE": "ns", "SHARD_IDENTITY": "Exporter_0"},
+		{"STORE_BACKEND": "s3", "S3_BUCKET": "b", "SHARD_NAMESPACE": "ns", "SHARD_LEASE_DURATION": "100ms"},
+	}
+	for _, envs := range invalid {
+		setEnvs(t, envs)
+		if _, err := Load(); err == nil {
+			t.Errorf("expected error for %v", envs)
+		}
+	}
+}
+
+func TestLoad_PollScheduling(t *testing.T) {
+	setEnvs(t, map[string]string{
+		"CLAIM_POLL_INTERVAL_SECONDS": "300",
+		"MR_POLL_INTERVAL_SECONDS":    "60",
+		"ADAPTIVE_POLL_FACTOR":        "4",
+		"POLL_JITTER":                 "0.1",
+	})
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.ClaimPollIntervalSeconds != 300 || cfg.XRPollIntervalSeconds != 0 || cfg.MRPollIntervalSeconds != 60 {
+		t.Errorf("unexpected class intervals: claims=%d xrs=%d mrs=%d",
+			cfg.ClaimPollIntervalSeconds, cfg.XRPollIntervalSeconds, cfg.MRPollIntervalSeconds)
+	}
+	if cfg.AdaptivePollFactor != 4 || cfg.PollJitter != 0.1 {
+		t.Errorf("unexpected adaptive factor %d or jitter %v", cfg.AdaptivePollFactor, cfg.PollJitter)
+	}
+	// 5 stale polls of a GVR's interval, stretched by the factor.
+	if got := cfg.ReadinessStaleAfter(60 * time.Second); got != 5*4*60*time.Second {
+		t.Errorf("unexpected readiness stale duration %v", got)
+	}
+
+	invalid := []map[string]string{
+		{"XR_POLL_INTERVAL_SECONDS": "0"},
+		{"ADAPTIVE_POLL_FACTOR": "-2"},
+		{"POLL_JITTER": "0.8"},
+		{"POLL_JITTER": "some"},
+	}
+	for _, envs := range invalid {
+		setEnvs(t, envs)
+		if _, err := Load(); err == nil {
+			t.Errorf("expected error for %v", envs)
+		}
+	}
+}
+
+func TestLoad_TableListing(t *testing.T) {
+	setEnvs(t, map[string]string{"TABLE_LISTING": "xrs, mrs"})
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(cfg.TableListing, []string{"xrs", "mrs"}) {
+		t.Errorf("unexpected table listing %v", cfg.TableListing)
+	}
+
+	setEnvs(t, map[string]string{"TABLE_LISTING": "mrs,compositions"})
+	if _, err := Load(); err == nil {
+		t.Error("expected error for an unknown resource class")
+	}
+}
+
+func TestLoad_SnapshotEncryptionKeyIDWithoutPath(t *testing.T) {
+	setEnvs(t, map[string]string{
+		"SNAPSHOT_ENCRYPTION_KEY_ID": "2026-01",
+	})
+
+	_, err := Load()
+	if err == nil {
+		t.Error("expected error when SNAPSHOT_ENCRYPTION_KEY_ID is set without SNAPSHOT_ENCRYPTION_KEY_PATH")
+	}
+}
+
 // setEnvs sets environment variables for the duration of the test and clears
 // all config-related env vars first to ensure clean state.
 func setEnvs(t *testing.T, envs map[string]string) {
@@ -289,8 +653,20 @@ func setEnvs(t *testing.T, envs map[string]string) {
 	keys := []string{
 		"CLAIM_GVRS", "XR_GVRS", "KUBE_NAMESPACE_SCOPE",
 		"CREATOR_ANNOTATION_KEY", "TEAM_ANNOTATION_KEY",
-		"COMPOSITION_LABEL_KEY", "POLL_INTERVAL_SECONDS", "METRICS_ADDR",
+		"COMPOSITION_LABEL_KEY", "POLL_INTERVAL_SECONDS", "READINESS_STALE_POLLS", "METRICS_ADDR",
 		"STORE_BACKEND", "S3_BUCKET", "S3_KEY_PREFIX", "S3_REGION", "S3_ENDPOINT",
+		"SNAPSHOT_ENCRYPTION_KEY_PATH", "SNAPSHOT_ENCRYPTION_KEY_ID",
+		"TOMBSTONE_RETENTION",
+		"EVENT_BUFFER_SIZE",
+		"AUTH_PATHS", "AUTH_CACHE_TTL",
+		"TENANT_SCOPE", "TENANT_NAMESPACE_RESOURCE", "TENANT_TEAM_GROUP_PREFIX",
+		"TLS_CERT_FILE", "TLS_KEY_FILE", "TLS_CLIENT_CA_FILE", "TLS_CLIENT_AUTH", "HEALTH_ADDR",
+		"GRPC_ADDR", "CONFIG_FILE", "GVR_INCLUDE", "GVR_EXCLUDE",
+		"CLAIM_LABEL_SELECTOR", "CLAIM_FIELD_SELECTOR", "XR_LABEL_SELECTOR", "XR_FIELD_SELECTOR",
+		"MR_LABEL_SELECTOR", "MR_FIELD_SELECTOR", "NAMESPACE_SELECTOR",
+		"SHARD_NAMESPACE", "SHARD_IDENTITY", "SHARD_LEASE_DURATION",
+		"CLAIM_POLL_INTERVAL_SECONDS", "XR_POLL_INTERVAL_SECONDS", "MR_POLL_INTERVAL_SECONDS",
+		"ADAPTIVE_POLL_FACTOR", "POLL_JITTER", "TABLE_LISTING",
 	}
 	for _, k := range keys {
 		if err := os.Unsetenv(k); err != nil {
@@ -0,0 +1,421 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/yaml"
+)
+
+// fileConfig is the YAML config file format. Every key mirrors an
+// environment variable; unset keys leave the default in place, and set
+// environment variables override the file.
+type fileConfig struct {
+	ClaimGVRs                []string `json:"claimGVRs"`
+	XRGVRs                   []string `json:"xrGVRs"`
+	MRGVRs                   []string `json:"mrGVRs"`
+	GVRInclude               []string `json:"gvrInclude"`
+	GVRExclude               []string `json:"gvrExclude"`
+	ClaimLabelSelector       string   `json:"claimLabelSelector"`
+	ClaimFieldSelector       string   `json:"claimFieldSelector"`
+	XRLabelSelector          string   `json:"xrLabelSelector"`
+	XRFieldSelector          string   `json:"xrFieldSelector"`
+	MRLabelSelector          string   `json:"mrLabelSelector"`
+	MRFieldSelector          string   `json:"mrFieldSelector"`
+	Namespaces               []string `json:"namespaces"`
+	NamespaceSelector        string   `json:"namespaceSelector"`
+	CreatorAnnotationKey     string   `json:"creatorAnnotationKey"`
+	TeamAnnotationKey        string   `json:"teamAnnotationKey"`
+	CompositionLabelKey      string   `json:"compositionLabelKey"`
+	CompositeLabelKey        string   `json:"compositeLabelKey"`
+	PollIntervalSeconds      *int     `json:"pollIntervalSeconds"`
+	ClaimPollIntervalSeconds *int     `json:"claimPollIntervalSeconds"`
+	XRPollIntervalSeconds    *int     `json:"xrPollIntervalSeconds"`
+	MRPollIntervalSeconds    *int     `json:"mrPollIntervalSeconds"`
+	AdaptivePollFactor       *int     `json:"adaptivePollFactor"`
+	PollJitter               *float64 `json:"pollJitter"`
+	TableListing             []string `json:"tableListing"`
+	ReadinessStalePolls      *int     `json:"readinessStalePolls"`
+	MetricsAddr              string   `json:"metricsAddr"`
+
+	StoreBackend              string `json:"storeBackend"`
+	S3Bucket                  string `json:"s3Bucket"`
+	S3KeyPrefix               string `json:"s3KeyPrefix"`
+	S3Region                  string `json:"s3Region"`
+	S3Endpoint                string `json:"s3Endpoint"`
+	SnapshotEncryptionKeyPath string `json:"snapshotEncryptionKeyPath"`
+	SnapshotEncryptionKeyID   string `json:"snapshotEncryptionKeyID"`
+	TombstoneRetention        string `json:"tombstoneRetention"`
+	EventBufferSize           *int   `json:"eventBufferSize"`
+
+	AuthPaths               []string `json:"authPaths"`
+	AuthCacheTTL            string   `json:"authCacheTTL"`
+	TenantScope             []string `json:"tenantScope"`
+	TenantNamespaceResource string   `json:"tenantNamespaceResource"`
+	TenantTeamGroupPrefix   string   `json:"tenantTeamGroupPrefix"`
+
+	TLSCertFile     string `json:"tlsCertFile"`
+	TLSKeyFile      string `json:"tlsKeyFile"`
+	TLSClientCAFile string `json:"tlsClientCAFile"`
+	TLSClientAuth   string `json:"tlsClientAuth"`
+	HealthAddr      string `json:"healthAddr"`
+	GRPCAddr        string `json:"grpcAddr"`
+
+	ShardNamespace     string `json:"shardNamespace"`
+	ShardIdentity      string `json:"shardIdentity"`
+	ShardLeaseDuration string `json:"shardLeaseDuration"`
+
+	Resources []ResourceConfig `json:"resources"`
+}
+
+// ResourceConfig overrides how one claim, XR or MR GVR is polled. Unset
+// fields fall back to the global settings.
+type ResourceConfig struct {
+	// GVR is the resource the overrides apply to, as group/version/resource.
+	GVR string `json:"gvr"`
+
+	// Enabled set to false stops the GVR from being polled, even when it is
+	// discovered from an XRD or MRD.
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// PollIntervalSeconds polls the GVR at its own interval.
+	PollIntervalSeconds int `json:"pollIntervalSeconds,omitempty"`
+
+	// Namespaces restricts the GVR to these namespaces instead of the
+	// global namespace scope and namespace selector.
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	// LabelSelector only lists objects matching this selector. For MRs it
+	// is combined with the composite label requirement.
+	LabelSelector string `json:"labelSelector,omitempty"`
+
+	// FieldSelector only lists objects matching this field selector.
+	FieldSelector string `json:"fieldSelector,omitempty"`
+
+	// CreatorAnnotationKey and TeamAnnotationKey override the ownership
+	// annotation keys for objects of this GVR.
+	CreatorAnnotationKey string `json:"creatorAnnotationKey,omitempty"`
+	TeamAnnotationKey    string `json:"teamAnnotationKey,omitempty"`
+}
+
+// GVRSettings are the effective settings for polling one GVR. The label and
+// field selectors are the GVR's own; they are combined with the selectors of
+// its resource class (ClaimSelector, XRSelector or MRSelector).
+type GVRSettings struct {
+	Enabled              bool
+	PollIntervalSeconds  int
+	Namespaces           []string
+	LabelSelector        string
+	FieldSelector        string
+	CreatorAnnotationKey string
+	TeamAnnotationKey    string
+}
+
+// readFile reads and strictly parses the config file at path, so that
+// misspelt keys are reported rather than ignored.
+func readFile(path string) (*fileConfig, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("read config file: %w", err)
+	}
+	var f fileConfig
+	if err := yaml.UnmarshalStrict(data, &f); err != nil {
+		return nil, fmt.Errorf("parse config file %s: %w", path, err)
+	}
+	return &f, nil
+}
+
+// apply copies the settings present in f into cfg, adding a problem for
+// every invalid value. Problems name the file key.
+func (f *fileConfig) apply(cfg *Config, p *problems) {
+	gvrList := func(key string, raw []string) []schema.GroupVersionResource {
+		if len(raw) == 0 {
+			return nil
+		}
+		gvrs, err := ParseGVRs(strings.Join(raw, ","))
+		if err != nil {
+			p.addf("%s: %v", key, err)
+		}
+		return gvrs
+	}
+	setString := func(dst *string, v string) {
+		if v != "" {
+			*dst = v
+		}
+	}
+	setDuration := func(dst *time.Duration, key, v string) {
+		if v == "" {
+			return
+		}
+		d, err := time.ParseDuration(v)
+		if err != nil || d < 0 {
+			p.addf("%s must be a non-negative duration, got %q", key, v)
+			return
+		}
+		*dst = d
+	}
+
+	if gvrs := gvrList("claimGVRs", f.ClaimGVRs); gvrs != nil {
+		cfg.ClaimGVRs = gvrs
+	}
+	if gvrs := gvrList("xrGVRs", f.XRGVRs); gvrs != nil {
+		cfg.XRGVRs = gvrs
+	}
+	if gvrs := gvrList("mrGVRs", f.MRGVRs); gvrs != nil {
+		cfg.MRGVRs = gvrs
+	}
+	if len(f.GVRInclude) > 0 {
+		cfg.GVRInclude = f.GVRInclude
+		if _, err := ParseGVRFilter(f.GVRInclude, nil); err != nil {
+			p.addf("gvrInclude: %v", err)
+		}
+	}
+	if len(f.GVRExclude) > 0 {
+		cfg.GVRExclude = f.GVRExclude
+		if _, err := ParseGVRFilter(nil, f.GVRExclude); err != nil {
+			p.addf("gvrExclude: %v", err)
+		}
+	}
+	for _, s := range []struct {
+		key string
+		v   string
+		dst *string
+		fn  func(string) error
+	}{
+		{"namespaceSelector", f.NamespaceSelector, &cfg.NamespaceSelector, validateLabelSelector},
+		{"claimLabelSelector", f.ClaimLabelSelector, &cfg.ClaimSelector.Label, validateLabelSelector},
+		{"claimFieldSelector", f.ClaimFieldSelector, &cfg.ClaimSelector.Field, validateFieldSelector},
+		{"xrLabelSelector", f.XRLabelSelector, &cfg.XRSelector.Label, validateLabelSelector},
+		{"xrFieldSelector", f.XRFieldSelector, &cfg.XRSelector.Field, validateFieldSelector},
+		{"mrLabelSelector", f.MRLabelSelector, &cfg.MRSelector.Label, validateLabelSelector},
+		{"mrFieldSelector", f.MRFieldSelector, &cfg.MRSelector.Field, validateFieldSelector},
+	} {
+		if s.v == "" {
+			continue
+		}
+		if err := s.fn(s.v); err != nil {
+			p.addf("%s: %v", s.key, err)
+		}
+		*s.dst = s.v
+	}
+	if len(f.Namespaces) > 0 {
+		cfg.Namespaces = splitAndTrim(strings.Join(f.Namespaces, ","))
+	}
+	setString(&cfg.CreatorAnnotationKey, f.CreatorAnnotationKey)
+	setString(&cfg.TeamAnnotationKey, f.TeamAnnotationKey)
+	setString(&cfg.CompositionLabelKey, f.CompositionLabelKey)
+	setString(&cfg.CompositeLabelKey, f.CompositeLabelKey)
+	if f.PollIntervalSeconds != nil {
+		if *f.PollIntervalSeconds < 1 {
+			p.addf("pollIntervalSeconds must be a positive integer, got %d", *f.PollIntervalSeconds)
+		} else {
+			cfg.PollIntervalSeconds = *f.PollIntervalSeconds
+		}
+	}
+	for _, s := range []struct {
+		key string
+		v   *int
+		dst *int
+	}{
+		{"claimPollIntervalSeconds", f.ClaimPollIntervalSeconds, &cfg.ClaimPollIntervalSeconds},
+		{"xrPollIntervalSeconds", f.XRPollIntervalSeconds, &cfg.XRPollIntervalSeconds},
+		{"mrPollIntervalSeconds", f.MRPollIntervalSeconds, &cfg.MRPollIntervalSeconds},
+	} {
+		if s.v == nil {
+			continue
+		}
+		if *s.v < 1 {
+			p.addf("%s must be a positive integer, got %d", s.key, *s.v)
+		} else {
+			*s.dst = *s.v
+		}
+	}
+	if f.AdaptivePollFactor != nil {
+		if *f.AdaptivePollFactor < 0 {
+			p.addf("adaptivePollFactor must be a non-negative integer, got %d", *f.AdaptivePollFactor)
+		} else {
+			cfg.AdaptivePollFactor = *f.AdaptivePollFactor
+		}
+	}
+	if f.PollJitter != nil {
+		if *f.PollJitter < 0 || *f.PollJitter > maxPollJitter {
+			p.addf("pollJitter must be a number between 0 and %g, got %g", maxPollJitter, *f.PollJitter)
+		} else {
+			cfg.PollJitter = *f.PollJitter
+		}
+	}
+	if len(f.TableListing) > 0 {
+		cfg.TableListing = splitAndTrim(strings.Join(f.TableListing, ","))
+	}
+	if f.ReadinessStalePolls != nil {
+		if *f.ReadinessStalePolls < 0 {
+			p.addf("readinessStalePolls must be a non-negative integer, got %d", *f.ReadinessStalePolls)
+		} else {
+			cfg.ReadinessStalePolls = *f.ReadinessStalePolls
+		}
+	}
+	setString(&cfg.MetricsAddr, f.MetricsAddr)
+
+	setString(&cfg.StoreBackend, f.StoreBackend)
+	setString(&cfg.S3Bucket, f.S3Bucket)
+	setString(&cfg.S3KeyPrefix, f.S3KeyPrefix)
+	setString(&cfg.S3Region, f.S3Region)
+	setString(&cfg.S3Endpoint, f.S3Endpoint)
+	setString(&cfg.EncryptionKeyPath, f.SnapshotEncryptionKeyPath)
+	setString(&cfg.EncryptionKeyID, f.SnapshotEncryptionKeyID)
+	setDuration(&cfg.TombstoneRetention, "tombstoneRetention", f.TombstoneRetention)
+	if f.EventBufferSize != nil {
+		if *f.EventBufferSize < 0 {
+			p.addf("eventBufferSize must be a non-negative integer, got %d", *f.EventBufferSize)
+		} else {
+			cfg.EventBufferSize = *f.EventBufferSize
+		}
+	}
+
+	if len(f.AuthPaths) > 0 {
+		cfg.AuthPaths = splitAndTrim(strings.Join(f.AuthPaths, ","))
+	}
+	setDuration(&cfg.AuthCacheTTL, "authCacheTTL", f.AuthCacheTTL)
+	if len(f.TenantScope) > 0 {
+		cfg.TenantScope = splitAndTrim(strings.Join(f.TenantScope, ","))
+	}
+	setString(&cfg.TenantNamespaceResource, f.TenantNamespaceResource)
+	setString(&cfg.TenantTeamGroupPrefix, f.TenantTeamGroupPrefix)
+
+	setString(&cfg.TLSCertFile, f.TLSCertFile)
+	setString(&cfg.TLSKeyFile, f.TLSKeyFile)
+	setString(&cfg.TLSClientCAFile, f.TLSClientCAFile)
+	setString(&cfg.TLSClientAuth, f.TLSClientAuth)
+	setString(&cfg.HealthAddr, f.HealthAddr)
+	setString(&cfg.GRPCAddr, f.GRPCAddr)
+
+	setString(&cfg.ShardNamespace, f.ShardNamespace)
+	setString(&cfg.ShardIdentity, f.ShardIdentity)
+	setDuration(&cfg.ShardLeaseDuration, "shardLeaseDuration", f.ShardLeaseDuration)
+
+	for i, r := range f.Resources {
+		key := fmt.Sprintf("resources[%d]", i)
+		gvr, err := ParseGVR(r.GVR)
+		if err != nil {
+			p.addf("%s.gvr: %v", key, err)
+			continue
+		}
+		gvrStr := gvr.Group + "/" + gvr.Version + "/" + gvr.Resource
+		if _, dup := cfg.Resources[gvrStr]; dup {
+			p.addf("%s.gvr: duplicate entry for %q", key, gvrStr)
+			continue
+		}
+		if r.PollIntervalSeconds < 0 {
+			p.addf("%s.pollIntervalSeconds must be a positive integer, got %d", key, r.PollIntervalSeconds)
+		}
+		if r.LabelSelector != "" {
+			if err := validateLabelSelector(r.LabelSelector); err != nil {
+				p.addf("%s.labelSelector: %v", key, err)
+			}
+		}
+		if r.FieldSelector != "" {
+			if err := validateFieldSelector(r.FieldSelector); err != nil {
+				p.addf("%s.fieldSelector: %v", key, err)
+			}
+		}
+		r.GVR = gvrStr
+		r.Namespaces = splitAndTrim(strings.Join(r.Namespaces, ","))
+		if cfg.Resources == nil {
+			cfg.Resources = make(map[string]ResourceConfig)
+		}
+		cfg.Resources[gvrStr] = r
+	}
+}
+
+// ForGVR returns the effective settings for polling gvr, a GVR string
+// (group/version/resource): its overrides from the config file on top of
+// the global settings.
+func (c *Config) ForGVR(gvr string) GVRSettings {
+	s := GVRSettings{
+		Enabled:              true,
+		PollIntervalSeconds:  c.PollIntervalSeconds,
+		Namespaces:           c.Namespaces,
+		CreatorAnnotationKey: c.CreatorAnnotationKey,
+		TeamAnnotationKey:    c.TeamAnnotationKey,
+	}
+	r, ok := c.Resources[gvr]
+	if !ok {
+		return s
+	}
+	if r.Enabled != nil {
+		s.Enabled = *r.Enabled
+	}
+	if r.PollIntervalSeconds > 0 {
+		s.PollIntervalSeconds = r.PollIntervalSeconds
+	}
+	if len(r.Namespaces) > 0 {
+		s.Namespaces = r.Namespaces
+	}
+	s.LabelSelector = r.LabelSelector
+	s.FieldSelector = r.FieldSelector
+	if r.CreatorAnnotationKey != "" {
+		s.CreatorAnnotationKey = r.CreatorAnnotationKey
+	}
+	if r.TeamAnnotationKey != "" {
+		s.TeamAnnotationKey = r.TeamAnnotationKey
+	}
+	return s
+}
+
+// ReadinessStaleAfter returns how long after its last successful list a
+// GVR polled every interval makes /readyz report degraded:
+// ReadinessStalePolls times interval, stretched by AdaptivePollFactor, since
+// its polls may be that far apart. Zero disables the check.
+func (c *Config) ReadinessStaleAfter(interval time.Duration) time.Duration {
+	stale := time.Duration(c.ReadinessStalePolls) * interval
+	if c.AdaptivePollFactor > 1 {
+		stale *= time.Duration(c.AdaptivePollFactor)
+	}
+	return stale
+}
+
+// DropDisabled removes the GVRs disabled in the config file from the claim,
+// XR and MR lists and returns them. Call it after discovery, which replaces
+// the lists.
+func (c *Config) DropDisabled() []string {
+	var dropped []string
+	keep := func(gvrs []schema.GroupVersionResource) []schema.GroupVersionResource {
+		return slices.DeleteFunc(gvrs, func(gvr schema.GroupVersionResource) bool {
+			key := gvr.Group + "/" + gvr.Version + "/" + gvr.Resource
+			if c.ForGVR(key).Enabled {
+				return false
+			}
+			dropped = append(dropped, key)
+			return true
+		})
+	}
+	c.ClaimGVRs = keep(c.ClaimGVRs)
+	c.XRGVRs = keep(c.XRGVRs)
+	c.MRGVRs = keep(c.MRGVRs)
+	return dropped
+}
+
+// UnmatchedResources returns the GVRs with overrides in the config file
+// that are not tracked as a claim, XR or MR, usually because of a typo or
+// a missing XRD. The result is sorted.
+func (c *Config) UnmatchedResources() []string {
+	tracked := make(map[string]struct{}, len(c.ClaimGVRs)+len(c.XRGVRs)+len(c.MRGVRs))
+	for _, gvrs := range [][]schema.GroupVersionResource{c.ClaimGVRs, c.XRGVRs, c.MRGVRs} {
+		for _, gvr := range gvrs {
+			tracked[gvr.Group+"/"+gvr.Version+"/"+gvr.Resource] = struct{}{}
+		}
+	}
+	var out []string
+	for key, r := range c.Resources {
+		if _, ok := tracked[key]; !ok && (r.Enabled == nil || *r.Enabled) {
+			out = append(out, key)
+		}
+	}
+	slices.Sort(out)
+	return out
+}
@@ -0,0 +1,213 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// writeConfigFile writes content to a config file and points CONFIG_FILE at
+// it, on top of the given environment.
+func writeConfigFile(t *testing.T, content string, envs map[string]string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write config file: %v", err)
+	}
+	all := map[string]string{"CONFIG_FILE": path}
+	for k, v := range envs {
+		all[k] = v
+	}
+	setEnvs(t, all)
+}
+
+func TestLoad_File(t *testing.T) {
+	writeConfigFile(t, `
+mrGVRs:
+  - ec2.aws.upbound.io/v1beta1/instances
+namespaces: [team-a, team-b]
+creatorAnnotationKey: example.org/created-by
+pollIntervalSeconds: 60
+mrPollIntervalSeconds: 120
+pollJitter: 0.2
+tableListing: [xrs, mrs]
+storeBackend: s3
+s3Bucket: inventory
+tombstoneRetention: 1h
+authPaths: [/bookkeeping]
+claimLabelSelector: platform=dev
+resources:
+  - gvr: platform.example.org/v1alpha1/postgresqlinstances
+    pollIntervalSeconds: 300
+    namespaces: [databases]
+    labelSelector: tier=prod
+    fieldSelector: metadata.name!=scratch
+    teamAnnotationKey: example.org/owner
+  - gvr: ec2.aws.upbound.io/v1beta1/instances
+    enabled: false
+`, nil)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(cfg.MRGVRs) != 1 || cfg.MRGVRs[0].Resource != "instances" {
+		t.Errorf("unexpected MR GVRs: %v", cfg.MRGVRs)
+	}
+	if !slices.Equal(cfg.Namespaces, []string{"team-a", "team-b"}) {
+		t.Errorf("unexpected namespaces: %v", cfg.Namespaces)
+	}
+	if cfg.CreatorAnnotationKey != "example.org/created-by" || cfg.PollIntervalSeconds != 60 {
+		t.Errorf("unexpected settings: creator=%q interval=%d", cfg.CreatorAnnotationKey, cfg.PollIntervalSeconds)
+	}
+	if cfg.MRPollIntervalSeconds != 120 || cfg.PollJitter != 0.2 {
+		t.Errorf("unexpected MR interval %d or jitter %v", cfg.MRPollIntervalSeconds, cfg.PollJitter)
+	}
+	if !slices.Equal(cfg.TableListing, []string{"xrs", "mrs"}) {
+		t.Errorf("unexpected table listing: %v", cfg.TableListing)
+	}
+	if cfg.StoreBackend != "s3" || cfg.S3Bucket != "inventory" || cfg.S3Region != "us-east-1" {
+		t.Errorf("unexpected store settings: %q %q %q", cfg.StoreBackend, cfg.S3Bucket, cfg.S3Region)
+	}
+	if cfg.TombstoneRetention != time.Hour || !slices.Equal(cfg.AuthPaths, []string{"/bookkeeping"}) {
+		t.Errorf("unexpected retention %v or auth paths %v", cfg.TombstoneRetention, cfg.AuthPaths)
+	}
+	if cfg.ClaimSelector.Label != "platform=dev" {
+		t.Errorf("unexpected claim selector: %+v", cfg.ClaimSelector)
+	}
+	if cfg.MetricsAddr != ":8080" {
+		t.Errorf("expected unset keys to keep defaults, got metrics addr %q", cfg.MetricsAddr)
+	}
+
+	got := cfg.ForGVR("platform.example.org/v1alpha1/postgresqlinstances")
+	want := GVRSettings{
+		Enabled:              true,
+		PollIntervalSeconds:  300,
+		Namespaces:           []string{"databases"},
+		LabelSelector:        "tier=prod",
+		FieldSelector:        "metadata.name!=scratch",
+		CreatorAnnotationKey: "example.org/created-by",
+		TeamAnnotationKey:    "example.org/owner",
+	}
+	if got.Enabled != want.Enabled || got.PollIntervalSeconds != want.PollIntervalSeconds ||
+		!slices.Equal(got.Namespaces, want.Namespaces) || got.LabelSelector != want.LabelSelector || got.FieldSelector != want.FieldSelector ||
+		got.CreatorAnnotationKey != want.CreatorAnnotationKey || got.TeamAnnotationKey != want.TeamAnnotationKey {
+		t.Errorf("ForGVR = %+v, want %+v", got, want)
+	}
+	if s := cfg.ForGVR("other/v1/things"); !s.Enabled || s.PollIntervalSeconds != 60 || len(s.Namespaces) != 2 {
+		t.Errorf("expected global settings for a GVR without overrides, got %+v", s)
+	}
+}
+
+func TestLoad_FileEnvPrecedence(t *testing.T) {
+	writeConfigFile(t, `
+pollIntervalSeconds: 60
+metricsAddr: ":9000"
+teamAnnotationKey: example.org/team
+`, map[string]string{
+		"POLL_INTERVAL_SECONDS": "15",
+		"METRICS_ADDR":          ":9100",
+	})
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.PollIntervalSeconds != 15 || cfg.MetricsAddr != ":9100" {
+		t.Errorf("expected env to override the file, got interval=%d addr=%q", cfg.PollIntervalSeconds, cfg.MetricsAddr)
+	}
+	if cfg.TeamAnnotationKey != "example.org/team" {
+		t.Errorf("expected file value when env is unset, got %q", cfg.TeamAnnotationKey)
+	}
+}
+
+func TestLoad_FileReportsAllProblems(t *testing.T) {
+	writeConfigFile(t, `
+pollIntervalSeconds: 0
+tombstoneRetention: forever
+resources:
+  - gvr: not-a-gvr
+  - gvr: g/v1/things
+    labelSelector: "tier in (prod"
+  - gvr: g/v1/things
+`, map[string]string{"STORE_BACKEND": "disk"})
+
+	_, err := Load()
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("expected a ValidationError, got %v", err)
+	}
+	wantPrefixes := []string{
+		"pollIntervalSeconds",
+		"tombstoneRetention",
+		"resources[0].gvr",
+		"resources[1].labelSelector",
+		"resources[2].gvr",
+		"STORE_BACKEND",
+	}
+	if len(verr.Problems) != len(wantPrefixes) {
+		t.Fatalf("expected %d problems, got %d: %v", len(wantPrefixes), len(verr.Problems), verr.Problems)
+	}
+	for i, prefix := range wantPrefixes {
+		if !strings.HasPrefix(verr.Problems[i], prefix) {
+			t.Errorf("problem %d = %q, want prefix %q", i, verr.Problems[i], prefix)
+		}
+	}
+	if !strings.HasPrefix(err.Error(), "6 configuration problems: ") {
+		t.Errorf("unexpected error message: %v", err)
+	}
+}
+
+func TestLoad_FileInvalid(t *testing.T) {
+	tests := map[string]string{
+		"unknown key": "pollInterval: 30\n",
+		"wrong type":  "pollIntervalSeconds: soon\n",
+	}
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			writeConfigFile(t, content, nil)
+			if _, err := Load(); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+
+	setEnvs(t, map[string]string{"CONFIG_FILE": filepath.Join(t.TempDir(), "missing.yaml")})
+	if _, err := Load(); err == nil {
+		t.Error("expected error for a missing config file")
+	}
+}
+
+func TestConfig_DropDisabled(t *testing.T) {
+	disabled := false
+	cfg := &Config{
+		ClaimGVRs: []schema.GroupVersionResource{{Group: "g", Version: "v1", Resource: "things"}},
+		XRGVRs:    []schema.GroupVersionResource{{Group: "g", Version: "v1", Resource: "xthings"}},
+		MRGVRs: []schema.GroupVersionResource{
+			{Group: "aws", Version: "v1", Resource: "buckets"},
+			{Group: "aws", Version: "v1", Resource: "queues"},
+		},
+		Resources: map[string]ResourceConfig{
+			"aws/v1/buckets": {GVR: "aws/v1/buckets", Enabled: &disabled},
+			"g/v1/things":    {GVR: "g/v1/things", PollIntervalSeconds: 10},
+			"g/v1/typo":      {GVR: "g/v1/typo", LabelSelector: "a=b"},
+		},
+	}
+
+	dropped := cfg.DropDisabled()
+	if !slices.Equal(dropped, []string{"aws/v1/buckets"}) {
+		t.Errorf("unexpected dropped GVRs: %v", dropped)
+	}
+	if len(cfg.MRGVRs) != 1 || cfg.MRGVRs[0].Resource != "queues" || len(cfg.ClaimGVRs) != 1 {
+		t.Errorf("unexpected GVRs after dropping: claims=%v mrs=%v", cfg.ClaimGVRs, cfg.MRGVRs)
+	}
+	if unmatched := cfg.UnmatchedResources(); !slices.Equal(unmatched, []string{"g/v1/typo"}) {
+		t.Errorf("unexpected unmatched resources: %v", unmatched)
+	}
+}
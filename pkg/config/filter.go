@@ -0,0 +1,153 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// GVRFilter decides which discovered and configured GVRs are polled, from
+// include and exclude patterns on the API group, the resource and the
+// provider package.
+//
+// Each pattern has the form field=pattern, where field is "group",
+// "resource" or "provider". The pattern is a glob (path.Match syntax), or a
+// regular expression when wrapped in slashes, e.g. group=/^(ec2|s3)\./.
+//
+// A GVR is polled when it matches no exclude pattern and, if there are
+// include patterns that apply to it, at least one of them. Provider
+// patterns only apply to MRs whose provider package is known.
+type GVRFilter struct {
+	include []gvrMatcher
+	exclude []gvrMatcher
+}
+
+// gvrMatcher matches one field of a GVR against a glob or regexp.
+type gvrMatcher struct {
+	field string // "group", "resource" or "provider"
+	glob  string
+	re    *regexp.Regexp
+}
+
+// ParseGVRFilter compiles include and exclude patterns.
+func ParseGVRFilter(include, exclude []string) (GVRFilter, error) {
+	var f GVRFilter
+	var err error
+	if f.include, err = parseGVRMatchers(include); err != nil {
+		return GVRFilter{}, err
+	}
+	if f.exclude, err = parseGVRMatchers(exclude); err != nil {
+		return GVRFilter{}, err
+	}
+	return f, nil
+}
+
+func parseGVRMatchers(patterns []string) ([]gvrMatcher, error) {
+	out := make([]gvrMatcher, 0, len(patterns))
+	for _, p := range patterns {
+		field, pattern, ok := strings.Cut(p, "=")
+		if !ok || pattern == "" {
+			return nil, fmt.Errorf("invalid pattern %q: expected field=pattern", p)
+		}
+		m := gvrMatcher{field: field}
+		switch field {
+		case "group", "resource", "provider":
+		default:
+			return nil, fmt.Errorf("invalid pattern %q: field must be group, resource or provider", p)
+		}
+		if len(pattern) > 1 && strings.HasPrefix(pattern, "/") && strings.HasSuffix(pattern, "/") {
+			re, err := regexp.Compile(pattern[1 : len(pattern)-1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid pattern %q: %w", p, err)
+			}
+			m.re = re
+		} else {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return nil, fmt.Errorf("invalid pattern %q: %w", p, err)
+			}
+			m.glob = pattern
+		}
+		out = append(out, m)
+	}
+	return out, nil
+}
+
+// value returns the GVR field the matcher applies to, and whether the field
+// is known.
+func (m gvrMatcher) value(gvr schema.GroupVersionResource, provider string) (string, bool) {
+	switch m.field {
+	case "group":
+		return gvr.Group, true
+	case "resource":
+		return gvr.Resource, true
+	default:
+		return provider, provider != ""
+	}
+}
+
+func (m gvrMatcher) match(v string) bool {
+	if m.re != nil {
+		return m.re.MatchString(v)
+	}
+	ok, _ := path.Match(m.glob, v)
+	return ok
+}
+
+// Empty reports whether the filter has no patterns and so keeps every GVR.
+func (f GVRFilter) Empty() bool {
+	return len(f.include) == 0 && len(f.exclude) == 0
+}
+
+// Match reports whether gvr, with provider package provider ("" when not
+// an MR or unknown), is polled.
+func (f GVRFilter) Match(gvr schema.GroupVersionResource, provider string) bool {
+	for _, m := range f.exclude {
+		if v, ok := m.value(gvr, provider); ok && m.match(v) {
+			return false
+		}
+	}
+	applicable := false
+	for _, m := range f.include {
+		v, ok := m.value(gvr, provider)
+		if !ok {
+			continue
+		}
+		if m.match(v) {
+			return true
+		}
+		applicable = true
+	}
+	return !applicable
+}
+
+// ApplyGVRFilter removes the claim, XR and MR GVRs rejected by GVRFilter and
+// returns them. Call it after discovery, which replaces the lists.
+func (c *Config) ApplyGVRFilter() []string {
+	if c.GVRFilter.Empty() {
+		return nil
+	}
+	var dropped []string
+	keep := func(gvrs []schema.GroupVersionResource, mr bool) []schema.GroupVersionResource {
+		out := gvrs[:0]
+		for _, gvr := range gvrs {
+			key := gvr.Group + "/" + gvr.Version + "/" + gvr.Resource
+			var provider string
+			if mr {
+				provider = c.MRProviderNames[key]
+			}
+			if c.GVRFilter.Match(gvr, provider) {
+				out = append(out, gvr)
+			} else {
+				dropped = append(dropped, key)
+			}
+		}
+		return out
+	}
+	c.ClaimGVRs = keep(c.ClaimGVRs, false)
+	c.XRGVRs = keep(c.XRGVRs, false)
+	c.MRGVRs = keep(c.MRGVRs, true)
+	return dropped
+}
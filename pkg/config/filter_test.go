@@ -0,0 +1,88 @@
+package config
+
+import (
+	"slices"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestGVRFilter_Match(t *testing.T) {
+	bucket := schema.GroupVersionResource{Group: "s3.aws.upbound.io", Version: "v1beta1", Resource: "buckets"}
+	instance := schema.GroupVersionResource{Group: "ec2.aws.upbound.io", Version: "v1beta1", Resource: "instances"}
+	claim := schema.GroupVersionResource{Group: "platform.example.org", Version: "v1alpha1", Resource: "databases"}
+
+	tests := []struct {
+		name     string
+		include  []string
+		exclude  []string
+		gvr      schema.GroupVersionResource
+		provider string
+		want     bool
+	}{
+		{name: "no patterns", gvr: bucket, want: true},
+		{name: "group glob include", include: []string{"group=s3.*"}, gvr: bucket, want: true},
+		{name: "group glob not included", include: []string{"group=s3.*"}, gvr: instance, want: false},
+		{name: "regexp include", include: []string{"group=/^(s3|ec2)\\./"}, gvr: instance, want: true},
+		{name: "exclude wins", include: []string{"group=*.aws.upbound.io"}, exclude: []string{"resource=buckets"}, gvr: bucket, want: false},
+		{name: "provider include", include: []string{"provider=provider-aws-s3"}, gvr: bucket, provider: "provider-aws-s3", want: true},
+		{name: "provider include rejects other providers", include: []string{"provider=provider-aws-s3"}, gvr: instance, provider: "provider-aws-ec2", want: false},
+		{name: "provider include ignores claims", include: []string{"provider=provider-aws-*"}, gvr: claim, want: true},
+		{name: "provider exclude", exclude: []string{"provider=provider-aws-ec2"}, gvr: instance, provider: "provider-aws-ec2", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := ParseGVRFilter(tt.include, tt.exclude)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := f.Match(tt.gvr, tt.provider); got != tt.want {
+				t.Errorf("Match = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseGVRFilter_Invalid(t *testing.T) {
+	for _, p := range []string{"buckets", "kind=Bucket", "group=", "group=[", "resource=/(/"} {
+		if _, err := ParseGVRFilter([]string{p}, nil); err == nil {
+			t.Errorf("expected error for %q", p)
+		}
+	}
+}
+
+func TestLoad_GVRFilter(t *testing.T) {
+	setEnvs(t, map[string]string{
+		"GVR_INCLUDE": "group=*.aws.upbound.io, group=platform.example.org",
+		"GVR_EXCLUDE": "provider=provider-aws-ec2",
+	})
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cfg.ClaimGVRs = []schema.GroupVersionResource{{Group: "platform.example.org", Version: "v1", Resource: "databases"}}
+	cfg.XRGVRs = []schema.GroupVersionResource{{Group: "other.example.org", Version: "v1", Resource: "xqueues"}}
+	cfg.MRGVRs = []schema.GroupVersionResource{
+		{Group: "s3.aws.upbound.io", Version: "v1beta1", Resource: "buckets"},
+		{Group: "ec2.aws.upbound.io", Version: "v1beta1", Resource: "instances"},
+	}
+	cfg.MRProviderNames = map[string]string{
+		"s3.aws.upbound.io/v1beta1/buckets":    "provider-aws-s3",
+		"ec2.aws.upbound.io/v1beta1/instances": "provider-aws-ec2",
+	}
+
+	dropped := cfg.ApplyGVRFilter()
+	want := []string{"other.example.org/v1/xqueues", "ec2.aws.upbound.io/v1beta1/instances"}
+	if !slices.Equal(dropped, want) {
+		t.Errorf("dropped = %v, want %v", dropped, want)
+	}
+	if len(cfg.ClaimGVRs) != 1 || len(cfg.XRGVRs) != 0 || len(cfg.MRGVRs) != 1 {
+		t.Errorf("unexpected GVRs after filtering: claims=%v xrs=%v mrs=%v", cfg.ClaimGVRs, cfg.XRGVRs, cfg.MRGVRs)
+	}
+
+	setEnvs(t, map[string]string{"GVR_INCLUDE": "kind=Bucket", "GVR_EXCLUDE": "group=["})
+	if _, err := Load(); err == nil {
+		t.Error("expected error for invalid patterns")
+	}
+}
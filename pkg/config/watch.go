@@ -0,0 +1,108 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+)
+
+// reloadInterval is how often the config file is checked for changes.
+const reloadInterval = 10 * time.Second
+
+// Watcher reloads the configuration when the config file changes. A
+// ConfigMap volume updates its files by swapping a symlink, so a changed
+// modification time or size is taken as a change.
+type Watcher struct {
+	path     string
+	interval time.Duration
+	stamp    fileStamp
+}
+
+// fileStamp identifies a version of a file.
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// NewWatcher returns a Watcher for the config file at path, taking its
+// current contents as already loaded.
+func NewWatcher(path string) *Watcher {
+	w := &Watcher{path: path, interval: reloadInterval}
+	w.stamp, _ = stat(path)
+	return w
+}
+
+// Run checks the config file for changes until ctx is cancelled. After each
+// change the configuration is loaded again with Load, and reload is called
+// with the result. A file that cannot be read counts as unchanged, so an
+// update caught half-way is retried at the next check.
+func (w *Watcher) Run(ctx context.Context, reload func(*Config, error)) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			stamp, err := stat(w.path)
+			if err != nil || stamp == w.stamp {
+				continue
+			}
+			w.stamp = stamp
+			reload(Load())
+		}
+	}
+}
+
+func stat(path string) (fileStamp, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileStamp{}, err
+	}
+	return fileStamp{modTime: info.ModTime(), size: info.Size()}, nil
+}
+
+// RestartRequired returns the settings, by environment variable name, that
+// differ in next but only take effect on restart: listen addresses, the
+// store, authentication, TLS and sharding. The poller settings, including
+// the readiness staleness threshold, per-GVR overrides and ownership keys
+// are applied live.
+func (c *Config) RestartRequired(next *Config) []string {
+	settings := []struct {
+		name string
+		get  func(*Config) any
+	}{
+		{"METRICS_ADDR", func(c *Config) any { return c.MetricsAddr }},
+		{"HEALTH_ADDR", func(c *Config) any { return c.HealthAddr }},
+		{"GRPC_ADDR", func(c *Config) any { return c.GRPCAddr }},
+		{"STORE_BACKEND", func(c *Config) any { return c.StoreBackend }},
+		{"S3_BUCKET", func(c *Config) any { return c.S3Bucket }},
+		{"S3_KEY_PREFIX", func(c *Config) any { return c.S3KeyPrefix }},
+		{"S3_REGION", func(c *Config) any { return c.S3Region }},
+		{"S3_ENDPOINT", func(c *Config) any { return c.S3Endpoint }},
+		{"SNAPSHOT_ENCRYPTION_KEY_PATH", func(c *Config) any { return c.EncryptionKeyPath }},
+		{"SNAPSHOT_ENCRYPTION_KEY_ID", func(c *Config) any { return c.EncryptionKeyID }},
+		{"TOMBSTONE_RETENTION", func(c *Config) any { return c.TombstoneRetention }},
+		{"EVENT_BUFFER_SIZE", func(c *Config) any { return c.EventBufferSize }},
+		{"AUTH_PATHS", func(c *Config) any { return c.AuthPaths }},
+		{"AUTH_CACHE_TTL", func(c *Config) any { return c.AuthCacheTTL }},
+		{"TENANT_SCOPE", func(c *Config) any { return c.TenantScope }},
+		{"TENANT_NAMESPACE_RESOURCE", func(c *Config) any { return c.TenantNamespaceResource }},
+		{"TENANT_TEAM_GROUP_PREFIX", func(c *Config) any { return c.TenantTeamGroupPrefix }},
+		{"TLS_CERT_FILE", func(c *Config) any { return c.TLSCertFile }},
+		{"TLS_KEY_FILE", func(c *Config) any { return c.TLSKeyFile }},
+		{"TLS_CLIENT_CA_FILE", func(c *Config) any { return c.TLSClientCAFile }},
+		{"TLS_CLIENT_AUTH", func(c *Config) any { return c.TLSClientAuth }},
+		{"SHARD_NAMESPACE", func(c *Config) any { return c.ShardNamespace }},
+		{"SHARD_IDENTITY", func(c *Config) any { return c.ShardIdentity }},
+		{"SHARD_LEASE_DURATION", func(c *Config) any { return c.ShardLeaseDuration }},
+	}
+	var changed []string
+	for _, s := range settings {
+		if fmt.Sprint(s.get(c)) != fmt.Sprint(s.get(next)) {
+			changed = append(changed, s.name)
+		}
+	}
+	return changed
+}
@@ -0,0 +1,60 @@
+package config
+
+import (
+	"context"
+	"os"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestWatcher_Reload(t *testing.T) {
+	writeConfigFile(t, "pollIntervalSeconds: 60\n", nil)
+	path := os.Getenv("CONFIG_FILE")
+
+	w := NewWatcher(path)
+	w.interval = 10 * time.Millisecond
+
+	type result struct {
+		cfg *Config
+		err error
+	}
+	results := make(chan result, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go w.Run(ctx, func(cfg *Config, err error) { results <- result{cfg, err} })
+
+	next := func() result {
+		t.Helper()
+		select {
+		case r := <-results:
+			return r
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for a reload")
+			return result{}
+		}
+	}
+
+	if err := os.WriteFile(path, []byte("pollIntervalSeconds: 120\n"), 0o600); err != nil {
+		t.Fatalf("write config file: %v", err)
+	}
+	if r := next(); r.err != nil || r.cfg.PollIntervalSeconds != 120 {
+		t.Fatalf("expected the new interval, got cfg=%+v err=%v", r.cfg, r.err)
+	}
+
+	if err := os.WriteFile(path, []byte("pollIntervalSeconds: -1\n"), 0o600); err != nil {
+		t.Fatalf("write config file: %v", err)
+	}
+	if r := next(); r.err == nil {
+		t.Fatal("expected an invalid config to be reported")
+	}
+}
+
+func TestConfig_RestartRequired(t *testing.T) {
+	cur := &Config{MetricsAddr: ":8080", PollIntervalSeconds: 30, AuthPaths: []string{"/bookkeeping"}}
+	next := &Config{MetricsAddr: ":8080", PollIntervalSeconds: 60, AuthPaths: []string{"/"}, TeamAnnotationKey: "example.org/team"}
+
+	if got := cur.RestartRequired(next); !slices.Equal(got, []string{"AUTH_PATHS"}) {
+		t.Errorf("expected only AUTH_PATHS to need a restart, got %v", got)
+	}
+}
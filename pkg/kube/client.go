@@ -14,10 +14,20 @@ import (
 // NewDynamicClient creates a dynamic Kubernetes client.
 // It tries in-cluster config first, then falls back to the default kubeconfig.
 func NewDynamicClient() (dynamic.Interface, error) {
+	cfg, err := RESTConfig()
+	if err != nil {
+		return nil, err
+	}
+	return dynamic.NewForConfig(cfg)
+}
+
+// RESTConfig returns the client configuration used to reach the API server.
+// It tries in-cluster config first, then falls back to the default kubeconfig.
+func RESTConfig() (*rest.Config, error) {
 	cfg, err := rest.InClusterConfig()
 	if err == nil {
 		applyClientLimits(cfg)
-		return dynamic.NewForConfig(cfg)
+		return cfg, nil
 	}
 
 	// Fall back to kubeconfig for local development.
@@ -36,7 +46,7 @@ func NewDynamicClient() (dynamic.Interface, error) {
 	}
 
 	applyClientLimits(cfg)
-	return dynamic.NewForConfig(cfg)
+	return cfg, nil
 }
 
 // applyClientLimits raises the default client-side rate limits.
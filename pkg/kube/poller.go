@@ -3,11 +3,15 @@ package kube
 import (
 	"context"
 	"log/slog"
+	"slices"
+	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 
 	"golang.org/x/sync/errgroup"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
 
@@ -30,54 +34,383 @@ const mrPollConcurrency = 20
 // and updates the in-memory store.
 type Poller struct {
 	client dynamic.Interface
-	cfg    *config.Config
 	store  store.Store
+
+	// cfgMu guards cfg, which Reconfigure replaces. The polling goroutine is
+	// the only writer, so it reads cfg without locking.
+	cfgMu       sync.RWMutex
+	cfg         *config.Config
+	reconfigure chan *config.Config // holds the newest config not applied yet
+	removed     removedGVRs         // dropped by a reconfiguration, cleared by the next cycle
+	refiltered  gvrSet              // selectors changed by a reconfiguration, cleared by the next complete list
+
+	firstPoll   chan struct{} // closed when the first cycle completes
+	firstPollOK bool          // written before firstPoll is closed
+	tracker     pollTracker
+
+	// unpersisted is set when a cycle committed a generation that persist
+	// has not written to the store backend yet. Only used by the polling
+	// goroutine.
+	unpersisted bool
+
+	// schedule records when each GVR is polled next, and nextCycle when
+	// the earliest of them is due. nextCycle is only used by the polling
+	// goroutine.
+	schedule  pollSchedule
+	nextCycle time.Time
+
+	// selectedNS holds the namespaces matching the namespace selector, once
+	// nsSelected is set. Only used by the polling goroutine.
+	selectedNS []string
+	nsSelected bool
+
+	// shards, when set, restricts polling to the GVRs this replica owns;
+	// the others are merged from the peers' shards. See SetShards.
+	shards *Shards
+
+	// tables, when set, lists the resource classes in TableListing as
+	// Tables. See SetTableClient.
+	tables *TableClient
+}
+
+// namespaceGVR is the GVR of core v1 namespaces, listed to resolve the
+// namespace selector.
+var namespaceGVR = schema.GroupVersionResource{Version: "v1", Resource: "namespaces"}
+
+// removedGVRs lists the GVR strings, by kind, that are no longer tracked.
+type removedGVRs struct {
+	claims, xrs, mrs []string
+}
+
+// gvrSet is a set of GVR strings, safe for concurrent use since MR GVRs
+// are polled in parallel.
+type gvrSet struct {
+	mu   sync.Mutex
+	gvrs map[string]bool
+}
+
+func (s *gvrSet) add(gvr string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.gvrs == nil {
+		s.gvrs = make(map[string]bool)
+	}
+	s.gvrs[gvr] = true
+}
+
+func (s *gvrSet) has(gvr string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.gvrs[gvr]
+}
+
+func (s *gvrSet) remove(gvr string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.gvrs, gvr)
 }
 
 // NewPoller creates a new Poller.
 func NewPoller(client dynamic.Interface, cfg *config.Config, s store.Store) *Poller {
 	return &Poller{
-		client: client,
-		cfg:    cfg,
-		store:  s,
+		client:      client,
+		cfg:         cfg,
+		reconfigure: make(chan *config.Config, 1),
+		store:       s,
+		firstPoll:   make(chan struct{}),
 	}
 }
 
-// Run starts the polling loop. It blocks until ctx is cancelled.
-func (p *Poller) Run(ctx context.Context) {
-	ticker := time.NewTicker(time.Duration(p.cfg.PollIntervalSeconds) * time.Second)
-	defer ticker.Stop()
+// SetShards enables sharded polling: each cycle lists only the GVRs this
+// replica owns among the members of shards; once per global poll interval,
+// persist writes them as its shard and copies the other GVRs from the
+// shards of their owners. The store must be a store.ShardStore. It must be called before Run.
+func (p *Poller) SetShards(s *Shards) {
+	p.shards = s
+}
 
+// SetTableClient lets the poller list the resource classes in the
+// TableListing setting as server-side Tables; without it, every class is
+// listed in full. It must be called before Run.
+func (p *Poller) SetTableClient(c *TableClient) {
+	p.tables = c
+}
+
+// Reconfigure replaces the poller's configuration. The polling loop applies
+// it and immediately polls every GVR with the new settings; GVRs no longer
+// tracked are removed from the store in the same cycle.
+func (p *Poller) Reconfigure(cfg *config.Config) {
+	for {
+		select {
+		case p.reconfigure <- cfg:
+			return
+		default:
+		}
+		// Replace a config the loop has not picked up yet.
+		select {
+		case <-p.reconfigure:
+		default:
+		}
+	}
+}
+
+// config returns the current configuration, for use outside the polling
+// goroutine.
+func (p *Poller) config() *config.Config {
+	p.cfgMu.RLock()
+	defer p.cfgMu.RUnlock()
+	return p.cfg
+}
+
+// applyConfig switches to cfg and records the GVRs it drops. It must only
+// be called from the polling goroutine.
+func (p *Poller) applyConfig(cfg *config.Config) {
+	p.cfgMu.Lock()
+	old := p.cfg
+	p.cfg = cfg
+	p.cfgMu.Unlock()
+
+	p.removed.claims = append(p.removed.claims, droppedGVRs(old.ClaimGVRs, cfg.ClaimGVRs)...)
+	p.removed.xrs = append(p.removed.xrs, droppedGVRs(old.XRGVRs, cfg.XRGVRs)...)
+	p.removed.mrs = append(p.removed.mrs, droppedGVRs(old.MRGVRs, cfg.MRGVRs)...)
+	for _, gvr := range refilteredGVRs(old, cfg) {
+		p.refiltered.add(gvr)
+	}
+	p.schedule.reset()
+	if cfg.NamespaceSelector != old.NamespaceSelector || !slices.Equal(cfg.Namespaces, old.Namespaces) {
+		p.nsSelected = false
+	}
+}
+
+// droppedGVRs returns the GVR strings in old that are not in next.
+func droppedGVRs(old, next []schema.GroupVersionResource) []string {
+	var out []string
+	for _, gvr := range old {
+		if !slices.Contains(next, gvr) {
+			out = append(out, GVRString(gvr))
+		}
+	}
+	return out
+}
+
+// refilteredGVRs returns the GVR strings tracked by both old and next whose
+// selectors differ between them.
+func refilteredGVRs(old, next *config.Config) []string {
+	var out []string
+	changed := func(gvrs []schema.GroupVersionResource, selector func(*config.Config, string) config.Selector) {
+		for _, gvr := range gvrs {
+			gvrStr := GVRString(gvr)
+			if selector(old, gvrStr) != selector(next, gvrStr) {
+				out = append(out, gvrStr)
+			}
+		}
+	}
+	changed(next.ClaimGVRs, func(cfg *config.Config, gvr string) config.Selector {
+		return selectors(cfg.ClaimSelector, cfg.ForGVR(gvr))
+	})
+	changed(next.XRGVRs, func(cfg *config.Config, gvr string) config.Selector {
+		return selectors(cfg.XRSelector, cfg.ForGVR(gvr))
+	})
+	changed(next.MRGVRs, func(cfg *config.Config, gvr string) config.Selector {
+		sel := selectors(cfg.MRSelector, cfg.ForGVR(gvr))
+		sel.Label = joinSelectors(cfg.CompositeLabelKey, sel.Label)
+		return sel
+	})
+	return out
+}
+
+// FirstPoll returns a channel that is closed when the first poll cycle has
+// completed and its results are committed to the store.
+func (p *Poller) FirstPoll() <-chan struct{} {
+	return p.firstPoll
+}
+
+// FirstPollSucceeded reports whether the first poll cycle listed every GVR
+// without errors. It is only meaningful once FirstPoll is closed.
+func (p *Poller) FirstPollSucceeded() bool {
+	<-p.firstPoll
+	return p.firstPollOK
+}
+
+// LastSuccess returns the oldest of the last successful lists of the GVRs
+// this replica polls, so that a single GVR failing on every cycle makes the
+// data stale. It is the zero time while one of them has not been listed
+// successfully yet. Without GVRs, the end of the last cycle counts.
+func (p *Poller) LastSuccess() time.Time {
+	p.tracker.mu.Lock()
+	defer p.tracker.mu.Unlock()
+	return p.tracker.oldestSuccess()
+}
+
+// Staleness reports whether the data is stale: whether a GVR this replica
+// polls was last listed successfully longer ago than ReadinessStalePolls of
+// its own poll interval, times AdaptivePollFactor when set. age is how long
+// ago the most overdue GVR was listed; one not listed successfully yet
+// counts from the end of the first cycle, and without GVRs the last cycle
+// counts. It never reports stale when ReadinessStalePolls is zero or before
+// the first cycle completes.
+func (p *Poller) Staleness() (age time.Duration, stale bool) {
+	cfg := p.config()
+	if cfg.ReadinessStalePolls == 0 {
+		return 0, false
+	}
+	p.tracker.mu.Lock()
+	defer p.tracker.mu.Unlock()
+	if p.tracker.cycles == 0 {
+		return 0, false
+	}
+
+	now := time.Now()
+	if len(p.tracker.polled) == 0 {
+		age = now.Sub(p.tracker.lastStart.Add(p.tracker.lastDuration))
+		return age, age > cfg.ReadinessStaleAfter(time.Duration(cfg.PollIntervalSeconds)*time.Second)
+	}
+	var overdue time.Duration
+	for i, key := range p.tracker.polled {
+		last := p.tracker.firstEnd
+		if st, ok := p.tracker.gvrs[key]; ok && !st.LastSuccess.IsZero() {
+			last = st.LastSuccess
+		}
+		limit := cfg.ReadinessStaleAfter(pollInterval(cfg, classInterval(cfg, key.kind), key.gvr))
+		if d := now.Sub(last) - limit; i == 0 || d > overdue {
+			age, overdue = now.Sub(last), d
+		}
+	}
+	return age, overdue > 0
+}
+
+// Run starts the polling loop. It blocks until ctx is cancelled. Each GVR
+// is polled on its own schedule: a cycle starts when the earliest GVR is
+// due, lists only the GVRs that are due and commits them. Persistence runs
+// apart, once per global poll interval.
+func (p *Poller) Run(ctx context.Context) {
 	// Run an initial poll immediately.
-	p.poll(ctx)
+	p.firstPollOK = p.poll(ctx)
+	close(p.firstPoll)
+
+	timer := time.NewTimer(time.Until(p.nextCycle))
+	defer timer.Stop()
+	persist := time.NewTicker(p.globalInterval())
+	defer persist.Stop()
 
 	for {
 		select {
 		case <-ctx.Done():
 			slog.Info("poller shutting down")
 			return
-		case <-ticker.C:
+		case <-timer.C:
+			p.pollDue(ctx)
+			timer.Reset(time.Until(p.nextCycle))
+		case <-persist.C:
+			p.persist(ctx)
+		case cfg := <-p.reconfigure:
+			p.applyConfig(cfg)
+			slog.Info("poller reconfigured",
+				"claim_gvrs", len(cfg.ClaimGVRs),
+				"xr_gvrs", len(cfg.XRGVRs),
+				"mr_gvrs", len(cfg.MRGVRs),
+				"poll_interval_seconds", cfg.PollIntervalSeconds,
+			)
 			p.poll(ctx)
+			timer.Reset(time.Until(p.nextCycle))
+			persist.Reset(p.globalInterval())
 		}
 	}
 }
 
-// poll executes a single polling cycle: list all configured GVRs and update the store.
-func (p *Poller) poll(ctx context.Context) {
+// globalInterval returns the global poll interval, which paces
+// persistence.
+func (p *Poller) globalInterval() time.Duration {
+	return time.Duration(p.cfg.PollIntervalSeconds) * time.Second
+}
+
+// poll executes a polling cycle and persists its result at once, for the
+// first cycle and after a reconfiguration. It reports whether the GVRs
+// that were due were all listed without errors.
+func (p *Poller) poll(ctx context.Context) bool {
+	ok := p.pollDue(ctx)
+	p.persist(ctx)
+	return ok
+}
+
+// owned returns the configured GVRs this replica polls, by class: all of
+// them, or in sharded polling its share among members.
+func (p *Poller) owned() (claims, xrs, mrs []schema.GroupVersionResource, members []string) {
+	claims, xrs, mrs = p.cfg.ClaimGVRs, p.cfg.XRGVRs, p.cfg.MRGVRs
+	if p.shards == nil {
+		return claims, xrs, mrs, nil
+	}
+	members = p.shards.Members()
+	return p.shards.owned(members, claims), p.shards.owned(members, xrs), p.shards.owned(members, mrs), members
+}
+
+// pollDue lists the GVRs that are due and commits them to the store. All
+// writes are staged in a new store generation that is committed only after
+// enrichment, so readers never observe a half-updated inventory. It reports
+// whether the GVRs were all listed without errors.
+func (p *Poller) pollDue(ctx context.Context) bool {
 	slog.Debug("polling cycle started")
 	start := time.Now()
+	p.tracker.startCycle(start)
 
 	var hadErrors bool
 
+	if p.cfg.NamespaceSelector != "" {
+		if err := p.selectNamespaces(ctx); err != nil {
+			if p.nsSelected {
+				slog.Error("failed to resolve namespace selector, keeping the previous namespaces",
+					"selector", p.cfg.NamespaceSelector, "error", err)
+			} else {
+				slog.Error("failed to resolve namespace selector, skipping the GVRs listed in the selected namespaces",
+					"selector", p.cfg.NamespaceSelector, "error", err)
+			}
+			hadErrors = true
+			metrics.PollErrors.WithLabelValues(GVRString(namespaceGVR)).Inc()
+		}
+	}
+
+	// In sharded polling, only the GVRs this replica owns are listed.
+	ownedClaims, ownedXRs, ownedMRs, members := p.owned()
+	p.tracker.setPolled(ownedClaims, ownedXRs, ownedMRs)
+	if p.shards != nil {
+		ownedCount := len(ownedClaims) + len(ownedXRs) + len(ownedMRs)
+		p.tracker.setShard(p.shards.Identity(), members, ownedCount)
+		metrics.ShardOwnedGVRs.Set(float64(ownedCount))
+	}
+
+	xrGVRs := p.due(p.cfg.XRPollIntervalSeconds, p.selectable(ownedXRs), start)
+	claimGVRs := p.due(p.cfg.ClaimPollIntervalSeconds, p.selectable(ownedClaims), start)
+	mrGVRs := p.due(p.cfg.MRPollIntervalSeconds, p.selectable(ownedMRs), start)
+
+	// A cycle with nothing to list or purge leaves the store as it is.
+	changed := len(xrGVRs)+len(claimGVRs)+len(mrGVRs) > 0 ||
+		len(p.removed.claims)+len(p.removed.xrs)+len(p.removed.mrs) > 0
+	if changed {
+		p.store.BeginGeneration()
+	}
+
+	// Purge the GVRs dropped by Reconfigure. Their objects were not deleted,
+	// so they get no tombstones.
+	for _, gvr := range p.removed.claims {
+		p.store.PurgeClaims(gvr, nil)
+	}
+	for _, gvr := range p.removed.xrs {
+		p.store.PurgeXRs(gvr, nil)
+	}
+	for _, gvr := range p.removed.mrs {
+		p.store.PurgeMRs(gvr, nil)
+	}
+	p.removed = removedGVRs{}
+
 	// Poll XRs first so composition data is available for claim enrichment.
-	for _, gvr := range p.cfg.XRGVRs {
+	for _, gvr := range xrGVRs {
 		if err := p.pollXRs(ctx, gvr); err != nil {
 			hadErrors = true
 			metrics.PollErrors.WithLabelValues(GVRString(gvr)).Inc()
 		}
 	}
 
-	for _, gvr := range p.cfg.ClaimGVRs {
+	for _, gvr := range claimGVRs {
 		if err := p.pollClaims(ctx, gvr); err != nil {
 			hadErrors = true
 			metrics.PollErrors.WithLabelValues(GVRString(gvr)).Inc()
@@ -87,55 +420,353 @@ func (p *Poller) poll(ctx context.Context) {
 	// MR polling is fan-out: there can be 1000+ GVRs (one per provider resource
 	// type). Sequential listing would take minutes; a bounded worker pool keeps
 	// it to seconds without flooding the API server.
-	if mrErrors := p.pollMRsConcurrent(ctx); mrErrors {
+	if mrErrors := p.pollMRsConcurrent(ctx, mrGVRs); mrErrors {
 		hadErrors = true
 	}
 
-	// Enrich claims with composition data from XRs, XRs with claim data from claims,
-	// and MRs with claim data from XRs.
+	if changed {
+		gen := p.commit()
+		p.unpersisted = true
+		slog.Info("polling cycle complete",
+			"claim_gvrs", len(claimGVRs),
+			"xr_gvrs", len(xrGVRs),
+			"mr_gvrs", len(mrGVRs),
+			"errors", hadErrors,
+			"generation", gen.Number,
+		)
+	}
+	metrics.PollDuration.Observe(time.Since(start).Seconds())
+
+	now := time.Now()
+	p.tracker.endCycle(now)
+	if last := p.LastSuccess(); !last.IsZero() {
+		metrics.LastSuccessfulPoll.Set(float64(last.Unix()))
+	}
+
+	// The next cycle starts when the earliest GVR is due, or after the
+	// global interval when no GVR is tracked.
+	if next, ok := p.schedule.earliest(slices.Concat(ownedClaims, ownedXRs, ownedMRs)); ok {
+		p.nextCycle = next
+	} else {
+		p.nextCycle = now.Add(p.globalInterval())
+	}
+	return !hadErrors
+}
+
+// commit enriches the staged generation and commits it: claims with
+// composition data from XRs, XRs with claim data from claims, and MRs with
+// claim data from XRs. It updates the self-monitoring gauges.
+func (p *Poller) commit() store.GenerationInfo {
 	p.store.EnrichClaimCompositions()
 	p.store.EnrichXRClaims()
 	p.store.EnrichMRClaims()
 
-	// Only persist if the entire cycle succeeded. Persisting a partial
-	// snapshot could overwrite a valid one with incomplete data.
-	if hadErrors {
-		slog.Warn("skipping persistence due to polling errors")
-	} else if ps, ok := p.store.(store.PersistentStore); ok {
+	gen := p.store.CommitGeneration()
+	metrics.StoreGeneration.Set(float64(gen.Number))
+
+	view := p.store.View()
+	metrics.StoreClaims.Set(float64(view.ClaimCount()))
+	metrics.StoreXRs.Set(float64(view.XRCount()))
+	metrics.StoreMRs.Set(float64(view.MRCount()))
+	return gen
+}
+
+// persist writes the store to its backend, apart from the cycles so that
+// polling GVRs more often than the global interval does not add writes. In
+// sharded polling it first merges the peers' shards into a new generation.
+func (p *Poller) persist(ctx context.Context) {
+	ownedClaims, ownedXRs, ownedMRs, members := p.owned()
+
+	// Only the GVRs whose last list succeeded are complete. In sharded
+	// polling the replica persists those of its own GVRs as its shard, and
+	// peers keep their previous data for the others. Otherwise, persisting
+	// a partial snapshot could overwrite a valid one with incomplete data,
+	// so it waits until every GVR is listed.
+	listed := slices.Concat(
+		p.tracker.listed("claim", ownedClaims),
+		p.tracker.listed("xr", ownedXRs),
+		p.tracker.listed("mr", ownedMRs),
+	)
+	if ss, ok := p.store.(store.ShardStore); ok && p.shards != nil {
+		p.store.BeginGeneration()
+		p.mergeShards(ctx, members)
+		gen := p.commit()
+		slog.Debug("merged shards", "members", len(members), "generation", gen.Number)
+
 		persistStart := time.Now()
-		if err := ps.Persist(ctx); err != nil {
-			slog.Error("failed to persist store snapshot", "error", err)
+		if err := ss.PersistShard(ctx, listed); err != nil {
+			slog.Error("failed to persist shard snapshot", "error", err)
 		} else {
 			metrics.S3PersistDuration.Observe(time.Since(persistStart).Seconds())
 		}
+		p.pruneShards(ctx, ss, members)
+		return
 	}
 
-	// Update self-monitoring gauges.
-	claimCount := p.store.ClaimCount()
-	xrCount := p.store.XRCount()
-	mrCount := p.store.MRCount()
-	metrics.StoreClaims.Set(float64(claimCount))
-	metrics.StoreXRs.Set(float64(xrCount))
-	metrics.StoreMRs.Set(float64(mrCount))
-	metrics.PollDuration.Observe(time.Since(start).Seconds())
+	ps, ok := p.store.(store.PersistentStore)
+	if !ok || !p.unpersisted {
+		return
+	}
+	if failed := len(ownedClaims) + len(ownedXRs) + len(ownedMRs) - len(listed); failed > 0 {
+		slog.Warn("skipping persistence due to polling errors", "failed_gvrs", failed)
+		return
+	}
+	persistStart := time.Now()
+	if err := ps.Persist(ctx); err != nil {
+		slog.Error("failed to persist store snapshot", "error", err)
+		return
+	}
+	metrics.S3PersistDuration.Observe(time.Since(persistStart).Seconds())
+	p.unpersisted = false
+}
 
-	slog.Info("polling cycle complete",
-		"claims", claimCount,
-		"xrs", xrCount,
-		"mrs", mrCount,
-	)
+// mergeShards copies the GVRs owned by the other members into the staged
+// generation, from the shards they persisted. A GVR keeps its previous data
+// while its owner's shard cannot be read or does not list it yet, for
+// example just after ownership moved.
+func (p *Poller) mergeShards(ctx context.Context, members []string) {
+	ss, ok := p.store.(store.ShardStore)
+	if !ok {
+		return
+	}
+	for _, member := range members {
+		if member == p.shards.Identity() {
+			continue
+		}
+		snap, found, err := ss.LoadShard(ctx, member)
+		if err != nil {
+			slog.Warn("failed to load shard, keeping its previous data", "shard", member, "error", err)
+			metrics.ShardLoadErrors.Inc()
+			continue
+		}
+		if !found {
+			slog.Debug("shard not persisted yet", "shard", member)
+			continue
+		}
+
+		var gvrs []string
+		for _, gvr := range gvrStrings(slices.Concat(p.cfg.ClaimGVRs, p.cfg.XRGVRs, p.cfg.MRGVRs)) {
+			if slices.Contains(snap.GVRs, gvr) && shardOwner(members, gvr) == member {
+				gvrs = append(gvrs, gvr)
+			}
+		}
+		ss.MergeShard(snap, gvrs)
+		slog.Debug("merged shard", "shard", member, "gvrs", len(gvrs), "persisted_at", snap.PersistedAt)
+	}
+}
+
+// pruneShards deletes the shards of replicas that left: those that are not
+// members and were not written within a lease duration, so that a replica
+// that joined after members was read keeps its shard. Only the first member
+// prunes, to spare the others the listing.
+func (p *Poller) pruneShards(ctx context.Context, ss store.ShardStore, members []string) {
+	if len(members) == 0 || members[0] != p.shards.Identity() {
+		return
+	}
+	objs, err := ss.ListShards(ctx)
+	if err != nil {
+		slog.Warn("failed to list shards for pruning", "error", err)
+		return
+	}
+	cutoff := time.Now().Add(-p.shards.leaseDuration)
+	for _, obj := range objs {
+		if slices.Contains(members, obj.Shard) || obj.LastModified.After(cutoff) {
+			continue
+		}
+		if err := ss.DeleteShard(ctx, obj.Shard); err != nil {
+			slog.Warn("failed to delete the shard of a former member", "shard", obj.Shard, "error", err)
+		}
+	}
+}
+
+// gvrStrings converts GVRs to their GVRString form.
+func gvrStrings(gvrs []schema.GroupVersionResource) []string {
+	out := make([]string, len(gvrs))
+	for i, gvr := range gvrs {
+		out[i] = GVRString(gvr)
+	}
+	return out
+}
+
+// due returns the GVRs that are due at now and schedules their next poll.
+// classSeconds is the poll interval of their resource class, or zero to
+// use the global interval.
+func (p *Poller) due(classSeconds int, gvrs []schema.GroupVersionResource, now time.Time) []schema.GroupVersionResource {
+	return p.schedule.due(gvrs, now, func(gvr string) time.Duration {
+		return pollInterval(p.cfg, classSeconds, gvr)
+	}, p.cfg.PollJitter)
+}
+
+// pollInterval returns the configured poll interval of gvr in cfg: its
+// per-GVR override, else the interval of its resource class, else the
+// global interval.
+func pollInterval(cfg *config.Config, classSeconds int, gvr string) time.Duration {
+	if r := cfg.Resources[gvr]; r.PollIntervalSeconds > 0 {
+		return time.Duration(r.PollIntervalSeconds) * time.Second
+	}
+	if classSeconds > 0 {
+		return time.Duration(classSeconds) * time.Second
+	}
+	return time.Duration(cfg.PollIntervalSeconds) * time.Second
+}
+
+// classInterval returns the poll interval setting of a resource class:
+// "claim", "xr" or "mr".
+func classInterval(cfg *config.Config, kind string) int {
+	switch kind {
+	case "claim":
+		return cfg.ClaimPollIntervalSeconds
+	case "xr":
+		return cfg.XRPollIntervalSeconds
+	default:
+		return cfg.MRPollIntervalSeconds
+	}
+}
+
+// selectNamespaces resolves the namespace selector to the namespaces to
+// poll. When the selection changes, every GVR is due this cycle, so objects
+// in namespaces that left the scope are purged from the store at once.
+func (p *Poller) selectNamespaces(ctx context.Context) error {
+	var names []string
+	var continueToken string
+	for {
+		list, err := p.client.Resource(namespaceGVR).List(ctx, metav1.ListOptions{
+			Limit:         500,
+			Continue:      continueToken,
+			LabelSelector: p.cfg.NamespaceSelector,
+		})
+		if err != nil {
+			return err
+		}
+		for _, item := range list.Items {
+			name := item.GetName()
+			if len(p.cfg.Namespaces) > 0 && !slices.Contains(p.cfg.Namespaces, name) {
+				continue
+			}
+			names = append(names, name)
+		}
+		continueToken = list.GetContinue()
+		if continueToken == "" {
+			break
+		}
+	}
+	slices.Sort(names)
+
+	if p.nsSelected && slices.Equal(names, p.selectedNS) {
+		return nil
+	}
+	var added, removed []string
+	for _, ns := range names {
+		if !slices.Contains(p.selectedNS, ns) {
+			added = append(added, ns)
+		}
+	}
+	for _, ns := range p.selectedNS {
+		if !slices.Contains(names, ns) {
+			removed = append(removed, ns)
+		}
+	}
+	slog.Info("selected namespaces changed", "count", len(names), "added", added, "removed", removed)
+
+	p.selectedNS = names
+	p.nsSelected = true
+	p.tracker.setNamespaces(names)
+	p.schedule.reset()
+	return nil
+}
+
+// selectable returns the GVRs that can be listed: until the namespace
+// selector has been resolved, those listed in the selected namespaces are
+// left out, so that their objects keep their previous state rather than
+// being dropped for lack of namespaces.
+func (p *Poller) selectable(gvrs []schema.GroupVersionResource) []schema.GroupVersionResource {
+	if p.cfg.NamespaceSelector == "" || p.nsSelected {
+		return gvrs
+	}
+	return slices.DeleteFunc(slices.Clone(gvrs), func(gvr schema.GroupVersionResource) bool {
+		return len(p.cfg.Resources[GVRString(gvr)].Namespaces) == 0
+	})
+}
+
+// namespaces returns the namespaces to list gvr in, or all=true to list it
+// across all namespaces. A GVR's own namespaces take precedence over the
+// namespace selector, which takes precedence over the namespace scope.
+func (p *Poller) namespaces(gvr string) (namespaces []string, all bool) {
+	if r, ok := p.cfg.Resources[gvr]; ok && len(r.Namespaces) > 0 {
+		return r.Namespaces, false
+	}
+	if p.cfg.NamespaceSelector != "" {
+		return p.selectedNS, false
+	}
+	return p.cfg.Namespaces, len(p.cfg.Namespaces) == 0
+}
+
+// convertConfig returns the config used to convert objects polled with
+// settings s: the global config, or a copy carrying the GVR's own
+// ownership annotation keys.
+func (p *Poller) convertConfig(s config.GVRSettings) *config.Config {
+	if s.CreatorAnnotationKey == p.cfg.CreatorAnnotationKey && s.TeamAnnotationKey == p.cfg.TeamAnnotationKey {
+		return p.cfg
+	}
+	cfg := *p.cfg
+	cfg.CreatorAnnotationKey = s.CreatorAnnotationKey
+	cfg.TeamAnnotationKey = s.TeamAnnotationKey
+	return &cfg
+}
+
+// keptNamespaces returns the namespaces whose objects of gvr survive a list
+// that succeeded in listed, before the listed objects replace theirs.
+// Objects in namespaces that left the scope are purged. After a selector
+// change, so are those in listed: the list adds back the ones that still
+// match, and those that do not are dropped without a tombstone.
+func (p *Poller) keptNamespaces(gvr string, namespaces, listed []string) []string {
+	if !p.refiltered.has(gvr) {
+		return namespaces
+	}
+	return slices.DeleteFunc(slices.Clone(namespaces), func(ns string) bool {
+		return slices.Contains(listed, ns)
+	})
+}
+
+// selectors returns the selectors for listing a GVR: those of its resource
+// class combined with its own.
+func selectors(class config.Selector, s config.GVRSettings) config.Selector {
+	return config.Selector{
+		Label: joinSelectors(class.Label, s.LabelSelector),
+		Field: joinSelectors(class.Field, s.FieldSelector),
+	}
+}
+
+// joinSelectors combines selectors so that objects must match all of them.
+func joinSelectors(selectors ...string) string {
+	parts := make([]string, 0, len(selectors))
+	for _, s := range selectors {
+		if s != "" {
+			parts = append(parts, s)
+		}
+	}
+	return strings.Join(parts, ",")
 }
 
 // pollClaims lists all claims for a given GVR and updates the store.
-func (p *Poller) pollClaims(ctx context.Context, gvr schema.GroupVersionResource) error {
+func (p *Poller) pollClaims(ctx context.Context, gvr schema.GroupVersionResource) (err error) {
 	gvrStr := GVRString(gvr)
-	namespaces := p.cfg.Namespaces
+	settings := p.cfg.ForGVR(gvrStr)
+	namespaces, all := p.namespaces(gvrStr)
 
 	var allClaims []store.ClaimInfo
+	digest := newListDigest()
+	defer func() {
+		p.tracker.record("claim", gvrStr, len(allClaims), err)
+		if err == nil {
+			p.schedule.observe(gvrStr, digest.sum(), p.cfg.AdaptivePollFactor, p.cfg.PollJitter)
+			p.refiltered.remove(gvrStr)
+		}
+	}()
 
-	if len(namespaces) == 0 {
+	if all {
 		// List across all namespaces.
-		claims, err := p.listClaims(ctx, gvr, "")
+		claims, err := p.listClaims(ctx, gvr, "", settings, digest)
 		if err != nil {
 			slog.Error("failed to list claims", "gvr", gvrStr, "error", err)
 			return err
@@ -143,38 +774,56 @@ func (p *Poller) pollClaims(ctx context.Context, gvr schema.GroupVersionResource
 		allClaims = claims
 	} else {
 		var errs []error
+		var listed []string
 		for _, ns := range namespaces {
-			claims, err := p.listClaims(ctx, gvr, ns)
+			claims, err := p.listClaims(ctx, gvr, ns, settings, digest)
 			if err != nil {
 				slog.Error("failed to list claims", "gvr", gvrStr, "namespace", ns, "error", err)
 				errs = append(errs, err)
 				continue
 			}
+			listed = append(listed, ns)
 			allClaims = append(allClaims, claims...)
 		}
+		// Claims in namespaces that left the scope are purged; those in
+		// namespaces that failed to list keep their previous state.
+		p.store.PurgeClaims(gvrStr, p.keptNamespaces(gvrStr, namespaces, listed))
+		p.store.ReplaceClaimsIn(gvrStr, listed, allClaims)
 		if len(errs) > 0 {
-			// Still update the store with whatever we got, but report errors.
-			p.store.ReplaceClaims(gvrStr, allClaims)
 			slog.Debug("claims partially updated", "gvr", gvrStr, "count", len(allClaims))
 			return errs[0]
 		}
+		slog.Debug("claims updated", "gvr", gvrStr, "count", len(allClaims))
+		return nil
 	}
 
+	if p.refiltered.has(gvrStr) {
+		p.store.PurgeClaims(gvrStr, nil)
+	}
 	p.store.ReplaceClaims(gvrStr, allClaims)
 	slog.Debug("claims updated", "gvr", gvrStr, "count", len(allClaims))
 	return nil
 }
 
 // pollXRs lists all XRs for a given GVR and updates the store.
-func (p *Poller) pollXRs(ctx context.Context, gvr schema.GroupVersionResource) error {
+func (p *Poller) pollXRs(ctx context.Context, gvr schema.GroupVersionResource) (err error) {
 	gvrStr := GVRString(gvr)
+	settings := p.cfg.ForGVR(gvrStr)
 
 	// XRs are typically cluster-scoped, but respect namespace config if set.
-	namespaces := p.cfg.Namespaces
+	namespaces, all := p.namespaces(gvrStr)
 	var allXRs []store.XRInfo
+	digest := newListDigest()
+	defer func() {
+		p.tracker.record("xr", gvrStr, len(allXRs), err)
+		if err == nil {
+			p.schedule.observe(gvrStr, digest.sum(), p.cfg.AdaptivePollFactor, p.cfg.PollJitter)
+			p.refiltered.remove(gvrStr)
+		}
+	}()
 
-	if len(namespaces) == 0 {
-		xrs, err := p.listXRs(ctx, gvr, "")
+	if all {
+		xrs, err := p.listXRs(ctx, gvr, "", settings, digest)
 		if err != nil {
 			slog.Error("failed to list XRs", "gvr", gvrStr, "error", err)
 			return err
@@ -182,22 +831,30 @@ func (p *Poller) pollXRs(ctx context.Context, gvr schema.GroupVersionResource) e
 		allXRs = xrs
 	} else {
 		var errs []error
+		var listed []string
 		for _, ns := range namespaces {
-			xrs, err := p.listXRs(ctx, gvr, ns)
+			xrs, err := p.listXRs(ctx, gvr, ns, settings, digest)
 			if err != nil {
 				slog.Error("failed to list XRs", "gvr", gvrStr, "namespace", ns, "error", err)
 				errs = append(errs, err)
 				continue
 			}
+			listed = append(listed, ns)
 			allXRs = append(allXRs, xrs...)
 		}
+		p.store.PurgeXRs(gvrStr, p.keptNamespaces(gvrStr, namespaces, listed))
+		p.store.ReplaceXRsIn(gvrStr, listed, allXRs)
 		if len(errs) > 0 {
-			p.store.ReplaceXRs(gvrStr, allXRs)
 			slog.Debug("XRs partially updated", "gvr", gvrStr, "count", len(allXRs))
 			return errs[0]
 		}
+		slog.Debug("XRs updated", "gvr", gvrStr, "count", len(allXRs))
+		return nil
 	}
 
+	if p.refiltered.has(gvrStr) {
+		p.store.PurgeXRs(gvrStr, nil)
+	}
 	p.store.ReplaceXRs(gvrStr, allXRs)
 	slog.Debug("XRs updated", "gvr", gvrStr, "count", len(allXRs))
 	return nil
@@ -205,8 +862,8 @@ func (p *Poller) pollXRs(ctx context.Context, gvr schema.GroupVersionResource) e
 
 // pollMRsConcurrent fans out MR polling across all configured GVRs using a
 // bounded worker pool. It returns true if any GVR produced an error.
-func (p *Poller) pollMRsConcurrent(ctx context.Context) (hadErrors bool) {
-	if len(p.cfg.MRGVRs) == 0 {
+func (p *Poller) pollMRsConcurrent(ctx context.Context, gvrs []schema.GroupVersionResource) (hadErrors bool) {
+	if len(gvrs) == 0 {
 		return false
 	}
 
@@ -215,7 +872,7 @@ func (p *Poller) pollMRsConcurrent(ctx context.Context) (hadErrors bool) {
 	g, ctx := errgroup.WithContext(ctx)
 	g.SetLimit(mrPollConcurrency)
 
-	for _, gvr := range p.cfg.MRGVRs {
+	for _, gvr := range gvrs {
 		gvr := gvr // capture loop variable (pre-Go 1.22)
 		g.Go(func() error {
 			if err := p.pollMRs(ctx, gvr); err != nil {
@@ -234,15 +891,24 @@ func (p *Poller) pollMRsConcurrent(ctx context.Context) (hadErrors bool) {
 }
 
 // pollMRs lists claim-linked MRs for a given GVR and updates the store.
-func (p *Poller) pollMRs(ctx context.Context, gvr schema.GroupVersionResource) error {
+func (p *Poller) pollMRs(ctx context.Context, gvr schema.GroupVersionResource) (err error) {
 	gvrStr := GVRString(gvr)
 	provider := p.cfg.MRProviderNames[gvrStr]
+	settings := p.cfg.ForGVR(gvrStr)
 
-	namespaces := p.cfg.Namespaces
+	namespaces, all := p.namespaces(gvrStr)
 	var allMRs []store.MRInfo
+	digest := newListDigest()
+	defer func() {
+		p.tracker.record("mr", gvrStr, len(allMRs), err)
+		if err == nil {
+			p.schedule.observe(gvrStr, digest.sum(), p.cfg.AdaptivePollFactor, p.cfg.PollJitter)
+			p.refiltered.remove(gvrStr)
+		}
+	}()
 
-	if len(namespaces) == 0 {
-		mrs, err := p.listMRs(ctx, gvr, "", provider)
+	if all {
+		mrs, err := p.listMRs(ctx, gvr, "", provider, settings, digest)
 		if err != nil {
 			slog.Error("failed to list MRs", "gvr", gvrStr, "error", err)
 			return err
@@ -250,22 +916,30 @@ func (p *Poller) pollMRs(ctx context.Context, gvr schema.GroupVersionResource) e
 		allMRs = mrs
 	} else {
 		var errs []error
+		var listed []string
 		for _, ns := range namespaces {
-			mrs, err := p.listMRs(ctx, gvr, ns, provider)
+			mrs, err := p.listMRs(ctx, gvr, ns, provider, settings, digest)
 			if err != nil {
 				slog.Error("failed to list MRs", "gvr", gvrStr, "namespace", ns, "error", err)
 				errs = append(errs, err)
 				continue
 			}
+			listed = append(listed, ns)
 			allMRs = append(allMRs, mrs...)
 		}
+		p.store.PurgeMRs(gvrStr, p.keptNamespaces(gvrStr, namespaces, listed))
+		p.store.ReplaceMRsIn(gvrStr, listed, allMRs)
 		if len(errs) > 0 {
-			p.store.ReplaceMRs(gvrStr, allMRs)
 			slog.Debug("MRs partially updated", "gvr", gvrStr, "count", len(allMRs))
 			return errs[0]
 		}
+		slog.Debug("MRs updated", "gvr", gvrStr, "count", len(allMRs))
+		return nil
 	}
 
+	if p.refiltered.has(gvrStr) {
+		p.store.PurgeMRs(gvrStr, nil)
+	}
 	p.store.ReplaceMRs(gvrStr, allMRs)
 	slog.Debug("MRs updated", "gvr", gvrStr, "count", len(allMRs))
 	return nil
@@ -273,15 +947,9 @@ func (p *Poller) pollMRs(ctx context.Context, gvr schema.GroupVersionResource) e
 
 // listMRs lists MRs for a specific GVR and optional namespace.
 // Only resources with the composite label (claim chain) are returned.
-func (p *Poller) listMRs(ctx context.Context, gvr schema.GroupVersionResource, namespace, provider string) ([]store.MRInfo, error) {
-	var ri dynamic.ResourceInterface
-	if namespace == "" {
-		ri = p.client.Resource(gvr)
-	} else {
-		ri = p.client.Resource(gvr).Namespace(namespace)
-	}
-
-	labelSelector := p.cfg.CompositeLabelKey
+func (p *Poller) listMRs(ctx context.Context, gvr schema.GroupVersionResource, namespace, provider string, settings config.GVRSettings, digest *listDigest) ([]store.MRInfo, error) {
+	sel := selectors(p.cfg.MRSelector, settings)
+	cfg := p.convertConfig(settings)
 
 	var mrs []store.MRInfo
 	var continueToken string
@@ -289,15 +957,17 @@ func (p *Poller) listMRs(ctx context.Context, gvr schema.GroupVersionResource, n
 		opts := metav1.ListOptions{
 			Limit:         500,
 			Continue:      continueToken,
-			LabelSelector: labelSelector,
+			LabelSelector: joinSelectors(p.cfg.CompositeLabelKey, sel.Label),
+			FieldSelector: sel.Field,
 		}
-		list, err := ri.List(ctx, opts)
+		list, err := p.list(ctx, "mrs", gvr, namespace, opts)
 		if err != nil {
 			return nil, err
 		}
 
 		for _, item := range list.Items {
-			mr := UnstructuredToMR(item, gvr, p.cfg, provider)
+			digest.add(item)
+			mr := UnstructuredToMR(item, gvr, cfg, provider)
 			if mr.XRName == "" {
 				continue
 			}
@@ -315,28 +985,26 @@ func (p *Poller) listMRs(ctx context.Context, gvr schema.GroupVersionResource, n
 // listClaims lists claims for a specific GVR and optional namespace.
 // If namespace is empty, lists across all namespaces.
 // Uses server-side pagination to avoid unbounded response sizes.
-func (p *Poller) listClaims(ctx context.Context, gvr schema.GroupVersionResource, namespace string) ([]store.ClaimInfo, error) {
-	var ri dynamic.ResourceInterface
-	if namespace == "" {
-		ri = p.client.Resource(gvr)
-	} else {
-		ri = p.client.Resource(gvr).Namespace(namespace)
-	}
-
+func (p *Poller) listClaims(ctx context.Context, gvr schema.GroupVersionResource, namespace string, settings config.GVRSettings, digest *listDigest) ([]store.ClaimInfo, error) {
+	sel := selectors(p.cfg.ClaimSelector, settings)
+	cfg := p.convertConfig(settings)
 	var claims []store.ClaimInfo
 	var continueToken string
 	for {
 		opts := metav1.ListOptions{
-			Limit:    500,
-			Continue: continueToken,
+			Limit:         500,
+			Continue:      continueToken,
+			LabelSelector: sel.Label,
+			FieldSelector: sel.Field,
 		}
-		list, err := ri.List(ctx, opts)
+		list, err := p.list(ctx, "claims", gvr, namespace, opts)
 		if err != nil {
 			return nil, err
 		}
 
 		for _, item := range list.Items {
-			claims = append(claims, UnstructuredToClaim(item, gvr, p.cfg))
+			digest.add(item)
+			claims = append(claims, UnstructuredToClaim(item, gvr, cfg))
 		}
 
 		continueToken = list.GetContinue()
@@ -349,28 +1017,26 @@ func (p *Poller) listClaims(ctx context.Context, gvr schema.GroupVersionResource
 
 // listXRs lists XRs for a specific GVR and optional namespace.
 // Uses server-side pagination to avoid unbounded response sizes.
-func (p *Poller) listXRs(ctx context.Context, gvr schema.GroupVersionResource, namespace string) ([]store.XRInfo, error) {
-	var ri dynamic.ResourceInterface
-	if namespace == "" {
-		ri = p.client.Resource(gvr)
-	} else {
-		ri = p.client.Resource(gvr).Namespace(namespace)
-	}
-
+func (p *Poller) listXRs(ctx context.Context, gvr schema.GroupVersionResource, namespace string, settings config.GVRSettings, digest *listDigest) ([]store.XRInfo, error) {
+	sel := selectors(p.cfg.XRSelector, settings)
+	cfg := p.convertConfig(settings)
 	var xrs []store.XRInfo
 	var continueToken string
 	for {
 		opts := metav1.ListOptions{
-			Limit:    500,
-			Continue: continueToken,
+			Limit:         500,
+			Continue:      continueToken,
+			LabelSelector: sel.Label,
+			FieldSelector: sel.Field,
 		}
-		list, err := ri.List(ctx, opts)
+		list, err := p.list(ctx, "xrs", gvr, namespace, opts)
 		if err != nil {
 			return nil, err
 		}
 
 		for _, item := range list.Items {
-			xrs = append(xrs, UnstructuredToXR(item, gvr, p.cfg))
+			digest.add(item)
+			xrs = append(xrs, UnstructuredToXR(item, gvr, cfg))
 		}
 
 		continueToken = list.GetContinue()
@@ -380,3 +1046,15 @@ func (p *Poller) listXRs(ctx context.Context, gvr schema.GroupVersionResource, n
 	}
 	return xrs, nil
 }
+
+// list lists one page of gvr in namespace, or across all namespaces when
+// namespace is empty. Resource classes in TableListing are listed as Tables.
+func (p *Poller) list(ctx context.Context, class string, gvr schema.GroupVersionResource, namespace string, opts metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+	if p.tables != nil && slices.Contains(p.cfg.TableListing, class) {
+		return p.tables.List(ctx, gvr, namespace, opts)
+	}
+	if namespace == "" {
+		return p.client.Resource(gvr).List(ctx, opts)
+	}
+	return p.client.Resource(gvr).Namespace(namespace).List(ctx, opts)
+}
@@ -2,7 +2,9 @@ package kube
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
 	"testing"
 	"time"
 
@@ -11,6 +13,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
 
 	"github.com/kanzifucius/xp-tracker/pkg/config"
 	"github.com/kanzifucius/xp-tracker/pkg/store"
@@ -128,11 +131,11 @@ func TestPoller_PollClaims(t *testing.T) {
 	poller.poll(ctx)
 
 	// Verify claims.
-	if s.ClaimCount() != 2 {
-		t.Fatalf("expected 2 claims, got %d", s.ClaimCount())
+	if s.View().ClaimCount() != 2 {
+		t.Fatalf("expected 2 claims, got %d", s.View().ClaimCount())
 	}
 
-	claims := s.SnapshotClaims()
+	claims := s.View().Claims()
 	byName := make(map[string]store.ClaimInfo)
 	for _, c := range claims {
 		byName[c.Name] = c
@@ -161,11 +164,11 @@ func TestPoller_PollClaims(t *testing.T) {
 	}
 
 	// Verify XRs.
-	if s.XRCount() != 1 {
-		t.Fatalf("expected 1 XR, got %d", s.XRCount())
+	if s.View().XRCount() != 1 {
+		t.Fatalf("expected 1 XR, got %d", s.View().XRCount())
 	}
 
-	xrs := s.SnapshotXRs()
+	xrs := s.View().XRs()
 	if xrs[0].Composition != "prod-postgres" {
 		t.Errorf("XR composition: got %q", xrs[0].Composition)
 	}
@@ -224,16 +227,112 @@ func TestPoller_NamespaceScoped(t *testing.T) {
 	poller.poll(context.Background())
 
 	// Should only see the claim in ns-a.
-	if s.ClaimCount() != 1 {
-		t.Fatalf("expected 1 claim (ns-a only), got %d", s.ClaimCount())
+	if s.View().ClaimCount() != 1 {
+		t.Fatalf("expected 1 claim (ns-a only), got %d", s.View().ClaimCount())
 	}
 
-	claims := s.SnapshotClaims()
+	claims := s.View().Claims()
 	if claims[0].Namespace != "ns-a" {
 		t.Errorf("expected namespace ns-a, got %q", claims[0].Namespace)
 	}
 }
 
+func TestPoller_PartialListKeepsFailedNamespaces(t *testing.T) {
+	claimGVR := schema.GroupVersionResource{Group: "g", Version: "v1", Resource: "things"}
+	claim := func(ns string) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "g/v1",
+			"kind":       "Thing",
+			"metadata":   map[string]interface{}{"name": "t", "namespace": ns},
+		}}
+	}
+	client := newFakeClient(map[schema.GroupVersionResource]string{claimGVR: "ThingList"}, claim("ns-a"), claim("ns-b"))
+	cfg := &config.Config{
+		ClaimGVRs:           []schema.GroupVersionResource{claimGVR},
+		Namespaces:          []string{"ns-a", "ns-b"},
+		PollIntervalSeconds: 30,
+	}
+	s := store.New()
+	s.SetTombstoneRetention(time.Hour)
+	poller := NewPoller(client, cfg, s)
+	poller.poll(context.Background())
+	if s.View().ClaimCount() != 2 {
+		t.Fatalf("expected 2 claims, got %d", s.View().ClaimCount())
+	}
+
+	// ns-b fails to list: its claim is kept rather than recorded as deleted.
+	client.PrependReactor("list", "things", func(a k8stesting.Action) (bool, runtime.Object, error) {
+		if a.GetNamespace() == "ns-b" {
+			return true, nil, errors.New("apiserver unavailable")
+		}
+		return false, nil, nil
+	})
+	poller.schedule.reset()
+	if poller.poll(context.Background()) {
+		t.Error("expected the cycle to report errors")
+	}
+	if s.View().ClaimCount() != 2 || len(s.View().DeletedClaims(store.Query{})) != 0 {
+		t.Errorf("expected both claims kept without tombstones, got %d claims and %d tombstones", s.View().ClaimCount(), len(s.View().DeletedClaims(store.Query{})))
+	}
+
+	// ns-b leaves the scope: its claim is purged without a tombstone.
+	next := *cfg
+	next.Namespaces = []string{"ns-a"}
+	poller.applyConfig(&next)
+	poller.poll(context.Background())
+	if claims := s.View().Claims(); len(claims) != 1 || claims[0].Namespace != "ns-a" {
+		t.Errorf("expected only the claim in ns-a, got %+v", claims)
+	}
+	if len(s.View().DeletedClaims(store.Query{})) != 0 {
+		t.Errorf("expected no tombstones, got %+v", s.View().DeletedClaims(store.Query{}))
+	}
+}
+
+func TestPoller_SelectorChangeLeavesNoTombstones(t *testing.T) {
+	claimGVR := schema.GroupVersionResource{Group: "g", Version: "v1", Resource: "things"}
+	claim := func(name, env string) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "g/v1",
+			"kind":       "Thing",
+			"metadata": map[string]interface{}{
+				"name":      name,
+				"namespace": "ns",
+				"labels":    map[string]interface{}{"env": env},
+			},
+		}}
+	}
+	client := newFakeClient(map[schema.GroupVersionResource]string{claimGVR: "ThingList"}, claim("a", "dev"), claim("b", "prod"))
+	cfg := &config.Config{
+		ClaimGVRs:           []schema.GroupVersionResource{claimGVR},
+		PollIntervalSeconds: 30,
+	}
+	s := store.New()
+	s.SetTombstoneRetention(time.Hour)
+	poller := NewPoller(client, cfg, s)
+	poller.poll(context.Background())
+
+	next := *cfg
+	next.ClaimSelector = config.Selector{Label: "env=dev"}
+	poller.applyConfig(&next)
+	poller.poll(context.Background())
+	if claims := s.View().Claims(); len(claims) != 1 || claims[0].Name != "a" {
+		t.Fatalf("expected only claim a, got %+v", claims)
+	}
+	if len(s.View().DeletedClaims(store.Query{})) != 0 {
+		t.Errorf("expected no tombstone for the filtered claim, got %+v", s.View().DeletedClaims(store.Query{}))
+	}
+
+	// Once the new selector has listed, removals are deletions again.
+	if err := client.Resource(claimGVR).Namespace("ns").Delete(context.Background(), "a", metav1.DeleteOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	poller.schedule.reset()
+	poller.poll(context.Background())
+	if s.View().ClaimCount() != 0 || len(s.View().DeletedClaims(store.Query{})) != 1 {
+		t.Errorf("expected claim a tombstoned, got %d claims and %d tombstones", s.View().ClaimCount(), len(s.View().DeletedClaims(store.Query{})))
+	}
+}
+
 func TestPoller_RunStopsOnCancel(t *testing.T) {
 	claimGVR := schema.GroupVersionResource{Group: "g", Version: "v1", Resource: "things"}
 	xrGVR := schema.GroupVersionResource{Group: "g", Version: "v1", Resource: "xthings"}
@@ -275,6 +374,157 @@ func TestPoller_RunStopsOnCancel(t *testing.T) {
 	}
 }
 
+func TestPoller_FirstPoll(t *testing.T) {
+	claimGVR := schema.GroupVersionResource{Group: "g", Version: "v1", Resource: "things"}
+	xrGVR := schema.GroupVersionResource{Group: "g", Version: "v1", Resource: "xthings"}
+
+	for _, failing := range []bool{false, true} {
+		client := newFakeClient(
+			map[schema.GroupVersionResource]string{
+				claimGVR: "ThingList",
+				xrGVR:    "XThingList",
+			},
+		)
+		if failing {
+			client.PrependReactor("list", "xthings", func(k8stesting.Action) (bool, runtime.Object, error) {
+				return true, nil, errors.New("apiserver unavailable")
+			})
+		}
+
+		cfg := &config.Config{
+			ClaimGVRs:           []schema.GroupVersionResource{claimGVR},
+			XRGVRs:              []schema.GroupVersionResource{xrGVR},
+			CompositionLabelKey: "crossplane.io/composition-name",
+			PollIntervalSeconds: 30,
+		}
+		poller := NewPoller(client, cfg, store.New())
+
+		select {
+		case <-poller.FirstPoll():
+			t.Fatal("FirstPoll closed before Run")
+		default:
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		go poller.Run(ctx)
+		select {
+		case <-poller.FirstPoll():
+		case <-time.After(3 * time.Second):
+			t.Fatal("first poll did not complete")
+		}
+		cancel()
+
+		if got := poller.FirstPollSucceeded(); got == failing {
+			t.Errorf("failing=%v: expected FirstPollSucceeded=%v", failing, !failing)
+		}
+		if got := poller.LastSuccess().IsZero(); got != failing {
+			t.Errorf("failing=%v: unexpected LastSuccess %v", failing, poller.LastSuccess())
+		}
+	}
+}
+
+func TestPoller_LastSuccessOldestGVR(t *testing.T) {
+	claimGVR := schema.GroupVersionResource{Group: "g", Version: "v1", Resource: "things"}
+	xrGVR := schema.GroupVersionResource{Group: "g", Version: "v1", Resource: "xthings"}
+	client := newFakeClient(map[schema.GroupVersionResource]string{
+		claimGVR: "ThingList",
+		xrGVR:    "XThingList",
+	})
+	var failing bool
+	client.PrependReactor("list", "xthings", func(k8stesting.Action) (bool, runtime.Object, error) {
+		if failing {
+			return true, nil, errors.New("apiserver unavailable")
+		}
+		return false, nil, nil
+	})
+	cfg := &config.Config{
+		ClaimGVRs:           []schema.GroupVersionResource{claimGVR},
+		XRGVRs:              []schema.GroupVersionResource{xrGVR},
+		PollIntervalSeconds: 30,
+	}
+	poller := NewPoller(client, cfg, store.New())
+	ctx := context.Background()
+
+	poller.poll(ctx)
+	first := poller.LastSuccess()
+	if first.IsZero() {
+		t.Fatal("expected a last success after listing every GVR")
+	}
+
+	// The claims keep succeeding, but the XRs fail: the data is as old as
+	// the XRs' last success.
+	failing = true
+	time.Sleep(10 * time.Millisecond)
+	poller.schedule.reset()
+	poller.poll(ctx)
+	st := poller.Status()
+	if !st.Claims[0].LastSuccess.After(first) {
+		t.Errorf("expected the claims to succeed again, got %v", st.Claims[0].LastSuccess)
+	}
+	if got := poller.LastSuccess(); !got.Equal(st.XRs[0].LastSuccess) || !got.Equal(st.LastSuccess) {
+		t.Errorf("expected the XRs' last success %v, got %v (status %v)", st.XRs[0].LastSuccess, got, st.LastSuccess)
+	}
+}
+
+func TestPoller_StalenessPerGVR(t *testing.T) {
+	claimGVR := schema.GroupVersionResource{Group: "g", Version: "v1", Resource: "things"}
+	mrGVR := schema.GroupVersionResource{Group: "aws", Version: "v1", Resource: "buckets"}
+	client := newFakeClient(map[schema.GroupVersionResource]string{
+		claimGVR: "ThingList",
+		mrGVR:    "BucketList",
+	})
+	cfg := &config.Config{
+		ClaimGVRs:           []schema.GroupVersionResource{claimGVR},
+		MRGVRs:              []schema.GroupVersionResource{mrGVR},
+		CompositeLabelKey:   "crossplane.io/composite",
+		PollIntervalSeconds: 30,
+		ReadinessStalePolls: 5,
+		Resources: map[string]config.ResourceConfig{
+			"aws/v1/buckets": {GVR: "aws/v1/buckets", PollIntervalSeconds: 600},
+		},
+	}
+	poller := NewPoller(client, cfg, store.New())
+	poller.poll(context.Background())
+
+	if _, stale := poller.Staleness(); stale {
+		t.Fatal("expected fresh data right after a cycle")
+	}
+
+	setLastSuccess := func(kind, gvr string, ago time.Duration) {
+		poller.tracker.mu.Lock()
+		defer poller.tracker.mu.Unlock()
+		poller.tracker.gvrs[statusKey{kind: kind, gvr: gvr}].LastSuccess = time.Now().Add(-ago)
+	}
+
+	// 10 minutes is within 5 polls of the MRs' own 10 minute interval,
+	// though well over 5 global intervals.
+	setLastSuccess("mr", "aws/v1/buckets", 10*time.Minute)
+	if _, stale := poller.Staleness(); stale {
+		t.Error("expected the MRs to be old but not stale")
+	}
+
+	// 3 minutes is over 5 polls of the claims' 30 second interval.
+	setLastSuccess("claim", "g/v1/things", 3*time.Minute)
+	if age, stale := poller.Staleness(); !stale || age < 3*time.Minute || age > 4*time.Minute {
+		t.Errorf("expected the claims to be stale, got %v, stale %v", age, stale)
+	}
+
+	// A reload that lengthens the claims' interval applies at once.
+	next := *cfg
+	next.ClaimPollIntervalSeconds = 60
+	poller.applyConfig(&next)
+	if _, stale := poller.Staleness(); stale {
+		t.Error("expected the longer interval to apply after a reload")
+	}
+
+	disabled := next
+	disabled.ReadinessStalePolls = 0
+	poller.applyConfig(&disabled)
+	if _, stale := poller.Staleness(); stale {
+		t.Error("expected no staleness with the check disabled")
+	}
+}
+
 func TestPoller_StaleRemoval(t *testing.T) {
 	claimGVR := schema.GroupVersionResource{Group: "g", Version: "v1", Resource: "things"}
 	xrGVR := schema.GroupVersionResource{Group: "g", Version: "v1", Resource: "xthings"}
@@ -307,8 +557,8 @@ func TestPoller_StaleRemoval(t *testing.T) {
 
 	// First poll — should find 1 claim.
 	poller.poll(context.Background())
-	if s.ClaimCount() != 1 {
-		t.Fatalf("expected 1 claim, got %d", s.ClaimCount())
+	if s.View().ClaimCount() != 1 {
+		t.Fatalf("expected 1 claim, got %d", s.View().ClaimCount())
 	}
 
 	// Remove the object from the fake client.
@@ -319,10 +569,64 @@ func TestPoller_StaleRemoval(t *testing.T) {
 		t.Fatalf("failed to delete fake object: %v", err)
 	}
 
-	// Second poll — stale claim should be removed.
+	// Second poll, once the GVRs are due again — stale claim should be removed.
+	poller.schedule.reset()
 	poller.poll(context.Background())
-	if s.ClaimCount() != 0 {
-		t.Fatalf("expected 0 claims after deletion, got %d", s.ClaimCount())
+	if s.View().ClaimCount() != 0 {
+		t.Fatalf("expected 0 claims after deletion, got %d", s.View().ClaimCount())
+	}
+}
+
+func TestPoller_CommitsOneGenerationPerCycle(t *testing.T) {
+	claimGVR := schema.GroupVersionResource{Group: "g", Version: "v1", Resource: "things"}
+	xrGVR := schema.GroupVersionResource{Group: "g", Version: "v1", Resource: "xthings"}
+
+	client := newFakeClient(
+		map[schema.GroupVersionResource]string{
+			claimGVR: "ThingList",
+			xrGVR:    "XThingList",
+		},
+		&unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "g/v1",
+				"kind":       "Thing",
+				"metadata":   map[string]interface{}{"name": "t1", "namespace": "ns"},
+				"spec":       map[string]interface{}{"resourceRef": map[string]interface{}{"name": "xt1"}},
+			},
+		},
+		&unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "g/v1",
+				"kind":       "XThing",
+				"metadata": map[string]interface{}{
+					"name":   "xt1",
+					"labels": map[string]interface{}{"crossplane.io/composition-name": "comp"},
+				},
+			},
+		},
+	)
+
+	cfg := &config.Config{
+		ClaimGVRs:           []schema.GroupVersionResource{claimGVR},
+		XRGVRs:              []schema.GroupVersionResource{xrGVR},
+		CompositionLabelKey: "crossplane.io/composition-name",
+		PollIntervalSeconds: 30,
+	}
+
+	s := store.New()
+	poller := NewPoller(client, cfg, s)
+
+	poller.poll(context.Background())
+	// Make every GVR due again, so the second cycle lists them too.
+	poller.schedule.reset()
+	poller.poll(context.Background())
+
+	if got := s.Generation().Number; got != 2 {
+		t.Errorf("expected exactly one generation per poll cycle (2), got %d", got)
+	}
+	snap := s.Snapshot()
+	if len(snap.Claims) != 1 || snap.Claims[0].Composition != "comp" {
+		t.Errorf("expected committed generation to be enriched, got %+v", snap.Claims)
 	}
 }
 
@@ -385,7 +689,7 @@ func TestPoller_PollMRsConcurrent(t *testing.T) {
 	poller := NewPoller(client, cfg, s)
 	poller.poll(context.Background())
 
-	if got := s.MRCount(); got != n {
+	if got := s.View().MRCount(); got != n {
 		t.Fatalf("expected %d MRs, got %d", n, got)
 	}
 }
@@ -455,11 +759,11 @@ func TestPoller_PollMRs(t *testing.T) {
 	poller := NewPoller(client, cfg, s)
 	poller.poll(context.Background())
 
-	if s.MRCount() != 1 {
-		t.Fatalf("expected 1 MR, got %d", s.MRCount())
+	if s.View().MRCount() != 1 {
+		t.Fatalf("expected 1 MR, got %d", s.View().MRCount())
 	}
 
-	mrs := s.SnapshotMRs()
+	mrs := s.View().MRs()
 	if mrs[0].XRName != "xr-1" {
 		t.Errorf("XRName: got %q", mrs[0].XRName)
 	}
@@ -470,3 +774,319 @@ func TestPoller_PollMRs(t *testing.T) {
 		t.Errorf("Provider: got %q", mrs[0].Provider)
 	}
 }
+
+func TestPoller_ResourceOverrides(t *testing.T) {
+	thingGVR := schema.GroupVersionResource{Group: "g", Version: "v1", Resource: "things"}
+	widgetGVR := schema.GroupVersionResource{Group: "g", Version: "v1", Resource: "widgets"}
+
+	claim := func(kind, name, ns, tier string) *unstructured.Unstructured {
+		return &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "g/v1",
+				"kind":       kind,
+				"metadata": map[string]interface{}{
+					"name":        name,
+					"namespace":   ns,
+					"labels":      map[string]interface{}{"tier": tier},
+					"annotations": map[string]interface{}{"example.org/owner": "payments", "example.org/team": "platform"},
+				},
+			},
+		}
+	}
+
+	client := newFakeClient(
+		map[schema.GroupVersionResource]string{
+			thingGVR:  "ThingList",
+			widgetGVR: "WidgetList",
+		},
+		claim("Thing", "t1", "ns-a", "prod"),
+		claim("Thing", "t2", "ns-b", "prod"),
+		claim("Thing", "t3", "ns-b", "dev"),
+		claim("Widget", "w1", "ns-a", "dev"),
+	)
+
+	cfg := &config.Config{
+		ClaimGVRs:           []schema.GroupVersionResource{thingGVR, widgetGVR},
+		TeamAnnotationKey:   "example.org/team",
+		PollIntervalSeconds: 30,
+		Resources: map[string]config.ResourceConfig{
+			"g/v1/things": {
+				GVR:                 "g/v1/things",
+				PollIntervalSeconds: 90,
+				Namespaces:          []string{"ns-b"},
+				LabelSelector:       "tier=prod",
+				TeamAnnotationKey:   "example.org/owner",
+			},
+		},
+	}
+
+	s := store.New()
+	poller := NewPoller(client, cfg, s)
+	poller.poll(context.Background())
+
+	teams := func() map[string]string {
+		out := make(map[string]string)
+		for _, c := range s.View().Claims() {
+			out[c.Name] = c.Team
+		}
+		return out
+	}
+	if got := teams(); len(got) != 2 || got["t2"] != "payments" || got["w1"] != "platform" {
+		t.Fatalf("expected t2 with its own team key and w1 with the global one, got %v", got)
+	}
+
+	// things is polled every 90s, so a cycle before then keeps its previous
+	// results.
+	if _, err := client.Resource(thingGVR).Namespace("ns-b").Create(context.Background(), claim("Thing", "t4", "ns-b", "prod"), metav1.CreateOptions{}); err != nil {
+		t.Fatalf("create claim: %v", err)
+	}
+	poller.poll(context.Background())
+	if got := teams(); len(got) != 2 {
+		t.Fatalf("expected things not to be re-polled yet, got %v", got)
+	}
+
+	poller.schedule.gvrs["g/v1/things"].next = time.Now()
+	poller.poll(context.Background())
+	if got := teams(); len(got) != 3 || got["t4"] != "payments" {
+		t.Errorf("expected things to be re-polled once due, got %v", got)
+	}
+}
+
+func TestPoller_Reconfigure(t *testing.T) {
+	thingGVR := schema.GroupVersionResource{Group: "g", Version: "v1", Resource: "things"}
+	widgetGVR := schema.GroupVersionResource{Group: "g", Version: "v1", Resource: "widgets"}
+
+	claim := func(kind, name string) *unstructured.Unstructured {
+		return &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "g/v1",
+				"kind":       kind,
+				"metadata": map[string]interface{}{
+					"name":        name,
+					"namespace":   "ns",
+					"annotations": map[string]interface{}{"example.org/team": "platform", "example.org/owner": "payments"},
+				},
+			},
+		}
+	}
+	client := newFakeClient(
+		map[schema.GroupVersionResource]string{
+			thingGVR:  "ThingList",
+			widgetGVR: "WidgetList",
+		},
+		claim("Thing", "t1"), claim("Widget", "w1"),
+	)
+
+	cfg := &config.Config{
+		ClaimGVRs:           []schema.GroupVersionResource{thingGVR, widgetGVR},
+		TeamAnnotationKey:   "example.org/team",
+		PollIntervalSeconds: 3600,
+	}
+	s := store.New()
+	poller := NewPoller(client, cfg, s)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go poller.Run(ctx)
+	<-poller.FirstPoll()
+	if s.View().ClaimCount() != 2 {
+		t.Fatalf("expected 2 claims, got %d", s.View().ClaimCount())
+	}
+
+	// Drop widgets and switch the team annotation key.
+	next := *cfg
+	next.ClaimGVRs = []schema.GroupVersionResource{thingGVR}
+	next.TeamAnnotationKey = "example.org/owner"
+	poller.Reconfigure(&next)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		claims := s.View().Claims()
+		if len(claims) == 1 && claims[0].Name == "t1" && claims[0].Team == "payments" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected only t1 with the new team key, got %+v", claims)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if st := poller.Status(); len(st.Claims) != 1 || st.Claims[0].GVR != "g/v1/things" {
+		t.Errorf("expected status to list the new GVRs, got %+v", st.Claims)
+	}
+}
+
+func TestPoller_Selectors(t *testing.T) {
+	claimGVR := schema.GroupVersionResource{Group: "g", Version: "v1", Resource: "things"}
+	xrGVR := schema.GroupVersionResource{Group: "g", Version: "v1", Resource: "xthings"}
+	mrGVR := schema.GroupVersionResource{Group: "aws", Version: "v1", Resource: "buckets"}
+
+	client := newFakeClient(map[schema.GroupVersionResource]string{
+		claimGVR: "ThingList",
+		xrGVR:    "XThingList",
+		mrGVR:    "BucketList",
+	})
+	var mu sync.Mutex
+	got := make(map[string][2]string)
+	client.PrependReactor("list", "*", func(a k8stesting.Action) (bool, runtime.Object, error) {
+		r := a.(k8stesting.ListAction).GetListRestrictions()
+		mu.Lock()
+		got[a.GetResource().Resource] = [2]string{r.Labels.String(), r.Fields.String()}
+		mu.Unlock()
+		return false, nil, nil
+	})
+
+	cfg := &config.Config{
+		ClaimGVRs:           []schema.GroupVersionResource{claimGVR},
+		XRGVRs:              []schema.GroupVersionResource{xrGVR},
+		MRGVRs:              []schema.GroupVersionResource{mrGVR},
+		CompositeLabelKey:   "crossplane.io/composite",
+		PollIntervalSeconds: 30,
+		ClaimSelector:       config.Selector{Label: "platform=dev"},
+		MRSelector:          config.Selector{Field: "metadata.namespace!=kube-system"},
+		Resources: map[string]config.ResourceConfig{
+			"g/v1/things": {GVR: "g/v1/things", LabelSelector: "tier=prod", FieldSelector: "metadata.name!=scratch"},
+		},
+	}
+	NewPoller(client, cfg, store.New()).poll(context.Background())
+
+	want := map[string][2]string{
+		"things":  {"platform=dev,tier=prod", "metadata.name!=scratch"},
+		"xthings": {"", ""},
+		"buckets": {"crossplane.io/composite", "metadata.namespace!=kube-system"},
+	}
+	for resource, w := range want {
+		if got[resource] != w {
+			t.Errorf("%s: got selectors %q, want %q", resource, got[resource], w)
+		}
+	}
+}
+
+func TestPoller_NamespaceSelectorUnresolved(t *testing.T) {
+	claimGVR := schema.GroupVersionResource{Group: "g", Version: "v1", Resource: "things"}
+	ns := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Namespace",
+		"metadata":   map[string]interface{}{"name": "ns-a", "labels": map[string]interface{}{"tracked": "true"}},
+	}}
+	claim := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "g/v1",
+		"kind":       "Thing",
+		"metadata":   map[string]interface{}{"name": "t1", "namespace": "ns-a"},
+	}}
+	client := newFakeClient(map[schema.GroupVersionResource]string{
+		claimGVR:     "ThingList",
+		namespaceGVR: "NamespaceList",
+	}, ns, claim)
+	failing := true
+	client.PrependReactor("list", "namespaces", func(k8stesting.Action) (bool, runtime.Object, error) {
+		if failing {
+			return true, nil, errors.New("apiserver unavailable")
+		}
+		return false, nil, nil
+	})
+
+	cfg := &config.Config{
+		ClaimGVRs:           []schema.GroupVersionResource{claimGVR},
+		NamespaceSelector:   "tracked=true",
+		PollIntervalSeconds: 30,
+	}
+	s := store.New()
+	// The claim was restored from a snapshot.
+	s.ReplaceClaims("g/v1/things", []store.ClaimInfo{{GVR: "g/v1/things", Kind: "Thing", Namespace: "ns-a", Name: "t1"}})
+	poller := NewPoller(client, cfg, s)
+
+	if poller.poll(context.Background()) {
+		t.Error("expected the cycle to report errors")
+	}
+	if s.View().ClaimCount() != 1 {
+		t.Fatalf("expected the restored claim kept while the selector is unresolved, got %d claims", s.View().ClaimCount())
+	}
+
+	failing = false
+	poller.poll(context.Background())
+	if s.View().ClaimCount() != 1 {
+		t.Fatalf("expected the claim listed once the selector resolved, got %d claims", s.View().ClaimCount())
+	}
+
+	// A new selector that cannot be resolved keeps the store as it is.
+	failing = true
+	next := *cfg
+	next.NamespaceSelector = "tracked in (true,yes)"
+	poller.applyConfig(&next)
+	poller.poll(context.Background())
+	if s.View().ClaimCount() != 1 {
+		t.Errorf("expected the claim kept after reconfiguring, got %d claims", s.View().ClaimCount())
+	}
+}
+
+func TestPoller_NamespaceSelector(t *testing.T) {
+	claimGVR := schema.GroupVersionResource{Group: "g", Version: "v1", Resource: "things"}
+
+	namespace := func(name string, tracked bool) *unstructured.Unstructured {
+		ns := &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "Namespace",
+				"metadata":   map[string]interface{}{"name": name},
+			},
+		}
+		if tracked {
+			ns.SetLabels(map[string]string{"xp-tracker/tracked": "true"})
+		}
+		return ns
+	}
+	claim := func(name, ns string) *unstructured.Unstructured {
+		return &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "g/v1",
+				"kind":       "Thing",
+				"metadata":   map[string]interface{}{"name": name, "namespace": ns},
+			},
+		}
+	}
+
+	client := newFakeClient(
+		map[schema.GroupVersionResource]string{
+			claimGVR:     "ThingList",
+			namespaceGVR: "NamespaceList",
+		},
+		namespace("ns-a", true), namespace("ns-b", false),
+		claim("t1", "ns-a"), claim("t2", "ns-b"),
+	)
+
+	cfg := &config.Config{
+		ClaimGVRs:           []schema.GroupVersionResource{claimGVR},
+		NamespaceSelector:   "xp-tracker/tracked=true",
+		PollIntervalSeconds: 30,
+	}
+	s := store.New()
+	poller := NewPoller(client, cfg, s)
+	poller.poll(context.Background())
+
+	names := func() []string {
+		var out []string
+		for _, c := range s.View().Claims() {
+			out = append(out, c.Name)
+		}
+		return out
+	}
+	if got := names(); len(got) != 1 || got[0] != "t1" {
+		t.Fatalf("expected only the claim in ns-a, got %v", got)
+	}
+
+	// ns-b joins the scope and ns-a leaves it.
+	nsClient := client.Resource(namespaceGVR)
+	for _, ns := range []*unstructured.Unstructured{namespace("ns-a", false), namespace("ns-b", true)} {
+		if _, err := nsClient.Update(context.Background(), ns, metav1.UpdateOptions{}); err != nil {
+			t.Fatalf("update namespace: %v", err)
+		}
+	}
+	poller.poll(context.Background())
+
+	if got := names(); len(got) != 1 || got[0] != "t2" {
+		t.Errorf("expected only the claim in ns-b, got %v", got)
+	}
+	if st := poller.Status(); len(st.SelectedNamespaces) != 1 || st.SelectedNamespaces[0] != "ns-b" {
+		t.Errorf("unexpected selected namespaces: %v", st.SelectedNamespaces)
+	}
+}
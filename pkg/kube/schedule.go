@@ -0,0 +1,153 @@
+package kube
+
+import (
+	"hash"
+	"hash/fnv"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// pollBatchWindow is how early a GVR may be polled: a cycle also lists the
+// GVRs that fall due within this window, so that GVRs due at nearly the
+// same time share a cycle instead of each starting one.
+const pollBatchWindow = 2 * time.Second
+
+// pollSchedule tracks when each GVR is polled next. Every GVR has its own
+// interval: its configured one, or with adaptive polling an interval that
+// shrinks while the GVR changes and grows while it is idle. It is safe for
+// concurrent use, since MR GVRs are polled in parallel.
+type pollSchedule struct {
+	mu   sync.Mutex
+	gvrs map[string]*gvrSchedule
+}
+
+type gvrSchedule struct {
+	base     time.Duration // configured interval
+	interval time.Duration // current interval, adapted when adaptive polling is enabled
+	last     time.Time     // start of the cycle that last polled the GVR
+	next     time.Time
+	digest   uint64 // of the last successful list, when listed is set
+	listed   bool
+}
+
+// due returns the GVRs that are due at now, or within pollBatchWindow of
+// it, and schedules their next poll. GVRs not polled yet are always due.
+// base returns the configured interval of a GVR.
+func (s *pollSchedule) due(gvrs []schema.GroupVersionResource, now time.Time, base func(string) time.Duration, jitter float64) []schema.GroupVersionResource {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.gvrs == nil {
+		s.gvrs = make(map[string]*gvrSchedule)
+	}
+
+	out := make([]schema.GroupVersionResource, 0, len(gvrs))
+	for _, gvr := range gvrs {
+		gvrStr := GVRString(gvr)
+		b := base(gvrStr)
+		e, ok := s.gvrs[gvrStr]
+		if ok && e.base == b && now.Before(e.next.Add(-pollBatchWindow)) {
+			continue
+		}
+		if !ok || e.base != b {
+			e = &gvrSchedule{base: b, interval: b}
+			s.gvrs[gvrStr] = e
+		}
+		e.last = now
+		e.next = now.Add(jittered(e.interval, jitter))
+		out = append(out, gvr)
+	}
+	return out
+}
+
+// observe records a successful list of gvr with the given digest. With an
+// adaptive factor of at least 2, the interval is halved when the digest
+// changed since the previous list and grows by half when it did not,
+// within the configured interval divided and multiplied by the factor.
+func (s *pollSchedule) observe(gvr string, digest uint64, factor int, jitter float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.gvrs[gvr]
+	if !ok {
+		return
+	}
+	prev, listed := e.digest, e.listed
+	e.digest, e.listed = digest, true
+	if factor < 2 || !listed {
+		return
+	}
+
+	lo := max(e.base/time.Duration(factor), time.Second)
+	hi := e.base * time.Duration(factor)
+	if digest != prev {
+		e.interval /= 2
+	} else {
+		e.interval += e.interval / 2
+	}
+	e.interval = min(max(e.interval, lo), hi)
+	e.next = e.last.Add(jittered(e.interval, jitter))
+}
+
+// earliest returns the earliest next poll time of gvrs, and false when
+// none of them has been polled yet.
+func (s *pollSchedule) earliest(gvrs []schema.GroupVersionResource) (time.Time, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var next time.Time
+	for _, gvr := range gvrs {
+		if e, ok := s.gvrs[GVRString(gvr)]; ok && (next.IsZero() || e.next.Before(next)) {
+			next = e.next
+		}
+	}
+	return next, !next.IsZero()
+}
+
+// get returns the current interval and next poll time of gvr.
+func (s *pollSchedule) get(gvr string) (interval time.Duration, next time.Time, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if e, ok := s.gvrs[gvr]; ok {
+		return e.interval, e.next, true
+	}
+	return 0, time.Time{}, false
+}
+
+// reset makes every GVR due in the next cycle.
+func (s *pollSchedule) reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	clear(s.gvrs)
+}
+
+// jittered returns d moved randomly by up to the fraction jitter of it, so
+// that GVRs with the same interval spread out over time.
+func jittered(d time.Duration, jitter float64) time.Duration {
+	if jitter <= 0 {
+		return d
+	}
+	return time.Duration(float64(d) * (1 + jitter*(2*rand.Float64()-1)))
+}
+
+// listDigest hashes the identity and resource version of listed objects,
+// to tell whether a GVR changed between two polls.
+type listDigest struct {
+	h hash.Hash64
+}
+
+func newListDigest() *listDigest {
+	return &listDigest{h: fnv.New64a()}
+}
+
+func (d *listDigest) add(item unstructured.Unstructured) {
+	for _, s := range []string{item.GetNamespace(), item.GetName(), string(item.GetUID()), item.GetResourceVersion()} {
+		_, _ = d.h.Write([]byte(s))
+		_, _ = d.h.Write([]byte{0})
+	}
+}
+
+func (d *listDigest) sum() uint64 {
+	return d.h.Sum64()
+}
@@ -0,0 +1,220 @@
+package kube
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/kanzifucius/xp-tracker/pkg/config"
+	"github.com/kanzifucius/xp-tracker/pkg/store"
+)
+
+func TestPollSchedule_Adaptive(t *testing.T) {
+	gvr := schema.GroupVersionResource{Group: "g", Version: "v1", Resource: "things"}
+	base := func(string) time.Duration { return 30 * time.Second }
+	var s pollSchedule
+	now := time.Now()
+
+	poll := func(digest uint64) time.Duration {
+		t.Helper()
+		if due := s.due([]schema.GroupVersionResource{gvr}, now, base, 0); len(due) != 1 {
+			t.Fatalf("expected the GVR to be due at %v", now)
+		}
+		s.observe("g/v1/things", digest, 4, 0)
+		interval, next, _ := s.get("g/v1/things")
+		if !next.Equal(now.Add(interval)) {
+			t.Errorf("expected the next poll one interval later, got %v", next.Sub(now))
+		}
+		now = next
+		return interval
+	}
+
+	if got := poll(1); got != 30*time.Second {
+		t.Errorf("expected the configured interval after the first poll, got %v", got)
+	}
+	// Idle polls stretch the interval up to 4x.
+	for _, want := range []time.Duration{45 * time.Second, 67500 * time.Millisecond, 101250 * time.Millisecond, 120 * time.Second, 120 * time.Second} {
+		if got := poll(1); got != want {
+			t.Errorf("expected idle interval %v, got %v", want, got)
+		}
+	}
+	// Changes shrink it down to a quarter.
+	for i, want := range []time.Duration{60 * time.Second, 30 * time.Second, 15 * time.Second, 7500 * time.Millisecond, 7500 * time.Millisecond} {
+		if got := poll(uint64(i + 2)); got != want {
+			t.Errorf("expected interval %v after a change, got %v", want, got)
+		}
+	}
+
+	// Not due before its next poll, less the batch window.
+	if due := s.due([]schema.GroupVersionResource{gvr}, now.Add(-3*time.Second), base, 0); len(due) != 0 {
+		t.Errorf("expected the GVR not to be due yet, got %v", due)
+	}
+	// A new configured interval starts over.
+	longer := func(string) time.Duration { return time.Minute }
+	if due := s.due([]schema.GroupVersionResource{gvr}, now.Add(-3*time.Second), longer, 0); len(due) != 1 {
+		t.Errorf("expected the GVR to be due after its interval changed")
+	}
+	if interval, _, _ := s.get("g/v1/things"); interval != time.Minute {
+		t.Errorf("expected the new interval, got %v", interval)
+	}
+}
+
+func TestPollSchedule_Jitter(t *testing.T) {
+	var gvrs []schema.GroupVersionResource
+	for i := range 50 {
+		gvrs = append(gvrs, schema.GroupVersionResource{Group: fmt.Sprintf("g%d", i), Version: "v1", Resource: "things"})
+	}
+	var s pollSchedule
+	now := time.Now()
+	s.due(gvrs, now, func(string) time.Duration { return 30 * time.Second }, 0.2)
+
+	nexts := make(map[time.Time]bool)
+	for _, gvr := range gvrs {
+		_, next, _ := s.get(GVRString(gvr))
+		if d := next.Sub(now); d < 24*time.Second || d > 36*time.Second {
+			t.Errorf("%s: next poll in %v, outside 30s +-20%%", GVRString(gvr), d)
+		}
+		nexts[next] = true
+	}
+	if len(nexts) < 10 {
+		t.Errorf("expected jitter to spread the next polls, got %d distinct times", len(nexts))
+	}
+}
+
+func TestPoller_ClassIntervals(t *testing.T) {
+	claimGVR := schema.GroupVersionResource{Group: "g", Version: "v1", Resource: "things"}
+	xrGVR := schema.GroupVersionResource{Group: "g", Version: "v1", Resource: "xthings"}
+	mrGVR := schema.GroupVersionResource{Group: "aws", Version: "v1", Resource: "buckets"}
+	client := newFakeClient(map[schema.GroupVersionResource]string{
+		claimGVR: "ThingList",
+		xrGVR:    "XThingList",
+		mrGVR:    "BucketList",
+	})
+
+	cfg := &config.Config{
+		ClaimGVRs:                []schema.GroupVersionResource{claimGVR},
+		XRGVRs:                   []schema.GroupVersionResource{xrGVR},
+		MRGVRs:                   []schema.GroupVersionResource{mrGVR},
+		CompositeLabelKey:        "crossplane.io/composite",
+		PollIntervalSeconds:      30,
+		ClaimPollIntervalSeconds: 300,
+		MRPollIntervalSeconds:    10,
+		Resources: map[string]config.ResourceConfig{
+			"aws/v1/buckets": {GVR: "aws/v1/buckets", PollIntervalSeconds: 60},
+		},
+	}
+	poller := NewPoller(client, cfg, store.New())
+	start := time.Now()
+	poller.poll(context.Background())
+
+	for gvr, want := range map[string]time.Duration{
+		"g/v1/things":    300 * time.Second,
+		"g/v1/xthings":   30 * time.Second,
+		"aws/v1/buckets": 60 * time.Second,
+	} {
+		if interval, _, _ := poller.schedule.get(gvr); interval != want {
+			t.Errorf("%s: expected interval %v, got %v", gvr, want, interval)
+		}
+	}
+	if d := poller.nextCycle.Sub(start); d < 30*time.Second || d > 31*time.Second {
+		t.Errorf("expected the next cycle when the XRs are due, in %v", d)
+	}
+
+	// Nothing is due right away, so the next cycle lists nothing.
+	lists := len(client.Actions())
+	poller.poll(context.Background())
+	if got := len(client.Actions()); got != lists {
+		t.Errorf("expected no list calls before any GVR is due, got %d", got-lists)
+	}
+}
+
+func TestPoller_CommitsEachCycle(t *testing.T) {
+	gvr := schema.GroupVersionResource{Group: "g", Version: "v1", Resource: "things"}
+	client := newFakeClient(map[schema.GroupVersionResource]string{gvr: "ThingList"})
+	cfg := &config.Config{ClaimGVRs: []schema.GroupVersionResource{gvr}, PollIntervalSeconds: 30}
+	s := store.New()
+	poller := NewPoller(client, cfg, s)
+	ctx := context.Background()
+
+	poller.poll(ctx)
+	gen := s.Generation().Number
+
+	// A cycle that lists the GVR commits it at once.
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "g/v1",
+		"kind":       "Thing",
+		"metadata":   map[string]interface{}{"name": "c1", "namespace": "ns"},
+	}}
+	if _, err := client.Resource(gvr).Namespace("ns").Create(ctx, obj, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	poller.schedule.reset()
+	poller.pollDue(ctx)
+	if got := s.Generation().Number; got != gen+1 || s.View().ClaimCount() != 1 {
+		t.Errorf("expected one generation with the claim, got generation %d and %d claims", got, s.View().ClaimCount())
+	}
+
+	// A cycle with nothing due leaves the store as it is.
+	poller.pollDue(ctx)
+	if got := s.Generation().Number; got != gen+1 {
+		t.Errorf("expected no generation without a GVR due, got %d", got)
+	}
+}
+
+// countingStore is a PersistentStore that counts its persists.
+type countingStore struct {
+	*store.MemoryStore
+	persists atomic.Int32
+}
+
+func (c *countingStore) Persist(context.Context) error {
+	c.persists.Add(1)
+	return nil
+}
+
+func (c *countingStore) Restore(context.Context) error { return nil }
+
+func (c *countingStore) PersistenceStatus() store.PersistenceStatus {
+	return store.PersistenceStatus{}
+}
+
+func TestPoller_CommitsAtMRRate(t *testing.T) {
+	mrGVR := schema.GroupVersionResource{Group: "aws", Version: "v1", Resource: "buckets"}
+	client := newFakeClient(map[schema.GroupVersionResource]string{mrGVR: "BucketList"})
+	cfg := &config.Config{
+		MRGVRs:                []schema.GroupVersionResource{mrGVR},
+		CompositeLabelKey:     "crossplane.io/composite",
+		PollIntervalSeconds:   60,
+		MRPollIntervalSeconds: 1,
+	}
+	s := &countingStore{MemoryStore: store.New()}
+	poller := NewPoller(client, cfg, s)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		poller.Run(ctx)
+	}()
+	time.Sleep(2500 * time.Millisecond)
+	cancel()
+	<-done
+
+	// The first cycle and one per MR interval since each commit.
+	if got := s.Generation().Number; got < 3 {
+		t.Errorf("expected a generation per MR interval, got %d", got)
+	}
+	if got := len(client.Actions()); got < 3 {
+		t.Errorf("expected a list call per MR interval, got %d", got)
+	}
+	// Persistence waits for the global interval after the first cycle.
+	if got := s.persists.Load(); got != 1 {
+		t.Errorf("expected a single persist within the global interval, got %d", got)
+	}
+}
@@ -0,0 +1,214 @@
+package kube
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/kanzifucius/xp-tracker/pkg/metrics"
+)
+
+// shardLabelKey marks the Leases of replicas taking part in sharded polling.
+const shardLabelKey = "xp-tracker/shard"
+
+// shardLeasePrefix prefixes a replica's identity to name its Lease.
+const shardLeasePrefix = "xp-tracker-shard-"
+
+var leaseGVR = schema.GroupVersionResource{
+	Group:    "coordination.k8s.io",
+	Version:  "v1",
+	Resource: "leases",
+}
+
+// Shards tracks the replicas taking part in sharded polling.
+//
+// Every replica holds a Lease in a shared namespace and renews it at a third
+// of the lease duration. The replicas with unexpired Leases are the members,
+// and each GVR is owned by exactly one of them, chosen by rendezvous
+// hashing: when a member joins or leaves, only the GVRs it gains or held
+// change owner.
+type Shards struct {
+	client        dynamic.Interface
+	namespace     string
+	identity      string
+	leaseDuration time.Duration
+
+	mu      sync.RWMutex
+	members []string
+}
+
+// NewShards returns a Shards for the replica identity, holding its Lease in
+// namespace. Until the first Sync, the replica is the only member.
+func NewShards(client dynamic.Interface, namespace, identity string, leaseDuration time.Duration) *Shards {
+	return &Shards{
+		client:        client,
+		namespace:     namespace,
+		identity:      identity,
+		leaseDuration: leaseDuration,
+		members:       []string{identity},
+	}
+}
+
+// Identity returns this replica's identity.
+func (s *Shards) Identity() string {
+	return s.identity
+}
+
+// Members returns the identities of the current members, sorted. It always
+// includes this replica.
+func (s *Shards) Members() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.members
+}
+
+// Run keeps this replica's Lease renewed until ctx is cancelled, then
+// deletes it so that the other members take over its GVRs without waiting
+// for it to expire.
+func (s *Shards) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.leaseDuration / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			// The parent context is already cancelled.
+			delCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			err := s.client.Resource(leaseGVR).Namespace(s.namespace).Delete(delCtx, s.leaseName(), metav1.DeleteOptions{})
+			cancel()
+			if err != nil && !apierrors.IsNotFound(err) {
+				slog.Warn("failed to release shard lease", "lease", s.leaseName(), "error", err)
+			}
+			return
+		case <-ticker.C:
+			if err := s.Sync(ctx); err != nil && ctx.Err() == nil {
+				slog.Error("failed to sync shard membership, keeping the previous members", "error", err)
+			}
+		}
+	}
+}
+
+// Sync renews this replica's Lease and refreshes the members from the
+// Leases in the namespace.
+func (s *Shards) Sync(ctx context.Context) error {
+	now := time.Now()
+	if err := s.renew(ctx, now); err != nil {
+		return fmt.Errorf("renew lease %s/%s: %w", s.namespace, s.leaseName(), err)
+	}
+
+	list, err := s.client.Resource(leaseGVR).Namespace(s.namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: shardLabelKey + "=true",
+	})
+	if err != nil {
+		return fmt.Errorf("list shard leases in %s: %w", s.namespace, err)
+	}
+	members := []string{s.identity}
+	for _, item := range list.Items {
+		if holder, ok := leaseHolder(item, now); ok {
+			members = append(members, holder)
+		}
+	}
+	slices.Sort(members)
+	members = slices.Compact(members)
+
+	s.mu.Lock()
+	changed := !slices.Equal(members, s.members)
+	s.members = members
+	s.mu.Unlock()
+
+	if changed {
+		slog.Info("shard members changed", "identity", s.identity, "members", members)
+	}
+	metrics.ShardMembers.Set(float64(len(members)))
+	return nil
+}
+
+func (s *Shards) leaseName() string {
+	return shardLeasePrefix + s.identity
+}
+
+// renew creates or updates this replica's Lease.
+func (s *Shards) renew(ctx context.Context, now time.Time) error {
+	leases := s.client.Resource(leaseGVR).Namespace(s.namespace)
+	lease, err := leases.Get(ctx, s.leaseName(), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		lease = &unstructured.Unstructured{}
+		lease.SetAPIVersion("coordination.k8s.io/v1")
+		lease.SetKind("Lease")
+		lease.SetName(s.leaseName())
+		lease.SetNamespace(s.namespace)
+		lease.SetLabels(map[string]string{shardLabelKey: "true"})
+		if err := s.setLeaseSpec(lease, now); err != nil {
+			return err
+		}
+		_, err = leases.Create(ctx, lease, metav1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	if err := s.setLeaseSpec(lease, now); err != nil {
+		return err
+	}
+	_, err = leases.Update(ctx, lease, metav1.UpdateOptions{})
+	return err
+}
+
+func (s *Shards) setLeaseSpec(lease *unstructured.Unstructured, now time.Time) error {
+	spec := map[string]any{
+		"holderIdentity":       s.identity,
+		"leaseDurationSeconds": int64(s.leaseDuration / time.Second),
+		"renewTime":            now.UTC().Format(metav1.RFC3339Micro),
+	}
+	return unstructured.SetNestedMap(lease.Object, spec, "spec")
+}
+
+// leaseHolder returns the holder of a shard Lease, if the Lease has not
+// expired at now.
+func leaseHolder(lease unstructured.Unstructured, now time.Time) (string, bool) {
+	holder, _, _ := unstructured.NestedString(lease.Object, "spec", "holderIdentity")
+	seconds, _, _ := unstructured.NestedInt64(lease.Object, "spec", "leaseDurationSeconds")
+	renewed, _, _ := unstructured.NestedString(lease.Object, "spec", "renewTime")
+	renewTime, err := time.Parse(metav1.RFC3339Micro, renewed)
+	if holder == "" || err != nil {
+		return "", false
+	}
+	return holder, now.Before(renewTime.Add(time.Duration(seconds) * time.Second))
+}
+
+// shardOwner returns the member that owns gvr: the one with the highest
+// hash of member and GVR together.
+func shardOwner(members []string, gvr string) string {
+	var owner string
+	var best uint64
+	for _, m := range members {
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(m))
+		_, _ = h.Write([]byte{0})
+		_, _ = h.Write([]byte(gvr))
+		if sum := h.Sum64(); owner == "" || sum > best {
+			owner, best = m, sum
+		}
+	}
+	return owner
+}
+
+// owned returns the GVRs in gvrs that this replica owns among members.
+func (s *Shards) owned(members []string, gvrs []schema.GroupVersionResource) []schema.GroupVersionResource {
+	out := make([]schema.GroupVersionResource, 0, len(gvrs))
+	for _, gvr := range gvrs {
+		if shardOwner(members, GVRString(gvr)) == s.identity {
+			out = append(out, gvr)
+		}
+	}
+	return out
+}
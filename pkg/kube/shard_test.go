@@ -0,0 +1,268 @@
+package kube
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/kanzifucius/xp-tracker/pkg/config"
+	"github.com/kanzifucius/xp-tracker/pkg/store"
+)
+
+// fakeShardStore is a MemoryStore whose shards are kept in a map shared by
+// the replicas of a test.
+type fakeShardStore struct {
+	*store.MemoryStore
+	identity string
+	shards   map[string]store.Snapshot
+}
+
+func (f *fakeShardStore) Persist(context.Context) error { return nil }
+func (f *fakeShardStore) Restore(context.Context) error { return nil }
+func (f *fakeShardStore) PersistenceStatus() store.PersistenceStatus {
+	return store.PersistenceStatus{}
+}
+
+func (f *fakeShardStore) PersistShard(_ context.Context, gvrs []string) error {
+	snap := f.Snapshot()
+	snap.Shard = f.identity
+	snap.GVRs = gvrs
+	snap.PersistedAt = time.Now()
+	f.shards[f.identity] = snap
+	return nil
+}
+
+func (f *fakeShardStore) LoadShard(_ context.Context, shard string) (store.Snapshot, bool, error) {
+	snap, ok := f.shards[shard]
+	return snap, ok, nil
+}
+
+func (f *fakeShardStore) RestoreShards(context.Context, []string) error { return nil }
+
+func (f *fakeShardStore) ListShards(context.Context) ([]store.ShardObject, error) {
+	var out []store.ShardObject
+	for id, snap := range f.shards {
+		out = append(out, store.ShardObject{Shard: id, LastModified: snap.PersistedAt})
+	}
+	return out, nil
+}
+
+func (f *fakeShardStore) DeleteShard(_ context.Context, shard string) error {
+	delete(f.shards, shard)
+	return nil
+}
+
+func TestShards_Sync(t *testing.T) {
+	client := newFakeClient(map[schema.GroupVersionResource]string{leaseGVR: "LeaseList"})
+	ctx := context.Background()
+
+	a := NewShards(client, "xp", "exporter-0", 30*time.Second)
+	b := NewShards(client, "xp", "exporter-1", 30*time.Second)
+	if got := a.Members(); !slices.Equal(got, []string{"exporter-0"}) {
+		t.Errorf("expected only itself before the first sync, got %v", got)
+	}
+	for _, s := range []*Shards{a, b, a} {
+		if err := s.Sync(ctx); err != nil {
+			t.Fatalf("Sync: %v", err)
+		}
+	}
+	if got := a.Members(); !slices.Equal(got, []string{"exporter-0", "exporter-1"}) {
+		t.Errorf("unexpected members %v", got)
+	}
+
+	// A Lease that was not renewed in time is no longer a member.
+	expired := &unstructured.Unstructured{}
+	expired.SetAPIVersion("coordination.k8s.io/v1")
+	expired.SetKind("Lease")
+	expired.SetName(shardLeasePrefix + "exporter-2")
+	expired.SetNamespace("xp")
+	expired.SetLabels(map[string]string{shardLabelKey: "true"})
+	_ = unstructured.SetNestedMap(expired.Object, map[string]any{
+		"holderIdentity":       "exporter-2",
+		"leaseDurationSeconds": int64(30),
+		"renewTime":            time.Now().Add(-time.Minute).UTC().Format(metav1.RFC3339Micro),
+	}, "spec")
+	if _, err := client.Resource(leaseGVR).Namespace("xp").Create(ctx, expired, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("create lease: %v", err)
+	}
+	if err := a.Sync(ctx); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	if got := a.Members(); !slices.Equal(got, []string{"exporter-0", "exporter-1"}) {
+		t.Errorf("expected the expired lease to be ignored, got %v", got)
+	}
+
+	// Stopping a replica releases its Lease.
+	runCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		b.Run(runCtx)
+		close(done)
+	}()
+	cancel()
+	<-done
+	if err := a.Sync(ctx); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	if got := a.Members(); !slices.Equal(got, []string{"exporter-0"}) {
+		t.Errorf("expected the stopped replica to leave, got %v", got)
+	}
+}
+
+func TestShardOwner(t *testing.T) {
+	members := []string{"exporter-0", "exporter-1", "exporter-2"}
+	gvrs := make([]string, 100)
+	for i := range gvrs {
+		gvrs[i] = fmt.Sprintf("g%d.example.org/v1/things", i)
+	}
+
+	counts := make(map[string]int)
+	for _, gvr := range gvrs {
+		counts[shardOwner(members, gvr)]++
+	}
+	for _, m := range members {
+		if counts[m] < 15 {
+			t.Errorf("expected GVRs to spread across members, got %v", counts)
+		}
+	}
+
+	// When a member leaves, only its GVRs move.
+	remaining := []string{"exporter-0", "exporter-2"}
+	for _, gvr := range gvrs {
+		before := shardOwner(members, gvr)
+		if after := shardOwner(remaining, gvr); before != "exporter-1" && after != before {
+			t.Errorf("%s moved from %s to %s", gvr, before, after)
+		}
+	}
+}
+
+func TestPoller_Sharding(t *testing.T) {
+	var gvrs []schema.GroupVersionResource
+	listKinds := map[schema.GroupVersionResource]string{}
+	var objects []runtime.Object
+	for i := range 8 {
+		gvr := schema.GroupVersionResource{Group: fmt.Sprintf("g%d.example.org", i), Version: "v1", Resource: "things"}
+		gvrs = append(gvrs, gvr)
+		listKinds[gvr] = "ThingList"
+		objects = append(objects, &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": gvr.Group + "/v1",
+			"kind":       "Thing",
+			"metadata":   map[string]interface{}{"name": fmt.Sprintf("t%d", i), "namespace": "ns"},
+		}})
+	}
+	leases := newFakeClient(map[schema.GroupVersionResource]string{leaseGVR: "LeaseList"})
+	ctx := context.Background()
+	shared := make(map[string]store.Snapshot)
+
+	type replica struct {
+		client *dynamicfake.FakeDynamicClient
+		store  *fakeShardStore
+		poller *Poller
+		shards *Shards
+	}
+	var replicas []replica
+	for _, id := range []string{"exporter-0", "exporter-1"} {
+		c := newFakeClient(listKinds, objects...)
+		s := &fakeShardStore{MemoryStore: store.New(), identity: id, shards: shared}
+		cfg := &config.Config{ClaimGVRs: gvrs, PollIntervalSeconds: 30}
+		p := NewPoller(c, cfg, s)
+		shards := NewShards(leases, "xp", id, 30*time.Second)
+		p.SetShards(shards)
+		replicas = append(replicas, replica{client: c, store: s, poller: p, shards: shards})
+	}
+	for _, r := range replicas {
+		if err := r.shards.Sync(ctx); err != nil {
+			t.Fatalf("Sync: %v", err)
+		}
+	}
+	if err := replicas[0].shards.Sync(ctx); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+
+	replicas[0].poller.poll(ctx)
+	replicas[1].poller.poll(ctx)
+	replicas[0].poller.poll(ctx)
+
+	members := []string{"exporter-0", "exporter-1"}
+	for i, r := range replicas {
+		if n := r.store.View().ClaimCount(); n != len(gvrs) {
+			t.Errorf("replica %d: expected all %d claims after merging, got %d", i, len(gvrs), n)
+		}
+		var listed []schema.GroupVersionResource
+		for _, a := range r.client.Actions() {
+			if a.GetVerb() == "list" {
+				listed = append(listed, a.GetResource())
+			}
+		}
+		for _, gvr := range gvrs {
+			owner := shardOwner(members, GVRString(gvr))
+			if slices.Contains(listed, gvr) != (owner == r.shards.Identity()) {
+				t.Errorf("replica %d: %s owned by %s, listed=%v", i, GVRString(gvr), owner, slices.Contains(listed, gvr))
+			}
+		}
+		st := r.poller.Status().Shard
+		if st == nil || st.Identity != r.shards.Identity() || len(st.Members) != 2 {
+			t.Errorf("replica %d: unexpected shard status %+v", i, st)
+		}
+	}
+	if len(shared) != 2 {
+		t.Errorf("expected both replicas to persist a shard, got %d", len(shared))
+	}
+}
+
+func TestPoller_ShardPersistence(t *testing.T) {
+	var gvrs []schema.GroupVersionResource
+	listKinds := map[schema.GroupVersionResource]string{}
+	for i := range 3 {
+		gvr := schema.GroupVersionResource{Group: fmt.Sprintf("g%d.example.org", i), Version: "v1", Resource: "things"}
+		gvrs = append(gvrs, gvr)
+		listKinds[gvr] = "ThingList"
+	}
+	client := newFakeClient(listKinds)
+	client.PrependReactor("list", "things", func(a k8stesting.Action) (bool, runtime.Object, error) {
+		if a.GetResource() == gvrs[1] {
+			return true, nil, errors.New("apiserver unavailable")
+		}
+		return false, nil, nil
+	})
+	ctx := context.Background()
+
+	// exporter-8 left long ago; exporter-9 persisted just now, so may have
+	// joined after the members were read.
+	shared := map[string]store.Snapshot{
+		"exporter-8": {Shard: "exporter-8", PersistedAt: time.Now().Add(-time.Hour)},
+		"exporter-9": {Shard: "exporter-9", PersistedAt: time.Now()},
+	}
+	s := &fakeShardStore{MemoryStore: store.New(), identity: "exporter-0", shards: shared}
+	p := NewPoller(client, &config.Config{ClaimGVRs: gvrs, PollIntervalSeconds: 30}, s)
+	shards := NewShards(newFakeClient(map[schema.GroupVersionResource]string{leaseGVR: "LeaseList"}), "xp", "exporter-0", 30*time.Second)
+	p.SetShards(shards)
+
+	if p.poll(ctx) {
+		t.Fatal("expected the cycle to report errors")
+	}
+	own, ok := shared["exporter-0"]
+	if !ok {
+		t.Fatal("expected the shard to be persisted despite the failed GVR")
+	}
+	want := []string{GVRString(gvrs[0]), GVRString(gvrs[2])}
+	if !slices.Equal(own.GVRs, want) {
+		t.Errorf("expected the shard to list %v, got %v", want, own.GVRs)
+	}
+	if _, ok := shared["exporter-8"]; ok {
+		t.Error("expected the shard of a former member to be deleted")
+	}
+	if _, ok := shared["exporter-9"]; !ok {
+		t.Error("expected a recently persisted shard to be kept")
+	}
+}
@@ -0,0 +1,244 @@
+package kube
+
+import (
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// PollStatus describes the poller's recent cycles and the state of every
+// tracked GVR.
+type PollStatus struct {
+	IntervalSeconds int `json:"intervalSeconds"`
+	// Cycles is the number of completed poll cycles.
+	Cycles uint64 `json:"cycles"`
+	// LastStart and LastDurationSeconds describe the last completed cycle.
+	LastStart           time.Time `json:"lastStart,omitzero"`
+	LastDurationSeconds float64   `json:"lastDurationSeconds"`
+	// LastSuccess is the oldest of the last successful lists of the GVRs
+	// this replica polls; unset while one has not been listed yet.
+	LastSuccess time.Time `json:"lastSuccess,omitzero"`
+	// InProgressSince is set while a cycle is running, with
+	// CurrentDurationSeconds counting how long it has been running.
+	InProgressSince        time.Time `json:"inProgressSince,omitzero"`
+	CurrentDurationSeconds float64   `json:"currentDurationSeconds,omitempty"`
+	// SelectedNamespaces lists the namespaces matching the namespace
+	// selector, when one is configured.
+	SelectedNamespaces []string `json:"selectedNamespaces,omitempty"`
+	// Shard is set in sharded polling.
+	Shard *ShardStatus `json:"shard,omitempty"`
+
+	Claims []GVRStatus `json:"claims"`
+	XRs    []GVRStatus `json:"xrs"`
+	MRs    []GVRStatus `json:"mrs"`
+}
+
+// ShardStatus describes this replica's part in sharded polling.
+type ShardStatus struct {
+	Identity string   `json:"identity"`
+	Members  []string `json:"members"`
+	// OwnedGVRs is the number of GVRs this replica polls; the others are
+	// merged from the members' shards.
+	OwnedGVRs int `json:"ownedGVRs"`
+}
+
+// GVRStatus describes the outcome of listing one GVR.
+type GVRStatus struct {
+	GVR string `json:"gvr"`
+	// Items is the number of objects listed in the last attempt, which may
+	// be partial if it failed for some namespaces.
+	Items       int       `json:"items"`
+	LastSuccess time.Time `json:"lastSuccess,omitzero"`
+	LastError   string    `json:"lastError,omitempty"`
+	LastErrorAt time.Time `json:"lastErrorAt,omitzero"`
+	// IntervalSeconds is the GVR's current poll interval, which adaptive
+	// polling varies, and NextPoll when it is due next.
+	IntervalSeconds float64   `json:"intervalSeconds,omitempty"`
+	NextPoll        time.Time `json:"nextPoll,omitzero"`
+}
+
+// pollTracker records poll cycles and per-GVR results for Status. It is
+// safe for concurrent use, since MR GVRs are polled in parallel.
+type pollTracker struct {
+	mu              sync.Mutex
+	cycles          uint64
+	lastStart       time.Time
+	lastDuration    time.Duration
+	firstEnd        time.Time // when the first cycle completed
+	inProgressSince time.Time
+	namespaces      []string
+	shard           *ShardStatus
+	gvrs            map[statusKey]*GVRStatus
+	polled          []statusKey // the GVRs this replica polls
+}
+
+// statusKey identifies a GVR polled as a claim, XR or MR.
+type statusKey struct {
+	kind string // "claim", "xr" or "mr"
+	gvr  string
+}
+
+func (t *pollTracker) startCycle(now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.inProgressSince = now
+}
+
+func (t *pollTracker) endCycle(now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.cycles == 0 {
+		t.firstEnd = now
+	}
+	t.cycles++
+	t.lastStart = t.inProgressSince
+	t.lastDuration = now.Sub(t.inProgressSince)
+	t.inProgressSince = time.Time{}
+}
+
+// setNamespaces records the namespaces matching the namespace selector.
+func (t *pollTracker) setNamespaces(namespaces []string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.namespaces = namespaces
+}
+
+// setShard records this replica's shard membership at the start of a cycle.
+func (t *pollTracker) setShard(identity string, members []string, owned int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.shard = &ShardStatus{Identity: identity, Members: members, OwnedGVRs: owned}
+}
+
+// setPolled records the GVRs this replica polls, by class, at the start of
+// a cycle.
+func (t *pollTracker) setPolled(claims, xrs, mrs []schema.GroupVersionResource) {
+	polled := make([]statusKey, 0, len(claims)+len(xrs)+len(mrs))
+	for _, c := range []struct {
+		kind string
+		gvrs []schema.GroupVersionResource
+	}{{"claim", claims}, {"xr", xrs}, {"mr", mrs}} {
+		for _, gvr := range c.gvrs {
+			polled = append(polled, statusKey{kind: c.kind, gvr: GVRString(gvr)})
+		}
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.polled = polled
+}
+
+// oldestSuccess returns the oldest last success of the polled GVRs, or the
+// zero time if one has none. Without polled GVRs, the end of the last cycle
+// counts. The caller must hold t.mu.
+func (t *pollTracker) oldestSuccess() time.Time {
+	if len(t.polled) == 0 {
+		if t.cycles == 0 {
+			return time.Time{}
+		}
+		return t.lastStart.Add(t.lastDuration)
+	}
+	var oldest time.Time
+	for _, key := range t.polled {
+		st, ok := t.gvrs[key]
+		if !ok || st.LastSuccess.IsZero() {
+			return time.Time{}
+		}
+		if oldest.IsZero() || st.LastSuccess.Before(oldest) {
+			oldest = st.LastSuccess
+		}
+	}
+	return oldest
+}
+
+// record stores the result of listing gvr as kind.
+func (t *pollTracker) record(kind, gvr string, items int, err error) {
+	now := time.Now()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.gvrs == nil {
+		t.gvrs = make(map[statusKey]*GVRStatus)
+	}
+	key := statusKey{kind: kind, gvr: gvr}
+	st, ok := t.gvrs[key]
+	if !ok {
+		st = &GVRStatus{GVR: gvr}
+		t.gvrs[key] = st
+	}
+	st.Items = items
+	if err != nil {
+		st.LastError = err.Error()
+		st.LastErrorAt = now
+	} else {
+		st.LastSuccess = now
+		st.LastError = ""
+	}
+}
+
+// listed returns, as GVRStrings, the GVRs of kind in gvrs whose last list
+// succeeded.
+func (t *pollTracker) listed(kind string, gvrs []schema.GroupVersionResource) []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	out := make([]string, 0, len(gvrs))
+	for _, gvr := range gvrs {
+		gvrStr := GVRString(gvr)
+		if st, ok := t.gvrs[statusKey{kind: kind, gvr: gvrStr}]; ok && !st.LastSuccess.IsZero() && st.LastError == "" {
+			out = append(out, gvrStr)
+		}
+	}
+	return out
+}
+
+// Status returns the state of the poller. Every configured GVR is listed,
+// including those not polled yet.
+func (p *Poller) Status() PollStatus {
+	cfg := p.config()
+	t := &p.tracker
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	st := PollStatus{
+		IntervalSeconds:     cfg.PollIntervalSeconds,
+		Cycles:              t.cycles,
+		LastStart:           t.lastStart,
+		LastDurationSeconds: t.lastDuration.Seconds(),
+		LastSuccess:         t.oldestSuccess(),
+		InProgressSince:     t.inProgressSince,
+	}
+	if !t.inProgressSince.IsZero() {
+		st.CurrentDurationSeconds = time.Since(t.inProgressSince).Seconds()
+	}
+	if cfg.NamespaceSelector != "" {
+		st.SelectedNamespaces = slices.Clone(t.namespaces)
+	}
+	if t.shard != nil {
+		shard := *t.shard
+		shard.Members = slices.Clone(shard.Members)
+		st.Shard = &shard
+	}
+
+	gvrStatuses := func(kind string, gvrs []schema.GroupVersionResource) []GVRStatus {
+		out := make([]GVRStatus, 0, len(gvrs))
+		for _, gvr := range gvrs {
+			gvrStr := GVRString(gvr)
+			s := GVRStatus{GVR: gvrStr}
+			if r, ok := t.gvrs[statusKey{kind: kind, gvr: gvrStr}]; ok {
+				s = *r
+			}
+			if interval, next, ok := p.schedule.get(gvrStr); ok {
+				s.IntervalSeconds = interval.Seconds()
+				s.NextPoll = next
+			}
+			out = append(out, s)
+		}
+		slices.SortFunc(out, func(a, b GVRStatus) int { return strings.Compare(a.GVR, b.GVR) })
+		return out
+	}
+	st.Claims = gvrStatuses("claim", cfg.ClaimGVRs)
+	st.XRs = gvrStatuses("xr", cfg.XRGVRs)
+	st.MRs = gvrStatuses("mr", cfg.MRGVRs)
+	return st
+}
@@ -0,0 +1,77 @@
+package kube
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/kanzifucius/xp-tracker/pkg/config"
+	"github.com/kanzifucius/xp-tracker/pkg/store"
+)
+
+func TestPoller_Status(t *testing.T) {
+	claimGVR := schema.GroupVersionResource{Group: "g", Version: "v1", Resource: "things"}
+	xrGVR := schema.GroupVersionResource{Group: "g", Version: "v1", Resource: "xthings"}
+	mrGVR := schema.GroupVersionResource{Group: "aws", Version: "v1", Resource: "buckets"}
+
+	claim := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "g/v1",
+			"kind":       "Thing",
+			"metadata":   map[string]interface{}{"name": "t1", "namespace": "ns"},
+		},
+	}
+	client := newFakeClient(
+		map[schema.GroupVersionResource]string{
+			claimGVR: "ThingList",
+			xrGVR:    "XThingList",
+			mrGVR:    "BucketList",
+		},
+		claim,
+	)
+	client.PrependReactor("list", "xthings", func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.New("apiserver unavailable")
+	})
+
+	cfg := &config.Config{
+		ClaimGVRs:           []schema.GroupVersionResource{claimGVR},
+		XRGVRs:              []schema.GroupVersionResource{xrGVR},
+		MRGVRs:              []schema.GroupVersionResource{mrGVR},
+		CompositionLabelKey: "crossplane.io/composition-name",
+		CompositeLabelKey:   "crossplane.io/composite",
+		PollIntervalSeconds: 30,
+	}
+	poller := NewPoller(client, cfg, store.New())
+
+	// Configured GVRs are listed before the first cycle.
+	st := poller.Status()
+	if st.Cycles != 0 || len(st.Claims) != 1 || st.Claims[0].GVR != "g/v1/things" || !st.Claims[0].LastSuccess.IsZero() {
+		t.Fatalf("unexpected status before polling: %+v", st)
+	}
+
+	if poller.poll(context.Background()) {
+		t.Fatal("expected the cycle to report errors")
+	}
+
+	st = poller.Status()
+	if st.Cycles != 1 || st.LastStart.IsZero() || !st.InProgressSince.IsZero() || st.IntervalSeconds != 30 {
+		t.Errorf("unexpected cycle status: %+v", st)
+	}
+	if !st.LastSuccess.IsZero() {
+		t.Errorf("expected no successful cycle, got %v", st.LastSuccess)
+	}
+	if c := st.Claims[0]; c.Items != 1 || c.LastSuccess.IsZero() || c.LastError != "" || c.IntervalSeconds != 30 || c.NextPoll.IsZero() {
+		t.Errorf("unexpected claim GVR status: %+v", c)
+	}
+	if x := st.XRs[0]; x.LastError != "apiserver unavailable" || x.LastErrorAt.IsZero() || !x.LastSuccess.IsZero() {
+		t.Errorf("unexpected XR GVR status: %+v", x)
+	}
+	if m := st.MRs[0]; m.GVR != "aws/v1/buckets" || m.Items != 0 || m.LastSuccess.IsZero() {
+		t.Errorf("unexpected MR GVR status: %+v", m)
+	}
+}
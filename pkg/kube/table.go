@@ -0,0 +1,184 @@
+package kube
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+)
+
+// tableAccept asks the API server for a meta.k8s.io/v1 Table, falling back
+// to the full list on servers that cannot produce one.
+const tableAccept = "application/json;as=Table;v=v1;g=meta.k8s.io,application/json"
+
+// TableClient lists resources as server-side Tables holding each object's
+// metadata and printer columns instead of the full object. Crossplane
+// claims, XRs and MRs print their Ready and Synced conditions as columns, so
+// a Table carries everything the converters need except condition reasons
+// and spec fields, at a fraction of the size of large specs and statuses.
+type TableClient struct {
+	client rest.Interface
+
+	mu    sync.Mutex
+	kinds map[schema.GroupVersion]map[string]string // resource -> kind
+}
+
+// NewTableClient creates a TableClient for the API server in cfg.
+func NewTableClient(cfg *rest.Config) (*TableClient, error) {
+	cfg = rest.CopyConfig(cfg)
+	cfg.NegotiatedSerializer = scheme.Codecs.WithoutConversion()
+	client, err := rest.UnversionedRESTClientFor(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &TableClient{client: client, kinds: make(map[schema.GroupVersion]map[string]string)}, nil
+}
+
+// List lists one page of gvr in namespace, or across all namespaces when
+// namespace is empty. Each row is returned as an object with the row's
+// metadata and, from the READY and SYNCED columns, the Ready and Synced
+// conditions. A server that answers with a full list instead is decoded
+// as is.
+func (c *TableClient) List(ctx context.Context, gvr schema.GroupVersionResource, namespace string, opts metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+	req := c.client.Get().
+		AbsPath(resourcePath(gvr, namespace)).
+		SetHeader("Accept", tableAccept).
+		Param("includeObject", string(metav1.IncludeMetadata))
+	if opts.LabelSelector != "" {
+		req = req.Param("labelSelector", opts.LabelSelector)
+	}
+	if opts.FieldSelector != "" {
+		req = req.Param("fieldSelector", opts.FieldSelector)
+	}
+	if opts.Limit > 0 {
+		req = req.Param("limit", strconv.FormatInt(opts.Limit, 10))
+	}
+	if opts.Continue != "" {
+		req = req.Param("continue", opts.Continue)
+	}
+	body, err := req.DoRaw(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var table metav1.Table
+	if err := json.Unmarshal(body, &table); err != nil {
+		return nil, fmt.Errorf("decode table: %w", err)
+	}
+	if table.Kind != "Table" {
+		list := &unstructured.UnstructuredList{}
+		if err := list.UnmarshalJSON(body); err != nil {
+			return nil, fmt.Errorf("decode list: %w", err)
+		}
+		return list, nil
+	}
+
+	ready, synced := -1, -1
+	for i, col := range table.ColumnDefinitions {
+		switch strings.ToUpper(col.Name) {
+		case "READY":
+			ready = i
+		case "SYNCED":
+			synced = i
+		}
+	}
+	kind := c.kind(ctx, gvr)
+
+	list := &unstructured.UnstructuredList{}
+	list.SetContinue(table.Continue)
+	list.SetResourceVersion(table.ResourceVersion)
+	list.Items = make([]unstructured.Unstructured, 0, len(table.Rows))
+	for _, row := range table.Rows {
+		var partial struct {
+			Metadata map[string]any `json:"metadata"`
+		}
+		if err := json.Unmarshal(row.Object.Raw, &partial); err != nil {
+			return nil, fmt.Errorf("decode table row: %w", err)
+		}
+		obj := unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": gvr.GroupVersion().String(),
+			"kind":       kind,
+			"metadata":   partial.Metadata,
+		}}
+		var conditions []any
+		for _, cond := range []struct {
+			typ string
+			col int
+		}{{"Ready", ready}, {"Synced", synced}} {
+			if status := cell(row.Cells, cond.col); status != "" {
+				conditions = append(conditions, map[string]any{"type": cond.typ, "status": status})
+			}
+		}
+		if len(conditions) > 0 {
+			_ = unstructured.SetNestedSlice(obj.Object, conditions, "status", "conditions")
+		}
+		list.Items = append(list.Items, obj)
+	}
+	return list, nil
+}
+
+// kind returns the kind of gvr from API discovery, or an empty string, for
+// the converters to derive it from the resource name, when discovery fails.
+// Tables only carry the metadata of each object, not its kind.
+func (c *TableClient) kind(ctx context.Context, gvr schema.GroupVersionResource) string {
+	gv := gvr.GroupVersion()
+	c.mu.Lock()
+	kinds, ok := c.kinds[gv]
+	c.mu.Unlock()
+	if ok {
+		return kinds[gvr.Resource]
+	}
+
+	p := path.Join("/apis", gv.Group, gv.Version)
+	if gv.Group == "" {
+		p = path.Join("/api", gv.Version)
+	}
+	body, err := c.client.Get().AbsPath(p).DoRaw(ctx)
+	if err != nil {
+		return ""
+	}
+	var resources metav1.APIResourceList
+	if err := json.Unmarshal(body, &resources); err != nil {
+		return ""
+	}
+	kinds = make(map[string]string, len(resources.APIResources))
+	for _, r := range resources.APIResources {
+		kinds[r.Name] = r.Kind
+	}
+	c.mu.Lock()
+	c.kinds[gv] = kinds
+	c.mu.Unlock()
+	return kinds[gvr.Resource]
+}
+
+// resourcePath returns the API path listing gvr in namespace, or across all
+// namespaces when namespace is empty.
+func resourcePath(gvr schema.GroupVersionResource, namespace string) string {
+	parts := []string{"/apis", gvr.Group, gvr.Version}
+	if gvr.Group == "" {
+		parts = []string{"/api", gvr.Version}
+	}
+	if namespace != "" {
+		parts = append(parts, "namespaces", namespace)
+	}
+	return path.Join(append(parts, gvr.Resource)...)
+}
+
+// cell returns the table cell at index i as a string, or an empty string
+// when the column is missing.
+func cell(cells []any, i int) string {
+	if i < 0 || i >= len(cells) {
+		return ""
+	}
+	s, _ := cells[i].(string)
+	return s
+}
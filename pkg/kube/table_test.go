@@ -0,0 +1,318 @@
+package kube
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+
+	"github.com/kanzifucius/xp-tracker/pkg/config"
+	"github.com/kanzifucius/xp-tracker/pkg/store"
+)
+
+var bucketGVR = schema.GroupVersionResource{Group: "s3.aws.example.org", Version: "v1beta1", Resource: "buckets"}
+
+// fakeAPIServer serves a list of MRs in full or as a Table, the way the
+// API server does for a CRD with Crossplane's printer columns, and counts
+// the bytes it sends.
+type fakeAPIServer struct {
+	*httptest.Server
+	objects []unstructured.Unstructured
+	sent    atomic.Int64
+	tables  atomic.Int64
+}
+
+func newFakeAPIServer(t testing.TB, objects []unstructured.Unstructured) *fakeAPIServer {
+	f := &fakeAPIServer{objects: objects}
+	f.Server = httptest.NewServer(http.HandlerFunc(f.serve))
+	t.Cleanup(f.Close)
+	return f
+}
+
+func (f *fakeAPIServer) serve(w http.ResponseWriter, r *http.Request) {
+	var body any
+	switch r.URL.Path {
+	case "/apis/s3.aws.example.org/v1beta1":
+		body = metav1.APIResourceList{
+			TypeMeta:     metav1.TypeMeta{Kind: "APIResourceList", APIVersion: "v1"},
+			GroupVersion: "s3.aws.example.org/v1beta1",
+			APIResources: []metav1.APIResource{{Name: "buckets", Kind: "Bucket", Namespaced: false}},
+		}
+	case "/apis/s3.aws.example.org/v1beta1/buckets":
+		items, next := f.page(r)
+		if strings.Contains(r.Header.Get("Accept"), "as=Table") {
+			f.tables.Add(1)
+			body = bucketTable(items, next)
+		} else {
+			list := &unstructured.UnstructuredList{Object: map[string]any{"apiVersion": "s3.aws.example.org/v1beta1", "kind": "BucketList"}}
+			list.SetContinue(next)
+			list.Items = items
+			body = list
+		}
+	default:
+		http.NotFound(w, r)
+		return
+	}
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	f.sent.Add(int64(len(data)))
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(data)
+}
+
+// page returns the objects selected by the limit and continue parameters,
+// and the continue token of the next page.
+func (f *fakeAPIServer) page(r *http.Request) ([]unstructured.Unstructured, string) {
+	start, _ := strconv.Atoi(r.URL.Query().Get("continue"))
+	end := len(f.objects)
+	if limit, _ := strconv.Atoi(r.URL.Query().Get("limit")); limit > 0 && start+limit < end {
+		end = start + limit
+	}
+	next := ""
+	if end < len(f.objects) {
+		next = strconv.Itoa(end)
+	}
+	return f.objects[start:end], next
+}
+
+// bucketTable renders items as the API server does for includeObject=Metadata.
+func bucketTable(items []unstructured.Unstructured, next string) *metav1.Table {
+	table := &metav1.Table{
+		TypeMeta: metav1.TypeMeta{Kind: "Table", APIVersion: "meta.k8s.io/v1"},
+		ListMeta: metav1.ListMeta{Continue: next},
+		ColumnDefinitions: []metav1.TableColumnDefinition{
+			{Name: "Name", Type: "string"},
+			{Name: "SYNCED", Type: "string"},
+			{Name: "READY", Type: "string"},
+			{Name: "EXTERNAL-NAME", Type: "string"},
+			{Name: "AGE", Type: "date"},
+		},
+	}
+	for _, item := range items {
+		partial := map[string]any{
+			"kind":       "PartialObjectMetadata",
+			"apiVersion": "meta.k8s.io/v1",
+			"metadata":   item.Object["metadata"],
+		}
+		raw, _ := json.Marshal(partial)
+		table.Rows = append(table.Rows, metav1.TableRow{
+			Cells: []any{
+				item.GetName(),
+				conditionCell(item.Object, "Synced"),
+				conditionCell(item.Object, "Ready"),
+				item.GetAnnotations()["crossplane.io/external-name"],
+				"5d",
+			},
+			Object: runtime.RawExtension{Raw: raw},
+		})
+	}
+	return table
+}
+
+func conditionCell(obj map[string]any, conditionType string) string {
+	if extractConditionStatus(obj, conditionType) {
+		return "True"
+	}
+	return "False"
+}
+
+// bucket returns an MR with spec and status of a typical provider size.
+func bucket(i int) unstructured.Unstructured {
+	forProvider := make(map[string]any)
+	atProvider := make(map[string]any)
+	for j := range 40 {
+		forProvider[fmt.Sprintf("setting%d", j)] = strings.Repeat("v", 40)
+		atProvider[fmt.Sprintf("observed%d", j)] = strings.Repeat("o", 60)
+	}
+	ready := "True"
+	if i%5 == 0 {
+		ready = "False"
+	}
+	return unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "s3.aws.example.org/v1beta1",
+		"kind":       "Bucket",
+		"metadata": map[string]any{
+			"name":              fmt.Sprintf("bucket-%d", i),
+			"uid":               fmt.Sprintf("uid-%d", i),
+			"resourceVersion":   strconv.Itoa(1000 + i),
+			"creationTimestamp": "2026-10-01T00:00:00Z",
+			"labels":            map[string]any{"crossplane.io/composite": fmt.Sprintf("xbucket-%d", i)},
+			"annotations": map[string]any{
+				"crossplane.io/external-name":                      fmt.Sprintf("bucket-%d-ext", i),
+				"kubectl.kubernetes.io/last-applied-configuration": strings.Repeat("x", 1500),
+			},
+		},
+		"spec": map[string]any{
+			"forProvider":       forProvider,
+			"providerConfigRef": map[string]any{"name": "default"},
+		},
+		"status": map[string]any{
+			"atProvider": atProvider,
+			"conditions": []any{
+				map[string]any{"type": "Ready", "status": ready, "reason": "Available"},
+				map[string]any{"type": "Synced", "status": "True", "reason": "ReconcileSuccess"},
+			},
+		},
+	}}
+}
+
+func buckets(n int) []unstructured.Unstructured {
+	out := make([]unstructured.Unstructured, n)
+	for i := range out {
+		out[i] = bucket(i)
+	}
+	return out
+}
+
+func TestTableClient_List(t *testing.T) {
+	srv := newFakeAPIServer(t, buckets(5))
+	tables, err := NewTableClient(&rest.Config{Host: srv.URL})
+	if err != nil {
+		t.Fatalf("NewTableClient: %v", err)
+	}
+
+	var items []unstructured.Unstructured
+	opts := metav1.ListOptions{Limit: 2}
+	for pages := 0; ; pages++ {
+		list, err := tables.List(context.Background(), bucketGVR, "", opts)
+		if err != nil {
+			t.Fatalf("List: %v", err)
+		}
+		items = append(items, list.Items...)
+		if opts.Continue = list.GetContinue(); opts.Continue == "" {
+			if pages != 2 {
+				t.Errorf("expected 3 pages, got %d", pages+1)
+			}
+			break
+		}
+	}
+	if len(items) != 5 || srv.tables.Load() != 3 {
+		t.Fatalf("expected 5 objects from 3 tables, got %d from %d", len(items), srv.tables.Load())
+	}
+
+	cfg := &config.Config{CompositeLabelKey: "crossplane.io/composite"}
+	for i, item := range items {
+		mr := UnstructuredToMR(item, bucketGVR, cfg, "provider-aws-s3")
+		if mr.Kind != "Bucket" || mr.Name != fmt.Sprintf("bucket-%d", i) || mr.XRName != fmt.Sprintf("xbucket-%d", i) ||
+			mr.ExternalName != fmt.Sprintf("bucket-%d-ext", i) || mr.CreatedAt.IsZero() {
+			t.Errorf("unexpected MR from table row: %+v", mr)
+		}
+		if mr.Ready != (i%5 != 0) || !mr.Synced {
+			t.Errorf("%s: expected conditions from the table cells, got ready=%v synced=%v", mr.Name, mr.Ready, mr.Synced)
+		}
+		// Spec fields and reasons are not part of a table.
+		if mr.ProviderConfig != "" || mr.Reason != "" {
+			t.Errorf("%s: unexpected fields outside the table: %+v", mr.Name, mr)
+		}
+	}
+}
+
+func TestPoller_TableListing(t *testing.T) {
+	srv := newFakeAPIServer(t, buckets(3))
+	restCfg := &rest.Config{Host: srv.URL}
+	client, err := dynamic.NewForConfig(restCfg)
+	if err != nil {
+		t.Fatalf("dynamic client: %v", err)
+	}
+	tables, err := NewTableClient(restCfg)
+	if err != nil {
+		t.Fatalf("NewTableClient: %v", err)
+	}
+
+	for _, listing := range [][]string{nil, {"mrs"}} {
+		s := store.New()
+		cfg := &config.Config{
+			MRGVRs:              []schema.GroupVersionResource{bucketGVR},
+			CompositeLabelKey:   "crossplane.io/composite",
+			PollIntervalSeconds: 30,
+			TableListing:        listing,
+		}
+		poller := NewPoller(client, cfg, s)
+		poller.SetTableClient(tables)
+		before := srv.tables.Load()
+		if !poller.poll(context.Background()) {
+			t.Fatalf("TableListing=%v: expected the cycle to succeed", listing)
+		}
+
+		if got := srv.tables.Load() - before; (got > 0) != (len(listing) > 0) {
+			t.Errorf("TableListing=%v: unexpected %d table requests", listing, got)
+		}
+		mrs := s.View().MRs()
+		if len(mrs) != 3 {
+			t.Fatalf("TableListing=%v: expected 3 MRs, got %d", listing, len(mrs))
+		}
+		for _, mr := range mrs {
+			if mr.Kind != "Bucket" || mr.XRName == "" || mr.Ready != (mr.Name != "bucket-0") {
+				t.Errorf("TableListing=%v: unexpected MR %+v", listing, mr)
+			}
+		}
+	}
+}
+
+// benchmarkListMRs lists and converts 500 MRs per iteration through list,
+// and reports the bytes sent by the API server per list.
+func benchmarkListMRs(b *testing.B, list func(*rest.Config) func(context.Context, metav1.ListOptions) (*unstructured.UnstructuredList, error)) {
+	srv := newFakeAPIServer(b, buckets(500))
+	listPage := list(&rest.Config{Host: srv.URL})
+	cfg := &config.Config{CompositeLabelKey: "crossplane.io/composite"}
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		page, err := listPage(ctx, metav1.ListOptions{Limit: 500})
+		if err != nil {
+			b.Fatalf("list: %v", err)
+		}
+		mrs := make([]store.MRInfo, 0, len(page.Items))
+		for _, item := range page.Items {
+			mrs = append(mrs, UnstructuredToMR(item, bucketGVR, cfg, "provider-aws-s3"))
+		}
+		if len(mrs) != 500 {
+			b.Fatalf("expected 500 MRs, got %d", len(mrs))
+		}
+	}
+	b.StopTimer()
+	b.ReportMetric(float64(srv.sent.Load())/float64(b.N), "sent-B/op")
+}
+
+// BenchmarkListMRs_Full measures listing 500 MRs as full objects.
+func BenchmarkListMRs_Full(b *testing.B) {
+	benchmarkListMRs(b, func(cfg *rest.Config) func(context.Context, metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+		client, err := dynamic.NewForConfig(cfg)
+		if err != nil {
+			b.Fatalf("dynamic client: %v", err)
+		}
+		return client.Resource(bucketGVR).List
+	})
+}
+
+// BenchmarkListMRs_Table measures listing the same MRs as a Table. The
+// first iteration also includes the kind discovery request.
+func BenchmarkListMRs_Table(b *testing.B) {
+	benchmarkListMRs(b, func(cfg *rest.Config) func(context.Context, metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+		tables, err := NewTableClient(cfg)
+		if err != nil {
+			b.Fatalf("NewTableClient: %v", err)
+		}
+		return func(ctx context.Context, opts metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+			return tables.List(ctx, bucketGVR, "", opts)
+		}
+	})
+}
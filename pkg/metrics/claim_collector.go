@@ -54,6 +54,13 @@ var (
 		claimLabels,
 		nil,
 	)
+
+	claimDeletedRecentlyDesc = prometheus.NewDesc(
+		"crossplane_claims_deleted_recently",
+		"Number of Crossplane claims removed from the cluster within the tombstone retention period.",
+		[]string{"group", "kind", "namespace", "creator", "team"},
+		nil,
+	)
 )
 
 // claimAggKey is the label tuple used to aggregate claim metrics.
@@ -99,11 +106,18 @@ func (c *ClaimCollector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- claimStatusReadyDesc
 	ch <- claimCreatedTimestampDesc
 	ch <- claimDeletionTimestampDesc
+	ch <- claimDeletedRecentlyDesc
 }
 
-// Collect snapshots the store, aggregates by label tuple, and emits gauge metrics.
+// Collect reads one view of the store, aggregates by label tuple, and emits
+// gauge metrics.
 func (c *ClaimCollector) Collect(ch chan<- prometheus.Metric) {
-	claims := c.store.SnapshotClaims()
+	c.collect(ch, c.store.View())
+}
+
+// collect emits the metrics of the claims and tombstones in view.
+func (c *ClaimCollector) collect(ch chan<- prometheus.Metric, view store.View) {
+	claims := view.Claims()
 
 	agg := make(map[claimAggKey]*claimAggVal)
 	for _, claim := range claims {
@@ -223,6 +237,30 @@ func (c *ClaimCollector) Collect(ch chan<- prometheus.Metric) {
 			ch <- m
 		}
 	}
+
+	c.collectDeleted(ch, view)
+}
+
+// collectDeleted emits tombstone counts by group, kind, namespace, creator and team.
+func (c *ClaimCollector) collectDeleted(ch chan<- prometheus.Metric, view store.View) {
+	counts := make(map[[5]string]int)
+	for _, d := range view.DeletedClaims(store.Query{}) {
+		counts[[5]string{d.Group, d.Kind, d.Namespace, d.Creator, d.Team}]++
+	}
+	emitCounts(ch, claimDeletedRecentlyDesc, counts, func(k [5]string) []string { return k[:] })
+}
+
+// emitCounts sends one gauge per key in counts, using labels to turn the key
+// into the label values of desc.
+func emitCounts[K comparable](ch chan<- prometheus.Metric, desc *prometheus.Desc, counts map[K]int, labels func(K) []string) {
+	for key, n := range counts {
+		m, err := prometheus.NewConstMetric(desc, prometheus.GaugeValue, float64(n), labels(key)...)
+		if err != nil {
+			slog.Error("failed to create deleted_recently metric", "error", err)
+			continue
+		}
+		ch <- m
+	}
 }
 
 func boolToLabel(v bool) string {
@@ -162,8 +162,8 @@ func TestClaimCollector_Describe(t *testing.T) {
 	for range ch {
 		count++
 	}
-	if count != 6 {
-		t.Fatalf("expected 6 descriptors, got %d", count)
+	if count != 7 {
+		t.Fatalf("expected 7 descriptors, got %d", count)
 	}
 }
 
@@ -290,3 +290,31 @@ func assertLabel(t *testing.T, labels map[string]string, name, want string) {
 		t.Errorf("label %q: got %q, want %q", name, got, want)
 	}
 }
+
+func TestClaimCollector_DeletedRecently(t *testing.T) {
+	s := store.New()
+	gvr := "platform.example.org/v1alpha1/postgresqlinstances"
+	claim := store.ClaimInfo{
+		GVR: gvr, Group: "platform.example.org", Kind: "PostgreSQLInstance",
+		Namespace: "team-a", Name: "db", Creator: "alice", Team: "payments",
+	}
+
+	// Tombstones are disabled by default.
+	s.ReplaceClaims(gvr, []store.ClaimInfo{claim})
+	s.ReplaceClaims(gvr, nil)
+	if fam := gatherCollector(t, NewClaimCollector(s))["crossplane_claims_deleted_recently"]; fam != nil {
+		t.Fatalf("expected no deleted_recently samples without retention, got %v", fam)
+	}
+
+	s.SetTombstoneRetention(time.Hour)
+	s.ReplaceClaims(gvr, []store.ClaimInfo{claim})
+	s.ReplaceClaims(gvr, nil)
+	fam := gatherCollector(t, NewClaimCollector(s))["crossplane_claims_deleted_recently"]
+	if fam == nil || len(fam.GetMetric()) != 1 {
+		t.Fatalf("expected 1 deleted_recently sample, got %v", fam)
+	}
+	labels := labelMap(fam.GetMetric()[0])
+	assertLabel(t, labels, "namespace", "team-a")
+	assertLabel(t, labels, "creator", "alice")
+	assertLabel(t, labels, "team", "payments")
+}
@@ -0,0 +1,45 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/kanzifucius/xp-tracker/pkg/store"
+)
+
+// InventoryCollector implements prometheus.Collector for claims, XRs and MRs
+// together. Every scrape reads a single view of the store, so the three
+// families, and live objects and tombstones, always come from the same
+// generation.
+type InventoryCollector struct {
+	store  store.Store
+	claims *ClaimCollector
+	xrs    *XRCollector
+	mrs    *MRCollector
+}
+
+// NewInventoryCollector creates a new InventoryCollector.
+func NewInventoryCollector(s store.Store) *InventoryCollector {
+	return &InventoryCollector{
+		store:  s,
+		claims: NewClaimCollector(s),
+		xrs:    NewXRCollector(s),
+		mrs:    NewMRCollector(s),
+	}
+}
+
+// Describe sends the metric descriptors of the three collectors to the
+// channel.
+func (c *InventoryCollector) Describe(ch chan<- *prometheus.Desc) {
+	c.claims.Describe(ch)
+	c.xrs.Describe(ch)
+	c.mrs.Describe(ch)
+}
+
+// Collect reads one view of the store and emits the claim, XR and MR
+// metrics from it.
+func (c *InventoryCollector) Collect(ch chan<- prometheus.Metric) {
+	view := c.store.View()
+	c.claims.collect(ch, view)
+	c.xrs.collect(ch, view)
+	c.mrs.collect(ch, view)
+}
@@ -0,0 +1,47 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/kanzifucius/xp-tracker/pkg/store"
+)
+
+// commitOnView is a MemoryStore that runs commit once, right after the
+// first View is taken, to commit a generation in the middle of a scrape.
+type commitOnView struct {
+	*store.MemoryStore
+	commit func()
+}
+
+func (s *commitOnView) View() store.View {
+	v := s.MemoryStore.View()
+	if s.commit != nil {
+		commit := s.commit
+		s.commit = nil
+		commit()
+	}
+	return v
+}
+
+func TestInventoryCollector_OneViewPerScrape(t *testing.T) {
+	mem := store.New()
+	mem.ReplaceClaims("g/v1/things", []store.ClaimInfo{{GVR: "g/v1/things", Group: "g", Kind: "Thing", Namespace: "ns1", Name: "a"}})
+	mem.ReplaceXRs("g/v1/xthings", []store.XRInfo{{GVR: "g/v1/xthings", Group: "g", Kind: "XThing", Name: "a-x"}})
+	s := &commitOnView{MemoryStore: mem, commit: func() {
+		// Deleting everything turns the objects into tombstones.
+		mem.ReplaceClaims("g/v1/things", nil)
+		mem.ReplaceXRs("g/v1/xthings", nil)
+	}}
+
+	families := gatherCollector(t, NewInventoryCollector(s))
+	for _, name := range []string{"crossplane_claims_total", "crossplane_xr_total"} {
+		if f := families[name]; f == nil || len(f.GetMetric()) != 1 {
+			t.Errorf("expected %s from the generation the scrape started with, got %v", name, f)
+		}
+	}
+	for _, name := range []string{"crossplane_claims_deleted_recently", "crossplane_xr_deleted_recently"} {
+		if f := families[name]; f != nil {
+			t.Errorf("expected no %s from a later generation, got %v", name, f)
+		}
+	}
+}
@@ -57,6 +57,13 @@ var (
 		mrLabels,
 		nil,
 	)
+
+	mrDeletedRecentlyDesc = prometheus.NewDesc(
+		"crossplane_mr_deleted_recently",
+		"Number of Crossplane provider managed resources removed from the cluster within the tombstone retention period.",
+		[]string{"group", "kind", "provider"},
+		nil,
+	)
 )
 
 // mrAggKey is the label tuple used to aggregate MR metrics.
@@ -107,11 +114,18 @@ func (c *MRCollector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- mrStatusReadyDesc
 	ch <- mrCreatedTimestampDesc
 	ch <- mrDeletionTimestampDesc
+	ch <- mrDeletedRecentlyDesc
 }
 
-// Collect snapshots the store, aggregates by label tuple, and emits gauge metrics.
+// Collect reads one view of the store, aggregates by label tuple, and emits
+// gauge metrics.
 func (c *MRCollector) Collect(ch chan<- prometheus.Metric) {
-	mrs := c.store.SnapshotMRs()
+	c.collect(ch, c.store.View())
+}
+
+// collect emits the metrics of the MRs and tombstones in view.
+func (c *MRCollector) collect(ch chan<- prometheus.Metric, view store.View) {
+	mrs := view.MRs()
 
 	agg := make(map[mrAggKey]*mrAggVal)
 	for _, mr := range mrs {
@@ -208,4 +222,15 @@ func (c *MRCollector) Collect(ch chan<- prometheus.Metric) {
 			ch <- m
 		}
 	}
+
+	c.collectDeleted(ch, view)
+}
+
+// collectDeleted emits tombstone counts by group, kind and provider.
+func (c *MRCollector) collectDeleted(ch chan<- prometheus.Metric, view store.View) {
+	counts := make(map[[3]string]int)
+	for _, d := range view.DeletedMRs(store.Query{}) {
+		counts[[3]string{d.Group, d.Kind, d.Provider}]++
+	}
+	emitCounts(ch, mrDeletedRecentlyDesc, counts, func(k [3]string) []string { return k[:] })
 }
@@ -103,8 +103,8 @@ func TestMRCollector_Describe(t *testing.T) {
 	for range ch {
 		count++
 	}
-	if count != 6 {
-		t.Fatalf("expected 6 descriptors, got %d", count)
+	if count != 7 {
+		t.Fatalf("expected 7 descriptors, got %d", count)
 	}
 }
 
@@ -140,3 +140,28 @@ func TestMRCollector_Deleting(t *testing.T) {
 		t.Errorf("deletion timestamp: got %v, want %v", got, deletedAt.Unix())
 	}
 }
+
+func TestMRCollector_DeletedRecently(t *testing.T) {
+	s := store.New()
+	s.SetTombstoneRetention(time.Hour)
+	gvr := "s3.aws.upbound.io/v1beta1/buckets"
+	s.ReplaceMRs(gvr, []store.MRInfo{
+		{GVR: gvr, Group: "s3.aws.upbound.io", Kind: "Bucket", Name: "b1", Provider: "provider-aws-s3"},
+		{GVR: gvr, Group: "s3.aws.upbound.io", Kind: "Bucket", Name: "b2", Provider: "provider-aws-s3"},
+	})
+	s.ReplaceMRs(gvr, nil)
+
+	families := gatherCollector(t, NewMRCollector(s))
+	fam := families["crossplane_mr_deleted_recently"]
+	if fam == nil || len(fam.GetMetric()) != 1 {
+		t.Fatalf("expected 1 deleted_recently sample, got %v", fam)
+	}
+	m := fam.GetMetric()[0]
+	assertLabel(t, labelMap(m), "provider", "provider-aws-s3")
+	if got := m.GetGauge().GetValue(); got != 2 {
+		t.Errorf("expected 2 recently deleted MRs, got %v", got)
+	}
+	if families["crossplane_mr_total"] != nil {
+		t.Error("expected no crossplane_mr_total samples for deleted MRs")
+	}
+}
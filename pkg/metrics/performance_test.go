@@ -109,6 +109,100 @@ func TestXRCollector_Performance1000(t *testing.T) {
 	t.Logf("XR Collect: 1000 objects -> %d label tuples in %v", len(totalFam.GetMetric()), elapsed)
 }
 
+// populateLargeStore fills a store with a realistic large-cluster inventory:
+// one claim and one XR per 10 MRs, with XRs lacking claim labels so that
+// every enrichment pass has to resolve linkage through the store.
+func populateLargeStore(b *testing.B, s *store.MemoryStore, mrCount int) {
+	b.Helper()
+	xrCount := mrCount / 10
+	now := time.Now()
+
+	claims := make([]store.ClaimInfo, xrCount)
+	xrs := make([]store.XRInfo, xrCount)
+	for i := 0; i < xrCount; i++ {
+		xrName := fmt.Sprintf("xr-%d", i)
+		claims[i] = store.ClaimInfo{
+			GVR: "example.org/v1alpha1/things", Group: "example.org", Kind: "Thing",
+			Namespace: fmt.Sprintf("ns-%d", i%100), Name: fmt.Sprintf("claim-%d", i),
+			XRRef: xrName, CreatedAt: now,
+		}
+		xrs[i] = store.XRInfo{
+			GVR: "example.org/v1alpha1/xthings", Group: "example.org", Kind: "XThing",
+			Name: xrName, Composition: fmt.Sprintf("comp-%d", i%5), CreatedAt: now,
+		}
+	}
+
+	mrs := make([]store.MRInfo, mrCount)
+	for i := range mrs {
+		mrs[i] = store.MRInfo{
+			GVR: "nop.crossplane.io/v1alpha1/nopresources", Group: "nop.crossplane.io", Kind: "NopResource",
+			Name: fmt.Sprintf("mr-%d", i), XRName: fmt.Sprintf("xr-%d", i%xrCount),
+			Provider: "provider-nop", Ready: i%7 != 0, CreatedAt: now,
+		}
+	}
+
+	s.ReplaceXRs("example.org/v1alpha1/xthings", xrs)
+	s.ReplaceClaims("example.org/v1alpha1/things", claims)
+	s.ReplaceMRs("nop.crossplane.io/v1alpha1/nopresources", mrs)
+}
+
+// BenchmarkEnrichment_100kMRs measures a full enrichment pass over 10k
+// claims, 10k XRs and 100k MRs. With indexed lookups this is linear in the
+// number of objects rather than O(claims × XRs).
+func BenchmarkEnrichment_100kMRs(b *testing.B) {
+	s := store.New()
+	populateLargeStore(b, s, 100_000)
+	xrs := s.View().XRs()
+	mrs := s.View().MRs()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		// Reset to un-enriched XRs and MRs, as a fresh poll cycle would.
+		b.StopTimer()
+		s.ReplaceXRs("example.org/v1alpha1/xthings", xrs)
+		s.ReplaceMRs("nop.crossplane.io/v1alpha1/nopresources", mrs)
+		b.StartTimer()
+
+		s.EnrichClaimCompositions()
+		s.EnrichXRClaims()
+		s.EnrichMRClaims()
+	}
+}
+
+// BenchmarkReplaceMRs_100k measures the cost of replacing 100k MRs for a
+// single GVR, including secondary index maintenance.
+func BenchmarkReplaceMRs_100k(b *testing.B) {
+	s := store.New()
+	populateLargeStore(b, s, 100_000)
+	mrs := s.View().MRs()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s.ReplaceMRs("nop.crossplane.io/v1alpha1/nopresources", mrs)
+	}
+}
+
+// BenchmarkMRCollector_100k measures a /metrics scrape of 100k MRs.
+func BenchmarkMRCollector_100k(b *testing.B) {
+	s := store.New()
+	populateLargeStore(b, s, 100_000)
+	s.EnrichXRClaims()
+	s.EnrichMRClaims()
+
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(NewMRCollector(s))
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := reg.Gather(); err != nil {
+			b.Fatalf("gather failed: %v", err)
+		}
+	}
+}
+
 func findFamily(families []*dto.MetricFamily, name string) *dto.MetricFamily {
 	for _, f := range families {
 		if f.GetName() == name {
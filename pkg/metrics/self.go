@@ -40,6 +40,55 @@ var (
 		Help: "Current number of provider MRs in the in-memory store.",
 	})
 
+	// StoreGeneration reports the number of the currently published store
+	// generation. It increases by one with every completed poll cycle.
+	StoreGeneration = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "xp_tracker_store_generation",
+		Help: "Number of the currently published store generation.",
+	})
+
+	// LastSuccessfulPoll reports the oldest last successful list among the
+	// polled GVRs.
+	LastSuccessfulPoll = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "xp_tracker_last_successful_poll_timestamp_seconds",
+		Help: "Unix time of the oldest last successful list among the polled GVRs.",
+	})
+
+	// ConfigReloads counts config file reloads by result: "success" when the
+	// new configuration was applied, "failure" when it was rejected and the
+	// previous one kept.
+	ConfigReloads = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "xp_tracker_config_reload_total",
+		Help: "Total number of config file reloads, partitioned by result.",
+	}, []string{"result"})
+
+	// EventStreamClients reports the number of connected /events/stream
+	// clients.
+	EventStreamClients = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "xp_tracker_event_stream_clients",
+		Help: "Current number of clients connected to the change event stream.",
+	})
+
+	// ShardMembers reports the number of replicas taking part in sharded
+	// polling, including this one.
+	ShardMembers = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "xp_tracker_shard_members",
+		Help: "Current number of replicas taking part in sharded polling.",
+	})
+
+	// ShardOwnedGVRs reports the number of GVRs this replica polls in
+	// sharded polling.
+	ShardOwnedGVRs = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "xp_tracker_shard_owned_gvrs",
+		Help: "Current number of GVRs polled by this replica in sharded polling.",
+	})
+
+	// ShardLoadErrors counts failures to read a peer replica's shard.
+	ShardLoadErrors = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "xp_tracker_shard_load_errors_total",
+		Help: "Total number of failures to load the shard of a peer replica.",
+	})
+
 	// S3PersistDuration tracks the duration of S3 persist operations.
 	S3PersistDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
 		Name:    "xp_tracker_s3_persist_duration_seconds",
@@ -57,6 +106,13 @@ func RegisterSelfMetrics(reg prometheus.Registerer) {
 		StoreClaims,
 		StoreXRs,
 		StoreMRs,
+		StoreGeneration,
+		LastSuccessfulPoll,
+		ConfigReloads,
+		EventStreamClients,
+		ShardMembers,
+		ShardOwnedGVRs,
+		ShardLoadErrors,
 		S3PersistDuration,
 	)
 }
@@ -13,6 +13,7 @@ func TestRegisterSelfMetrics(t *testing.T) {
 
 	// Initialise the counter vec so it appears in Gather output.
 	PollErrors.WithLabelValues("test-register").Add(0)
+	ConfigReloads.WithLabelValues("success").Add(0)
 
 	families, err := reg.Gather()
 	if err != nil {
@@ -20,12 +21,18 @@ func TestRegisterSelfMetrics(t *testing.T) {
 	}
 
 	want := map[string]bool{
-		"xp_tracker_poll_duration_seconds":       false,
-		"xp_tracker_poll_errors_total":           false,
-		"xp_tracker_store_claims":                false,
-		"xp_tracker_store_xrs":                   false,
-		"xp_tracker_store_mrs":                   false,
-		"xp_tracker_s3_persist_duration_seconds": false,
+		"xp_tracker_poll_duration_seconds":                  false,
+		"xp_tracker_poll_errors_total":                      false,
+		"xp_tracker_store_claims":                           false,
+		"xp_tracker_store_xrs":                              false,
+		"xp_tracker_store_mrs":                              false,
+		"xp_tracker_store_generation":                       false,
+		"xp_tracker_last_successful_poll_timestamp_seconds": false,
+		"xp_tracker_config_reload_total":                    false,
+		"xp_tracker_shard_members":                          false,
+		"xp_tracker_shard_owned_gvrs":                       false,
+		"xp_tracker_shard_load_errors_total":                false,
+		"xp_tracker_s3_persist_duration_seconds":            false,
 	}
 
 	for _, fam := range families {
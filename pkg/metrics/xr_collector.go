@@ -53,6 +53,13 @@ var (
 		xrLabels,
 		nil,
 	)
+
+	xrDeletedRecentlyDesc = prometheus.NewDesc(
+		"crossplane_xr_deleted_recently",
+		"Number of Crossplane composite resources removed from the cluster within the tombstone retention period.",
+		[]string{"group", "kind", "composition"},
+		nil,
+	)
 )
 
 // xrAggKey is the label tuple used to aggregate XR metrics.
@@ -98,11 +105,18 @@ func (c *XRCollector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- xrStatusReadyDesc
 	ch <- xrCreatedTimestampDesc
 	ch <- xrDeletionTimestampDesc
+	ch <- xrDeletedRecentlyDesc
 }
 
-// Collect snapshots the store, aggregates by label tuple, and emits gauge metrics.
+// Collect reads one view of the store, aggregates by label tuple, and emits
+// gauge metrics.
 func (c *XRCollector) Collect(ch chan<- prometheus.Metric) {
-	xrs := c.store.SnapshotXRs()
+	c.collect(ch, c.store.View())
+}
+
+// collect emits the metrics of the XRs and tombstones in view.
+func (c *XRCollector) collect(ch chan<- prometheus.Metric, view store.View) {
+	xrs := view.XRs()
 
 	agg := make(map[xrAggKey]*xrAggVal)
 	for _, xr := range xrs {
@@ -222,4 +236,15 @@ func (c *XRCollector) Collect(ch chan<- prometheus.Metric) {
 			ch <- m
 		}
 	}
+
+	c.collectDeleted(ch, view)
+}
+
+// collectDeleted emits tombstone counts by group, kind and composition.
+func (c *XRCollector) collectDeleted(ch chan<- prometheus.Metric, view store.View) {
+	counts := make(map[[3]string]int)
+	for _, d := range view.DeletedXRs(store.Query{}) {
+		counts[[3]string{d.Group, d.Kind, d.Composition}]++
+	}
+	emitCounts(ch, xrDeletedRecentlyDesc, counts, func(k [3]string) []string { return k[:] })
 }
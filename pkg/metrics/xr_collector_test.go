@@ -141,8 +141,8 @@ func TestXRCollector_Describe(t *testing.T) {
 	for range ch {
 		count++
 	}
-	if count != 6 {
-		t.Fatalf("expected 6 descriptors, got %d", count)
+	if count != 7 {
+		t.Fatalf("expected 7 descriptors, got %d", count)
 	}
 }
 
@@ -220,3 +220,20 @@ func TestXRCollector_NoneReady(t *testing.T) {
 		t.Errorf("ready: expected 0, got %v", ready)
 	}
 }
+
+func TestXRCollector_DeletedRecently(t *testing.T) {
+	s := store.New()
+	s.SetTombstoneRetention(time.Hour)
+	gvr := "platform.example.org/v1alpha1/xpostgresqlinstances"
+	s.ReplaceXRs(gvr, []store.XRInfo{
+		{GVR: gvr, Group: "platform.example.org", Kind: "XPostgreSQLInstance", Name: "xr-1", Composition: "pg-aws"},
+	})
+	s.ReplaceXRs(gvr, nil)
+
+	families := gatherCollector(t, NewXRCollector(s))
+	fam := families["crossplane_xr_deleted_recently"]
+	if fam == nil || len(fam.GetMetric()) != 1 {
+		t.Fatalf("expected 1 deleted_recently sample, got %v", fam)
+	}
+	assertLabel(t, labelMap(fam.GetMetric()[0]), "composition", "pg-aws")
+}
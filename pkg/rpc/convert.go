@@ -0,0 +1,110 @@
+package rpc
+
+import (
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/kanzifucius/xp-tracker/pkg/api/xptrackerv1"
+	"github.com/kanzifucius/xp-tracker/pkg/store"
+)
+
+// timestamp converts t, leaving zero times unset.
+func timestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+func claimToProto(c store.ClaimInfo) *xptrackerv1.Claim {
+	return &xptrackerv1.Claim{
+		Gvr:         c.GVR,
+		Group:       c.Group,
+		Version:     c.Version,
+		Kind:        c.Kind,
+		Namespace:   c.Namespace,
+		Name:        c.Name,
+		Creator:     c.Creator,
+		Team:        c.Team,
+		Composition: c.Composition,
+		Paused:      c.Paused,
+		Synced:      c.Synced,
+		Ready:       c.Ready,
+		Reason:      c.Reason,
+		CreatedAt:   timestamp(c.CreatedAt),
+		DeletedAt:   timestamp(c.DeletedAt),
+		XrRef:       c.XRRef,
+	}
+}
+
+func xrToProto(x store.XRInfo) *xptrackerv1.XR {
+	return &xptrackerv1.XR{
+		Gvr:            x.GVR,
+		Group:          x.Group,
+		Version:        x.Version,
+		Kind:           x.Kind,
+		Namespace:      x.Namespace,
+		Name:           x.Name,
+		ClaimName:      x.ClaimName,
+		ClaimNamespace: x.ClaimNS,
+		Composition:    x.Composition,
+		Paused:         x.Paused,
+		Synced:         x.Synced,
+		Ready:          x.Ready,
+		Reason:         x.Reason,
+		CreatedAt:      timestamp(x.CreatedAt),
+		DeletedAt:      timestamp(x.DeletedAt),
+	}
+}
+
+func mrToProto(m store.MRInfo) *xptrackerv1.MR {
+	return &xptrackerv1.MR{
+		Gvr:                m.GVR,
+		Group:              m.Group,
+		Version:            m.Version,
+		Kind:               m.Kind,
+		Namespace:          m.Namespace,
+		Name:               m.Name,
+		XrName:             m.XRName,
+		ClaimName:          m.ClaimName,
+		ClaimNamespace:     m.ClaimNS,
+		Provider:           m.Provider,
+		ProviderConfig:     m.ProviderConfig,
+		ExternalName:       m.ExternalName,
+		ManagementPolicies: m.ManagementPolicies,
+		Paused:             m.Paused,
+		Synced:             m.Synced,
+		Ready:              m.Ready,
+		Reason:             m.Reason,
+		CreatedAt:          timestamp(m.CreatedAt),
+		DeletedAt:          timestamp(m.DeletedAt),
+	}
+}
+
+var changeTypes = map[store.ChangeType]xptrackerv1.WatchEvent_Type{
+	store.ChangeAdded:   xptrackerv1.WatchEvent_TYPE_ADDED,
+	store.ChangeUpdated: xptrackerv1.WatchEvent_TYPE_UPDATED,
+	store.ChangeRemoved: xptrackerv1.WatchEvent_TYPE_REMOVED,
+}
+
+func changeToProto(c store.Change) *xptrackerv1.WatchEvent {
+	ev := &xptrackerv1.WatchEvent{
+		Seq:        c.Seq,
+		Type:       changeTypes[c.Type],
+		Generation: c.Generation,
+		At:         timestamp(c.At),
+		Namespace:  c.Namespace,
+		Name:       c.Name,
+		Team:       c.Team,
+	}
+	switch obj := c.Object.(type) {
+	case store.ClaimInfo:
+		ev.Object = &xptrackerv1.WatchEvent_Claim{Claim: claimToProto(obj)}
+	case store.XRInfo:
+		ev.Object = &xptrackerv1.WatchEvent_Xr{Xr: xrToProto(obj)}
+	case store.MRInfo:
+		ev.Object = &xptrackerv1.WatchEvent_Mr{Mr: mrToProto(obj)}
+	}
+	return ev
+}
@@ -0,0 +1,238 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/kanzifucius/xp-tracker/pkg/api/xptrackerv1"
+	"github.com/kanzifucius/xp-tracker/pkg/auth"
+	"github.com/kanzifucius/xp-tracker/pkg/store"
+)
+
+// ListClaims implements xptrackerv1.InventoryServiceServer.
+func (s *Server) ListClaims(ctx context.Context, req *xptrackerv1.ListRequest) (*xptrackerv1.ListClaimsResponse, error) {
+	view, q, err := s.query(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	page, err := view.QueryClaims(q)
+	if err != nil {
+		return nil, queryError(err)
+	}
+	resp := &xptrackerv1.ListClaimsResponse{
+		Claims:        make([]*xptrackerv1.Claim, 0, len(page.Items)),
+		NextPageToken: page.Next,
+		Total:         int64(page.Total),
+		Generation:    page.Generation,
+	}
+	for _, c := range page.Items {
+		resp.Claims = append(resp.Claims, claimToProto(c))
+	}
+	return resp, nil
+}
+
+// ListXRs implements xptrackerv1.InventoryServiceServer.
+func (s *Server) ListXRs(ctx context.Context, req *xptrackerv1.ListRequest) (*xptrackerv1.ListXRsResponse, error) {
+	view, q, err := s.query(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	page, err := view.QueryXRs(q)
+	if err != nil {
+		return nil, queryError(err)
+	}
+	resp := &xptrackerv1.ListXRsResponse{
+		Xrs:           make([]*xptrackerv1.XR, 0, len(page.Items)),
+		NextPageToken: page.Next,
+		Total:         int64(page.Total),
+		Generation:    page.Generation,
+	}
+	for _, x := range page.Items {
+		resp.Xrs = append(resp.Xrs, xrToProto(x))
+	}
+	return resp, nil
+}
+
+// ListMRs implements xptrackerv1.InventoryServiceServer.
+func (s *Server) ListMRs(ctx context.Context, req *xptrackerv1.ListRequest) (*xptrackerv1.ListMRsResponse, error) {
+	view, q, err := s.query(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	page, err := view.QueryMRs(q)
+	if err != nil {
+		return nil, queryError(err)
+	}
+	resp := &xptrackerv1.ListMRsResponse{
+		Mrs:           make([]*xptrackerv1.MR, 0, len(page.Items)),
+		NextPageToken: page.Next,
+		Total:         int64(page.Total),
+		Generation:    page.Generation,
+	}
+	for _, m := range page.Items {
+		resp.Mrs = append(resp.Mrs, mrToProto(m))
+	}
+	return resp, nil
+}
+
+// query maps req onto a store query over the current generation, restricted
+// to the caller's tenant scope.
+func (s *Server) query(ctx context.Context, req *xptrackerv1.ListRequest) (store.View, store.Query, error) {
+	if req.GetPageSize() < 0 {
+		return store.View{}, store.Query{}, status.Error(codes.InvalidArgument, "page_size must not be negative")
+	}
+	q := store.Query{
+		GVR:         req.GetGvr(),
+		Namespace:   req.GetNamespace(),
+		Kind:        req.GetKind(),
+		Team:        req.GetTeam(),
+		Creator:     req.GetCreator(),
+		Composition: req.GetComposition(),
+		Provider:    req.GetProvider(),
+		Ready:       req.Ready,
+		Synced:      req.Synced,
+		Paused:      req.Paused,
+		Deleting:    req.Deleting,
+		SortBy:      store.SortField(req.GetSortBy()),
+		Descending:  req.GetDescending(),
+		Limit:       int(req.GetPageSize()),
+		Cursor:      req.GetPageToken(),
+	}
+
+	view := s.store.View()
+	scope, err := s.scope(ctx, view)
+	if err != nil {
+		return store.View{}, store.Query{}, err
+	}
+	q.Scope = scope
+	return view, q, nil
+}
+
+// scope resolves the caller's tenant scope over view, or nil without
+// tenancy.
+func (s *Server) scope(ctx context.Context, view store.View) (*store.Scope, error) {
+	if s.tenancy == nil {
+		return nil, nil
+	}
+	u, ok := auth.UserFrom(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "tenant scoping requires an authenticated request")
+	}
+	scope, err := s.tenancy.Scope(ctx, u, view.Namespaces())
+	if err != nil {
+		slog.Error("failed to resolve tenant scope", "error", err)
+		return nil, status.Error(codes.Unavailable, "failed to resolve tenant scope")
+	}
+	return scope, nil
+}
+
+func queryError(err error) error {
+	if errors.Is(err, store.ErrInvalidQuery) {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	slog.Error("failed to query store", "error", err)
+	return status.Error(codes.Internal, "failed to query store")
+}
+
+// GetClaim implements xptrackerv1.InventoryServiceServer. Objects outside
+// the caller's tenant scope are reported as not found, as by the Get RPCs
+// below.
+func (s *Server) GetClaim(ctx context.Context, req *xptrackerv1.GetClaimRequest) (*xptrackerv1.Claim, error) {
+	view := s.store.View()
+	scope, err := s.scope(ctx, view)
+	if err != nil {
+		return nil, err
+	}
+	c, ok := view.Claim(req.GetNamespace(), req.GetName())
+	if !ok || !view.AllowsClaim(scope, c) {
+		return nil, status.Error(codes.NotFound, "claim not found")
+	}
+	return claimToProto(c), nil
+}
+
+// GetXR implements xptrackerv1.InventoryServiceServer.
+func (s *Server) GetXR(ctx context.Context, req *xptrackerv1.GetXRRequest) (*xptrackerv1.XR, error) {
+	view := s.store.View()
+	scope, err := s.scope(ctx, view)
+	if err != nil {
+		return nil, err
+	}
+	x, ok := view.XR(req.GetNamespace(), req.GetName())
+	if !ok || !view.AllowsXR(scope, x) {
+		return nil, status.Error(codes.NotFound, "XR not found")
+	}
+	return xrToProto(x), nil
+}
+
+// GetMR implements xptrackerv1.InventoryServiceServer.
+func (s *Server) GetMR(ctx context.Context, req *xptrackerv1.GetMRRequest) (*xptrackerv1.MR, error) {
+	view := s.store.View()
+	scope, err := s.scope(ctx, view)
+	if err != nil {
+		return nil, err
+	}
+	m, ok := view.MR(req.GetGvr(), req.GetNamespace(), req.GetName())
+	if !ok || !view.AllowsMR(scope, m) {
+		return nil, status.Error(codes.NotFound, "MR not found")
+	}
+	return mrToProto(m), nil
+}
+
+// Watch implements xptrackerv1.InventoryServiceServer. It follows the same
+// rules as the /events/stream endpoint: without after_seq the stream starts
+// with the next change, and a RESET event is sent when the requested
+// changes are no longer buffered. With a tenant scope, resolved when the
+// watch starts, only changes to objects in the caller's scope are sent.
+func (s *Server) Watch(req *xptrackerv1.WatchRequest, stream grpc.ServerStreamingServer[xptrackerv1.WatchEvent]) error {
+	feed := s.store.Changes()
+	if !feed.Enabled() {
+		return status.Error(codes.FailedPrecondition, "change feed is disabled")
+	}
+	scope, err := s.scope(stream.Context(), s.store.View())
+	if err != nil {
+		return err
+	}
+
+	seq := feed.LastSeq()
+	if req.AfterSeq != nil {
+		seq = req.GetAfterSeq()
+	}
+	// Send headers right away, so clients know the watch is established
+	// before the first change arrives.
+	if err := stream.SendHeader(nil); err != nil {
+		return err
+	}
+
+	for {
+		changes, wait, ok := feed.Since(seq)
+		if !ok {
+			seq = feed.LastSeq()
+			if err := stream.Send(&xptrackerv1.WatchEvent{Seq: seq, Type: xptrackerv1.WatchEvent_TYPE_RESET}); err != nil {
+				return err
+			}
+			changes, wait, _ = feed.Since(seq)
+		}
+		for _, c := range changes {
+			seq = c.Seq
+			if (req.GetNamespace() != "" && c.Namespace != req.GetNamespace()) || (req.GetTeam() != "" && c.Team != req.GetTeam()) || !scope.AllowsChange(c) {
+				continue
+			}
+			if err := stream.Send(changeToProto(c)); err != nil {
+				return err
+			}
+		}
+
+		select {
+		case <-wait:
+		case <-stream.Context().Done():
+			return nil
+		case <-s.stopping:
+			return status.Error(codes.Unavailable, "server is shutting down")
+		}
+	}
+}
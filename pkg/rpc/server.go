@@ -0,0 +1,222 @@
+// Package rpc serves the inventory over gRPC: List, Get and Watch RPCs for
+// claims, XRs and MRs backed by the store, plus the standard gRPC health
+// service.
+package rpc
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"log/slog"
+	"net"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/kanzifucius/xp-tracker/pkg/api/xptrackerv1"
+	"github.com/kanzifucius/xp-tracker/pkg/auth"
+	"github.com/kanzifucius/xp-tracker/pkg/store"
+)
+
+// shutdownTimeout bounds the graceful stop before open calls are cancelled.
+const shutdownTimeout = 5 * time.Second
+
+// Server serves the InventoryService and gRPC health checking.
+type Server struct {
+	xptrackerv1.UnimplementedInventoryServiceServer
+
+	addr      string
+	store     store.Store
+	health    *health.Server
+	tlsConfig *tls.Config   // nil serves plaintext
+	filter    *auth.Filter  // nil leaves every RPC open
+	tenancy   *auth.Tenancy // nil shows every caller the full inventory
+	listener  net.Listener
+	listening chan struct{} // closed once the listener is bound
+	stopping  chan struct{} // closed when shutdown begins, ends Watch streams
+}
+
+// New creates a gRPC Server for s. The health service reports NOT_SERVING
+// until SetReady(true) is called.
+func New(addr string, s store.Store) *Server {
+	srv := &Server{
+		addr:      addr,
+		store:     s,
+		health:    health.NewServer(),
+		listening: make(chan struct{}),
+		stopping:  make(chan struct{}),
+	}
+	srv.setServingStatus(healthpb.HealthCheckResponse_NOT_SERVING)
+	return srv
+}
+
+// Addr returns the listener address. It blocks until Run has opened the
+// listener. Useful for tests using ":0".
+func (s *Server) Addr() string {
+	<-s.listening
+	return s.listener.Addr().String()
+}
+
+// SetReady reports the server and the InventoryService to health checks as
+// SERVING when ready is true and NOT_SERVING otherwise. Call it whenever
+// readiness changes.
+func (s *Server) SetReady(ready bool) {
+	st := healthpb.HealthCheckResponse_NOT_SERVING
+	if ready {
+		st = healthpb.HealthCheckResponse_SERVING
+	}
+	s.setServingStatus(st)
+}
+
+func (s *Server) setServingStatus(st healthpb.HealthCheckResponse_ServingStatus) {
+	s.health.SetServingStatus("", st)
+	s.health.SetServingStatus(xptrackerv1.InventoryService_ServiceDesc.ServiceName, st)
+}
+
+// SetTLS serves gRPC over TLS with cfg. Call it before Run.
+func (s *Server) SetTLS(cfg *tls.Config) {
+	s.tlsConfig = cfg
+}
+
+// SetAuth requires authentication and authorization for the RPCs f
+// protects. The full method name ("/xptracker.v1.InventoryService/ListClaims")
+// is checked as the request path. Health checks are never protected. Call
+// it before Run.
+func (s *Server) SetAuth(f *auth.Filter) {
+	s.filter = f
+}
+
+// SetTenancy restricts the results of the inventory RPCs to what each
+// authenticated caller may see. The RPCs must then be protected (see
+// SetAuth). Call it before Run.
+func (s *Server) SetTenancy(t *auth.Tenancy) {
+	s.tenancy = t
+}
+
+// InventoryMethods returns the full method names of the InventoryService
+// RPCs, which tenant scoping restricts.
+func InventoryMethods() []string {
+	desc := xptrackerv1.InventoryService_ServiceDesc
+	var out []string
+	for _, m := range desc.Methods {
+		out = append(out, "/"+desc.ServiceName+"/"+m.MethodName)
+	}
+	for _, st := range desc.Streams {
+		out = append(out, "/"+desc.ServiceName+"/"+st.StreamName)
+	}
+	return out
+}
+
+// Run serves gRPC until ctx is cancelled, then stops gracefully.
+func (s *Server) Run(ctx context.Context) error {
+	var lc net.ListenConfig
+	ln, err := lc.Listen(ctx, "tcp", s.addr)
+	if err != nil {
+		close(s.listening)
+		return err
+	}
+	s.listener = ln
+	close(s.listening)
+
+	opts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(s.unaryAuth),
+		grpc.ChainStreamInterceptor(s.streamAuth),
+	}
+	if s.tlsConfig != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(s.tlsConfig)))
+	}
+	gs := grpc.NewServer(opts...)
+	xptrackerv1.RegisterInventoryServiceServer(gs, s)
+	healthpb.RegisterHealthServer(gs, s.health)
+
+	errCh := make(chan error, 1)
+	go func() {
+		slog.Info("gRPC server listening", "addr", ln.Addr().String(), "tls", s.tlsConfig != nil)
+		if err := gs.Serve(ln); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+			errCh <- err
+		}
+	}()
+
+	var runErr error
+	select {
+	case <-ctx.Done():
+		slog.Info("shutting down gRPC server")
+	case runErr = <-errCh:
+	}
+
+	s.health.Shutdown()
+	close(s.stopping)
+	stopped := make(chan struct{})
+	go func() {
+		gs.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(shutdownTimeout):
+		gs.Stop()
+	}
+	return runErr
+}
+
+// authorize checks the caller of method when the filter protects it and
+// returns a context carrying the authenticated user.
+func (s *Server) authorize(ctx context.Context, method string) (context.Context, error) {
+	if s.filter == nil || strings.HasPrefix(method, "/"+healthpb.Health_ServiceDesc.ServiceName+"/") || !s.filter.Protects(method) {
+		return ctx, nil
+	}
+
+	var token string
+	var ok bool
+	if md, found := metadata.FromIncomingContext(ctx); found {
+		if v := md.Get("authorization"); len(v) > 0 {
+			token, ok = auth.BearerToken(v[0])
+		}
+	}
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "missing bearer token")
+	}
+
+	u, err := s.filter.Check(ctx, token, "get", method)
+	switch {
+	case errors.Is(err, auth.ErrUnauthenticated):
+		return nil, status.Error(codes.Unauthenticated, "invalid bearer token")
+	case errors.Is(err, auth.ErrForbidden):
+		return nil, status.Error(codes.PermissionDenied, "forbidden")
+	case err != nil:
+		slog.Error("access review failed", "method", method, "error", err)
+		return nil, status.Error(codes.Unavailable, "access review failed")
+	}
+	return auth.WithUser(ctx, u), nil
+}
+
+func (s *Server) unaryAuth(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	ctx, err := s.authorize(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (s *Server) streamAuth(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := s.authorize(ss.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	return handler(srv, &authedStream{ServerStream: ss, ctx: ctx})
+}
+
+// authedStream carries the authenticated user in its context.
+type authedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authedStream) Context() context.Context { return s.ctx }
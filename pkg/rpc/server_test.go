@@ -0,0 +1,346 @@
+package rpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/kanzifucius/xp-tracker/pkg/api/xptrackerv1"
+	"github.com/kanzifucius/xp-tracker/pkg/auth"
+	"github.com/kanzifucius/xp-tracker/pkg/store"
+)
+
+const bucketGVR = "s3.aws.upbound.io/v1beta1/buckets"
+
+func testStore() *store.MemoryStore {
+	s := store.New()
+	s.ReplaceClaims("g/v1/things", []store.ClaimInfo{
+		{GVR: "g/v1/things", Kind: "Thing", Namespace: "team-a", Name: "db-1", Team: "backend", XRRef: "xr-1", Ready: true, CreatedAt: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{GVR: "g/v1/things", Kind: "Thing", Namespace: "team-a", Name: "db-2", Team: "backend"},
+		{GVR: "g/v1/things", Kind: "Thing", Namespace: "team-b", Name: "db-3", Team: "frontend", Ready: true},
+	})
+	s.ReplaceXRs("g/v1/xthings", []store.XRInfo{
+		{GVR: "g/v1/xthings", Kind: "XThing", Name: "xr-1", ClaimName: "db-1", ClaimNS: "team-a"},
+	})
+	s.ReplaceMRs(bucketGVR, []store.MRInfo{
+		{GVR: bucketGVR, Kind: "Bucket", Name: "bucket-1", XRName: "xr-1", ClaimName: "db-1", ClaimNS: "team-a", Provider: "provider-aws-s3"},
+	})
+	return s
+}
+
+// startServer runs srv and returns a client connection to it.
+func startServer(t *testing.T, srv *Server) *grpc.ClientConn {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go func() {
+		_ = srv.Run(ctx)
+	}()
+
+	conn, err := grpc.NewClient(srv.Addr(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+func TestListClaims(t *testing.T) {
+	client := xptrackerv1.NewInventoryServiceClient(startServer(t, New("127.0.0.1:0", testStore())))
+	ctx := context.Background()
+
+	resp, err := client.ListClaims(ctx, &xptrackerv1.ListRequest{Team: "backend", Ready: proto.Bool(true)})
+	if err != nil {
+		t.Fatalf("ListClaims: %v", err)
+	}
+	if len(resp.GetClaims()) != 1 || resp.GetClaims()[0].GetName() != "db-1" || resp.GetTotal() != 1 {
+		t.Fatalf("expected only db-1, got %v", resp.GetClaims())
+	}
+	if got := resp.GetClaims()[0].GetCreatedAt().AsTime(); !got.Equal(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected created_at %v", got)
+	}
+	if resp.GetClaims()[0].GetDeletedAt() != nil {
+		t.Error("expected deleted_at to be unset for a claim that is not deleting")
+	}
+
+	var names []string
+	req := &xptrackerv1.ListRequest{PageSize: 2, SortBy: "name"}
+	for {
+		page, err := client.ListClaims(ctx, req)
+		if err != nil {
+			t.Fatalf("ListClaims: %v", err)
+		}
+		for _, c := range page.GetClaims() {
+			names = append(names, c.GetName())
+		}
+		if page.GetNextPageToken() == "" {
+			break
+		}
+		req.PageToken = page.GetNextPageToken()
+	}
+	if len(names) != 3 || names[0] != "db-1" || names[2] != "db-3" {
+		t.Errorf("expected all claims in name order, got %v", names)
+	}
+
+	// MRs take their team from the claim.
+	mrs, err := client.ListMRs(ctx, &xptrackerv1.ListRequest{Team: "backend"})
+	if err != nil {
+		t.Fatalf("ListMRs: %v", err)
+	}
+	if len(mrs.GetMrs()) != 1 || mrs.GetMrs()[0].GetProvider() != "provider-aws-s3" {
+		t.Errorf("expected bucket-1, got %v", mrs.GetMrs())
+	}
+
+	for _, bad := range []*xptrackerv1.ListRequest{
+		{SortBy: "color"},
+		{PageSize: -1},
+		{PageSize: 1, PageToken: "garbage"},
+	} {
+		if _, err := client.ListXRs(ctx, bad); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("expected InvalidArgument for %v, got %v", bad, err)
+		}
+	}
+}
+
+func TestGet(t *testing.T) {
+	client := xptrackerv1.NewInventoryServiceClient(startServer(t, New("127.0.0.1:0", testStore())))
+	ctx := context.Background()
+
+	claim, err := client.GetClaim(ctx, &xptrackerv1.GetClaimRequest{Namespace: "team-a", Name: "db-1"})
+	if err != nil || claim.GetXrRef() != "xr-1" {
+		t.Errorf("GetClaim: %v, %v", claim, err)
+	}
+	xr, err := client.GetXR(ctx, &xptrackerv1.GetXRRequest{Name: "xr-1"})
+	if err != nil || xr.GetClaimNamespace() != "team-a" {
+		t.Errorf("GetXR: %v, %v", xr, err)
+	}
+	mr, err := client.GetMR(ctx, &xptrackerv1.GetMRRequest{Gvr: bucketGVR, Name: "bucket-1"})
+	if err != nil || mr.GetXrName() != "xr-1" {
+		t.Errorf("GetMR: %v, %v", mr, err)
+	}
+	if _, err := client.GetClaim(ctx, &xptrackerv1.GetClaimRequest{Namespace: "team-a", Name: "missing"}); status.Code(err) != codes.NotFound {
+		t.Errorf("expected NotFound, got %v", err)
+	}
+}
+
+func TestWatch(t *testing.T) {
+	s := testStore()
+	s.SetChangeBufferSize(2)
+	client := xptrackerv1.NewInventoryServiceClient(startServer(t, New("127.0.0.1:0", s)))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream, err := client.Watch(ctx, &xptrackerv1.WatchRequest{Namespace: "team-b"})
+	if err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+	// Wait until the stream is established before changing the store, so
+	// the change is not missed.
+	if _, err := stream.Header(); err != nil {
+		t.Fatalf("Header: %v", err)
+	}
+
+	s.BeginGeneration()
+	s.ReplaceClaims("g/v1/things", []store.ClaimInfo{
+		{GVR: "g/v1/things", Kind: "Thing", Namespace: "team-a", Name: "db-1", Team: "backend", XRRef: "xr-1", Ready: true, CreatedAt: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{GVR: "g/v1/things", Kind: "Thing", Namespace: "team-a", Name: "db-2", Team: "backend"},
+		{GVR: "g/v1/things", Kind: "Thing", Namespace: "team-b", Name: "db-3", Team: "frontend"},
+	})
+	s.CommitGeneration()
+
+	ev, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("Recv: %v", err)
+	}
+	if ev.GetType() != xptrackerv1.WatchEvent_TYPE_UPDATED || ev.GetClaim().GetName() != "db-3" || ev.GetClaim().GetReady() {
+		t.Errorf("expected db-3 to be updated to not ready, got %v", ev)
+	}
+
+	// Two more changes push the first out of the buffer, so resuming from
+	// before it resets.
+	s.BeginGeneration()
+	s.ReplaceClaims("g/v1/things", []store.ClaimInfo{
+		{GVR: "g/v1/things", Kind: "Thing", Namespace: "team-a", Name: "db-1", Team: "backend", XRRef: "xr-1"},
+		{GVR: "g/v1/things", Kind: "Thing", Namespace: "team-a", Name: "db-2", Team: "backend", Ready: true},
+		{GVR: "g/v1/things", Kind: "Thing", Namespace: "team-b", Name: "db-3", Team: "frontend"},
+	})
+	s.CommitGeneration()
+
+	reset, err := client.Watch(ctx, &xptrackerv1.WatchRequest{AfterSeq: proto.Uint64(0)})
+	if err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+	ev, err = reset.Recv()
+	if err != nil {
+		t.Fatalf("Recv: %v", err)
+	}
+	if ev.GetType() != xptrackerv1.WatchEvent_TYPE_RESET {
+		t.Errorf("expected a reset event, got %v", ev)
+	}
+}
+
+func TestHealth(t *testing.T) {
+	srv := New("127.0.0.1:0", testStore())
+	client := healthpb.NewHealthClient(startServer(t, srv))
+	ctx := context.Background()
+
+	check := func(service string) healthpb.HealthCheckResponse_ServingStatus {
+		t.Helper()
+		resp, err := client.Check(ctx, &healthpb.HealthCheckRequest{Service: service})
+		if err != nil {
+			t.Fatalf("Check(%q): %v", service, err)
+		}
+		return resp.GetStatus()
+	}
+
+	service := xptrackerv1.InventoryService_ServiceDesc.ServiceName
+	if got := check(""); got != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Errorf("expected NOT_SERVING before ready, got %v", got)
+	}
+	srv.SetReady(true)
+	if got := check(""); got != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("expected SERVING after ready, got %v", got)
+	}
+	if got := check(service); got != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("expected %s SERVING after ready, got %v", service, got)
+	}
+	srv.SetReady(false)
+	if got := check(service); got != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Errorf("expected %s NOT_SERVING once no longer ready, got %v", service, got)
+	}
+}
+
+// tokenReviewer accepts the token "good" as alice, who may only call
+// ListClaims.
+type tokenReviewer struct{}
+
+func (tokenReviewer) Authenticate(_ context.Context, token string) (auth.User, bool, error) {
+	return auth.User{Name: "alice"}, token == "good", nil
+}
+
+func (tokenReviewer) Authorize(_ context.Context, u auth.User, verb, path string) (bool, string, error) {
+	return u.Name == "alice" && verb == "get" && path == "/xptracker.v1.InventoryService/ListClaims", "", nil
+}
+
+func (tokenReviewer) AuthorizeResource(context.Context, auth.User, auth.ResourceAttributes) (bool, error) {
+	return false, nil
+}
+
+func TestAuth(t *testing.T) {
+	srv := New("127.0.0.1:0", testStore())
+	srv.SetAuth(auth.NewFilter(tokenReviewer{}, []string{"/xptracker.v1.InventoryService/"}))
+	conn := startServer(t, srv)
+	client := xptrackerv1.NewInventoryServiceClient(conn)
+
+	withToken := func(token string) context.Context {
+		return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+	}
+
+	if _, err := client.ListClaims(context.Background(), &xptrackerv1.ListRequest{}); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("expected Unauthenticated without a token, got %v", err)
+	}
+	if _, err := client.ListClaims(withToken("bad"), &xptrackerv1.ListRequest{}); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("expected Unauthenticated for an invalid token, got %v", err)
+	}
+	if _, err := client.ListClaims(withToken("good"), &xptrackerv1.ListRequest{}); err != nil {
+		t.Errorf("expected ListClaims to be allowed, got %v", err)
+	}
+	if _, err := client.ListXRs(withToken("good"), &xptrackerv1.ListRequest{}); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("expected PermissionDenied, got %v", err)
+	}
+	stream, err := client.Watch(context.Background(), &xptrackerv1.WatchRequest{})
+	if err == nil {
+		_, err = stream.Recv()
+	}
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("expected Watch to require a token, got %v", err)
+	}
+
+	// Health checks stay open.
+	if _, err := healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{}); err != nil {
+		t.Errorf("expected health check without a token, got %v", err)
+	}
+}
+
+// teamReviewer authenticates any token as a user in the group of the same
+// name, allowed every RPC but no namespace.
+type teamReviewer struct{}
+
+func (teamReviewer) Authenticate(_ context.Context, token string) (auth.User, bool, error) {
+	return auth.User{Name: token, Groups: []string{token}}, true, nil
+}
+
+func (teamReviewer) Authorize(context.Context, auth.User, string, string) (bool, string, error) {
+	return true, "", nil
+}
+
+func (teamReviewer) AuthorizeResource(context.Context, auth.User, auth.ResourceAttributes) (bool, error) {
+	return false, nil
+}
+
+func TestTenancy(t *testing.T) {
+	s := testStore()
+	s.SetChangeBufferSize(10)
+	srv := New("127.0.0.1:0", s)
+	srv.SetAuth(auth.NewFilter(teamReviewer{}, []string{"/xptracker.v1.InventoryService/"}))
+	srv.SetTenancy(auth.NewTenancy(teamReviewer{}, auth.TenancyOptions{ByTeam: true}))
+	client := xptrackerv1.NewInventoryServiceClient(startServer(t, srv))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	as := func(team string) context.Context {
+		return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+team)
+	}
+
+	if _, err := client.GetClaim(as("backend"), &xptrackerv1.GetClaimRequest{Namespace: "team-a", Name: "db-1"}); err != nil {
+		t.Errorf("GetClaim in scope: %v", err)
+	}
+	if _, err := client.GetClaim(as("frontend"), &xptrackerv1.GetClaimRequest{Namespace: "team-a", Name: "db-1"}); status.Code(err) != codes.NotFound {
+		t.Errorf("expected NotFound for a claim out of scope, got %v", err)
+	}
+	if _, err := client.GetXR(as("frontend"), &xptrackerv1.GetXRRequest{Name: "xr-1"}); status.Code(err) != codes.NotFound {
+		t.Errorf("expected NotFound for an XR out of scope, got %v", err)
+	}
+	if _, err := client.GetMR(as("frontend"), &xptrackerv1.GetMRRequest{Gvr: bucketGVR, Name: "bucket-1"}); status.Code(err) != codes.NotFound {
+		t.Errorf("expected NotFound for an MR out of scope, got %v", err)
+	}
+
+	stream, err := client.Watch(as("frontend"), &xptrackerv1.WatchRequest{})
+	if err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+	if _, err := stream.Header(); err != nil {
+		t.Fatalf("Header: %v", err)
+	}
+	s.BeginGeneration()
+	s.ReplaceClaims("g/v1/things", []store.ClaimInfo{
+		{GVR: "g/v1/things", Kind: "Thing", Namespace: "team-a", Name: "db-1", Team: "backend", XRRef: "xr-1", CreatedAt: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{GVR: "g/v1/things", Kind: "Thing", Namespace: "team-a", Name: "db-2", Team: "backend"},
+		{GVR: "g/v1/things", Kind: "Thing", Namespace: "team-b", Name: "db-3", Team: "frontend"},
+	})
+	s.CommitGeneration()
+	ev, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("Recv: %v", err)
+	}
+	if ev.GetClaim().GetName() != "db-3" {
+		t.Errorf("expected only the frontend team's change, got %v", ev)
+	}
+}
+
+func TestTenancy_Unauthenticated(t *testing.T) {
+	srv := New("127.0.0.1:0", testStore())
+	srv.SetTenancy(auth.NewTenancy(teamReviewer{}, auth.TenancyOptions{ByTeam: true}))
+	client := xptrackerv1.NewInventoryServiceClient(startServer(t, srv))
+	if _, err := client.GetClaim(context.Background(), &xptrackerv1.GetClaimRequest{Namespace: "team-a", Name: "db-1"}); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("expected Unauthenticated, got %v", err)
+	}
+}
@@ -2,6 +2,7 @@ package server
 
 import (
 	"encoding/json"
+	"errors"
 	"log/slog"
 	"net/http"
 	"time"
@@ -62,91 +63,137 @@ type MRDTO struct {
 	AgeSeconds         int64  `json:"ageSeconds"`
 }
 
+// DeletedClaimDTO is a claim tombstone. AgeSeconds is the claim's age when
+// it was removed.
+type DeletedClaimDTO struct {
+	ClaimDTO
+	RemovedAt string `json:"removedAt"`
+}
+
+// DeletedXRDTO is an XR tombstone.
+type DeletedXRDTO struct {
+	XRDTO
+	RemovedAt string `json:"removedAt"`
+}
+
+// DeletedMRDTO is an MR tombstone.
+type DeletedMRDTO struct {
+	MRDTO
+	RemovedAt string `json:"removedAt"`
+}
+
 // BookkeepingResponse is the top-level JSON response for the /bookkeeping endpoint.
+// All items come from the single store generation identified by Generation.
+// The Deleted* lists are only populated with ?includeDeleted=true. Next is
+// set when a paginated (?type=...&limit=...) request has more results.
 type BookkeepingResponse struct {
-	Claims      []ClaimDTO `json:"claims"`
-	XRs         []XRDTO    `json:"xrs"`
-	MRs         []MRDTO    `json:"mrs"`
-	GeneratedAt string     `json:"generatedAt"`
+	Claims                []ClaimDTO        `json:"claims"`
+	XRs                   []XRDTO           `json:"xrs"`
+	MRs                   []MRDTO           `json:"mrs"`
+	DeletedClaims         []DeletedClaimDTO `json:"deletedClaims,omitempty"`
+	DeletedXRs            []DeletedXRDTO    `json:"deletedXrs,omitempty"`
+	DeletedMRs            []DeletedMRDTO    `json:"deletedMrs,omitempty"`
+	Next                  string            `json:"next,omitempty"`
+	Generation            uint64            `json:"generation"`
+	GenerationCommittedAt string            `json:"generationCommittedAt,omitempty"`
+	GeneratedAt           string            `json:"generatedAt"`
 }
 
-// bookkeepingHandler returns an http.HandlerFunc that serves the bookkeeping JSON endpoint.
-func bookkeepingHandler(s store.Store) http.HandlerFunc {
-	return func(w http.ResponseWriter, _ *http.Request) {
+// scopeFunc returns the tenant scope of a request over view, or nil when the
+// caller may see everything.
+type scopeFunc func(r *http.Request, view store.View) (*store.Scope, error)
+
+// resolveScope returns the tenant scope of r over view, or nil when scope
+// is nil. When it cannot be resolved, it writes the error response and
+// returns false: 401 for requests without an authenticated user.
+func resolveScope(w http.ResponseWriter, r *http.Request, scope scopeFunc, view store.View) (*store.Scope, bool) {
+	if scope == nil {
+		return nil, true
+	}
+	sc, err := scope(r, view)
+	if err != nil {
+		if errors.Is(err, errUnauthenticated) {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="xp-tracker"`)
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return nil, false
+		}
+		slog.Error("failed to resolve tenant scope", "error", err)
+		http.Error(w, "failed to resolve tenant scope", http.StatusInternalServerError)
+		return nil, false
+	}
+	return sc, true
+}
+
+// bookkeepingHandler returns an http.HandlerFunc that serves the bookkeeping
+// endpoint as JSON, or as streamed CSV or NDJSON when requested through the
+// format parameter or the Accept header. When scope is non-nil, every list
+// is restricted to the caller's tenant scope in the store query itself, so
+// totals and pagination only ever cover visible objects.
+func bookkeepingHandler(s store.Store, scope scopeFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
 		now := time.Now().UTC()
 
-		claims := s.SnapshotClaims()
-		xrs := s.SnapshotXRs()
-		mrs := s.SnapshotMRs()
-
-		claimDTOs := make([]ClaimDTO, 0, len(claims))
-		for _, c := range claims {
-			age := int64(now.Sub(c.CreatedAt).Seconds())
-			claimDTOs = append(claimDTOs, ClaimDTO{
-				Group:       c.Group,
-				Version:     c.Version,
-				Kind:        c.Kind,
-				Namespace:   c.Namespace,
-				Name:        c.Name,
-				Creator:     c.Creator,
-				Team:        c.Team,
-				Composition: c.Composition,
-				Paused:      c.Paused,
-				Deleting:    !c.DeletedAt.IsZero(),
-				Ready:       c.Ready,
-				Reason:      c.Reason,
-				AgeSeconds:  age,
-			})
-		}
-
-		xrDTOs := make([]XRDTO, 0, len(xrs))
-		for _, x := range xrs {
-			age := int64(now.Sub(x.CreatedAt).Seconds())
-			xrDTOs = append(xrDTOs, XRDTO{
-				Group:       x.Group,
-				Version:     x.Version,
-				Kind:        x.Kind,
-				Namespace:   x.Namespace,
-				Name:        x.Name,
-				Composition: x.Composition,
-				Paused:      x.Paused,
-				Deleting:    !x.DeletedAt.IsZero(),
-				Ready:       x.Ready,
-				Reason:      x.Reason,
-				AgeSeconds:  age,
-			})
-		}
-
-		mrDTOs := make([]MRDTO, 0, len(mrs))
-		for _, m := range mrs {
-			age := int64(now.Sub(m.CreatedAt).Seconds())
-			mrDTOs = append(mrDTOs, MRDTO{
-				Group:              m.Group,
-				Version:            m.Version,
-				Kind:               m.Kind,
-				Namespace:          m.Namespace,
-				Name:               m.Name,
-				XRName:             m.XRName,
-				ClaimName:          m.ClaimName,
-				ClaimNamespace:     m.ClaimNS,
-				Provider:           m.Provider,
-				ProviderConfig:     m.ProviderConfig,
-				ExternalName:       m.ExternalName,
-				ManagementPolicies: m.ManagementPolicies,
-				Paused:             m.Paused,
-				Deleting:           !m.DeletedAt.IsZero(),
-				Ready:              m.Ready,
-				Reason:             m.Reason,
-				AgeSeconds:         age,
-			})
+		req, err := parseListRequest(r.URL.Query())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		format, err := negotiateFormat(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		view := s.View()
+
+		sc, ok := resolveScope(w, r, scope, view)
+		if !ok {
+			return
 		}
+		req.Query.Scope = sc
 
+		if format != formatJSON {
+			if req.Type == "" {
+				http.Error(w, format+" output requires type", http.StatusBadRequest)
+				return
+			}
+			if req.IncludeDeleted {
+				http.Error(w, "includeDeleted is only supported for JSON output", http.StatusBadRequest)
+				return
+			}
+			if err := streamBookkeeping(w, view, req, format, now); err != nil {
+				if errors.Is(err, store.ErrInvalidQuery) {
+					http.Error(w, err.Error(), http.StatusBadRequest)
+					return
+				}
+				// Rows may already have been sent; the status cannot change.
+				slog.Warn("failed to stream bookkeeping response", "format", format, "error", err)
+			}
+			return
+		}
+
+		gen := view.Generation()
 		resp := BookkeepingResponse{
-			Claims:      claimDTOs,
-			XRs:         xrDTOs,
-			MRs:         mrDTOs,
+			Claims:      []ClaimDTO{},
+			XRs:         []XRDTO{},
+			MRs:         []MRDTO{},
+			Generation:  gen.Number,
 			GeneratedAt: now.Format(time.RFC3339),
 		}
+		if !gen.CommittedAt.IsZero() {
+			resp.GenerationCommittedAt = gen.CommittedAt.Format(time.RFC3339)
+		}
+
+		if err := fillBookkeeping(&resp, view, req, now); err != nil {
+			if errors.Is(err, store.ErrInvalidQuery) {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			slog.Error("failed to query store", "error", err)
+			http.Error(w, "failed to query store", http.StatusInternalServerError)
+			return
+		}
 
 		data, err := json.Marshal(resp)
 		if err != nil {
@@ -159,3 +206,136 @@ func bookkeepingHandler(s store.Store) http.HandlerFunc {
 		_, _ = w.Write(data)
 	}
 }
+
+// fillBookkeeping runs req against view and fills the lists of resp that
+// req.Type selects (all three when it is empty).
+func fillBookkeeping(resp *BookkeepingResponse, view store.View, req listRequest, now time.Time) error {
+	q := req.Query
+
+	if req.Type == "" || req.Type == typeClaims {
+		page, err := view.QueryClaims(q)
+		if err != nil {
+			return err
+		}
+		resp.Next = page.Next
+		resp.Claims = make([]ClaimDTO, 0, len(page.Items))
+		for _, c := range page.Items {
+			resp.Claims = append(resp.Claims, newClaimDTO(c, now))
+		}
+		if req.IncludeDeleted {
+			deleted := view.DeletedClaims(q)
+			resp.DeletedClaims = make([]DeletedClaimDTO, 0, len(deleted))
+			for _, d := range deleted {
+				resp.DeletedClaims = append(resp.DeletedClaims, DeletedClaimDTO{
+					ClaimDTO:  newClaimDTO(d.ClaimInfo, d.RemovedAt),
+					RemovedAt: d.RemovedAt.Format(time.RFC3339),
+				})
+			}
+		}
+	}
+
+	if req.Type == "" || req.Type == typeXRs {
+		page, err := view.QueryXRs(q)
+		if err != nil {
+			return err
+		}
+		resp.Next = page.Next
+		resp.XRs = make([]XRDTO, 0, len(page.Items))
+		for _, x := range page.Items {
+			resp.XRs = append(resp.XRs, newXRDTO(x, now))
+		}
+		if req.IncludeDeleted {
+			deleted := view.DeletedXRs(q)
+			resp.DeletedXRs = make([]DeletedXRDTO, 0, len(deleted))
+			for _, d := range deleted {
+				resp.DeletedXRs = append(resp.DeletedXRs, DeletedXRDTO{
+					XRDTO:     newXRDTO(d.XRInfo, d.RemovedAt),
+					RemovedAt: d.RemovedAt.Format(time.RFC3339),
+				})
+			}
+		}
+	}
+
+	if req.Type == "" || req.Type == typeMRs {
+		page, err := view.QueryMRs(q)
+		if err != nil {
+			return err
+		}
+		resp.Next = page.Next
+		resp.MRs = make([]MRDTO, 0, len(page.Items))
+		for _, m := range page.Items {
+			resp.MRs = append(resp.MRs, newMRDTO(m, now))
+		}
+		if req.IncludeDeleted {
+			deleted := view.DeletedMRs(q)
+			resp.DeletedMRs = make([]DeletedMRDTO, 0, len(deleted))
+			for _, d := range deleted {
+				resp.DeletedMRs = append(resp.DeletedMRs, DeletedMRDTO{
+					MRDTO:     newMRDTO(d.MRInfo, d.RemovedAt),
+					RemovedAt: d.RemovedAt.Format(time.RFC3339),
+				})
+			}
+		}
+	}
+
+	return nil
+}
+
+// newClaimDTO converts a stored claim, computing its age as of at.
+func newClaimDTO(c store.ClaimInfo, at time.Time) ClaimDTO {
+	return ClaimDTO{
+		Group:       c.Group,
+		Version:     c.Version,
+		Kind:        c.Kind,
+		Namespace:   c.Namespace,
+		Name:        c.Name,
+		Creator:     c.Creator,
+		Team:        c.Team,
+		Composition: c.Composition,
+		Paused:      c.Paused,
+		Deleting:    !c.DeletedAt.IsZero(),
+		Ready:       c.Ready,
+		Reason:      c.Reason,
+		AgeSeconds:  int64(at.Sub(c.CreatedAt).Seconds()),
+	}
+}
+
+// newXRDTO converts a stored XR, computing its age as of at.
+func newXRDTO(x store.XRInfo, at time.Time) XRDTO {
+	return XRDTO{
+		Group:       x.Group,
+		Version:     x.Version,
+		Kind:        x.Kind,
+		Namespace:   x.Namespace,
+		Name:        x.Name,
+		Composition: x.Composition,
+		Paused:      x.Paused,
+		Deleting:    !x.DeletedAt.IsZero(),
+		Ready:       x.Ready,
+		Reason:      x.Reason,
+		AgeSeconds:  int64(at.Sub(x.CreatedAt).Seconds()),
+	}
+}
+
+// newMRDTO converts a stored MR, computing its age as of at.
+func newMRDTO(m store.MRInfo, at time.Time) MRDTO {
+	return MRDTO{
+		Group:              m.Group,
+		Version:            m.Version,
+		Kind:               m.Kind,
+		Namespace:          m.Namespace,
+		Name:               m.Name,
+		XRName:             m.XRName,
+		ClaimName:          m.ClaimName,
+		ClaimNamespace:     m.ClaimNS,
+		Provider:           m.Provider,
+		ProviderConfig:     m.ProviderConfig,
+		ExternalName:       m.ExternalName,
+		ManagementPolicies: m.ManagementPolicies,
+		Paused:             m.Paused,
+		Deleting:           !m.DeletedAt.IsZero(),
+		Ready:              m.Ready,
+		Reason:             m.Reason,
+		AgeSeconds:         int64(at.Sub(m.CreatedAt).Seconds()),
+	}
+}
@@ -3,17 +3,20 @@ package server
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"testing"
 	"time"
 
+	"github.com/kanzifucius/xp-tracker/pkg/auth"
 	"github.com/kanzifucius/xp-tracker/pkg/store"
 )
 
 func TestBookkeeping_Empty(t *testing.T) {
 	s := store.New()
-	handler := bookkeepingHandler(s)
+	handler := bookkeepingHandler(s, nil)
 
 	req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/bookkeeping", nil)
 	rec := httptest.NewRecorder()
@@ -79,7 +82,7 @@ func TestBookkeeping_WithData(t *testing.T) {
 		},
 	})
 
-	handler := bookkeepingHandler(s)
+	handler := bookkeepingHandler(s, nil)
 	req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/bookkeeping", nil)
 	rec := httptest.NewRecorder()
 
@@ -176,7 +179,7 @@ func TestBookkeeping_WithData(t *testing.T) {
 
 func TestBookkeeping_GeneratedAtIsUTC(t *testing.T) {
 	s := store.New()
-	handler := bookkeepingHandler(s)
+	handler := bookkeepingHandler(s, nil)
 
 	req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/bookkeeping", nil)
 	rec := httptest.NewRecorder()
@@ -198,6 +201,189 @@ func TestBookkeeping_GeneratedAtIsUTC(t *testing.T) {
 	}
 }
 
+func TestBookkeeping_ReportsGeneration(t *testing.T) {
+	s := store.New()
+	s.BeginGeneration()
+	s.ReplaceClaims("g/v1/things", []store.ClaimInfo{
+		{GVR: "g/v1/things", Group: "g", Version: "v1", Kind: "Thing", Namespace: "ns1", Name: "claim-a"},
+	})
+	info := s.CommitGeneration()
+
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/bookkeeping", nil)
+	rec := httptest.NewRecorder()
+	bookkeepingHandler(s, nil).ServeHTTP(rec, req)
+
+	var resp BookkeepingResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.Generation != info.Number {
+		t.Errorf("generation: got %d, want %d", resp.Generation, info.Number)
+	}
+	if _, err := time.Parse(time.RFC3339, resp.GenerationCommittedAt); err != nil {
+		t.Errorf("generationCommittedAt is not valid RFC3339: %v", err)
+	}
+}
+
+func TestBookkeeping_IncludeDeleted(t *testing.T) {
+	s := store.New()
+	s.SetTombstoneRetention(time.Hour)
+
+	createdAt := time.Now().Add(-48 * time.Hour)
+	s.ReplaceClaims("g/v1/dbs", []store.ClaimInfo{
+		{GVR: "g/v1/dbs", Kind: "DB", Namespace: "prod", Name: "orders", Creator: "alice", CreatedAt: createdAt},
+	})
+	s.ReplaceMRs("m/v1/instances", []store.MRInfo{
+		{GVR: "m/v1/instances", Kind: "Instance", Name: "orders-rds", CreatedAt: createdAt},
+	})
+	s.ReplaceClaims("g/v1/dbs", nil)
+	s.ReplaceMRs("m/v1/instances", nil)
+
+	handler := bookkeepingHandler(s, nil)
+
+	// Tombstones are omitted unless asked for.
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/bookkeeping", nil))
+	var resp BookkeepingResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.DeletedClaims != nil || resp.DeletedMRs != nil {
+		t.Errorf("expected no tombstones by default, got %+v / %+v", resp.DeletedClaims, resp.DeletedMRs)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/bookkeeping?includeDeleted=true", nil))
+	resp = BookkeepingResponse{}
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(resp.Claims) != 0 {
+		t.Errorf("expected no live claims, got %d", len(resp.Claims))
+	}
+	if len(resp.DeletedClaims) != 1 {
+		t.Fatalf("expected 1 deleted claim, got %d", len(resp.DeletedClaims))
+	}
+	d := resp.DeletedClaims[0]
+	if d.Name != "orders" || d.Creator != "alice" {
+		t.Errorf("unexpected tombstone %+v", d)
+	}
+	if _, err := time.Parse(time.RFC3339, d.RemovedAt); err != nil {
+		t.Errorf("removedAt is not valid RFC3339: %v", err)
+	}
+	// Age is measured at removal time.
+	if d.AgeSeconds < 47*3600 || d.AgeSeconds > 49*3600 {
+		t.Errorf("expected age of about 48h at removal, got %ds", d.AgeSeconds)
+	}
+	if len(resp.DeletedMRs) != 1 || resp.DeletedMRs[0].Name != "orders-rds" {
+		t.Errorf("unexpected deleted MRs %+v", resp.DeletedMRs)
+	}
+}
+
+func TestBookkeeping_IncludeDeletedInvalid(t *testing.T) {
+	handler := bookkeepingHandler(store.New(), nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/bookkeeping?includeDeleted=maybe", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", rec.Code)
+	}
+}
+
+func getBookkeeping(t *testing.T, s store.Store, rawQuery string) (int, BookkeepingResponse) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/bookkeeping?"+rawQuery, nil)
+	bookkeepingHandler(s, nil).ServeHTTP(rec, req)
+
+	var resp BookkeepingResponse
+	if rec.Code == http.StatusOK {
+		if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+	}
+	return rec.Code, resp
+}
+
+func TestBookkeeping_Filters(t *testing.T) {
+	s := store.New()
+	s.ReplaceClaims("g/v1/dbs", []store.ClaimInfo{
+		{GVR: "g/v1/dbs", Kind: "DB", Namespace: "team-a", Name: "db-1", Team: "payments", XRRef: "xr-1", Ready: true},
+		{GVR: "g/v1/dbs", Kind: "DB", Namespace: "team-b", Name: "db-2", Team: "search", XRRef: "xr-2"},
+	})
+	s.ReplaceXRs("g/v1/xdbs", []store.XRInfo{
+		{GVR: "g/v1/xdbs", Kind: "XDB", Name: "xr-1"},
+		{GVR: "g/v1/xdbs", Kind: "XDB", Name: "xr-2"},
+	})
+	s.ReplaceMRs("m/v1/instances", []store.MRInfo{
+		{GVR: "m/v1/instances", Kind: "Instance", Name: "rds-1", XRName: "xr-1", Provider: "provider-aws"},
+		{GVR: "m/v1/instances", Kind: "Instance", Name: "rds-2", XRName: "xr-2", Provider: "provider-aws"},
+	})
+	s.EnrichXRClaims()
+	s.EnrichMRClaims()
+
+	code, resp := getBookkeeping(t, s, "team=payments")
+	if code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", code)
+	}
+	if len(resp.Claims) != 1 || resp.Claims[0].Name != "db-1" {
+		t.Errorf("unexpected claims %+v", resp.Claims)
+	}
+	if len(resp.XRs) != 1 || resp.XRs[0].Name != "xr-1" {
+		t.Errorf("unexpected XRs %+v", resp.XRs)
+	}
+	if len(resp.MRs) != 1 || resp.MRs[0].Name != "rds-1" {
+		t.Errorf("unexpected MRs %+v", resp.MRs)
+	}
+
+	_, resp = getBookkeeping(t, s, "type=claims&ready=false")
+	if len(resp.Claims) != 1 || resp.Claims[0].Name != "db-2" {
+		t.Errorf("unexpected not-ready claims %+v", resp.Claims)
+	}
+	if len(resp.XRs) != 0 || len(resp.MRs) != 0 {
+		t.Errorf("expected only claims with type=claims, got %d XRs and %d MRs", len(resp.XRs), len(resp.MRs))
+	}
+}
+
+func TestBookkeeping_Pagination(t *testing.T) {
+	s := store.New()
+	var mrs []store.MRInfo
+	for i := range 5 {
+		mrs = append(mrs, store.MRInfo{GVR: "m/v1/r", Kind: "R", Name: fmt.Sprintf("mr-%d", i)})
+	}
+	s.ReplaceMRs("m/v1/r", mrs)
+
+	var names []string
+	query := "type=mrs&limit=2&sort=name&order=desc"
+	for pages := 0; ; pages++ {
+		if pages > 5 {
+			t.Fatal("pagination did not terminate")
+		}
+		code, resp := getBookkeeping(t, s, query)
+		if code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", code)
+		}
+		for _, m := range resp.MRs {
+			names = append(names, m.Name)
+		}
+		if resp.Next == "" {
+			break
+		}
+		query = "type=mrs&limit=2&sort=name&order=desc&cursor=" + url.QueryEscape(resp.Next)
+	}
+	if got := fmt.Sprint(names); got != "[mr-4 mr-3 mr-2 mr-1 mr-0]" {
+		t.Errorf("unexpected pages %s", got)
+	}
+}
+
+func TestBookkeeping_BadRequests(t *testing.T) {
+	s := store.New()
+	for _, raw := range []string{"limit=5", "type=nope", "type=claims&sort=size", "type=claims&cursor=%21%21"} {
+		if code, _ := getBookkeeping(t, s, raw); code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", raw, code)
+		}
+	}
+}
+
 func TestBookkeeping_WithMRs(t *testing.T) {
 	s := store.New()
 	createdAt := time.Now().Add(-30 * time.Minute)
@@ -213,7 +399,7 @@ func TestBookkeeping_WithMRs(t *testing.T) {
 		},
 	})
 
-	handler := bookkeepingHandler(s)
+	handler := bookkeepingHandler(s, nil)
 	req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/bookkeeping", nil)
 	rec := httptest.NewRecorder()
 	handler.ServeHTTP(rec, req)
@@ -258,7 +444,7 @@ func TestBookkeeping_ZeroCreatedAt(t *testing.T) {
 		{GVR: "g/v1/things", Group: "g", Kind: "Thing", Namespace: "ns1", Name: "a"},
 	})
 
-	handler := bookkeepingHandler(s)
+	handler := bookkeepingHandler(s, nil)
 	req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/bookkeeping", nil)
 	rec := httptest.NewRecorder()
 
@@ -342,3 +528,79 @@ func TestBookkeeping_Integration(t *testing.T) {
 		t.Errorf("xr name: got %q, want %q", bkResp.XRs[0].Name, "xr-db-1")
 	}
 }
+
+// namespaceReviewer lets each user list pods only in the namespace named
+// after them.
+type namespaceReviewer struct{ tokenReviewer }
+
+func (namespaceReviewer) AuthorizeResource(_ context.Context, u auth.User, attrs auth.ResourceAttributes) (bool, error) {
+	return attrs.Namespace != "" && attrs.Namespace == u.Name, nil
+}
+
+func TestBookkeeping_Tenancy(t *testing.T) {
+	s := store.New()
+	s.BeginGeneration()
+	s.ReplaceClaims("g/v1/dbs", []store.ClaimInfo{
+		{GVR: "g/v1/dbs", Kind: "DB", Namespace: "team-a", Name: "db-1", Team: "payments", XRRef: "xr-1"},
+		{GVR: "g/v1/dbs", Kind: "DB", Namespace: "team-b", Name: "db-2", Team: "search", XRRef: "xr-2"},
+		{GVR: "g/v1/dbs", Kind: "DB", Namespace: "team-c", Name: "db-3", Team: "search", XRRef: "xr-3"},
+	})
+	s.ReplaceXRs("g/v1/xdbs", []store.XRInfo{
+		{GVR: "g/v1/xdbs", Kind: "XDB", Name: "xr-1", ClaimNS: "team-a", ClaimName: "db-1"},
+		{GVR: "g/v1/xdbs", Kind: "XDB", Name: "xr-2", ClaimNS: "team-b", ClaimName: "db-2"},
+	})
+	s.ReplaceMRs("rds/v1/instances", []store.MRInfo{
+		{GVR: "rds/v1/instances", Kind: "Instance", Name: "rds-1", XRName: "xr-1", ClaimNS: "team-a", ClaimName: "db-1"},
+	})
+	s.CommitGeneration()
+
+	srv := New(":0", s)
+	srv.SetTenancy(auth.NewTenancy(namespaceReviewer{}, auth.TenancyOptions{
+		ByNamespace:     true,
+		NamespaceAccess: auth.ResourceAttributes{Verb: "list", Resource: "pods"},
+		ByTeam:          true,
+	}))
+
+	get := func(u *auth.User, rawQuery string) (int, BookkeepingResponse) {
+		ctx := context.Background()
+		if u != nil {
+			ctx = auth.WithUser(ctx, *u)
+		}
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequestWithContext(ctx, http.MethodGet, "/bookkeeping?"+rawQuery, nil)
+		srv.httpServer.Handler.ServeHTTP(rec, req)
+		var resp BookkeepingResponse
+		if rec.Code == http.StatusOK {
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+		}
+		return rec.Code, resp
+	}
+
+	code, resp := get(&auth.User{Name: "team-a"}, "")
+	if code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", code)
+	}
+	if len(resp.Claims) != 1 || resp.Claims[0].Name != "db-1" || len(resp.XRs) != 1 || len(resp.MRs) != 1 {
+		t.Errorf("expected only team-a's inventory, got %+v", resp)
+	}
+
+	// Team membership adds team-c's claim, which is in a namespace the
+	// caller cannot list.
+	code, resp = get(&auth.User{Name: "team-b", Groups: []string{"search"}}, "type=claims&limit=1")
+	if code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", code)
+	}
+	if len(resp.Claims) != 1 || resp.Claims[0].Name != "db-2" || resp.Next == "" {
+		t.Errorf("expected first page of the search team's claims, got %+v", resp)
+	}
+	code, resp = get(&auth.User{Name: "team-b", Groups: []string{"search"}}, "type=claims&limit=1&cursor="+url.QueryEscape(resp.Next))
+	if code != http.StatusOK || len(resp.Claims) != 1 || resp.Claims[0].Name != "db-3" || resp.Next != "" {
+		t.Errorf("expected last page with db-3, got %d %+v", code, resp)
+	}
+
+	if code, _ := get(nil, ""); code != http.StatusUnauthorized {
+		t.Errorf("expected 401 without an authenticated user, got %d", code)
+	}
+}
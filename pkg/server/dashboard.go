@@ -0,0 +1,39 @@
+package server
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+)
+
+// dashboardFiles is the read-only web dashboard. It is plain HTML, CSS and
+// JavaScript without external assets, and reads everything through the JSON
+// API, so it works inside clusters without internet access.
+//
+//go:embed ui
+var dashboardFiles embed.FS
+
+// dashboardCSP only allows the dashboard's own scripts, styles and API calls.
+const dashboardCSP = "default-src 'none'; script-src 'self'; style-src 'self'; connect-src 'self'; img-src 'self' data:; base-uri 'none'; form-action 'none'; frame-ancestors 'none'"
+
+// registerDashboardRoutes serves the dashboard under /ui/ and redirects the
+// site root to it. The dashboard resolves API paths relative to its own URL.
+func registerDashboardRoutes(mux *http.ServeMux) {
+	files, err := fs.Sub(dashboardFiles, "ui")
+	if err != nil {
+		panic(err) // the embedded directory always exists
+	}
+	fileServer := http.StripPrefix("/ui", http.FileServerFS(files))
+	mux.HandleFunc("GET /ui/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Security-Policy", dashboardCSP)
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		w.Header().Set("Cache-Control", "no-cache")
+		fileServer.ServeHTTP(w, r)
+	})
+	mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, _ *http.Request) {
+		// A relative Location (http.Redirect would make it absolute) keeps
+		// the redirect working behind a proxy that adds a path prefix.
+		w.Header().Set("Location", "ui/")
+		w.WriteHeader(http.StatusFound)
+	})
+}
@@ -0,0 +1,72 @@
+package server
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/kanzifucius/xp-tracker/pkg/store"
+)
+
+func TestDashboard(t *testing.T) {
+	baseURL, cancel := startTestServer(t, store.New())
+	defer cancel()
+
+	tests := []struct {
+		path        string
+		contentType string
+		contains    string
+	}{
+		{"/ui/", "text/html", `<script src="app.js"`},
+		{"/ui/app.js", "text/javascript", "/bookkeeping?"},
+		{"/ui/style.css", "text/css", ".badge"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			resp := httpGet(t, baseURL+tt.path)
+			defer func() { _ = resp.Body.Close() }()
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("expected 200, got %d", resp.StatusCode)
+			}
+			if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, tt.contentType) {
+				t.Errorf("expected content type %s, got %q", tt.contentType, ct)
+			}
+			if csp := resp.Header.Get("Content-Security-Policy"); !strings.Contains(csp, "script-src 'self'") {
+				t.Errorf("expected a restrictive Content-Security-Policy, got %q", csp)
+			}
+			body, _ := io.ReadAll(resp.Body)
+			if !strings.Contains(string(body), tt.contains) {
+				t.Errorf("expected body to contain %q", tt.contains)
+			}
+		})
+	}
+}
+
+func TestDashboard_RootRedirect(t *testing.T) {
+	baseURL, cancel := startTestServer(t, store.New())
+	defer cancel()
+
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, baseURL+"/", nil)
+	if err != nil {
+		t.Fatalf("failed to create request: %v", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("GET /: %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusFound || resp.Header.Get("Location") != "ui/" {
+		t.Errorf("expected redirect to ui/, got %d to %q", resp.StatusCode, resp.Header.Get("Location"))
+	}
+
+	// Other unknown paths are not swallowed by the root route.
+	unknown := httpGet(t, baseURL+"/nope")
+	_ = unknown.Body.Close()
+	if unknown.StatusCode != http.StatusNotFound {
+		t.Errorf("expected 404 for unknown path, got %d", unknown.StatusCode)
+	}
+}
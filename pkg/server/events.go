@@ -0,0 +1,138 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/kanzifucius/xp-tracker/pkg/metrics"
+	"github.com/kanzifucius/xp-tracker/pkg/store"
+)
+
+// eventStreamHeartbeat is how often an idle event stream sends a comment
+// line, so that proxies and load balancers keep the connection open.
+const eventStreamHeartbeat = 15 * time.Second
+
+// Event names of the change stream besides the store.ChangeType values.
+const eventReset = "reset"
+
+// resetEvent tells a client that it missed changes and must reload the full
+// inventory from /bookkeeping before applying further events.
+type resetEvent struct {
+	Seq uint64 `json:"seq"`
+}
+
+// eventsHandler streams store changes as Server-Sent Events.
+//
+// Each change is sent with its sequence number as the event id and its
+// change type (added, updated, removed) as the event name. The stream
+// resumes after the id given in the Last-Event-ID header (or lastEventId
+// parameter); without one it starts with the next change. If the requested
+// changes are no longer buffered a reset event is sent and the stream
+// continues from the newest change.
+//
+// The namespace and team parameters restrict the stream to matching
+// changes. With a tenant scope, only changes to objects in the caller's
+// scope are sent; the scope is resolved when the stream opens. The stream
+// ends when the client disconnects or stopping is
+// closed.
+func eventsHandler(s store.Store, scope scopeFunc, stopping <-chan struct{}, heartbeat time.Duration) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		feed := s.Changes()
+		if !feed.Enabled() {
+			http.Error(w, "change feed is disabled", http.StatusServiceUnavailable)
+			return
+		}
+		sc, ok := resolveScope(w, r, scope, s.View())
+		if !ok {
+			return
+		}
+
+		namespace := r.URL.Query().Get("namespace")
+		team := r.URL.Query().Get("team")
+
+		seq := feed.LastSeq()
+		lastID := r.Header.Get("Last-Event-ID")
+		if lastID == "" {
+			lastID = r.URL.Query().Get("lastEventId")
+		}
+		if lastID != "" {
+			n, err := strconv.ParseUint(lastID, 10, 64)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("invalid last event id %q", lastID), http.StatusBadRequest)
+				return
+			}
+			seq = n
+		}
+
+		// The stream outlives the server's write timeout.
+		rc := http.NewResponseController(w)
+		if err := rc.SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+			slog.Warn("failed to clear write deadline for event stream", "error", err)
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("X-Accel-Buffering", "no") // disable proxy buffering (nginx)
+		w.WriteHeader(http.StatusOK)
+
+		metrics.EventStreamClients.Inc()
+		defer metrics.EventStreamClients.Dec()
+
+		ticker := time.NewTicker(heartbeat)
+		defer ticker.Stop()
+
+		for {
+			changes, wait, ok := feed.Since(seq)
+			if !ok {
+				seq = feed.LastSeq()
+				if err := writeEvent(w, seq, eventReset, resetEvent{Seq: seq}); err != nil {
+					return
+				}
+				changes, wait, _ = feed.Since(seq)
+			}
+			for _, c := range changes {
+				seq = c.Seq
+				if (namespace != "" && c.Namespace != namespace) || (team != "" && c.Team != team) || !sc.AllowsChange(c) {
+					continue
+				}
+				if err := writeEvent(w, c.Seq, string(c.Type), c); err != nil {
+					return
+				}
+			}
+			if err := rc.Flush(); err != nil {
+				return
+			}
+
+			select {
+			case <-wait:
+			case <-ticker.C:
+				if _, err := io.WriteString(w, ": keepalive\n\n"); err != nil {
+					return
+				}
+				if err := rc.Flush(); err != nil {
+					return
+				}
+			case <-r.Context().Done():
+				return
+			case <-stopping:
+				return
+			}
+		}
+	}
+}
+
+// writeEvent writes one Server-Sent Event with a JSON payload.
+func writeEvent(w io.Writer, id uint64, name string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", id, name, data)
+	return err
+}
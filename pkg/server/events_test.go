@@ -0,0 +1,239 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kanzifucius/xp-tracker/pkg/store"
+)
+
+// sseEvent is one parsed Server-Sent Event.
+type sseEvent struct {
+	ID   string
+	Name string
+	Data string
+}
+
+// openEventStream connects to an events handler served over a real
+// connection and returns a channel of parsed events.
+func openEventStream(t *testing.T, s store.Store, rawQuery string, header http.Header) (*http.Response, <-chan sseEvent) {
+	t.Helper()
+	return openScopedEventStream(t, s, nil, rawQuery, header)
+}
+
+// openScopedEventStream is openEventStream with a tenant scope.
+func openScopedEventStream(t *testing.T, s store.Store, scope scopeFunc, rawQuery string, header http.Header) (*http.Response, <-chan sseEvent) {
+	t.Helper()
+	stopping := make(chan struct{})
+	ts := httptest.NewServer(eventsHandler(s, scope, stopping, 20*time.Millisecond))
+	t.Cleanup(func() {
+		close(stopping)
+		ts.Close()
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL+"/events/stream?"+rawQuery, nil)
+	if err != nil {
+		t.Fatalf("new request: %v", err)
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET /events/stream: %v", err)
+	}
+	t.Cleanup(func() { _ = resp.Body.Close() })
+
+	events := make(chan sseEvent, 16)
+	go func() {
+		defer close(events)
+		sc := bufio.NewScanner(resp.Body)
+		var ev sseEvent
+		for sc.Scan() {
+			line := sc.Text()
+			switch {
+			case line == "":
+				if ev.Name != "" {
+					events <- ev
+				}
+				ev = sseEvent{}
+			case strings.HasPrefix(line, "id: "):
+				ev.ID = strings.TrimPrefix(line, "id: ")
+			case strings.HasPrefix(line, "event: "):
+				ev.Name = strings.TrimPrefix(line, "event: ")
+			case strings.HasPrefix(line, "data: "):
+				ev.Data = strings.TrimPrefix(line, "data: ")
+			}
+		}
+	}()
+	return resp, events
+}
+
+func nextEvent(t *testing.T, events <-chan sseEvent) sseEvent {
+	t.Helper()
+	select {
+	case ev, ok := <-events:
+		if !ok {
+			t.Fatal("event stream closed")
+		}
+		return ev
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for an event")
+	}
+	return sseEvent{}
+}
+
+func decodeChange(t *testing.T, ev sseEvent) store.Change {
+	t.Helper()
+	var c store.Change
+	if err := json.Unmarshal([]byte(ev.Data), &c); err != nil {
+		t.Fatalf("decode change %q: %v", ev.Data, err)
+	}
+	return c
+}
+
+func TestEvents_Disabled(t *testing.T) {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/events/stream", nil)
+	eventsHandler(store.New(), nil, nil, time.Second).ServeHTTP(rec, req)
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected 503 with the change feed disabled, got %d", rec.Code)
+	}
+}
+
+func TestEvents_BadLastEventID(t *testing.T) {
+	s := store.New()
+	s.SetChangeBufferSize(10)
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/events/stream", nil)
+	req.Header.Set("Last-Event-ID", "abc")
+	eventsHandler(s, nil, nil, time.Second).ServeHTTP(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", rec.Code)
+	}
+}
+
+func TestEvents_StreamsFilteredChanges(t *testing.T) {
+	s := store.New()
+	s.SetChangeBufferSize(100)
+	s.ReplaceClaims("g/v1/r", []store.ClaimInfo{{GVR: "g/v1/r", Namespace: "ns1", Name: "before", Team: "a"}})
+
+	resp, events := openEventStream(t, s, "team=a", nil)
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("expected text/event-stream, got %q", ct)
+	}
+
+	s.ReplaceClaims("g/v1/r", []store.ClaimInfo{
+		{GVR: "g/v1/r", Namespace: "ns1", Name: "before", Team: "a", Ready: true},
+		{GVR: "g/v1/r", Namespace: "ns2", Name: "other-team", Team: "b"},
+	})
+	s.ReplaceClaims("g/v1/r", nil)
+
+	ev := nextEvent(t, events)
+	if ev.Name != "updated" || ev.ID != "2" {
+		t.Fatalf("expected updated event with id 2, got %+v", ev)
+	}
+	if c := decodeChange(t, ev); c.Name != "before" || c.Resource != store.ResourceClaim {
+		t.Errorf("unexpected change %+v", c)
+	}
+
+	// The other team's claim is skipped, both in its add and its removal.
+	ev = nextEvent(t, events)
+	if ev.Name != "removed" || decodeChange(t, ev).Name != "before" {
+		t.Errorf("expected removal of before, got %+v", ev)
+	}
+}
+
+func TestEvents_TenantScope(t *testing.T) {
+	s := store.New()
+	s.SetChangeBufferSize(100)
+	scope := func(*http.Request, store.View) (*store.Scope, error) {
+		return &store.Scope{Namespaces: []string{"mine"}}, nil
+	}
+
+	_, events := openScopedEventStream(t, s, scope, "", nil)
+	s.ReplaceClaims("g/v1/r", []store.ClaimInfo{{GVR: "g/v1/r", Namespace: "other", Name: "hidden"}})
+	s.ReplaceClaims("g/v1/r", []store.ClaimInfo{
+		{GVR: "g/v1/r", Namespace: "other", Name: "hidden"},
+		{GVR: "g/v1/r", Namespace: "mine", Name: "visible"},
+	})
+	if ev := nextEvent(t, events); ev.ID != "2" || decodeChange(t, ev).Name != "visible" {
+		t.Errorf("expected only the change in the caller's namespace, got %+v", ev)
+	}
+}
+
+func TestEvents_TenantScopeUnauthenticated(t *testing.T) {
+	s := store.New()
+	s.SetChangeBufferSize(10)
+	scope := func(*http.Request, store.View) (*store.Scope, error) { return nil, errUnauthenticated }
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/events/stream", nil)
+	eventsHandler(s, scope, nil, time.Second).ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401, got %d", rec.Code)
+	}
+}
+
+func TestEvents_ResumeFromLastEventID(t *testing.T) {
+	s := store.New()
+	s.SetChangeBufferSize(100)
+	s.ReplaceClaims("g/v1/r", []store.ClaimInfo{{GVR: "g/v1/r", Namespace: "ns", Name: "one"}})
+	s.ReplaceClaims("g/v1/r", []store.ClaimInfo{
+		{GVR: "g/v1/r", Namespace: "ns", Name: "one"},
+		{GVR: "g/v1/r", Namespace: "ns", Name: "two"},
+	})
+
+	_, events := openEventStream(t, s, "", http.Header{"Last-Event-Id": {"1"}})
+	ev := nextEvent(t, events)
+	if ev.ID != "2" || ev.Name != "added" || decodeChange(t, ev).Name != "two" {
+		t.Errorf("expected to resume with the addition of two, got %+v", ev)
+	}
+}
+
+func TestEvents_ResetWhenChangesWereDropped(t *testing.T) {
+	s := store.New()
+	s.SetChangeBufferSize(1)
+	for _, name := range []string{"one", "two", "three"} {
+		s.ReplaceClaims("g/v1/"+name, []store.ClaimInfo{{GVR: "g/v1/" + name, Namespace: "ns", Name: name}})
+	}
+
+	_, events := openEventStream(t, s, "lastEventId=1", nil)
+	ev := nextEvent(t, events)
+	if ev.Name != eventReset || ev.ID != "3" {
+		t.Fatalf("expected reset event with id 3, got %+v", ev)
+	}
+
+	s.ReplaceClaims("g/v1/four", []store.ClaimInfo{{GVR: "g/v1/four", Namespace: "ns", Name: "four"}})
+	ev = nextEvent(t, events)
+	if ev.ID != "4" || decodeChange(t, ev).Name != "four" {
+		t.Errorf("expected the stream to continue after the reset, got %+v", ev)
+	}
+}
+
+func TestEvents_EndsOnShutdown(t *testing.T) {
+	s := store.New()
+	s.SetChangeBufferSize(10)
+	stopping := make(chan struct{})
+	done := make(chan struct{})
+
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/events/stream", nil)
+	go func() {
+		eventsHandler(s, nil, stopping, time.Hour).ServeHTTP(httptest.NewRecorder(), req)
+		close(done)
+	}()
+	close(stopping)
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("event stream did not end on shutdown")
+	}
+}
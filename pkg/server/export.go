@@ -0,0 +1,200 @@
+package server
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kanzifucius/xp-tracker/pkg/store"
+)
+
+// Response formats for /bookkeeping.
+const (
+	formatJSON   = "json"
+	formatCSV    = "csv"
+	formatNDJSON = "ndjson"
+)
+
+var formatContentTypes = map[string]string{
+	formatJSON:   "application/json; charset=utf-8",
+	formatCSV:    "text/csv; charset=utf-8",
+	formatNDJSON: "application/x-ndjson",
+}
+
+// Column order for CSV exports. It matches the field order of the DTOs and
+// must only ever be appended to, so spreadsheets built on earlier exports
+// keep working.
+var (
+	claimColumns = []string{
+		"group", "version", "kind", "namespace", "name", "creator", "team", "composition",
+		"paused", "deleting", "ready", "reason", "ageSeconds",
+	}
+	xrColumns = []string{
+		"group", "version", "kind", "namespace", "name", "composition",
+		"paused", "deleting", "ready", "reason", "ageSeconds",
+	}
+	mrColumns = []string{
+		"group", "version", "kind", "namespace", "name", "xrName", "claimName", "claimNamespace",
+		"provider", "providerConfig", "externalName", "managementPolicies",
+		"paused", "deleting", "ready", "reason", "ageSeconds",
+	}
+)
+
+// negotiateFormat picks the response format from the format parameter or,
+// failing that, the Accept header. JSON is the default.
+func negotiateFormat(r *http.Request) (string, error) {
+	if f := r.URL.Query().Get("format"); f != "" {
+		if _, ok := formatContentTypes[f]; !ok {
+			return "", fmt.Errorf("format must be one of %q, %q or %q", formatJSON, formatCSV, formatNDJSON)
+		}
+		return f, nil
+	}
+	for _, part := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		switch mediaType {
+		case "text/csv":
+			return formatCSV, nil
+		case "application/x-ndjson":
+			return formatNDJSON, nil
+		case "application/json":
+			return formatJSON, nil
+		}
+	}
+	return formatJSON, nil
+}
+
+// rowWriter writes one DTO per row in a streaming format.
+type rowWriter interface {
+	header(columns []string) error
+	row(dto any, record []string) error
+	flush() error
+}
+
+type csvRowWriter struct{ w *csv.Writer }
+
+func (c csvRowWriter) header(columns []string) error    { return c.w.Write(columns) }
+func (c csvRowWriter) row(_ any, record []string) error { return c.w.Write(record) }
+func (c csvRowWriter) flush() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+type ndjsonRowWriter struct{ enc *json.Encoder }
+
+func (ndjsonRowWriter) header([]string) error           { return nil }
+func (n ndjsonRowWriter) row(dto any, _ []string) error { return n.enc.Encode(dto) }
+func (ndjsonRowWriter) flush() error                    { return nil }
+
+// streamBookkeeping writes the selected list of req as CSV or NDJSON, reading
+// rows one at a time from the store view. Streaming formats carry a single
+// resource type, so req.Type is required. The next-page cursor, if any, is
+// returned in the X-Next-Cursor header.
+func streamBookkeeping(w http.ResponseWriter, view store.View, req listRequest, format string, now time.Time) error {
+	var (
+		columns []string
+		next    string
+		each    func(fn func(dto any, record []string) error) error
+	)
+
+	switch req.Type {
+	case typeClaims:
+		sel, err := view.SelectClaims(req.Query)
+		if err != nil {
+			return err
+		}
+		columns, next = claimColumns, sel.Next
+		each = func(fn func(any, []string) error) error {
+			return sel.Each(func(c store.ClaimInfo) error {
+				d := newClaimDTO(c, now)
+				return fn(d, d.record())
+			})
+		}
+	case typeXRs:
+		sel, err := view.SelectXRs(req.Query)
+		if err != nil {
+			return err
+		}
+		columns, next = xrColumns, sel.Next
+		each = func(fn func(any, []string) error) error {
+			return sel.Each(func(x store.XRInfo) error {
+				d := newXRDTO(x, now)
+				return fn(d, d.record())
+			})
+		}
+	case typeMRs:
+		sel, err := view.SelectMRs(req.Query)
+		if err != nil {
+			return err
+		}
+		columns, next = mrColumns, sel.Next
+		each = func(fn func(any, []string) error) error {
+			return sel.Each(func(m store.MRInfo) error {
+				d := newMRDTO(m, now)
+				return fn(d, d.record())
+			})
+		}
+	default:
+		return fmt.Errorf("%w: unsupported type %q", store.ErrInvalidQuery, req.Type)
+	}
+
+	// A large export outlives the server's write timeout.
+	rc := http.NewResponseController(w)
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		slog.Warn("failed to clear write deadline for export", "error", err)
+	}
+
+	w.Header().Set("Content-Type", formatContentTypes[format])
+	w.Header().Set("X-Store-Generation", strconv.FormatUint(view.Generation().Number, 10))
+	if next != "" {
+		w.Header().Set("X-Next-Cursor", next)
+	}
+
+	var rw rowWriter = ndjsonRowWriter{enc: json.NewEncoder(w)}
+	if format == formatCSV {
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", req.Type+".csv"))
+		rw = csvRowWriter{w: csv.NewWriter(w)}
+	}
+
+	if err := rw.header(columns); err != nil {
+		return err
+	}
+	if err := each(rw.row); err != nil {
+		return err
+	}
+	return rw.flush()
+}
+
+func (d ClaimDTO) record() []string {
+	return []string{
+		d.Group, d.Version, d.Kind, d.Namespace, d.Name, d.Creator, d.Team, d.Composition,
+		strconv.FormatBool(d.Paused), strconv.FormatBool(d.Deleting), strconv.FormatBool(d.Ready),
+		d.Reason, strconv.FormatInt(d.AgeSeconds, 10),
+	}
+}
+
+func (d XRDTO) record() []string {
+	return []string{
+		d.Group, d.Version, d.Kind, d.Namespace, d.Name, d.Composition,
+		strconv.FormatBool(d.Paused), strconv.FormatBool(d.Deleting), strconv.FormatBool(d.Ready),
+		d.Reason, strconv.FormatInt(d.AgeSeconds, 10),
+	}
+}
+
+func (d MRDTO) record() []string {
+	return []string{
+		d.Group, d.Version, d.Kind, d.Namespace, d.Name, d.XRName, d.ClaimName, d.ClaimNamespace,
+		d.Provider, d.ProviderConfig, d.ExternalName, d.ManagementPolicies,
+		strconv.FormatBool(d.Paused), strconv.FormatBool(d.Deleting), strconv.FormatBool(d.Ready),
+		d.Reason, strconv.FormatInt(d.AgeSeconds, 10),
+	}
+}
@@ -0,0 +1,216 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kanzifucius/xp-tracker/pkg/store"
+)
+
+func exportStore() *store.MemoryStore {
+	s := store.New()
+	created := time.Now().Add(-time.Hour)
+	s.ReplaceClaims("g/v1/dbs", []store.ClaimInfo{
+		{GVR: "g/v1/dbs", Group: "g", Version: "v1", Kind: "DB", Namespace: "team-a", Name: "db-1",
+			Creator: "alice", Team: "payments", Ready: true, Reason: "Available", CreatedAt: created},
+		{GVR: "g/v1/dbs", Group: "g", Version: "v1", Kind: "DB", Namespace: "team-b", Name: "db-2",
+			Creator: "bob, jr.", Team: "search", Reason: "Creating", CreatedAt: created},
+	})
+	s.ReplaceMRs("m/v1/buckets", []store.MRInfo{
+		{GVR: "m/v1/buckets", Group: "m", Version: "v1", Kind: "Bucket", Name: "b-1", Provider: "provider-aws", CreatedAt: created},
+	})
+	return s
+}
+
+func doExport(t *testing.T, s store.Store, target, accept string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, target, nil)
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	rec := httptest.NewRecorder()
+	bookkeepingHandler(s, nil).ServeHTTP(rec, req)
+	return rec
+}
+
+func TestNegotiateFormat(t *testing.T) {
+	tests := []struct {
+		target, accept, want string
+	}{
+		{"/bookkeeping", "", formatJSON},
+		{"/bookkeeping", "*/*", formatJSON},
+		{"/bookkeeping", "text/csv", formatCSV},
+		{"/bookkeeping", "application/x-ndjson", formatNDJSON},
+		{"/bookkeeping", "text/html, text/csv;q=0.9", formatCSV},
+		{"/bookkeeping?format=ndjson", "text/csv", formatNDJSON},
+		{"/bookkeeping?format=json", "text/csv", formatJSON},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, tt.target, nil)
+		req.Header.Set("Accept", tt.accept)
+		got, err := negotiateFormat(req)
+		if err != nil || got != tt.want {
+			t.Errorf("%s (Accept %q): got %q, %v; want %q", tt.target, tt.accept, got, err, tt.want)
+		}
+	}
+
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/bookkeeping?format=xml", nil)
+	if _, err := negotiateFormat(req); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
+
+// TestCSVColumns_MatchDTOs guards the documented column order: CSV headers
+// must list the DTO JSON fields in declaration order.
+func TestCSVColumns_MatchDTOs(t *testing.T) {
+	for _, tt := range []struct {
+		dto     any
+		columns []string
+	}{
+		{ClaimDTO{}, claimColumns},
+		{XRDTO{}, xrColumns},
+		{MRDTO{}, mrColumns},
+	} {
+		typ := reflect.TypeOf(tt.dto)
+		var tags []string
+		for i := range typ.NumField() {
+			tags = append(tags, strings.Split(typ.Field(i).Tag.Get("json"), ",")[0])
+		}
+		if !reflect.DeepEqual(tags, tt.columns) {
+			t.Errorf("%s: columns %v do not match JSON fields %v", typ.Name(), tt.columns, tags)
+		}
+	}
+	if got := len(ClaimDTO{}.record()); got != len(claimColumns) {
+		t.Errorf("claim record has %d values for %d columns", got, len(claimColumns))
+	}
+	if got := len(XRDTO{}.record()); got != len(xrColumns) {
+		t.Errorf("XR record has %d values for %d columns", got, len(xrColumns))
+	}
+	if got := len(MRDTO{}.record()); got != len(mrColumns) {
+		t.Errorf("MR record has %d values for %d columns", got, len(mrColumns))
+	}
+}
+
+func TestBookkeeping_CSV(t *testing.T) {
+	rec := doExport(t, exportStore(), "/bookkeeping?type=claims&sort=name", "text/csv")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "text/csv; charset=utf-8" {
+		t.Errorf("unexpected content type %q", ct)
+	}
+
+	rows, err := csv.NewReader(rec.Body).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid CSV: %v", err)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("expected header and 2 rows, got %d", len(rows))
+	}
+	if !reflect.DeepEqual(rows[0], claimColumns) {
+		t.Errorf("unexpected header %v", rows[0])
+	}
+	if rows[1][4] != "db-1" || rows[1][5] != "alice" || rows[1][10] != "true" {
+		t.Errorf("unexpected first row %v", rows[1])
+	}
+	// Values containing the separator are quoted, not split.
+	if rows[2][5] != "bob, jr." {
+		t.Errorf("expected creator %q, got %q", "bob, jr.", rows[2][5])
+	}
+}
+
+func TestBookkeeping_StreamOutlivesWriteTimeout(t *testing.T) {
+	s := exportStore()
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Start streaming after the write deadline has passed.
+		time.Sleep(200 * time.Millisecond)
+		bookkeepingHandler(s, nil).ServeHTTP(w, r)
+	}))
+	ts.Config.WriteTimeout = 50 * time.Millisecond
+	ts.Start()
+	defer ts.Close()
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, ts.URL+"/bookkeeping?type=claims", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Accept", "text/csv")
+	resp, err := ts.Client().Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	rows, err := csv.NewReader(resp.Body).ReadAll()
+	if err != nil {
+		t.Fatalf("truncated export: %v", err)
+	}
+	if len(rows) != 3 {
+		t.Errorf("expected header and 2 rows, got %d", len(rows))
+	}
+}
+
+func TestBookkeeping_NDJSON(t *testing.T) {
+	rec := doExport(t, exportStore(), "/bookkeeping?type=mrs&format=ndjson", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/x-ndjson" {
+		t.Errorf("unexpected content type %q", ct)
+	}
+
+	var lines []MRDTO
+	scanner := bufio.NewScanner(rec.Body)
+	for scanner.Scan() {
+		var m MRDTO
+		if err := json.Unmarshal(scanner.Bytes(), &m); err != nil {
+			t.Fatalf("invalid NDJSON line %q: %v", scanner.Text(), err)
+		}
+		lines = append(lines, m)
+	}
+	if len(lines) != 1 || lines[0].Name != "b-1" || lines[0].Provider != "provider-aws" {
+		t.Errorf("unexpected NDJSON rows %+v", lines)
+	}
+}
+
+func TestBookkeeping_StreamPagination(t *testing.T) {
+	rec := doExport(t, exportStore(), "/bookkeeping?type=claims&limit=1&format=csv", "")
+	next := rec.Header().Get("X-Next-Cursor")
+	if next == "" {
+		t.Fatal("expected X-Next-Cursor header")
+	}
+	rows, _ := csv.NewReader(rec.Body).ReadAll()
+	if len(rows) != 2 || rows[1][4] != "db-1" {
+		t.Fatalf("unexpected first page %v", rows)
+	}
+
+	rec = doExport(t, exportStore(), "/bookkeeping?type=claims&limit=1&format=csv&cursor="+next, "")
+	rows, _ = csv.NewReader(rec.Body).ReadAll()
+	if len(rows) != 2 || rows[1][4] != "db-2" {
+		t.Errorf("unexpected second page %v", rows)
+	}
+	if rec.Header().Get("X-Next-Cursor") != "" {
+		t.Error("expected no cursor on the last page")
+	}
+}
+
+func TestBookkeeping_StreamBadRequests(t *testing.T) {
+	s := exportStore()
+	for _, target := range []string{
+		"/bookkeeping?format=csv",
+		"/bookkeeping?format=ndjson&type=claims&includeDeleted=true",
+		"/bookkeeping?format=csv&type=claims&sort=size",
+		"/bookkeeping?format=yaml",
+	} {
+		if rec := doExport(t, s, target, ""); rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", target, rec.Code)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package server
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/kanzifucius/xp-tracker/pkg/kube"
+	"github.com/kanzifucius/xp-tracker/pkg/store"
+)
+
+// openAPISchema is the part of an OpenAPI schema object the tests inspect.
+type openAPISchema struct {
+	Ref        string                   `json:"$ref"`
+	Properties map[string]openAPISchema `json:"properties"`
+	AllOf      []openAPISchema          `json:"allOf"`
+}
+
+type openAPIDoc struct {
+	OpenAPI    string                     `json:"openapi"`
+	Paths      map[string]json.RawMessage `json:"paths"`
+	Components struct {
+		Schemas map[string]openAPISchema `json:"schemas"`
+	} `json:"components"`
+}
+
+func loadOpenAPI(t *testing.T) openAPIDoc {
+	t.Helper()
+	var doc openAPIDoc
+	if err := json.Unmarshal(openAPIDocument, &doc); err != nil {
+		t.Fatalf("openapi.json is not valid JSON: %v", err)
+	}
+	return doc
+}
+
+func TestOpenAPI_Served(t *testing.T) {
+	baseURL, cancel := startTestServer(t, store.New())
+	defer cancel()
+
+	resp := httpGet(t, baseURL+"/openapi.json")
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "application/json") {
+		t.Errorf("expected JSON content type, got %q", ct)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("read body: %v", err)
+	}
+	var doc openAPIDoc
+	if err := json.Unmarshal(body, &doc); err != nil {
+		t.Fatalf("decode document: %v", err)
+	}
+	if !strings.HasPrefix(doc.OpenAPI, "3.") {
+		t.Errorf("expected an OpenAPI 3 document, got version %q", doc.OpenAPI)
+	}
+}
+
+// TestOpenAPI_PathsMatchRoutes checks that every documented path is served by
+// the route with the same pattern, and that every route is documented.
+func TestOpenAPI_PathsMatchRoutes(t *testing.T) {
+	doc := loadOpenAPI(t)
+	mux := New(":0", store.New()).httpServer.Handler.(*http.ServeMux)
+	param := regexp.MustCompile(`\{[^}]+\}`)
+
+	for path := range doc.Paths {
+		req := httptest.NewRequest(http.MethodGet, param.ReplaceAllString(path, "x"), nil)
+		if _, pattern := mux.Handler(req); pattern != "GET "+path {
+			t.Errorf("documented path %s is served by route %q", path, pattern)
+		}
+	}
+
+	routes := []string{
+		"/metrics", "/bookkeeping", "/events/stream", "/openapi.json", "/healthz", "/readyz", "/status",
+		"/claims/ns/name", "/xrs/name", "/xrs/ns/name", "/mrs/gvr/name", "/mrs/gvr/ns/name",
+	}
+	for _, route := range routes {
+		_, pattern := mux.Handler(httptest.NewRequest(http.MethodGet, route, nil))
+		if _, ok := doc.Paths[strings.TrimPrefix(pattern, "GET ")]; !ok {
+			t.Errorf("route %q is not documented", pattern)
+		}
+	}
+}
+
+// TestOpenAPI_SchemasMatchTypes checks that each schema lists exactly the
+// JSON fields of the Go type it documents.
+func TestOpenAPI_SchemasMatchTypes(t *testing.T) {
+	doc := loadOpenAPI(t)
+	types := map[string]any{
+		"Claim":               ClaimDTO{},
+		"XR":                  XRDTO{},
+		"MR":                  MRDTO{},
+		"DeletedClaim":        DeletedClaimDTO{},
+		"DeletedXR":           DeletedXRDTO{},
+		"DeletedMR":           DeletedMRDTO{},
+		"BookkeepingResponse": BookkeepingResponse{},
+		"ClaimRecord":         store.ClaimInfo{},
+		"XRRecord":            store.XRInfo{},
+		"MRRecord":            store.MRInfo{},
+		"ResourceLinks":       ResourceLinks{},
+		"ClaimResource":       ClaimResource{},
+		"XRResource":          XRResource{},
+		"MRResource":          MRResource{},
+		"Change":              store.Change{},
+		"ResetEvent":          resetEvent{},
+		"Status":              Status{},
+		"BuildInfo":           BuildInfo{},
+		"PollStatus":          kube.PollStatus{},
+		"GVRStatus":           kube.GVRStatus{},
+		"ShardStatus":         kube.ShardStatus{},
+		"StoreStatus":         StoreStatus{},
+		"PersistenceStatus":   store.PersistenceStatus{},
+	}
+	for name, v := range types {
+		schema, ok := doc.Components.Schemas[name]
+		if !ok {
+			t.Errorf("schema %s is missing", name)
+			continue
+		}
+		got := schemaProperties(doc, schema)
+		want := jsonFields(reflect.TypeOf(v))
+		slices.Sort(got)
+		slices.Sort(want)
+		if !slices.Equal(got, want) {
+			t.Errorf("schema %s has properties %v, type %T has fields %v", name, got, v, want)
+		}
+	}
+}
+
+// schemaProperties returns the property names of s, following allOf and
+// references to other component schemas.
+func schemaProperties(doc openAPIDoc, s openAPISchema) []string {
+	if name, ok := strings.CutPrefix(s.Ref, "#/components/schemas/"); ok {
+		return schemaProperties(doc, doc.Components.Schemas[name])
+	}
+	var names []string
+	for name := range s.Properties {
+		names = append(names, name)
+	}
+	for _, part := range s.AllOf {
+		names = append(names, schemaProperties(doc, part)...)
+	}
+	return names
+}
+
+// jsonFields returns the JSON names of the fields of struct type t,
+// including those promoted from embedded structs.
+func jsonFields(t reflect.Type) []string {
+	var names []string
+	for i := range t.NumField() {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if f.Anonymous && tag == "" {
+			names = append(names, jsonFields(f.Type)...)
+			continue
+		}
+		if name, _, _ := strings.Cut(tag, ","); name != "" && name != "-" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
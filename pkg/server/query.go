@@ -0,0 +1,106 @@
+package server
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/kanzifucius/xp-tracker/pkg/store"
+)
+
+// Resource types accepted by the type query parameter.
+const (
+	typeClaims = "claims"
+	typeXRs    = "xrs"
+	typeMRs    = "mrs"
+)
+
+// listRequest is a parsed list request: which resource type to return
+// (empty for all), the store query, and whether to include tombstones.
+type listRequest struct {
+	Type           string
+	Query          store.Query
+	IncludeDeleted bool
+}
+
+// parseListRequest maps query parameters onto a store query.
+//
+// Filters: gvr, namespace, kind, team, creator, composition, provider and
+// the booleans ready, synced, paused and deleting. Ordering: sort (a
+// store.SortField) and order (asc or desc). Pagination: limit and cursor,
+// which require type because a cursor addresses a single list.
+func parseListRequest(values url.Values) (listRequest, error) {
+	req := listRequest{
+		Type: values.Get("type"),
+		Query: store.Query{
+			GVR:         values.Get("gvr"),
+			Namespace:   values.Get("namespace"),
+			Kind:        values.Get("kind"),
+			Team:        values.Get("team"),
+			Creator:     values.Get("creator"),
+			Composition: values.Get("composition"),
+			Provider:    values.Get("provider"),
+			SortBy:      store.SortField(values.Get("sort")),
+			Cursor:      values.Get("cursor"),
+		},
+	}
+
+	switch req.Type {
+	case "", typeClaims, typeXRs, typeMRs:
+	default:
+		return listRequest{}, fmt.Errorf("type must be one of %q, %q or %q", typeClaims, typeXRs, typeMRs)
+	}
+
+	for name, dst := range map[string]**bool{
+		"ready":    &req.Query.Ready,
+		"synced":   &req.Query.Synced,
+		"paused":   &req.Query.Paused,
+		"deleting": &req.Query.Deleting,
+	} {
+		b, err := parseOptionalBool(values, name)
+		if err != nil {
+			return listRequest{}, err
+		}
+		*dst = b
+	}
+
+	includeDeleted, err := parseOptionalBool(values, "includeDeleted")
+	if err != nil {
+		return listRequest{}, err
+	}
+	req.IncludeDeleted = includeDeleted != nil && *includeDeleted
+
+	switch values.Get("order") {
+	case "", "asc":
+	case "desc":
+		req.Query.Descending = true
+	default:
+		return listRequest{}, fmt.Errorf("order must be \"asc\" or \"desc\"")
+	}
+
+	if v := values.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return listRequest{}, fmt.Errorf("limit must be a positive integer, got %q", v)
+		}
+		req.Query.Limit = n
+	}
+	if (req.Query.Limit > 0 || req.Query.Cursor != "") && req.Type == "" {
+		return listRequest{}, fmt.Errorf("limit and cursor require type")
+	}
+
+	return req, nil
+}
+
+// parseOptionalBool returns nil when the parameter is absent.
+func parseOptionalBool(values url.Values, name string) (*bool, error) {
+	v := values.Get(name)
+	if v == "" {
+		return nil, nil
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return nil, fmt.Errorf("%s must be a boolean, got %q", name, v)
+	}
+	return &b, nil
+}
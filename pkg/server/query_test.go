@@ -0,0 +1,47 @@
+package server
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/kanzifucius/xp-tracker/pkg/store"
+)
+
+func TestParseListRequest(t *testing.T) {
+	values, _ := url.ParseQuery("type=mrs&namespace=team-a&team=payments&creator=alice&kind=Bucket" +
+		"&composition=c&provider=provider-aws&ready=false&deleting=true&sort=createdAt&order=desc&limit=50&cursor=abc")
+	req, err := parseListRequest(values)
+	if err != nil {
+		t.Fatalf("parseListRequest: %v", err)
+	}
+
+	q := req.Query
+	if req.Type != typeMRs || q.Namespace != "team-a" || q.Team != "payments" || q.Creator != "alice" ||
+		q.Kind != "Bucket" || q.Composition != "c" || q.Provider != "provider-aws" {
+		t.Errorf("unexpected filters: %+v", req)
+	}
+	if q.Ready == nil || *q.Ready || q.Deleting == nil || !*q.Deleting || q.Synced != nil {
+		t.Errorf("unexpected boolean filters: ready=%v deleting=%v synced=%v", q.Ready, q.Deleting, q.Synced)
+	}
+	if q.SortBy != store.SortByCreatedAt || !q.Descending || q.Limit != 50 || q.Cursor != "abc" {
+		t.Errorf("unexpected ordering/pagination: %+v", q)
+	}
+}
+
+func TestParseListRequest_Invalid(t *testing.T) {
+	for _, raw := range []string{
+		"type=widgets",
+		"ready=maybe",
+		"includeDeleted=sometimes",
+		"order=sideways",
+		"type=claims&limit=0",
+		"type=claims&limit=ten",
+		"limit=10",
+		"cursor=abc",
+	} {
+		values, _ := url.ParseQuery(raw)
+		if _, err := parseListRequest(values); err == nil {
+			t.Errorf("expected error for %q", raw)
+		}
+	}
+}
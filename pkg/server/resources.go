@@ -0,0 +1,165 @@
+package server
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/url"
+
+	"github.com/kanzifucius/xp-tracker/pkg/store"
+)
+
+// ResourceLinks points from a single resource to itself and to the related
+// resources that are present in the same store generation. Parent and child
+// links are omitted when the related resource is not stored.
+type ResourceLinks struct {
+	Self  string   `json:"self"`
+	Claim string   `json:"claim,omitempty"`
+	XR    string   `json:"xr,omitempty"`
+	MRs   []string `json:"mrs,omitempty"`
+}
+
+// ClaimResource is the response of GET /claims/{namespace}/{name}: the full
+// stored claim record plus links.
+type ClaimResource struct {
+	store.ClaimInfo
+	Links      ResourceLinks `json:"links"`
+	Generation uint64        `json:"generation"`
+}
+
+// XRResource is the response of GET /xrs/{name}.
+type XRResource struct {
+	store.XRInfo
+	Links      ResourceLinks `json:"links"`
+	Generation uint64        `json:"generation"`
+}
+
+// MRResource is the response of GET /mrs/{gvr}/{namespace}/{name}.
+type MRResource struct {
+	store.MRInfo
+	Links      ResourceLinks `json:"links"`
+	Generation uint64        `json:"generation"`
+}
+
+// registerResourceRoutes adds the single-resource lookup endpoints to mux.
+// XRs and MRs may be cluster-scoped or namespaced, so both path shapes are
+// served. The gvr segment is "group/version/resource" with the slashes
+// percent-encoded (%2F). With a tenant scope, resources outside the
+// caller's scope are reported as not found and not linked to.
+func registerResourceRoutes(mux *http.ServeMux, s store.Store, scope scopeFunc) {
+	mux.HandleFunc("GET /claims/{namespace}/{name}", claimHandler(s, scope))
+	mux.HandleFunc("GET /xrs/{name}", xrHandler(s, scope))
+	mux.HandleFunc("GET /xrs/{namespace}/{name}", xrHandler(s, scope))
+	mux.HandleFunc("GET /mrs/{gvr}/{name}", mrHandler(s, scope))
+	mux.HandleFunc("GET /mrs/{gvr}/{namespace}/{name}", mrHandler(s, scope))
+}
+
+func claimHandler(s store.Store, scope scopeFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		view := s.View()
+		sc, ok := resolveScope(w, r, scope, view)
+		if !ok {
+			return
+		}
+		c, ok := view.Claim(r.PathValue("namespace"), r.PathValue("name"))
+		if !ok || !view.AllowsClaim(sc, c) {
+			http.Error(w, "claim not found", http.StatusNotFound)
+			return
+		}
+
+		links := ResourceLinks{Self: claimPath(c.Namespace, c.Name)}
+		if xr, ok := view.LookupXR(c.Namespace, c.XRRef); ok && view.AllowsXR(sc, xr) {
+			links.XR = xrPath(xr.Namespace, xr.Name)
+			links.MRs = mrPaths(view, sc, view.MRsForXR(xr.Name))
+		}
+		writeResource(w, ClaimResource{ClaimInfo: c, Links: links, Generation: view.Generation().Number})
+	}
+}
+
+func xrHandler(s store.Store, scope scopeFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		view := s.View()
+		sc, ok := resolveScope(w, r, scope, view)
+		if !ok {
+			return
+		}
+		x, ok := view.XR(r.PathValue("namespace"), r.PathValue("name"))
+		if !ok || !view.AllowsXR(sc, x) {
+			http.Error(w, "XR not found", http.StatusNotFound)
+			return
+		}
+
+		links := ResourceLinks{
+			Self: xrPath(x.Namespace, x.Name),
+			MRs:  mrPaths(view, sc, view.MRsForXR(x.Name)),
+		}
+		if c, ok := view.Claim(x.ClaimNS, x.ClaimName); ok && view.AllowsClaim(sc, c) {
+			links.Claim = claimPath(c.Namespace, c.Name)
+		}
+		writeResource(w, XRResource{XRInfo: x, Links: links, Generation: view.Generation().Number})
+	}
+}
+
+func mrHandler(s store.Store, scope scopeFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		view := s.View()
+		sc, ok := resolveScope(w, r, scope, view)
+		if !ok {
+			return
+		}
+		m, ok := view.MR(r.PathValue("gvr"), r.PathValue("namespace"), r.PathValue("name"))
+		if !ok || !view.AllowsMR(sc, m) {
+			http.Error(w, "MR not found", http.StatusNotFound)
+			return
+		}
+
+		links := ResourceLinks{Self: mrPath(m.GVR, m.Namespace, m.Name)}
+		if xr, ok := view.LookupXR(m.Namespace, m.XRName); ok && view.AllowsXR(sc, xr) {
+			links.XR = xrPath(xr.Namespace, xr.Name)
+		}
+		if c, ok := view.Claim(m.ClaimNS, m.ClaimName); ok && view.AllowsClaim(sc, c) {
+			links.Claim = claimPath(c.Namespace, c.Name)
+		}
+		writeResource(w, MRResource{MRInfo: m, Links: links, Generation: view.Generation().Number})
+	}
+}
+
+func writeResource(w http.ResponseWriter, v any) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		slog.Error("failed to marshal resource", "error", err)
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	_, _ = w.Write(data)
+}
+
+func claimPath(namespace, name string) string {
+	return "/claims/" + url.PathEscape(namespace) + "/" + url.PathEscape(name)
+}
+
+func xrPath(namespace, name string) string {
+	if namespace == "" {
+		return "/xrs/" + url.PathEscape(name)
+	}
+	return "/xrs/" + url.PathEscape(namespace) + "/" + url.PathEscape(name)
+}
+
+func mrPath(gvr, namespace, name string) string {
+	if namespace == "" {
+		return "/mrs/" + url.PathEscape(gvr) + "/" + url.PathEscape(name)
+	}
+	return "/mrs/" + url.PathEscape(gvr) + "/" + url.PathEscape(namespace) + "/" + url.PathEscape(name)
+}
+
+// mrPaths returns the paths of the MRs in scope.
+func mrPaths(view store.View, scope *store.Scope, mrs []store.MRInfo) []string {
+	var paths []string
+	for _, m := range mrs {
+		if view.AllowsMR(scope, m) {
+			paths = append(paths, mrPath(m.GVR, m.Namespace, m.Name))
+		}
+	}
+	return paths
+}
@@ -0,0 +1,206 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"testing"
+
+	"github.com/kanzifucius/xp-tracker/pkg/auth"
+	"github.com/kanzifucius/xp-tracker/pkg/store"
+)
+
+const bucketGVR = "s3.aws.upbound.io/v1beta1/buckets"
+
+func resourceStore() *store.MemoryStore {
+	s := store.New()
+	s.ReplaceClaims("g/v1/things", []store.ClaimInfo{{
+		GVR: "g/v1/things", Group: "g", Version: "v1", Kind: "Thing",
+		Namespace: "ns1", Name: "claim-a", Creator: "alice", Team: "backend",
+		Composition: "comp-a", XRRef: "xr-a", Ready: true,
+	}})
+	s.ReplaceXRs("g/v1/xthings", []store.XRInfo{{
+		GVR: "g/v1/xthings", Group: "g", Version: "v1", Kind: "XThing",
+		Name: "xr-a", Composition: "comp-a", ClaimName: "claim-a", ClaimNS: "ns1",
+	}})
+	s.ReplaceMRs(bucketGVR, []store.MRInfo{
+		{
+			GVR: bucketGVR, Group: "s3.aws.upbound.io", Version: "v1beta1", Kind: "Bucket",
+			Name: "bucket-2", XRName: "xr-a", ClaimName: "claim-a", ClaimNS: "ns1", Provider: "aws",
+		},
+		{
+			GVR: bucketGVR, Group: "s3.aws.upbound.io", Version: "v1beta1", Kind: "Bucket",
+			Name: "bucket-1", XRName: "xr-a", ClaimName: "claim-a", ClaimNS: "ns1", Provider: "aws",
+		},
+		{
+			GVR: bucketGVR, Group: "s3.aws.upbound.io", Version: "v1beta1", Kind: "Bucket",
+			Name: "orphan",
+		},
+	})
+	return s
+}
+
+// getResource serves path through a mux with the resource routes registered,
+// so path patterns and escaping behave as in production.
+func getResource(t *testing.T, s store.Store, path string) *httptest.ResponseRecorder {
+	t.Helper()
+	mux := http.NewServeMux()
+	registerResourceRoutes(mux, s, nil)
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, path, nil)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	return rec
+}
+
+func decodeResource[T any](t *testing.T, rec *httptest.ResponseRecorder) T {
+	t.Helper()
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json; charset=utf-8" {
+		t.Errorf("expected application/json content-type, got %q", ct)
+	}
+	var v T
+	if err := json.NewDecoder(rec.Body).Decode(&v); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	return v
+}
+
+func TestResource_Claim(t *testing.T) {
+	s := resourceStore()
+	got := decodeResource[ClaimResource](t, getResource(t, s, "/claims/ns1/claim-a"))
+
+	if got.GVR != "g/v1/things" || got.XRRef != "xr-a" || got.Creator != "alice" {
+		t.Errorf("unexpected record: %+v", got.ClaimInfo)
+	}
+	escaped := url.PathEscape(bucketGVR)
+	want := ResourceLinks{
+		Self: "/claims/ns1/claim-a",
+		XR:   "/xrs/xr-a",
+		MRs:  []string{"/mrs/" + escaped + "/bucket-1", "/mrs/" + escaped + "/bucket-2"},
+	}
+	if got.Links.Self != want.Self || got.Links.XR != want.XR || got.Links.Claim != "" || !slices.Equal(got.Links.MRs, want.MRs) {
+		t.Errorf("links = %+v, want %+v", got.Links, want)
+	}
+	if got.Generation != s.Generation().Number {
+		t.Errorf("generation = %d, want %d", got.Generation, s.Generation().Number)
+	}
+}
+
+func TestResource_ClaimWithoutXR(t *testing.T) {
+	s := store.New()
+	s.ReplaceClaims("g/v1/things", []store.ClaimInfo{{
+		GVR: "g/v1/things", Namespace: "ns1", Name: "pending", XRRef: "not-yet",
+	}})
+	got := decodeResource[ClaimResource](t, getResource(t, s, "/claims/ns1/pending"))
+	if got.Links.XR != "" || got.Links.MRs != nil {
+		t.Errorf("expected no links to missing resources, got %+v", got.Links)
+	}
+}
+
+func TestResource_XR(t *testing.T) {
+	got := decodeResource[XRResource](t, getResource(t, resourceStore(), "/xrs/xr-a"))
+
+	if got.GVR != "g/v1/xthings" || got.ClaimName != "claim-a" || got.ClaimNS != "ns1" {
+		t.Errorf("unexpected record: %+v", got.XRInfo)
+	}
+	if got.Links.Self != "/xrs/xr-a" || got.Links.Claim != "/claims/ns1/claim-a" || len(got.Links.MRs) != 2 {
+		t.Errorf("unexpected links: %+v", got.Links)
+	}
+}
+
+func TestResource_NamespacedXR(t *testing.T) {
+	s := store.New()
+	s.ReplaceXRs("g/v2/xthings", []store.XRInfo{{GVR: "g/v2/xthings", Namespace: "ns2", Name: "xr-n"}})
+
+	got := decodeResource[XRResource](t, getResource(t, s, "/xrs/ns2/xr-n"))
+	if got.Links.Self != "/xrs/ns2/xr-n" {
+		t.Errorf("self = %q", got.Links.Self)
+	}
+	if rec := getResource(t, s, "/xrs/xr-n"); rec.Code != http.StatusNotFound {
+		t.Errorf("cluster-scoped lookup of namespaced XR: expected 404, got %d", rec.Code)
+	}
+}
+
+func TestResource_MR(t *testing.T) {
+	path := "/mrs/" + url.PathEscape(bucketGVR) + "/bucket-1"
+	got := decodeResource[MRResource](t, getResource(t, resourceStore(), path))
+
+	if got.GVR != bucketGVR || got.XRName != "xr-a" || got.Provider != "aws" {
+		t.Errorf("unexpected record: %+v", got.MRInfo)
+	}
+	want := ResourceLinks{Self: path, Claim: "/claims/ns1/claim-a", XR: "/xrs/xr-a"}
+	if got.Links.Self != want.Self || got.Links.Claim != want.Claim || got.Links.XR != want.XR || got.Links.MRs != nil {
+		t.Errorf("links = %+v, want %+v", got.Links, want)
+	}
+}
+
+func TestResource_OrphanMR(t *testing.T) {
+	got := decodeResource[MRResource](t, getResource(t, resourceStore(), "/mrs/"+url.PathEscape(bucketGVR)+"/orphan"))
+	if got.Links.Claim != "" || got.Links.XR != "" {
+		t.Errorf("expected only a self link, got %+v", got.Links)
+	}
+}
+
+func TestResource_NotFound(t *testing.T) {
+	s := resourceStore()
+	for _, path := range []string{
+		"/claims/ns1/missing",
+		"/claims/other/claim-a",
+		"/xrs/missing",
+		"/mrs/" + url.PathEscape(bucketGVR) + "/missing",
+		"/mrs/" + url.PathEscape("other.io/v1/buckets") + "/bucket-1",
+		"/mrs/" + url.PathEscape(bucketGVR) + "/ns1/bucket-1",
+	} {
+		t.Run(path, func(t *testing.T) {
+			if rec := getResource(t, s, path); rec.Code != http.StatusNotFound {
+				t.Errorf("expected 404, got %d", rec.Code)
+			}
+		})
+	}
+}
+
+func TestResources_TenantScope(t *testing.T) {
+	s := resourceStore()
+	srv := New(":0", s)
+	srv.SetTenancy(auth.NewTenancy(namespaceReviewer{}, auth.TenancyOptions{
+		ByNamespace:     true,
+		NamespaceAccess: auth.ResourceAttributes{Verb: "list", Resource: "pods"},
+	}))
+	get := func(u *auth.User, path string) *httptest.ResponseRecorder {
+		ctx := context.Background()
+		if u != nil {
+			ctx = auth.WithUser(ctx, *u)
+		}
+		rec := httptest.NewRecorder()
+		srv.httpServer.Handler.ServeHTTP(rec, httptest.NewRequestWithContext(ctx, http.MethodGet, path, nil))
+		return rec
+	}
+
+	owner := &auth.User{Name: "ns1"}
+	if rec := get(owner, "/claims/ns1/claim-a"); rec.Code != http.StatusOK {
+		t.Errorf("expected 200 for a claim in scope, got %d", rec.Code)
+	}
+	xr := decodeResource[XRResource](t, get(owner, "/xrs/xr-a"))
+	if xr.Links.Claim == "" || len(xr.Links.MRs) != 2 {
+		t.Errorf("expected links to the claim and both MRs, got %+v", xr.Links)
+	}
+	// The orphan MR has no namespace in the caller's scope.
+	if rec := get(owner, "/mrs/"+url.PathEscape(bucketGVR)+"/orphan"); rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for an MR out of scope, got %d", rec.Code)
+	}
+
+	other := &auth.User{Name: "ns2"}
+	for _, path := range []string{"/claims/ns1/claim-a", "/xrs/xr-a", "/mrs/" + url.PathEscape(bucketGVR) + "/bucket-1"} {
+		if rec := get(other, path); rec.Code != http.StatusNotFound {
+			t.Errorf("%s: expected 404 out of scope, got %d", path, rec.Code)
+		}
+	}
+	if rec := get(nil, "/claims/ns1/claim-a"); rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401 without an authenticated user, got %d", rec.Code)
+	}
+}
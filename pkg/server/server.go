@@ -4,49 +4,68 @@ package server
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log/slog"
 	"net"
 	"net/http"
+	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 
+	"github.com/kanzifucius/xp-tracker/pkg/auth"
+	"github.com/kanzifucius/xp-tracker/pkg/kube"
 	"github.com/kanzifucius/xp-tracker/pkg/metrics"
 	"github.com/kanzifucius/xp-tracker/pkg/store"
 )
 
-// Server serves Prometheus metrics over HTTP.
+// Server serves Prometheus metrics over HTTP or HTTPS.
 type Server struct {
-	httpServer *http.Server
-	registry   *prometheus.Registry
-	listener   net.Listener
-	ready      atomic.Bool
-	listening  chan struct{} // closed once the listener is bound
+	httpServer     *http.Server
+	healthServer   *http.Server // plain HTTP health probes; nil unless SetHealthAddr was called
+	registry       *prometheus.Registry
+	listener       net.Listener
+	healthListener net.Listener
+	tls            *tlsReloader  // nil serves plain HTTP
+	tenancy        *auth.Tenancy // nil shows every caller the full inventory
+	readyMu        sync.Mutex    // serializes readiness changes
+	ready          atomic.Bool
+	shuttingDown   bool                         // set when Run begins shutting down, ends readiness; guarded by readyMu
+	onReadiness    []func(bool)                 // run whenever readiness changes
+	staleness      func() (time.Duration, bool) // nil disables the staleness check
+	build          BuildInfo
+	pollStatus     func() kube.PollStatus // nil omits the poller from /status
+	listening      chan struct{}          // closed once the listeners are bound
+	stopping       chan struct{}          // closed when shutdown begins, ends event streams
 }
 
 // New creates a new metrics Server.
-// It registers claim and XR collectors with a dedicated Prometheus registry.
+// It registers the inventory collector with a dedicated Prometheus registry.
 func New(addr string, s store.Store) *Server {
 	registry := prometheus.NewRegistry()
-	registry.MustRegister(metrics.NewClaimCollector(s))
-	registry.MustRegister(metrics.NewXRCollector(s))
-	registry.MustRegister(metrics.NewMRCollector(s))
+	registry.MustRegister(metrics.NewInventoryCollector(s))
 	metrics.RegisterSelfMetrics(registry)
 
 	srv := &Server{
 		registry:  registry,
 		listening: make(chan struct{}),
+		stopping:  make(chan struct{}),
 	}
 
 	mux := http.NewServeMux()
 	mux.Handle("GET /metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{
 		EnableOpenMetrics: false, // stick to classic Prometheus text format
 	}))
-	mux.HandleFunc("GET /bookkeeping", bookkeepingHandler(s))
+	mux.HandleFunc("GET /bookkeeping", bookkeepingHandler(s, srv.tenantScope))
+	registerResourceRoutes(mux, s, srv.tenantScope)
+	mux.HandleFunc("GET /events/stream", eventsHandler(s, srv.tenantScope, srv.stopping, eventStreamHeartbeat))
+	mux.HandleFunc("GET /openapi.json", openAPIHandler)
+	registerDashboardRoutes(mux)
 	mux.HandleFunc("GET /healthz", srv.healthzHandler)
 	mux.HandleFunc("GET /readyz", srv.readyzHandler)
+	mux.HandleFunc("GET /status", srv.statusHandler(s))
 
 	srv.httpServer = &http.Server{
 		Addr:              addr,
@@ -56,6 +75,9 @@ func New(addr string, s store.Store) *Server {
 		WriteTimeout:      60 * time.Second,
 		MaxHeaderBytes:    1 << 20, // 1 MiB
 	}
+	// Shutdown waits for active requests, so long-lived event streams must
+	// be told to end.
+	srv.httpServer.RegisterOnShutdown(func() { close(srv.stopping) })
 	return srv
 }
 
@@ -66,9 +88,118 @@ func (s *Server) Addr() string {
 	return s.listener.Addr().String()
 }
 
-// SetReady marks the server as ready. Call this after the first successful
-// polling cycle completes.
-func (s *Server) SetReady() { s.ready.Store(true) }
+// HealthAddr returns the address of the separate health listener, or ""
+// if there is none. Like Addr, it blocks until Run has opened the listeners.
+func (s *Server) HealthAddr() string {
+	<-s.listening
+	if s.healthListener == nil {
+		return ""
+	}
+	return s.healthListener.Addr().String()
+}
+
+// SetTenancy restricts the inventory served by /bookkeeping, the
+// single-resource endpoints and the event stream to what each
+// authenticated caller may see. Requests to them must then be
+// authenticated (see SetAuth and ScopedPaths). Call it before Run.
+func (s *Server) SetTenancy(t *auth.Tenancy) {
+	s.tenancy = t
+}
+
+// ScopedPaths are the inventory endpoints that tenant scoping restricts;
+// a path ending in "/" stands for every path below it.
+var ScopedPaths = []string{"/bookkeeping", "/claims/", "/xrs/", "/mrs/", "/events/stream"}
+
+// errUnauthenticated is returned by tenantScope for requests without an
+// authenticated user.
+var errUnauthenticated = errors.New("tenant scoping requires an authenticated request")
+
+// tenantScope resolves the tenant scope of r, or nil without tenancy.
+func (s *Server) tenantScope(r *http.Request, view store.View) (*store.Scope, error) {
+	if s.tenancy == nil {
+		return nil, nil
+	}
+	u, ok := auth.UserFrom(r.Context())
+	if !ok {
+		return nil, errUnauthenticated
+	}
+	return s.tenancy.Scope(r.Context(), u, view.Namespaces())
+}
+
+// SetTLS serves the API over HTTPS using the certificate and key files in
+// cfg, optionally verifying client certificates. The files are reloaded when
+// they change. Call it before Run.
+func (s *Server) SetTLS(cfg TLSConfig) error {
+	r, err := newTLSReloader(cfg)
+	if err != nil {
+		return err
+	}
+	s.tls = r
+	s.httpServer.TLSConfig = r.tlsConfig()
+	return nil
+}
+
+// SetHealthAddr additionally serves /healthz and /readyz over plain HTTP on
+// addr, so that kubelet probes work when the main listener requires TLS or
+// client certificates. Call it before Run.
+func (s *Server) SetHealthAddr(addr string) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", s.healthzHandler)
+	mux.HandleFunc("GET /readyz", s.readyzHandler)
+	s.healthServer = &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       30 * time.Second,
+		WriteTimeout:      30 * time.Second,
+		MaxHeaderBytes:    1 << 20, // 1 MiB
+	}
+}
+
+// SetReady marks the server as ready. Call this after the first polling
+// cycle completes. The server stays ready until it shuts down.
+func (s *Server) SetReady() {
+	s.readyMu.Lock()
+	defer s.readyMu.Unlock()
+	if s.shuttingDown {
+		return
+	}
+	s.setReady(true)
+}
+
+// setReady records whether the server is ready and runs the callbacks
+// registered with OnReadinessChange if that changed. The caller must hold
+// readyMu.
+func (s *Server) setReady(ready bool) {
+	if s.ready.Swap(ready) == ready {
+		return
+	}
+	for _, fn := range s.onReadiness {
+		fn(ready)
+	}
+}
+
+// OnReadinessChange registers fn to run whenever the server becomes ready
+// or stops being ready, so that other listeners (such as gRPC health
+// checking) report the same readiness as /readyz. Being degraded does not
+// change readiness. Call it before Run.
+func (s *Server) OnReadinessChange(fn func(ready bool)) {
+	s.onReadiness = append(s.onReadiness, fn)
+}
+
+// SetStaleness makes /readyz report the exporter as degraded whenever fn
+// reports the data as stale, along with the age of the last successful
+// poll. A degraded exporter stays ready. fn is called on every request, so
+// it follows configuration reloads. Call it before Run.
+func (s *Server) SetStaleness(fn func() (age time.Duration, stale bool)) {
+	s.staleness = fn
+}
+
+// SetAuth requires authentication and authorization for the endpoints f
+// protects. Call it before Run.
+func (s *Server) SetAuth(f *auth.Filter) {
+	s.httpServer.Handler = f.Wrap(s.httpServer.Handler)
+}
 
 // healthzHandler responds with 200 OK if the process is alive.
 func (s *Server) healthzHandler(w http.ResponseWriter, _ *http.Request) {
@@ -78,21 +209,40 @@ func (s *Server) healthzHandler(w http.ResponseWriter, _ *http.Request) {
 }
 
 // readyzHandler responds with 200 OK only after SetReady has been called,
-// indicating the first poll cycle has completed and metrics are populated.
+// indicating the first poll cycle has completed and metrics are populated,
+// and until the server shuts down. When the last successful poll cycle is
+// too old (see SetStaleness), it still responds with 200 but reports the
+// exporter as degraded in the body and the X-Readiness header.
 func (s *Server) readyzHandler(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
 	if !s.ready.Load() {
-		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
 		w.WriteHeader(http.StatusServiceUnavailable)
 		_, _ = w.Write([]byte("not ready\n"))
 		return
 	}
-	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	if age, stale := s.pollAge(); stale {
+		w.Header().Set("X-Readiness", "degraded")
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprintf(w, "degraded: last successful poll %s ago\n", age.Truncate(time.Second))
+		return
+	}
+	w.Header().Set("X-Readiness", "ok")
 	w.WriteHeader(http.StatusOK)
 	_, _ = w.Write([]byte("ok\n"))
 }
 
-// Run starts the HTTP server. It blocks until the server is stopped.
-// When ctx is cancelled, the server shuts down gracefully.
+// pollAge returns the age of the last successful poll and whether the data
+// is stale.
+func (s *Server) pollAge() (time.Duration, bool) {
+	if s.staleness == nil {
+		return 0, false
+	}
+	return s.staleness()
+}
+
+// Run starts the HTTP server, and the health server if one is configured.
+// It blocks until the servers are stopped. When ctx is cancelled, the
+// servers shut down gracefully.
 func (s *Server) Run(ctx context.Context) error {
 	var lc net.ListenConfig
 	ln, err := lc.Listen(ctx, "tcp", s.httpServer.Addr)
@@ -101,24 +251,60 @@ func (s *Server) Run(ctx context.Context) error {
 		return err
 	}
 	s.listener = ln
+	if s.healthServer != nil {
+		hln, err := lc.Listen(ctx, "tcp", s.healthServer.Addr)
+		if err != nil {
+			_ = ln.Close()
+			close(s.listening)
+			return err
+		}
+		s.healthListener = hln
+	}
 	close(s.listening)
 
-	errCh := make(chan error, 1)
+	errCh := make(chan error, 2)
 	go func() {
-		slog.Info("metrics server listening", "addr", ln.Addr().String())
-		if err := s.httpServer.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		var err error
+		if s.tls != nil {
+			slog.Info("metrics server listening", "addr", ln.Addr().String(), "tls", true)
+			err = s.httpServer.ServeTLS(ln, "", "")
+		} else {
+			slog.Info("metrics server listening", "addr", ln.Addr().String())
+			err = s.httpServer.Serve(ln)
+		}
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
 			errCh <- err
 		}
-		close(errCh)
 	}()
+	if s.healthServer != nil {
+		go func() {
+			slog.Info("health server listening", "addr", s.healthListener.Addr().String())
+			if err := s.healthServer.Serve(s.healthListener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				errCh <- err
+			}
+		}()
+	}
 
+	var runErr error
 	select {
 	case <-ctx.Done():
 		slog.Info("shutting down metrics server")
-		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-		defer cancel()
-		return s.httpServer.Shutdown(shutdownCtx)
-	case err := <-errCh:
-		return err
+	case runErr = <-errCh:
+	}
+
+	s.readyMu.Lock()
+	s.shuttingDown = true
+	s.setReady(false)
+	s.readyMu.Unlock()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err = s.httpServer.Shutdown(shutdownCtx)
+	if s.healthServer != nil {
+		err = errors.Join(err, s.healthServer.Shutdown(shutdownCtx))
+	}
+	if runErr != nil {
+		return runErr
 	}
+	return err
 }
@@ -4,9 +4,14 @@ import (
 	"context"
 	"io"
 	"net/http"
+	"slices"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/kanzifucius/xp-tracker/pkg/auth"
 	"github.com/kanzifucius/xp-tracker/pkg/store"
 )
 
@@ -192,3 +197,151 @@ func TestServer_ReadyzEndpoint(t *testing.T) {
 		t.Errorf("expected 'ok', got %q", string(body))
 	}
 }
+
+func TestServer_ReadyzDegraded(t *testing.T) {
+	srv := New(":0", store.New())
+	var stale atomic.Bool
+	srv.SetStaleness(func() (time.Duration, bool) {
+		if stale.Load() {
+			return 2 * time.Minute, true
+		}
+		return time.Second, false
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		_ = srv.Run(ctx)
+	}()
+	baseURL := "http://" + srv.Addr()
+	srv.SetReady()
+
+	readyz := func() (int, string) {
+		t.Helper()
+		resp := httpGet(t, baseURL+"/readyz")
+		defer func() { _ = resp.Body.Close() }()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("failed to read body: %v", err)
+		}
+		return resp.StatusCode, string(body)
+	}
+
+	if code, body := readyz(); code != http.StatusOK || body != "ok\n" {
+		t.Fatalf("expected 200 ok with fresh data, got %d: %s", code, body)
+	}
+
+	// Stale data degrades the exporter but keeps it ready.
+	stale.Store(true)
+	if code, body := readyz(); code != http.StatusOK || body != "degraded: last successful poll 2m0s ago\n" {
+		t.Errorf("expected 200 degraded for a stale poll, got %d: %s", code, body)
+	}
+	resp := httpGet(t, baseURL+"/readyz")
+	_ = resp.Body.Close()
+	if got := resp.Header.Get("X-Readiness"); got != "degraded" {
+		t.Errorf("expected X-Readiness: degraded, got %q", got)
+	}
+
+	stale.Store(false)
+	if code, body := readyz(); code != http.StatusOK {
+		t.Errorf("expected 200 after a fresh poll, got %d: %s", code, body)
+	}
+}
+
+func TestServer_ReadinessChanges(t *testing.T) {
+	srv := New(":0", store.New())
+	var mu sync.Mutex
+	var changes []bool
+	srv.OnReadinessChange(func(ready bool) {
+		mu.Lock()
+		defer mu.Unlock()
+		changes = append(changes, ready)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		_ = srv.Run(ctx)
+		close(done)
+	}()
+	baseURL := "http://" + srv.Addr()
+	srv.SetReady()
+	srv.SetReady()
+	resp := httpGet(t, baseURL+"/readyz")
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200 after SetReady, got %d", resp.StatusCode)
+	}
+
+	// Shutting down ends readiness.
+	cancel()
+	<-done
+	mu.Lock()
+	defer mu.Unlock()
+	if !slices.Equal(changes, []bool{true, false}) {
+		t.Errorf("expected readiness to change to true and back to false, got %v", changes)
+	}
+	if srv.ready.Load() {
+		t.Error("expected the server not to be ready after shutdown")
+	}
+}
+
+// tokenReviewer accepts the token "scraper", which may only get /metrics.
+type tokenReviewer struct{}
+
+func (tokenReviewer) Authenticate(_ context.Context, token string) (auth.User, bool, error) {
+	return auth.User{Name: token}, token == "scraper", nil
+}
+
+func (tokenReviewer) Authorize(_ context.Context, u auth.User, verb, path string) (bool, string, error) {
+	return u.Name == "scraper" && verb == "get" && path == "/metrics", "", nil
+}
+
+func (tokenReviewer) AuthorizeResource(context.Context, auth.User, auth.ResourceAttributes) (bool, error) {
+	return false, nil
+}
+
+func TestServer_Auth(t *testing.T) {
+	srv := New(":0", store.New())
+	srv.SetAuth(auth.NewFilter(tokenReviewer{}, []string{"/"}))
+	srv.SetReady()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		_ = srv.Run(ctx)
+	}()
+	baseURL := "http://" + srv.Addr()
+
+	get := func(path, token string) int {
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, baseURL+path, nil)
+		if err != nil {
+			t.Fatalf("failed to create request: %v", err)
+		}
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("failed to GET %s: %v", path, err)
+		}
+		_ = resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	tests := []struct {
+		path, token string
+		want        int
+	}{
+		{"/metrics", "", http.StatusUnauthorized},
+		{"/metrics", "scraper", http.StatusOK},
+		{"/bookkeeping", "scraper", http.StatusForbidden},
+		{"/healthz", "", http.StatusOK},
+		{"/readyz", "", http.StatusOK},
+	}
+	for _, tt := range tests {
+		if got := get(tt.path, tt.token); got != tt.want {
+			t.Errorf("GET %s with token %q: expected %d, got %d", tt.path, tt.token, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/kanzifucius/xp-tracker/pkg/kube"
+	"github.com/kanzifucius/xp-tracker/pkg/store"
+)
+
+// Status is the response of GET /status: the state of the exporter itself,
+// for diagnosing it without reading logs.
+type Status struct {
+	Build    BuildInfo        `json:"build"`
+	Ready    bool             `json:"ready"`
+	Degraded bool             `json:"degraded"`       // see SetStaleAfter
+	Poll     *kube.PollStatus `json:"poll,omitempty"` // omitted until SetPollStatus is called
+	Store    StoreStatus      `json:"store"`
+}
+
+// BuildInfo identifies the running build.
+type BuildInfo struct {
+	Version string `json:"version"`
+	Commit  string `json:"commit"`
+	Date    string `json:"date"`
+}
+
+// StoreStatus describes the store contents and, for persistent backends,
+// the state of the snapshots.
+type StoreStatus struct {
+	Backend     string                   `json:"backend"` // "memory" or the persistent backend
+	Generation  store.GenerationInfo     `json:"generation"`
+	Claims      int                      `json:"claims"`
+	XRs         int                      `json:"xrs"`
+	MRs         int                      `json:"mrs"`
+	Persistence *store.PersistenceStatus `json:"persistence,omitempty"`
+}
+
+// SetBuildInfo sets the build reported by /status. Call it before Run.
+func (s *Server) SetBuildInfo(b BuildInfo) {
+	s.build = b
+}
+
+// SetPollStatus makes /status report the poller state returned by fn. Call
+// it before Run.
+func (s *Server) SetPollStatus(fn func() kube.PollStatus) {
+	s.pollStatus = fn
+}
+
+func (s *Server) statusHandler(st store.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		_, stale := s.pollAge()
+		view := st.View()
+		resp := Status{
+			Build:    s.build,
+			Ready:    s.ready.Load(),
+			Degraded: s.ready.Load() && stale,
+			Store: StoreStatus{
+				Backend:    "memory",
+				Generation: view.Generation(),
+				Claims:     view.ClaimCount(),
+				XRs:        view.XRCount(),
+				MRs:        view.MRCount(),
+			},
+		}
+		if s.pollStatus != nil {
+			ps := s.pollStatus()
+			resp.Poll = &ps
+		}
+		if ps, ok := st.(store.PersistentStore); ok {
+			p := ps.PersistenceStatus()
+			resp.Store.Backend = p.Backend
+			resp.Store.Persistence = &p
+		}
+		writeResource(w, resp)
+	}
+}
@@ -0,0 +1,97 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/kanzifucius/xp-tracker/pkg/kube"
+	"github.com/kanzifucius/xp-tracker/pkg/store"
+)
+
+// persistentStore is a MemoryStore reporting a fixed persistence status.
+type persistentStore struct {
+	*store.MemoryStore
+	status store.PersistenceStatus
+}
+
+func (persistentStore) Persist(context.Context) error                { return nil }
+func (persistentStore) Restore(context.Context) error                { return nil }
+func (p persistentStore) PersistenceStatus() store.PersistenceStatus { return p.status }
+
+func getStatus(t *testing.T, srv *Server) Status {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go func() {
+		_ = srv.Run(ctx)
+	}()
+
+	resp := httpGet(t, "http://"+srv.Addr()+"/status")
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	var st Status
+	if err := json.NewDecoder(resp.Body).Decode(&st); err != nil {
+		t.Fatalf("decode status: %v", err)
+	}
+	return st
+}
+
+func TestServer_Status(t *testing.T) {
+	s := store.New()
+	s.BeginGeneration()
+	s.ReplaceClaims("g/v1/things", []store.ClaimInfo{{GVR: "g/v1/things", Namespace: "ns", Name: "a"}})
+	s.CommitGeneration()
+
+	lastSuccess := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	srv := New(":0", s)
+	srv.SetBuildInfo(BuildInfo{Version: "v1.2.3", Commit: "abc123", Date: "2026-10-01"})
+	srv.SetPollStatus(func() kube.PollStatus {
+		return kube.PollStatus{
+			Cycles:      4,
+			LastSuccess: lastSuccess,
+			XRs:         []kube.GVRStatus{{GVR: "g/v1/xthings", LastError: "forbidden"}},
+		}
+	})
+	srv.SetReady()
+
+	st := getStatus(t, srv)
+	if st.Build.Version != "v1.2.3" || st.Build.Commit != "abc123" {
+		t.Errorf("unexpected build info: %+v", st.Build)
+	}
+	if !st.Ready || st.Degraded {
+		t.Errorf("expected ready and not degraded, got ready=%v degraded=%v", st.Ready, st.Degraded)
+	}
+	if st.Poll == nil || st.Poll.Cycles != 4 || !st.Poll.LastSuccess.Equal(lastSuccess) || st.Poll.XRs[0].LastError != "forbidden" {
+		t.Errorf("unexpected poll status: %+v", st.Poll)
+	}
+	if st.Store.Backend != "memory" || st.Store.Claims != 1 || st.Store.Generation.Number != 1 || st.Store.Persistence != nil {
+		t.Errorf("unexpected store status: %+v", st.Store)
+	}
+}
+
+func TestServer_StatusPersistence(t *testing.T) {
+	snapshotAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	ps := persistentStore{
+		MemoryStore: store.New(),
+		status: store.PersistenceStatus{
+			Backend:       "s3",
+			Location:      "s3://bucket/xp-tracker/snapshot.json",
+			RestoreResult: store.RestoreRestored,
+			SnapshotAt:    snapshotAt,
+		},
+	}
+
+	st := getStatus(t, New(":0", ps))
+	if st.Ready || st.Poll != nil {
+		t.Errorf("expected not ready and no poll status, got ready=%v poll=%+v", st.Ready, st.Poll)
+	}
+	p := st.Store.Persistence
+	if st.Store.Backend != "s3" || p == nil || p.RestoreResult != store.RestoreRestored || !p.SnapshotAt.Equal(snapshotAt) {
+		t.Errorf("unexpected store status: %+v", st.Store)
+	}
+}
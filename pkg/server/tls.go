@@ -0,0 +1,179 @@
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+)
+
+// tlsReloadInterval is the minimum time between checks of the certificate,
+// key and CA files for changes.
+const tlsReloadInterval = 10 * time.Second
+
+// TLSConfig configures HTTPS for the metrics server.
+type TLSConfig struct {
+	// CertFile and KeyFile hold the PEM-encoded serving certificate (with any
+	// intermediates) and its private key.
+	CertFile string
+	KeyFile  string
+
+	// ClientCAFile is an optional PEM bundle of CAs that client certificates
+	// are verified against. Empty disables client certificate verification.
+	ClientCAFile string
+
+	// ClientAuth says whether clients must present a certificate when
+	// ClientCAFile is set. Defaults to tls.RequireAndVerifyClientCert.
+	ClientAuth tls.ClientAuthType
+}
+
+// tlsReloader serves the certificate and client CAs from files, reloading
+// them when the files change. cert-manager rotates certificates by updating
+// the mounted Secret, so a changed modification time or size is taken as a
+// rotation. If reloading fails the previous certificate stays in use.
+type tlsReloader struct {
+	cfg TLSConfig
+	now func() time.Time
+
+	mu        sync.Mutex
+	cert      *tls.Certificate
+	clientCAs *x509.CertPool
+	stamps    map[string]fileStamp
+	checked   time.Time
+}
+
+// fileStamp identifies a version of a file.
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// newTLSReloader loads the files named by cfg and returns a reloader for
+// them.
+func newTLSReloader(cfg TLSConfig) (*tlsReloader, error) {
+	if cfg.CertFile == "" || cfg.KeyFile == "" {
+		return nil, errors.New("TLS requires both a certificate and a key file")
+	}
+	if cfg.ClientAuth == tls.NoClientCert && cfg.ClientCAFile != "" {
+		cfg.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	r := &tlsReloader{cfg: cfg, now: time.Now}
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+	r.checked = r.now()
+	return r, nil
+}
+
+// NewTLSConfig returns a server TLS configuration for the files named by cfg
+// that reloads them when they change, for listeners other than the metrics
+// server such as the gRPC API.
+func NewTLSConfig(cfg TLSConfig) (*tls.Config, error) {
+	r, err := newTLSReloader(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return r.tlsConfig(), nil
+}
+
+// tlsConfig returns the server TLS configuration. Certificates and client
+// CAs are resolved per handshake, so rotations apply to new connections
+// without a restart.
+func (r *tlsReloader) tlsConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			cert, clientCAs := r.current()
+			cfg := &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				Certificates: []tls.Certificate{*cert},
+				NextProtos:   []string{"h2", "http/1.1"},
+			}
+			if clientCAs != nil {
+				cfg.ClientCAs = clientCAs
+				cfg.ClientAuth = r.cfg.ClientAuth
+			}
+			return cfg, nil
+		},
+	}
+}
+
+// current returns the certificate and client CAs, reloading them first if
+// the files changed since the last check.
+func (r *tlsReloader) current() (*tls.Certificate, *x509.CertPool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if now := r.now(); now.Sub(r.checked) >= tlsReloadInterval {
+		r.checked = now
+		if r.changed() {
+			if err := r.load(); err != nil {
+				slog.Error("failed to reload TLS certificate, keeping the previous one", "error", err)
+			} else {
+				slog.Info("reloaded TLS certificate", "cert_file", r.cfg.CertFile)
+			}
+		}
+	}
+	return r.cert, r.clientCAs
+}
+
+// files returns the files the reloader watches.
+func (r *tlsReloader) files() []string {
+	files := []string{r.cfg.CertFile, r.cfg.KeyFile}
+	if r.cfg.ClientCAFile != "" {
+		files = append(files, r.cfg.ClientCAFile)
+	}
+	return files
+}
+
+// changed reports whether any watched file differs from when it was last
+// loaded. Files that cannot be read count as unchanged, so a rotation caught
+// half-way is retried at the next check.
+func (r *tlsReloader) changed() bool {
+	for _, f := range r.files() {
+		info, err := os.Stat(f)
+		if err != nil {
+			continue
+		}
+		if (fileStamp{modTime: info.ModTime(), size: info.Size()}) != r.stamps[f] {
+			return true
+		}
+	}
+	return false
+}
+
+// load reads the certificate, key and client CA files. Callers other than
+// newTLSReloader must hold r.mu.
+func (r *tlsReloader) load() error {
+	stamps := make(map[string]fileStamp, 3)
+	for _, f := range r.files() {
+		info, err := os.Stat(f)
+		if err != nil {
+			return err
+		}
+		stamps[f] = fileStamp{modTime: info.ModTime(), size: info.Size()}
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.cfg.CertFile, r.cfg.KeyFile)
+	if err != nil {
+		return fmt.Errorf("load TLS certificate: %w", err)
+	}
+
+	var clientCAs *x509.CertPool
+	if r.cfg.ClientCAFile != "" {
+		pem, err := os.ReadFile(r.cfg.ClientCAFile)
+		if err != nil {
+			return fmt.Errorf("read client CA file: %w", err)
+		}
+		clientCAs = x509.NewCertPool()
+		if !clientCAs.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("client CA file %s contains no PEM certificates", r.cfg.ClientCAFile)
+		}
+	}
+
+	r.cert, r.clientCAs, r.stamps = &cert, clientCAs, stamps
+	return nil
+}
@@ -0,0 +1,250 @@
+package server
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kanzifucius/xp-tracker/pkg/store"
+)
+
+// testCA issues certificates for TLS tests.
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA(t *testing.T) *testCA {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate CA key: %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("create CA certificate: %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("parse CA certificate: %v", err)
+	}
+	return &testCA{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+// issue returns a PEM certificate and key for cn, valid for localhost.
+func (ca *testCA) issue(t *testing.T, cn string, serial int64, usage x509.ExtKeyUsage) (certPEM, keyPEM []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1"), net.IPv6loopback},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatalf("create certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("marshal key: %v", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func writeFile(t *testing.T, path string, data []byte) {
+	t.Helper()
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+}
+
+// startTLSServer runs a Server with TLS and a separate health listener.
+func startTLSServer(t *testing.T, cfg TLSConfig) *Server {
+	t.Helper()
+	srv := New("127.0.0.1:0", store.New())
+	if err := srv.SetTLS(cfg); err != nil {
+		t.Fatalf("SetTLS: %v", err)
+	}
+	srv.SetHealthAddr("127.0.0.1:0")
+	srv.SetReady()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go func() {
+		_ = srv.Run(ctx)
+	}()
+	return srv
+}
+
+// tlsGet performs a GET with the given client TLS configuration.
+func tlsGet(t *testing.T, url string, cfg *tls.Config) (*http.Response, error) {
+	t.Helper()
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: cfg}}
+	defer client.CloseIdleConnections()
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatalf("failed to create request: %v", err)
+	}
+	resp, err := client.Do(req)
+	if err == nil {
+		_ = resp.Body.Close()
+	}
+	return resp, err
+}
+
+func TestServer_TLS(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCA(t)
+	certPEM, keyPEM := ca.issue(t, "server", 2, x509.ExtKeyUsageServerAuth)
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	writeFile(t, certFile, certPEM)
+	writeFile(t, keyFile, keyPEM)
+
+	srv := startTLSServer(t, TLSConfig{CertFile: certFile, KeyFile: keyFile})
+	roots := x509.NewCertPool()
+	roots.AppendCertsFromPEM(ca.pem)
+
+	resp, err := tlsGet(t, "https://"+srv.Addr()+"/metrics", &tls.Config{RootCAs: roots, MinVersion: tls.VersionTLS12})
+	if err != nil {
+		t.Fatalf("GET /metrics over TLS: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected 200, got %d", resp.StatusCode)
+	}
+
+	// The health listener stays plain HTTP.
+	health := httpGet(t, "http://"+srv.HealthAddr()+"/readyz")
+	_ = health.Body.Close()
+	if health.StatusCode != http.StatusOK {
+		t.Errorf("expected 200 from plain HTTP /readyz, got %d", health.StatusCode)
+	}
+	metrics := httpGet(t, "http://"+srv.HealthAddr()+"/metrics")
+	_ = metrics.Body.Close()
+	if metrics.StatusCode != http.StatusNotFound {
+		t.Errorf("expected health listener to serve only probes, got %d for /metrics", metrics.StatusCode)
+	}
+}
+
+func TestServer_MutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCA(t)
+	certPEM, keyPEM := ca.issue(t, "server", 2, x509.ExtKeyUsageServerAuth)
+	clientCertPEM, clientKeyPEM := ca.issue(t, "prometheus", 3, x509.ExtKeyUsageClientAuth)
+	certFile, keyFile, caFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key"), filepath.Join(dir, "ca.crt")
+	writeFile(t, certFile, certPEM)
+	writeFile(t, keyFile, keyPEM)
+	writeFile(t, caFile, ca.pem)
+
+	srv := startTLSServer(t, TLSConfig{CertFile: certFile, KeyFile: keyFile, ClientCAFile: caFile})
+	roots := x509.NewCertPool()
+	roots.AppendCertsFromPEM(ca.pem)
+	url := "https://" + srv.Addr() + "/metrics"
+
+	if _, err := tlsGet(t, url, &tls.Config{RootCAs: roots, MinVersion: tls.VersionTLS12}); err == nil {
+		t.Error("expected handshake to fail without a client certificate")
+	}
+
+	clientCert, err := tls.X509KeyPair(clientCertPEM, clientKeyPEM)
+	if err != nil {
+		t.Fatalf("load client certificate: %v", err)
+	}
+	resp, err := tlsGet(t, url, &tls.Config{RootCAs: roots, Certificates: []tls.Certificate{clientCert}, MinVersion: tls.VersionTLS12})
+	if err != nil {
+		t.Fatalf("GET /metrics with client certificate: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected 200, got %d", resp.StatusCode)
+	}
+}
+
+func TestTLSReloader_Rotation(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCA(t)
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	certPEM, keyPEM := ca.issue(t, "server", 2, x509.ExtKeyUsageServerAuth)
+	writeFile(t, certFile, certPEM)
+	writeFile(t, keyFile, keyPEM)
+
+	r, err := newTLSReloader(TLSConfig{CertFile: certFile, KeyFile: keyFile})
+	if err != nil {
+		t.Fatalf("newTLSReloader: %v", err)
+	}
+	now := time.Now()
+	r.now = func() time.Time { return now }
+
+	serial := func() int64 {
+		cert, _ := r.current()
+		leaf, err := x509.ParseCertificate(cert.Certificate[0])
+		if err != nil {
+			t.Fatalf("parse certificate: %v", err)
+		}
+		return leaf.SerialNumber.Int64()
+	}
+
+	// Rotate the certificate; the change is picked up at the next check.
+	certPEM, keyPEM = ca.issue(t, "server", 4, x509.ExtKeyUsageServerAuth)
+	writeFile(t, certFile, certPEM)
+	writeFile(t, keyFile, keyPEM)
+	later := now.Add(time.Minute)
+	if err := os.Chtimes(certFile, later, later); err != nil {
+		t.Fatalf("chtimes: %v", err)
+	}
+	if got := serial(); got != 2 {
+		t.Errorf("expected old certificate before the reload interval, got serial %d", got)
+	}
+	now = now.Add(tlsReloadInterval)
+	if got := serial(); got != 4 {
+		t.Errorf("expected rotated certificate, got serial %d", got)
+	}
+
+	// A broken rotation keeps the previous certificate.
+	writeFile(t, keyFile, []byte("not a key"))
+	if err := os.Chtimes(keyFile, later.Add(time.Minute), later.Add(time.Minute)); err != nil {
+		t.Fatalf("chtimes: %v", err)
+	}
+	now = now.Add(tlsReloadInterval)
+	if got := serial(); got != 4 {
+		t.Errorf("expected previous certificate after failed reload, got serial %d", got)
+	}
+}
+
+func TestSetTLS_Invalid(t *testing.T) {
+	srv := New(":0", store.New())
+	if err := srv.SetTLS(TLSConfig{CertFile: "/nonexistent/tls.crt", KeyFile: "/nonexistent/tls.key"}); err == nil {
+		t.Error("expected error for missing certificate files")
+	}
+	if err := srv.SetTLS(TLSConfig{CertFile: "tls.crt"}); err == nil {
+		t.Error("expected error without a key file")
+	}
+}
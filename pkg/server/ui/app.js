@@ -0,0 +1,374 @@
+'use strict';
+
+// Resources that have not become ready this long after creation are shown
+// as stuck.
+const STUCK_AFTER_SECONDS = 600;
+const PAGE_SIZE = 1000;
+const REFRESH_MS = 30000;
+const TOKEN_KEY = 'xp-tracker-token';
+
+const columns = {
+  claims: [
+    ['name', 'Name'], ['namespace', 'Namespace'], ['kind', 'Kind'], ['team', 'Team'],
+    ['creator', 'Creator'], ['composition', 'Composition'], ['status', 'Status'], ['ageSeconds', 'Age'],
+  ],
+  xrs: [
+    ['name', 'Name'], ['namespace', 'Namespace'], ['kind', 'Kind'], ['composition', 'Composition'],
+    ['status', 'Status'], ['ageSeconds', 'Age'],
+  ],
+  mrs: [
+    ['name', 'Name'], ['namespace', 'Namespace'], ['kind', 'Kind'], ['provider', 'Provider'],
+    ['claim', 'Claim'], ['xrName', 'XR'], ['status', 'Status'], ['ageSeconds', 'Age'],
+  ],
+};
+
+const searchFields = ['name', 'namespace', 'kind', 'team', 'creator', 'composition', 'provider', 'reason', 'xrName', 'claimName'];
+
+const state = { type: 'claims', items: [], sort: 'name', desc: false };
+
+const $ = (sel) => document.querySelector(sel);
+
+// el builds an element. Text is always set through text nodes, never parsed
+// as HTML.
+function el(tag, attrs, ...children) {
+  const node = document.createElement(tag);
+  for (const [k, v] of Object.entries(attrs || {})) {
+    if (v !== undefined && v !== null && v !== false) node.setAttribute(k, v);
+  }
+  for (const c of children.flat()) {
+    if (c === null || c === undefined || c === false) continue;
+    node.append(c instanceof Node ? c : document.createTextNode(String(c)));
+  }
+  return node;
+}
+
+// apiURL resolves an API path relative to the dashboard, so that the UI also
+// works behind a reverse proxy that adds a path prefix.
+function apiURL(path) {
+  return new URL('..' + path, location.href);
+}
+
+class AuthError extends Error {}
+
+async function getJSON(path) {
+  const headers = { Accept: 'application/json' };
+  const token = sessionStorage.getItem(TOKEN_KEY);
+  if (token) headers.Authorization = 'Bearer ' + token;
+  const resp = await fetch(apiURL(path), { headers });
+  if (resp.status === 401) {
+    $('#token-form').hidden = false;
+    throw new AuthError('authentication required');
+  }
+  if (!resp.ok) {
+    const text = (await resp.text()).trim();
+    throw new Error(`${resp.status} ${resp.statusText}${text ? ': ' + text : ''}`);
+  }
+  return resp.json();
+}
+
+function showError(err) {
+  const box = $('#error');
+  if (!err || err instanceof AuthError) {
+    box.hidden = true;
+    return;
+  }
+  box.textContent = err.message;
+  box.hidden = false;
+}
+
+function isStuck(item) {
+  return !item.ready && !item.paused && item.ageSeconds > STUCK_AFTER_SECONDS;
+}
+
+function hasProblem(item) {
+  return !item.ready || item.deleting;
+}
+
+function statusBadges(item) {
+  const badges = [];
+  if (item.ready) badges.push(el('span', { class: 'badge ok' }, 'ready'));
+  else if (isStuck(item)) badges.push(el('span', { class: 'badge stuck', title: item.reason }, 'stuck'));
+  else badges.push(el('span', { class: 'badge not-ready', title: item.reason }, 'not ready'));
+  if (item.synced === false) badges.push(' ', el('span', { class: 'badge not-ready' }, 'not synced'));
+  if (item.paused) badges.push(' ', el('span', { class: 'badge paused' }, 'paused'));
+  if (item.deleting || (item.deletedAt && !item.deletedAt.startsWith('0001-'))) {
+    badges.push(' ', el('span', { class: 'badge deleting' }, 'deleting'));
+  }
+  return badges;
+}
+
+function formatAge(seconds) {
+  if (seconds < 60) return seconds + 's';
+  if (seconds < 3600) return Math.floor(seconds / 60) + 'm';
+  if (seconds < 86400) return Math.floor(seconds / 3600) + 'h';
+  return Math.floor(seconds / 86400) + 'd';
+}
+
+function ageOf(createdAt) {
+  return Math.max(0, Math.floor((Date.now() - Date.parse(createdAt)) / 1000));
+}
+
+function enc(s) {
+  return encodeURIComponent(s || '');
+}
+
+// detailLink returns the dashboard route that drills into item, preferring
+// the claim's resource tree.
+function detailLink(type, item) {
+  if (type === 'claims') return `#/claim/${enc(item.namespace)}/${enc(item.name)}`;
+  if (type === 'xrs') return `#/xr/${enc(item.namespace)}/${enc(item.name)}`;
+  if (item.claimName) return `#/claim/${enc(item.claimNamespace)}/${enc(item.claimName)}`;
+  if (item.xrName) return `#/xr/${enc(item.namespace)}/${enc(item.xrName)}`;
+  return null;
+}
+
+function cellValue(item, key) {
+  switch (key) {
+    case 'claim': return item.claimName ? `${item.claimNamespace}/${item.claimName}` : '';
+    case 'ageSeconds': return formatAge(item.ageSeconds);
+    default: return item[key] || '';
+  }
+}
+
+function sortValue(item, key) {
+  switch (key) {
+    case 'ageSeconds': return item.ageSeconds;
+    case 'status': return (item.ready ? 2 : isStuck(item) ? 0 : 1);
+    default: return String(cellValue(item, key)).toLowerCase();
+  }
+}
+
+// --- list view ---
+
+function filtersFromForm() {
+  const data = new FormData($('#filters'));
+  const params = new URLSearchParams();
+  for (const [k, v] of data) {
+    if (v) params.set(k, v === 'on' ? 'true' : v);
+  }
+  return params;
+}
+
+function fillForm(params) {
+  const form = $('#filters');
+  for (const input of form.elements) {
+    if (!input.name) continue;
+    if (input.type === 'checkbox') input.checked = params.get(input.name) === 'true';
+    else input.value = params.get(input.name) || '';
+  }
+}
+
+async function loadList(type, params) {
+  const query = new URLSearchParams({ type, limit: PAGE_SIZE });
+  for (const key of ['namespace', 'team', 'kind', 'ready']) {
+    if (params.get(key)) query.set(key, params.get(key));
+  }
+  let items = [];
+  let generation = 0;
+  for (;;) {
+    const page = await getJSON('/bookkeeping?' + query);
+    items = items.concat(page[type]);
+    generation = page.generation;
+    if (!page.next) break;
+    query.set('cursor', page.next);
+  }
+  return { items, generation };
+}
+
+function renderList(params) {
+  const cols = columns[state.type];
+  const q = (params.get('q') || '').toLowerCase();
+  const problemsOnly = params.get('problems') === 'true';
+
+  const rows = state.items.filter((item) =>
+    (!problemsOnly || hasProblem(item)) &&
+    (!q || searchFields.some((f) => String(item[f] || '').toLowerCase().includes(q))));
+
+  rows.sort((a, b) => {
+    const x = sortValue(a, state.sort);
+    const y = sortValue(b, state.sort);
+    const c = x < y ? -1 : x > y ? 1 : 0;
+    return state.desc ? -c : c;
+  });
+
+  const head = el('tr', {}, cols.map(([key, label]) => {
+    const arrow = state.sort === key ? (state.desc ? ' ▾' : ' ▴') : '';
+    const th = el('th', { 'data-key': key }, label + arrow);
+    th.addEventListener('click', () => {
+      state.desc = state.sort === key ? !state.desc : false;
+      state.sort = key;
+      renderList(filtersFromForm());
+    });
+    return th;
+  }));
+  $('#list thead').replaceChildren(head);
+
+  $('#list tbody').replaceChildren(...rows.map((item) => {
+    const link = detailLink(state.type, item);
+    const cells = cols.map(([key]) => {
+      if (key === 'status') return el('td', {}, statusBadges(item));
+      const value = cellValue(item, key);
+      if (key === 'name' && link) return el('td', {}, el('a', { href: link }, value));
+      return el('td', {}, value);
+    });
+    const cls = item.ready ? null : isStuck(item) ? 'stuck' : 'not-ready';
+    return el('tr', { class: cls }, cells);
+  }));
+
+  const notReady = state.items.filter((i) => !i.ready).length;
+  const stuck = state.items.filter(isStuck).length;
+  $('#summary').textContent =
+    `Showing ${rows.length} of ${state.items.length} ${state.type} · ${notReady} not ready · ${stuck} stuck`;
+}
+
+async function showList(type, params) {
+  $('#list-view').hidden = false;
+  $('#detail-view').hidden = true;
+  if (state.type !== type) {
+    state.type = type;
+    state.sort = 'name';
+    state.desc = false;
+  }
+  fillForm(params);
+  await reloadList(params);
+}
+
+// reloadList fetches the current list again. Search and "problems only" are
+// taken from params, so a refresh keeps what the user is typing.
+async function reloadList(params) {
+  $('#status').textContent = 'Loading…';
+  const { items, generation } = await loadList(state.type, params);
+  state.items = items;
+  $('#status').textContent = `Generation ${generation} · updated ${new Date().toLocaleTimeString()}`;
+  renderList(params);
+}
+
+// --- detail view ---
+
+function field(label, value) {
+  return value ? [el('dt', {}, label), el('dd', {}, value)] : [];
+}
+
+function node(rec, extra) {
+  rec.ageSeconds = ageOf(rec.createdAt);
+  return el('div', { class: 'node' },
+    el('h3', {}, `${rec.kind} ${rec.name} `, statusBadges(rec)),
+    el('dl', {},
+      field('Namespace', rec.namespace),
+      field('API version', rec.group + '/' + rec.version),
+      field('Age', formatAge(rec.ageSeconds)),
+      field('Reason', rec.reason),
+      extra));
+}
+
+function mrNode(mr) {
+  return node(mr, [
+    field('Provider', mr.provider),
+    field('Provider config', mr.providerConfig),
+    field('External name', mr.externalName),
+    field('Management policies', mr.managementPolicies),
+  ]);
+}
+
+async function mrTree(paths) {
+  const mrs = await Promise.all((paths || []).map((p) => getJSON(p)));
+  return el('ul', { class: 'tree' }, mrs.map((mr) => el('li', {}, mrNode(mr))));
+}
+
+async function xrTree(xr) {
+  return el('ul', { class: 'tree' }, el('li', {},
+    node(xr, [field('Composition', xr.composition)]),
+    await mrTree(xr.links.mrs)));
+}
+
+async function showClaim(namespace, name) {
+  const claim = await getJSON(`/claims/${enc(namespace)}/${enc(name)}`);
+  const children = claim.links.xr
+    ? await xrTree(await getJSON(claim.links.xr))
+    : el('p', { class: 'muted' }, 'No composite resource found for this claim.');
+  return [
+    el('h2', {}, `Claim ${claim.namespace}/${claim.name}`),
+    node(claim, [
+      field('Team', claim.team),
+      field('Creator', claim.creator),
+      field('Composition', claim.composition),
+    ]),
+    children,
+  ];
+}
+
+async function showXR(namespace, name) {
+  const path = namespace ? `/xrs/${enc(namespace)}/${enc(name)}` : `/xrs/${enc(name)}`;
+  const xr = await getJSON(path);
+  if (xr.links.claim) {
+    const [, , ns, claimName] = xr.links.claim.split('/');
+    return showClaim(decodeURIComponent(ns), decodeURIComponent(claimName));
+  }
+  return [el('h2', {}, `XR ${xr.name}`), await xrTree(xr)];
+}
+
+async function showDetail(kind, parts) {
+  $('#list-view').hidden = true;
+  $('#detail-view').hidden = false;
+  $('#detail').replaceChildren(el('p', { class: 'muted' }, 'Loading…'));
+  const [ns, name] = parts.map(decodeURIComponent);
+  const content = kind === 'claim' ? await showClaim(ns, name) : await showXR(ns, name);
+  $('#detail').replaceChildren(...content);
+}
+
+// --- routing ---
+
+function route() {
+  const [path, query] = location.hash.replace(/^#/, '').split('?');
+  const parts = path.split('/').filter((p, i) => i > 0);
+  const params = new URLSearchParams(query || '');
+  const type = columns[parts[0]] ? parts[0] : null;
+
+  for (const a of document.querySelectorAll('#tabs a')) {
+    a.classList.toggle('active', a.dataset.type === (type || state.type));
+  }
+
+  let work;
+  if (parts[0] === 'claim' || parts[0] === 'xr') {
+    work = showDetail(parts[0], parts.slice(1, 3));
+  } else {
+    work = showList(type || 'claims', params);
+  }
+  work.then(() => showError(null), showError);
+}
+
+function init() {
+  $('#filters').addEventListener('submit', (e) => {
+    e.preventDefault();
+    const params = filtersFromForm().toString();
+    const target = `#/${state.type}${params ? '?' + params : ''}`;
+    if (location.hash === target) route();
+    else location.hash = target;
+  });
+  $('#filters').addEventListener('input', (e) => {
+    // Search and "problems only" filter the loaded rows without a reload.
+    if (e.target.name === 'q' || e.target.name === 'problems') renderList(filtersFromForm());
+  });
+  $('#refresh').addEventListener('click', () => {
+    reloadList(filtersFromForm()).then(() => showError(null), showError);
+  });
+
+  $('#token-form form').addEventListener('submit', (e) => {
+    e.preventDefault();
+    sessionStorage.setItem(TOKEN_KEY, $('#token').value.trim());
+    $('#token').value = '';
+    $('#token-form').hidden = true;
+    route();
+  });
+
+  setInterval(() => {
+    if (!document.hidden && !$('#list-view').hidden) {
+      reloadList(filtersFromForm()).then(() => showError(null), showError);
+    }
+  }, REFRESH_MS);
+
+  window.addEventListener('hashchange', route);
+  route();
+}
+
+init();
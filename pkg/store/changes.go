@@ -0,0 +1,224 @@
+package store
+
+import (
+	"cmp"
+	"slices"
+	"sync"
+	"time"
+)
+
+// ChangeType says what happened to an object between two generations.
+type ChangeType string
+
+// Change types.
+const (
+	ChangeAdded   ChangeType = "added"
+	ChangeUpdated ChangeType = "updated"
+	ChangeRemoved ChangeType = "removed"
+)
+
+// Resource classes of a Change.
+const (
+	ResourceClaim = "claim"
+	ResourceXR    = "xr"
+	ResourceMR    = "mr"
+)
+
+// Change is one entry of the change feed: an object that was added, updated
+// or removed when a generation was published. Namespace and Team are
+// resolved like query fields, so XRs and MRs carry the team of their claim.
+type Change struct {
+	Seq        uint64     `json:"seq"` // increases by one with every change
+	Type       ChangeType `json:"type"`
+	Resource   string     `json:"resource"` // ResourceClaim, ResourceXR or ResourceMR
+	Generation uint64     `json:"generation"`
+	At         time.Time  `json:"at"` // commit time of the generation
+	Namespace  string     `json:"namespace"`
+	Name       string     `json:"name"`
+	Team       string     `json:"team"`
+	Object     any        `json:"object"` // ClaimInfo, XRInfo or MRInfo; the last known state for removals
+}
+
+// ChangeFeed is a bounded, in-memory log of changes. When it is full the
+// oldest changes are dropped; readers that fall behind find out from
+// Since and must resynchronise from a full listing.
+//
+// All methods are safe for concurrent use.
+type ChangeFeed struct {
+	mu      sync.Mutex
+	buf     []Change // ring buffer
+	start   int      // index of the oldest change in buf
+	size    int      // number of changes in buf
+	lastSeq uint64
+	notify  chan struct{} // closed and replaced when changes are appended
+}
+
+// NewChangeFeed returns a feed holding at most capacity changes. A feed with
+// zero capacity records nothing.
+func NewChangeFeed(capacity int) *ChangeFeed {
+	return &ChangeFeed{
+		buf:    make([]Change, capacity),
+		notify: make(chan struct{}),
+	}
+}
+
+// Enabled reports whether the feed records changes.
+func (f *ChangeFeed) Enabled() bool {
+	return f != nil && len(f.buf) > 0
+}
+
+// LastSeq returns the sequence number of the most recent change, or zero if
+// nothing has been recorded.
+func (f *ChangeFeed) LastSeq() uint64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.lastSeq
+}
+
+// Since returns the changes after seq, oldest first, and a channel that is
+// closed when further changes are appended. ok is false when changes after
+// seq have already been dropped or seq is ahead of the feed (for example
+// after a restart); the caller has missed changes and must resynchronise.
+func (f *ChangeFeed) Since(seq uint64) (changes []Change, wait <-chan struct{}, ok bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if seq > f.lastSeq {
+		return nil, f.notify, false
+	}
+	oldest := f.lastSeq - uint64(f.size) + 1
+	if seq+1 < oldest {
+		return nil, f.notify, false
+	}
+	n := int(f.lastSeq - seq)
+	changes = make([]Change, 0, n)
+	for i := f.size - n; i < f.size; i++ {
+		changes = append(changes, f.buf[(f.start+i)%len(f.buf)])
+	}
+	return changes, f.notify, true
+}
+
+// append assigns sequence numbers to changes and adds them to the feed,
+// dropping the oldest entries when it is full.
+func (f *ChangeFeed) append(changes []Change) {
+	if !f.Enabled() || len(changes) == 0 {
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, c := range changes {
+		f.lastSeq++
+		c.Seq = f.lastSeq
+		end := (f.start + f.size) % len(f.buf)
+		f.buf[end] = c
+		if f.size < len(f.buf) {
+			f.size++
+		} else {
+			f.start = (f.start + 1) % len(f.buf)
+		}
+	}
+	close(f.notify)
+	f.notify = make(chan struct{})
+}
+
+// diffGenerations returns the changes that turn prev into next. Only the
+// buckets next does not share with prev are compared.
+func diffGenerations(prev, next *generation) []Change {
+	var changes []Change
+	// sortFrom orders the changes appended since start by namespace and name,
+	// so each resource class is reported in a stable order.
+	sortFrom := func(start int) {
+		slices.SortFunc(changes[start:], func(a, b Change) int {
+			return cmp.Or(cmp.Compare(a.Namespace, b.Namespace), cmp.Compare(a.Name, b.Name))
+		})
+	}
+	add := func(t ChangeType, resource string, f fields, obj any) {
+		changes = append(changes, Change{
+			Type: t, Resource: resource, Generation: next.number, At: next.committedAt,
+			Namespace: f.namespace, Name: f.name, Team: f.team, Object: obj,
+		})
+	}
+
+	for prevBucket, nextBucket := range next.claims.changedBuckets(&prev.claims) {
+		for key, c := range nextBucket {
+			old, ok := prevBucket[key]
+			switch {
+			case !ok:
+				add(ChangeAdded, ResourceClaim, next.claimFields(c), c)
+			case !sameClaim(old, c):
+				add(ChangeUpdated, ResourceClaim, next.claimFields(c), c)
+			}
+		}
+		for key, c := range prevBucket {
+			if _, ok := nextBucket[key]; !ok {
+				add(ChangeRemoved, ResourceClaim, prev.claimFields(c), c)
+			}
+		}
+	}
+	sortFrom(0)
+
+	start := len(changes)
+	for prevBucket, nextBucket := range next.xrs.changedBuckets(&prev.xrs) {
+		for key, x := range nextBucket {
+			old, ok := prevBucket[key]
+			switch {
+			case !ok:
+				add(ChangeAdded, ResourceXR, next.xrFields(x), x)
+			case !sameXR(old, x):
+				add(ChangeUpdated, ResourceXR, next.xrFields(x), x)
+			}
+		}
+		for key, x := range prevBucket {
+			if _, ok := nextBucket[key]; !ok {
+				add(ChangeRemoved, ResourceXR, prev.xrFields(x), x)
+			}
+		}
+	}
+	sortFrom(start)
+
+	start = len(changes)
+	for prevBucket, nextBucket := range next.mrs.changedBuckets(&prev.mrs) {
+		for key, m := range nextBucket {
+			old, ok := prevBucket[key]
+			switch {
+			case !ok:
+				add(ChangeAdded, ResourceMR, next.mrFields(m), m)
+			case !sameMR(old, m):
+				add(ChangeUpdated, ResourceMR, next.mrFields(m), m)
+			}
+		}
+		for key, m := range prevBucket {
+			if _, ok := nextBucket[key]; !ok {
+				add(ChangeRemoved, ResourceMR, prev.mrFields(m), m)
+			}
+		}
+	}
+	sortFrom(start)
+
+	return changes
+}
+
+// sameClaim, sameXR and sameMR compare timestamps with time.Time.Equal so
+// that re-parsed but identical timestamps do not register as updates.
+func sameClaim(a, b ClaimInfo) bool {
+	if !a.CreatedAt.Equal(b.CreatedAt) || !a.DeletedAt.Equal(b.DeletedAt) {
+		return false
+	}
+	a.CreatedAt, a.DeletedAt, b.CreatedAt, b.DeletedAt = time.Time{}, time.Time{}, time.Time{}, time.Time{}
+	return a == b
+}
+
+func sameXR(a, b XRInfo) bool {
+	if !a.CreatedAt.Equal(b.CreatedAt) || !a.DeletedAt.Equal(b.DeletedAt) {
+		return false
+	}
+	a.CreatedAt, a.DeletedAt, b.CreatedAt, b.DeletedAt = time.Time{}, time.Time{}, time.Time{}, time.Time{}
+	return a == b
+}
+
+func sameMR(a, b MRInfo) bool {
+	if !a.CreatedAt.Equal(b.CreatedAt) || !a.DeletedAt.Equal(b.DeletedAt) {
+		return false
+	}
+	a.CreatedAt, a.DeletedAt, b.CreatedAt, b.DeletedAt = time.Time{}, time.Time{}, time.Time{}, time.Time{}
+	return a == b
+}
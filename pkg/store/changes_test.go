@@ -0,0 +1,108 @@
+package store
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func changeSummary(changes []Change) []string {
+	out := make([]string, 0, len(changes))
+	for _, c := range changes {
+		out = append(out, string(c.Type)+" "+c.Resource+" "+c.Name)
+	}
+	return out
+}
+
+func TestChanges_DisabledByDefault(t *testing.T) {
+	s := New()
+	s.ReplaceClaims("g/v1/r", []ClaimInfo{{GVR: "g/v1/r", Namespace: "ns", Name: "a"}})
+
+	if s.Changes().Enabled() {
+		t.Fatal("expected the change feed to be disabled by default")
+	}
+	if got := s.Changes().LastSeq(); got != 0 {
+		t.Errorf("expected no changes recorded, got last seq %d", got)
+	}
+}
+
+func TestChanges_AddUpdateRemove(t *testing.T) {
+	s := New()
+	s.SetChangeBufferSize(100)
+	created := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	s.BeginGeneration()
+	s.ReplaceClaims("g/v1/r", []ClaimInfo{
+		{GVR: "g/v1/r", Namespace: "ns", Name: "b", Team: "t1", XRRef: "xr-b", CreatedAt: created},
+		{GVR: "g/v1/r", Namespace: "ns", Name: "a", Team: "t1", CreatedAt: created},
+	})
+	s.ReplaceXRs("g/v1/xr", []XRInfo{{GVR: "g/v1/xr", Name: "xr-b", CreatedAt: created}})
+	s.EnrichXRClaims()
+	s.CommitGeneration()
+
+	changes, _, ok := s.Changes().Since(0)
+	if !ok {
+		t.Fatal("expected changes since 0 to be available")
+	}
+	want := []string{"added claim a", "added claim b", "added xr xr-b"}
+	if got := changeSummary(changes); !slices.Equal(got, want) {
+		t.Fatalf("changes = %v, want %v", got, want)
+	}
+	if xr := changes[2]; xr.Team != "t1" || xr.Namespace != "ns" {
+		t.Errorf("expected the XR change to carry its claim's team and namespace, got %+v", xr)
+	}
+	for i, c := range changes {
+		if c.Seq != uint64(i+1) || c.Generation != 1 {
+			t.Errorf("change %d: seq %d generation %d", i, c.Seq, c.Generation)
+		}
+	}
+
+	// Re-publishing identical objects (timestamps in another location) is
+	// not a change.
+	s.ReplaceClaims("g/v1/r", []ClaimInfo{
+		{GVR: "g/v1/r", Namespace: "ns", Name: "b", Team: "t1", XRRef: "xr-b", CreatedAt: created.In(time.FixedZone("X", 3600))},
+		{GVR: "g/v1/r", Namespace: "ns", Name: "a", Team: "t2", CreatedAt: created},
+	})
+	s.ReplaceClaims("g/v1/r", []ClaimInfo{
+		{GVR: "g/v1/r", Namespace: "ns", Name: "a", Team: "t2", CreatedAt: created},
+	})
+
+	changes, _, _ = s.Changes().Since(3)
+	want = []string{"updated claim a", "removed claim b"}
+	if got := changeSummary(changes); !slices.Equal(got, want) {
+		t.Fatalf("changes = %v, want %v", got, want)
+	}
+	if removed, ok := changes[1].Object.(ClaimInfo); !ok || removed.XRRef != "xr-b" {
+		t.Errorf("expected the removal to carry the last known claim, got %#v", changes[1].Object)
+	}
+}
+
+func TestChangeFeed_Since(t *testing.T) {
+	f := NewChangeFeed(3)
+	_, wait, _ := f.Since(0)
+	f.append([]Change{{Name: "1"}, {Name: "2"}, {Name: "3"}, {Name: "4"}})
+
+	select {
+	case <-wait:
+	default:
+		t.Error("expected the wait channel to be closed by append")
+	}
+	if f.LastSeq() != 4 {
+		t.Fatalf("expected last seq 4, got %d", f.LastSeq())
+	}
+
+	if _, _, ok := f.Since(0); ok {
+		t.Error("expected Since(0) to report dropped changes")
+	}
+	if _, _, ok := f.Since(5); ok {
+		t.Error("expected Since beyond the last seq to report a gap")
+	}
+	got, _, ok := f.Since(1)
+	if !ok || len(got) != 3 || got[0].Name != "2" || got[2].Seq != 4 {
+		t.Errorf("Since(1) = %+v, %v", got, ok)
+	}
+	got, _, ok = f.Since(4)
+	if !ok || len(got) != 0 {
+		t.Errorf("Since(4) = %+v, %v", got, ok)
+	}
+}
@@ -0,0 +1,215 @@
+package store
+
+import (
+	"hash/maphash"
+	"iter"
+	"maps"
+)
+
+// cowBuckets is the number of buckets a cowMap is split into. The first
+// write to a bucket in a generation copies that bucket, about 1/cowBuckets
+// of the map.
+const cowBuckets = 256
+
+var cowSeed = maphash.MakeSeed()
+
+// cowMap is a string-keyed map that generations share until they write to
+// it. Its entries are spread over buckets, and a generation copies a bucket
+// the first time it changes it, so staging a generation in which a few
+// objects changed does not copy the whole inventory. Buckets a generation
+// did not change are shared with its predecessor, which also lets
+// diffGenerations skip them.
+//
+// Writers must only write to a cowMap of a generation that is not yet
+// published. The zero value is an empty map.
+type cowMap[V any] struct {
+	buckets [cowBuckets]*map[string]V // nil when never written
+	owned   [cowBuckets]bool          // copied by this generation, so writable
+	n       int
+}
+
+func bucketOf(key string) int {
+	return int(maphash.String(cowSeed, key) % cowBuckets)
+}
+
+// clone returns a copy of m that shares all buckets with it.
+func (m *cowMap[V]) clone() cowMap[V] {
+	return cowMap[V]{buckets: m.buckets, n: m.n}
+}
+
+func (m *cowMap[V]) len() int {
+	return m.n
+}
+
+func (m *cowMap[V]) get(key string) (V, bool) {
+	b := m.buckets[bucketOf(key)]
+	if b == nil {
+		var zero V
+		return zero, false
+	}
+	v, ok := (*b)[key]
+	return v, ok
+}
+
+func (m *cowMap[V]) set(key string, v V) {
+	b := m.writable(bucketOf(key))
+	if _, ok := b[key]; !ok {
+		m.n++
+	}
+	b[key] = v
+}
+
+func (m *cowMap[V]) delete(key string) {
+	i := bucketOf(key)
+	if b := m.buckets[i]; b == nil {
+		return
+	} else if _, ok := (*b)[key]; !ok {
+		return
+	}
+	delete(m.writable(i), key)
+	m.n--
+}
+
+// deleteFunc deletes the entries for which del returns true, copying only
+// the buckets that hold one.
+func (m *cowMap[V]) deleteFunc(del func(string, V) bool) {
+	for i, b := range m.buckets {
+		if b == nil {
+			continue
+		}
+		for key, v := range *b {
+			if del(key, v) {
+				delete(m.writable(i), key)
+				m.n--
+			}
+		}
+	}
+}
+
+// all iterates over the entries of m in no particular order.
+func (m *cowMap[V]) all() iter.Seq2[string, V] {
+	return func(yield func(string, V) bool) {
+		for _, b := range m.buckets {
+			if b == nil {
+				continue
+			}
+			for key, v := range *b {
+				if !yield(key, v) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// changedBuckets iterates over the pairs of buckets of prev and m that are
+// not shared, so that only they need comparing.
+func (m *cowMap[V]) changedBuckets(prev *cowMap[V]) iter.Seq2[map[string]V, map[string]V] {
+	return func(yield func(map[string]V, map[string]V) bool) {
+		for i, b := range m.buckets {
+			if b == prev.buckets[i] {
+				continue
+			}
+			if !yield(deref(prev.buckets[i]), deref(b)) {
+				return
+			}
+		}
+	}
+}
+
+// writable returns bucket i, copying it first unless this generation
+// already has.
+func (m *cowMap[V]) writable(i int) map[string]V {
+	if !m.owned[i] {
+		b := maps.Clone(deref(m.buckets[i]))
+		if b == nil {
+			b = make(map[string]V)
+		}
+		m.buckets[i] = &b
+		m.owned[i] = true
+	}
+	return *m.buckets[i]
+}
+
+func deref[V any](b *map[string]V) map[string]V {
+	if b == nil {
+		return nil
+	}
+	return *b
+}
+
+// keySet is a secondary index from a lookup value to the primary map keys
+// of the objects carrying that value. Like a cowMap, it is shared between
+// generations, and the keys of a value are copied on their first change.
+type keySet struct {
+	sets  cowMap[map[string]struct{}]
+	owned map[string]bool // values whose keys this generation copied
+}
+
+func (ks *keySet) clone() keySet {
+	return keySet{sets: ks.sets.clone()}
+}
+
+func (ks *keySet) len() int {
+	return ks.sets.len()
+}
+
+// keys returns the keys of the objects carrying value. The result must not
+// be modified.
+func (ks *keySet) keys(value string) map[string]struct{} {
+	keys, _ := ks.sets.get(value)
+	return keys
+}
+
+// values iterates over the values with at least one key.
+func (ks *keySet) values() iter.Seq[string] {
+	return func(yield func(string) bool) {
+		for value := range ks.sets.all() {
+			if !yield(value) {
+				return
+			}
+		}
+	}
+}
+
+func (ks *keySet) add(value, key string) {
+	if value == "" {
+		return
+	}
+	keys := ks.keys(value)
+	if _, ok := keys[key]; ok {
+		return
+	}
+	ks.writable(value, keys)[key] = struct{}{}
+}
+
+func (ks *keySet) remove(value, key string) {
+	keys := ks.keys(value)
+	if _, ok := keys[key]; !ok {
+		return
+	}
+	if len(keys) == 1 {
+		ks.sets.delete(value)
+		delete(ks.owned, value)
+		return
+	}
+	delete(ks.writable(value, keys), key)
+}
+
+// writable returns the keys of value, copying them first unless this
+// generation already has.
+func (ks *keySet) writable(value string, keys map[string]struct{}) map[string]struct{} {
+	if ks.owned[value] {
+		return keys
+	}
+	keys = maps.Clone(keys)
+	if keys == nil {
+		keys = make(map[string]struct{}, 1)
+	}
+	ks.sets.set(value, keys)
+	if ks.owned == nil {
+		ks.owned = make(map[string]bool)
+	}
+	ks.owned[value] = true
+	return keys
+}
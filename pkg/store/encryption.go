@@ -0,0 +1,280 @@
+package store
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// envelopeFormat identifies an encrypted snapshot envelope. Plaintext
+// snapshots never carry this field, which lets Restore tell the two apart.
+const envelopeFormat = "xp-tracker.envelope.v1"
+
+// keySize is the required key-encryption-key length (AES-256).
+const keySize = 32
+
+// Keyring holds the key-encryption keys (KEKs) used for envelope encryption
+// of persisted snapshots.
+//
+// Each snapshot is encrypted with a fresh random data key (DEK) using
+// AES-256-GCM; the DEK is then wrapped with the active KEK and stored in the
+// envelope alongside the KEK's ID. Older keys stay in the keyring so snapshots
+// written before a rotation can still be opened.
+type Keyring struct {
+	keys   map[string][]byte // key ID → 32-byte KEK
+	active string
+}
+
+// encryptedSnapshot is the on-the-wire envelope for an encrypted snapshot.
+// Byte slices are base64-encoded by encoding/json.
+type encryptedSnapshot struct {
+	Format     string `json:"format"`
+	KeyID      string `json:"keyId"`
+	WrappedKey []byte `json:"wrappedKey"` // DEK sealed with the KEK, nonce-prefixed
+	Ciphertext []byte `json:"ciphertext"` // snapshot JSON sealed with the DEK, nonce-prefixed
+}
+
+// NewKeyring creates a Keyring from a map of key ID to 32-byte key.
+// activeID selects the key used to encrypt new snapshots.
+func NewKeyring(keys map[string][]byte, activeID string) (*Keyring, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("keyring contains no keys")
+	}
+	k := &Keyring{keys: make(map[string][]byte, len(keys)), active: activeID}
+	for id, key := range keys {
+		if len(key) != keySize {
+			return nil, fmt.Errorf("key %q must be %d bytes, got %d", id, keySize, len(key))
+		}
+		k.keys[id] = append([]byte(nil), key...)
+	}
+	if _, ok := k.keys[activeID]; !ok {
+		return nil, fmt.Errorf("active key %q not found in keyring", activeID)
+	}
+	return k, nil
+}
+
+// LoadKeyring reads encryption keys from path.
+//
+// If path is a regular file it holds a single key whose ID is the file name.
+// If path is a directory (e.g. a mounted Kubernetes Secret), every regular
+// file in it is a key whose ID is the file name; hidden entries such as the
+// kubelet's "..data" symlink are skipped. Each file contains either 32 raw
+// bytes or the standard base64 encoding of 32 bytes.
+//
+// activeID selects the key used for new snapshots. It may be empty when
+// exactly one key is present.
+func LoadKeyring(path, activeID string) (*Keyring, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string][]byte)
+	if !info.IsDir() {
+		key, err := readKeyFile(path)
+		if err != nil {
+			return nil, err
+		}
+		keys[filepath.Base(path)] = key
+	} else {
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range entries {
+			if strings.HasPrefix(e.Name(), ".") {
+				continue
+			}
+			full := filepath.Join(path, e.Name())
+			// Stat follows the symlinks kubelet uses for Secret volumes.
+			fi, err := os.Stat(full)
+			if err != nil || !fi.Mode().IsRegular() {
+				continue
+			}
+			key, err := readKeyFile(full)
+			if err != nil {
+				return nil, err
+			}
+			keys[e.Name()] = key
+		}
+	}
+
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no encryption keys found in %s", path)
+	}
+	if activeID == "" {
+		if len(keys) > 1 {
+			return nil, fmt.Errorf("an active key ID is required when %s contains %d keys", path, len(keys))
+		}
+		for id := range keys {
+			activeID = id
+		}
+	}
+	return NewKeyring(keys, activeID)
+}
+
+// ActiveKeyID returns the ID of the key used to encrypt new snapshots.
+func (k *Keyring) ActiveKeyID() string { return k.active }
+
+// KeyIDs returns the sorted IDs of all keys in the keyring.
+func (k *Keyring) KeyIDs() []string {
+	ids := make([]string, 0, len(k.keys))
+	for id := range k.keys {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Seal encrypts plaintext into a JSON envelope using a fresh data key wrapped
+// with the active key.
+func (k *Keyring) Seal(plaintext []byte) ([]byte, error) {
+	dek := make([]byte, keySize)
+	if _, err := rand.Read(dek); err != nil {
+		return nil, fmt.Errorf("generate data key: %w", err)
+	}
+
+	aad := []byte(envelopeFormat + "/" + k.active)
+	wrapped, err := gcmSeal(k.keys[k.active], dek, aad)
+	if err != nil {
+		return nil, fmt.Errorf("wrap data key: %w", err)
+	}
+	ciphertext, err := gcmSeal(dek, plaintext, aad)
+	if err != nil {
+		return nil, fmt.Errorf("encrypt snapshot: %w", err)
+	}
+
+	return json.Marshal(encryptedSnapshot{
+		Format:     envelopeFormat,
+		KeyID:      k.active,
+		WrappedKey: wrapped,
+		Ciphertext: ciphertext,
+	})
+}
+
+// Open decrypts an envelope produced by Seal with any key in the keyring.
+func (k *Keyring) Open(data []byte) ([]byte, error) {
+	var env encryptedSnapshot
+	if err := json.Unmarshal(data, &env); err != nil {
+		return nil, err
+	}
+	if env.Format != envelopeFormat {
+		return nil, fmt.Errorf("unsupported envelope format %q", env.Format)
+	}
+	kek, ok := k.keys[env.KeyID]
+	if !ok {
+		return nil, fmt.Errorf("snapshot encrypted with unknown key %q", env.KeyID)
+	}
+
+	aad := []byte(envelopeFormat + "/" + env.KeyID)
+	dek, err := gcmOpen(kek, env.WrappedKey, aad)
+	if err != nil {
+		return nil, fmt.Errorf("unwrap data key with key %q: %w", env.KeyID, err)
+	}
+	plaintext, err := gcmOpen(dek, env.Ciphertext, aad)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt snapshot: %w", err)
+	}
+	return plaintext, nil
+}
+
+// encodeSnapshot marshals snap to JSON and, when keyring is non-nil, seals it
+// in an encryption envelope. Persistent backends should use this (and
+// decodeSnapshot) so every backend shares one on-disk format.
+func encodeSnapshot(snap Snapshot, keyring *Keyring) ([]byte, error) {
+	data, err := json.Marshal(snap)
+	if err != nil {
+		return nil, err
+	}
+	if keyring == nil {
+		return data, nil
+	}
+	return keyring.Seal(data)
+}
+
+// decodeSnapshot reverses encodeSnapshot. Plaintext snapshots are accepted
+// even when a keyring is configured so that enabling encryption does not
+// strand the existing snapshot; encrypted snapshots require a keyring.
+func decodeSnapshot(data []byte, keyring *Keyring) (Snapshot, error) {
+	var probe struct {
+		Format string `json:"format"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return Snapshot{}, err
+	}
+
+	if probe.Format == envelopeFormat {
+		if keyring == nil {
+			return Snapshot{}, errors.New("snapshot is encrypted but no encryption key is configured")
+		}
+		plaintext, err := keyring.Open(data)
+		if err != nil {
+			return Snapshot{}, err
+		}
+		data = plaintext
+	}
+
+	var snap Snapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return Snapshot{}, err
+	}
+	return snap, nil
+}
+
+// readKeyFile reads a single key file containing raw or base64-encoded bytes.
+func readKeyFile(path string) ([]byte, error) {
+	raw, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+	if len(raw) == keySize {
+		return raw, nil
+	}
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(raw)))
+	if err != nil || len(decoded) != keySize {
+		return nil, fmt.Errorf("key file %s must contain %d raw bytes or their base64 encoding", path, keySize)
+	}
+	return decoded, nil
+}
+
+// gcmSeal encrypts plaintext with AES-GCM and prefixes the random nonce.
+func gcmSeal(key, plaintext, aad []byte) ([]byte, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, aad), nil
+}
+
+// gcmOpen decrypts nonce-prefixed AES-GCM ciphertext.
+func gcmOpen(key, data, aad []byte) ([]byte, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < aead.NonceSize() {
+		return nil, errors.New("ciphertext too short")
+	}
+	nonce, ciphertext := data[:aead.NonceSize()], data[aead.NonceSize():]
+	return aead.Open(nil, nonce, ciphertext, aad)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,227 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func testKey(b byte) []byte {
+	return bytes.Repeat([]byte{b}, keySize)
+}
+
+func TestKeyring_SealOpen(t *testing.T) {
+	k, err := NewKeyring(map[string][]byte{"k1": testKey(1)}, "k1")
+	if err != nil {
+		t.Fatalf("NewKeyring: %v", err)
+	}
+
+	plaintext := []byte(`{"claims":[{"creator":"alice@example.com"}]}`)
+	sealed, err := k.Seal(plaintext)
+	if err != nil {
+		t.Fatalf("Seal: %v", err)
+	}
+	if bytes.Contains(sealed, []byte("alice@example.com")) {
+		t.Fatal("sealed envelope contains plaintext")
+	}
+
+	opened, err := k.Open(sealed)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if !bytes.Equal(opened, plaintext) {
+		t.Errorf("round trip mismatch: got %s", opened)
+	}
+}
+
+func TestKeyring_Rotation(t *testing.T) {
+	old, err := NewKeyring(map[string][]byte{"old": testKey(1)}, "old")
+	if err != nil {
+		t.Fatalf("NewKeyring: %v", err)
+	}
+	sealed, err := old.Seal([]byte("secret"))
+	if err != nil {
+		t.Fatalf("Seal: %v", err)
+	}
+
+	// After rotation the new key is active but the old one can still open.
+	rotated, err := NewKeyring(map[string][]byte{"old": testKey(1), "new": testKey(2)}, "new")
+	if err != nil {
+		t.Fatalf("NewKeyring: %v", err)
+	}
+	opened, err := rotated.Open(sealed)
+	if err != nil {
+		t.Fatalf("Open with rotated keyring: %v", err)
+	}
+	if string(opened) != "secret" {
+		t.Errorf("unexpected plaintext %q", opened)
+	}
+
+	// Once the old key is retired, its snapshots can no longer be opened.
+	retired, err := NewKeyring(map[string][]byte{"new": testKey(2)}, "new")
+	if err != nil {
+		t.Fatalf("NewKeyring: %v", err)
+	}
+	if _, err := retired.Open(sealed); err == nil {
+		t.Error("expected error opening snapshot sealed with a retired key")
+	}
+}
+
+func TestKeyring_WrongKeyMaterial(t *testing.T) {
+	a, _ := NewKeyring(map[string][]byte{"k": testKey(1)}, "k")
+	b, _ := NewKeyring(map[string][]byte{"k": testKey(2)}, "k")
+
+	sealed, err := a.Seal([]byte("secret"))
+	if err != nil {
+		t.Fatalf("Seal: %v", err)
+	}
+	if _, err := b.Open(sealed); err == nil {
+		t.Error("expected authentication failure with different key material under the same ID")
+	}
+}
+
+func TestNewKeyring_Invalid(t *testing.T) {
+	if _, err := NewKeyring(nil, "k"); err == nil {
+		t.Error("expected error for empty keyring")
+	}
+	if _, err := NewKeyring(map[string][]byte{"k": []byte("short")}, "k"); err == nil {
+		t.Error("expected error for short key")
+	}
+	if _, err := NewKeyring(map[string][]byte{"k": testKey(1)}, "missing"); err == nil {
+		t.Error("expected error for unknown active key")
+	}
+}
+
+func TestLoadKeyring_File(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "primary")
+	if err := os.WriteFile(path, []byte(base64.StdEncoding.EncodeToString(testKey(7))+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	k, err := LoadKeyring(path, "")
+	if err != nil {
+		t.Fatalf("LoadKeyring: %v", err)
+	}
+	if k.ActiveKeyID() != "primary" {
+		t.Errorf("expected active key 'primary', got %q", k.ActiveKeyID())
+	}
+}
+
+func TestLoadKeyring_SecretDirectory(t *testing.T) {
+	// Mimic the kubelet layout: key files are symlinks into a hidden
+	// timestamped directory, alongside a "..data" symlink.
+	dir := t.TempDir()
+	data := filepath.Join(dir, "..2026_01_01")
+	if err := os.Mkdir(data, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(data, "2025-12"), testKey(1), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(data, "2026-01"), []byte(base64.StdEncoding.EncodeToString(testKey(2))), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(data, filepath.Join(dir, "..data")); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"2025-12", "2026-01"} {
+		if err := os.Symlink(filepath.Join("..data", name), filepath.Join(dir, name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	k, err := LoadKeyring(dir, "2026-01")
+	if err != nil {
+		t.Fatalf("LoadKeyring: %v", err)
+	}
+	if got := strings.Join(k.KeyIDs(), ","); got != "2025-12,2026-01" {
+		t.Errorf("unexpected key IDs: %s", got)
+	}
+
+	if _, err := LoadKeyring(dir, ""); err == nil {
+		t.Error("expected error when multiple keys are present without an active key ID")
+	}
+}
+
+func TestLoadKeyring_InvalidKeyFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bad")
+	if err := os.WriteFile(path, []byte("not-a-key"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadKeyring(path, ""); err == nil {
+		t.Error("expected error for invalid key file")
+	}
+}
+
+func TestS3Store_EncryptedPersistAndRestore(t *testing.T) {
+	keyring, _ := NewKeyring(map[string][]byte{"k1": testKey(1)}, "k1")
+	mock := newMockS3Client()
+
+	ss := NewS3Store(New(), mock, "b", "p")
+	ss.SetKeyring(keyring)
+	ss.ReplaceClaims("g/v1/r", []ClaimInfo{
+		{GVR: "g/v1/r", Group: "g", Kind: "K", Namespace: "ns", Name: "db", Creator: "alice@example.com", Team: "payments"},
+	})
+	ss.ReplaceMRs("aws/v1/buckets", []MRInfo{
+		{GVR: "aws/v1/buckets", Group: "aws", Kind: "Bucket", Name: "b1", XRName: "xr", ExternalName: "prod-billing-bucket"},
+	})
+
+	ctx := context.Background()
+	if err := ss.Persist(ctx); err != nil {
+		t.Fatalf("Persist: %v", err)
+	}
+
+	stored := mock.objects["p/snapshot.json"]
+	for _, sensitive := range []string{"alice@example.com", "payments", "prod-billing-bucket"} {
+		if bytes.Contains(stored, []byte(sensitive)) {
+			t.Errorf("persisted object contains plaintext %q", sensitive)
+		}
+	}
+
+	// Without a keyring the encrypted snapshot must not be restorable.
+	if err := NewS3Store(New(), mock, "b", "p").Restore(ctx); err == nil {
+		t.Error("expected Restore without keyring to fail on encrypted snapshot")
+	}
+
+	ss2 := NewS3Store(New(), mock, "b", "p")
+	ss2.SetKeyring(keyring)
+	if err := ss2.Restore(ctx); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	claims := ss2.View().Claims()
+	if len(claims) != 1 || claims[0].Creator != "alice@example.com" {
+		t.Errorf("unexpected restored claims: %+v", claims)
+	}
+	if ss2.View().MRCount() != 1 {
+		t.Errorf("expected 1 MR after restore, got %d", ss2.View().MRCount())
+	}
+}
+
+func TestS3Store_RestorePlaintextWithKeyring(t *testing.T) {
+	mock := newMockS3Client()
+
+	// Snapshot written before encryption was enabled.
+	plain := NewS3Store(New(), mock, "b", "p")
+	plain.ReplaceClaims("g/v1/r", []ClaimInfo{
+		{GVR: "g/v1/r", Group: "g", Kind: "K", Namespace: "ns", Name: "db"},
+	})
+	ctx := context.Background()
+	if err := plain.Persist(ctx); err != nil {
+		t.Fatalf("Persist: %v", err)
+	}
+
+	keyring, _ := NewKeyring(map[string][]byte{"k1": testKey(1)}, "k1")
+	ss := NewS3Store(New(), mock, "b", "p")
+	ss.SetKeyring(keyring)
+	if err := ss.Restore(ctx); err != nil {
+		t.Fatalf("Restore of plaintext snapshot with keyring: %v", err)
+	}
+	if ss.View().ClaimCount() != 1 {
+		t.Errorf("expected 1 claim, got %d", ss.View().ClaimCount())
+	}
+}
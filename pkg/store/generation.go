@@ -0,0 +1,367 @@
+package store
+
+import (
+	"time"
+)
+
+// generation is one complete, internally consistent view of the store.
+// Once published by MemoryStore it is never mutated; writers work on a
+// clone and swap it in. A clone shares its maps with the original and
+// copies only the parts it changes (see cowMap), so writers avoid storing
+// values equal to the stored ones.
+//
+// Besides the primary maps, a generation carries secondary indexes that are
+// updated incrementally by the replace methods so that enrichment runs in
+// time linear in the number of stored objects and queries can skip objects
+// of other GVRs or namespaces.
+type generation struct {
+	number      uint64
+	committedAt time.Time
+
+	claims cowMap[ClaimInfo] // keyed by "namespace/name"
+	xrs    cowMap[XRInfo]    // keyed by "namespace/name" (or just "name" for cluster-scoped)
+	mrs    cowMap[MRInfo]    // keyed by "namespace/name"
+
+	claimsByXRRef     keySet // XRRef → claim keys
+	claimsByGVR       keySet // GVR → claim keys
+	claimsByNamespace keySet // namespace → claim keys
+	xrsByGVR          keySet // GVR → XR keys
+	mrsByXR           keySet // XRName → MR keys
+	mrsByGVR          keySet // GVR → MR keys
+
+	// Tombstones of removed objects, keyed like the primary maps.
+	deletedClaims cowMap[DeletedClaim]
+	deletedXRs    cowMap[DeletedXR]
+	deletedMRs    cowMap[DeletedMR]
+}
+
+func newGeneration() *generation {
+	return &generation{}
+}
+
+// clone returns a copy of g that can be mutated independently.
+func (g *generation) clone() *generation {
+	return &generation{
+		number:            g.number,
+		committedAt:       g.committedAt,
+		claims:            g.claims.clone(),
+		xrs:               g.xrs.clone(),
+		mrs:               g.mrs.clone(),
+		claimsByXRRef:     g.claimsByXRRef.clone(),
+		claimsByGVR:       g.claimsByGVR.clone(),
+		claimsByNamespace: g.claimsByNamespace.clone(),
+		xrsByGVR:          g.xrsByGVR.clone(),
+		mrsByXR:           g.mrsByXR.clone(),
+		mrsByGVR:          g.mrsByGVR.clone(),
+		deletedClaims:     g.deletedClaims.clone(),
+		deletedXRs:        g.deletedXRs.clone(),
+		deletedMRs:        g.deletedMRs.clone(),
+	}
+}
+
+func (g *generation) info() GenerationInfo {
+	return GenerationInfo{Number: g.number, CommittedAt: g.committedAt}
+}
+
+func (g *generation) replaceClaims(gvr string, items []ClaimInfo, scope replaceScope) {
+	newKeys := make(map[string]struct{}, len(items))
+
+	// Add/update incoming items.
+	for _, c := range items {
+		key := objectKey(c.Namespace, c.Name)
+		newKeys[key] = struct{}{}
+		g.deletedClaims.delete(key)
+		old, ok := g.claims.get(key)
+		if ok && sameClaim(old, c) {
+			continue
+		}
+		if ok {
+			g.unindexClaim(key, old)
+		}
+		g.claims.set(key, c)
+		g.claimsByXRRef.add(c.XRRef, key)
+		g.claimsByGVR.add(c.GVR, key)
+		g.claimsByNamespace.add(c.Namespace, key)
+	}
+
+	// Remove stale entries belonging to this GVR, keeping a tombstone when
+	// they were deleted.
+	removedAt := time.Now().UTC()
+	for key := range g.claimsByGVR.keys(gvr) {
+		if _, ok := newKeys[key]; ok {
+			continue
+		}
+		old, _ := g.claims.get(key)
+		if !scope.covers(old.Namespace) {
+			continue
+		}
+		g.unindexClaim(key, old)
+		g.claims.delete(key)
+		if scope.deleted {
+			g.deletedClaims.set(key, DeletedClaim{ClaimInfo: old, RemovedAt: removedAt})
+		}
+	}
+}
+
+func (g *generation) unindexClaim(key string, c ClaimInfo) {
+	g.claimsByXRRef.remove(c.XRRef, key)
+	g.claimsByGVR.remove(c.GVR, key)
+	g.claimsByNamespace.remove(c.Namespace, key)
+}
+
+func (g *generation) replaceXRs(gvr string, items []XRInfo, scope replaceScope) {
+	newKeys := make(map[string]struct{}, len(items))
+
+	for _, x := range items {
+		key := objectKey(x.Namespace, x.Name)
+		newKeys[key] = struct{}{}
+		g.deletedXRs.delete(key)
+		old, ok := g.xrs.get(key)
+		if ok && sameXR(old, x) {
+			continue
+		}
+		if ok {
+			g.xrsByGVR.remove(old.GVR, key)
+		}
+		g.xrs.set(key, x)
+		g.xrsByGVR.add(x.GVR, key)
+	}
+
+	removedAt := time.Now().UTC()
+	for key := range g.xrsByGVR.keys(gvr) {
+		if _, ok := newKeys[key]; ok {
+			continue
+		}
+		old, _ := g.xrs.get(key)
+		if !scope.covers(old.Namespace) {
+			continue
+		}
+		if scope.deleted {
+			g.deletedXRs.set(key, DeletedXR{XRInfo: old, RemovedAt: removedAt})
+		}
+		g.xrs.delete(key)
+		g.xrsByGVR.remove(gvr, key)
+	}
+}
+
+func (g *generation) replaceMRs(gvr string, items []MRInfo, scope replaceScope) {
+	newKeys := make(map[string]struct{}, len(items))
+
+	for _, m := range items {
+		key := objectKey(m.Namespace, m.Name)
+		newKeys[key] = struct{}{}
+		g.deletedMRs.delete(key)
+		old, ok := g.mrs.get(key)
+		if ok && sameMR(old, m) {
+			continue
+		}
+		if ok {
+			g.mrsByXR.remove(old.XRName, key)
+			g.mrsByGVR.remove(old.GVR, key)
+		}
+		g.mrs.set(key, m)
+		g.mrsByXR.add(m.XRName, key)
+		g.mrsByGVR.add(m.GVR, key)
+	}
+
+	removedAt := time.Now().UTC()
+	for key := range g.mrsByGVR.keys(gvr) {
+		if _, ok := newKeys[key]; ok {
+			continue
+		}
+		old, _ := g.mrs.get(key)
+		if !scope.covers(old.Namespace) {
+			continue
+		}
+		g.mrsByXR.remove(old.XRName, key)
+		g.mrsByGVR.remove(gvr, key)
+		g.mrs.delete(key)
+		if scope.deleted {
+			g.deletedMRs.set(key, DeletedMR{MRInfo: old, RemovedAt: removedAt})
+		}
+	}
+}
+
+// restoreTombstones adds the tombstones of snap for objects that are not
+// live. Tombstones already stored are left as they are.
+func (g *generation) restoreTombstones(snap Snapshot) {
+	for _, d := range snap.DeletedClaims {
+		key := objectKey(d.Namespace, d.Name)
+		if _, live := g.claims.get(key); live {
+			continue
+		}
+		if old, ok := g.deletedClaims.get(key); !ok || !old.RemovedAt.Equal(d.RemovedAt) {
+			g.deletedClaims.set(key, d)
+		}
+	}
+	for _, d := range snap.DeletedXRs {
+		key := objectKey(d.Namespace, d.Name)
+		if _, live := g.xrs.get(key); live {
+			continue
+		}
+		if old, ok := g.deletedXRs.get(key); !ok || !old.RemovedAt.Equal(d.RemovedAt) {
+			g.deletedXRs.set(key, d)
+		}
+	}
+	for _, d := range snap.DeletedMRs {
+		key := objectKey(d.Namespace, d.Name)
+		if _, live := g.mrs.get(key); live {
+			continue
+		}
+		if old, ok := g.deletedMRs.get(key); !ok || !old.RemovedAt.Equal(d.RemovedAt) {
+			g.deletedMRs.set(key, d)
+		}
+	}
+}
+
+// mergeShard replaces the objects of gvrs with those in snap, a peer's
+// shard, and adds its tombstones. Objects missing from snap get no
+// tombstone here: the peer recorded the ones that were deleted.
+func (g *generation) mergeShard(snap Snapshot, gvrs []string) {
+	snap = snap.forGVRs(gvrs)
+	claims := groupByGVR(snap.Claims, func(c ClaimInfo) string { return c.GVR })
+	xrs := groupByGVR(snap.XRs, func(x XRInfo) string { return x.GVR })
+	mrs := groupByGVR(snap.MRs, func(m MRInfo) string { return m.GVR })
+	for _, gvr := range gvrs {
+		g.replaceClaims(gvr, claims[gvr], mirrored)
+		g.replaceXRs(gvr, xrs[gvr], mirrored)
+		g.replaceMRs(gvr, mrs[gvr], mirrored)
+	}
+	g.restoreTombstones(snap)
+}
+
+// replaceScope says which stored objects of a GVR a replace covers, and
+// whether those it covers that are missing from the new items were deleted
+// from the cluster, rather than having left the polled scope.
+type replaceScope struct {
+	namespace func(string) bool // covers objects in these namespaces; nil covers all
+	deleted   bool              // keep tombstones of removed objects
+}
+
+// listed is the scope of a complete list across all namespaces.
+var listed = replaceScope{deleted: true}
+
+// mirrored is the scope of objects copied from another store.
+var mirrored = replaceScope{}
+
+// listedIn is the scope of a complete list of namespaces.
+func listedIn(namespaces []string) replaceScope {
+	set := namespaceSet(namespaces)
+	return replaceScope{namespace: func(ns string) bool { return set[ns] }, deleted: true}
+}
+
+// outside is the scope of a purge of the objects outside namespaces.
+func outside(namespaces []string) replaceScope {
+	set := namespaceSet(namespaces)
+	return replaceScope{namespace: func(ns string) bool { return !set[ns] }}
+}
+
+func namespaceSet(namespaces []string) map[string]bool {
+	set := make(map[string]bool, len(namespaces))
+	for _, ns := range namespaces {
+		set[ns] = true
+	}
+	return set
+}
+
+func (r replaceScope) covers(namespace string) bool {
+	return r.namespace == nil || r.namespace(namespace)
+}
+
+// groupByGVR groups items by the GVR string gvr returns for them.
+func groupByGVR[T any](items []T, gvr func(T) string) map[string][]T {
+	out := make(map[string][]T)
+	for _, item := range items {
+		out[gvr(item)] = append(out[gvr(item)], item)
+	}
+	return out
+}
+
+// pruneTombstones drops tombstones removed at or before cutoff.
+func (g *generation) pruneTombstones(cutoff time.Time) {
+	g.deletedClaims.deleteFunc(func(_ string, d DeletedClaim) bool { return !d.RemovedAt.After(cutoff) })
+	g.deletedXRs.deleteFunc(func(_ string, d DeletedXR) bool { return !d.RemovedAt.After(cutoff) })
+	g.deletedMRs.deleteFunc(func(_ string, d DeletedMR) bool { return !d.RemovedAt.After(cutoff) })
+}
+
+// The enrich methods only store the objects whose linkage changed, so that
+// unchanged parts of the generation stay shared.
+
+func (g *generation) enrichXRClaims() {
+	for key, xr := range g.xrs.all() {
+		if xr.ClaimName != "" {
+			continue
+		}
+		claimKey, ok := firstKey(g.claimsByXRRef.keys(xr.Name))
+		if !ok {
+			continue
+		}
+		claim, _ := g.claims.get(claimKey)
+		xr.ClaimName = claim.Name
+		xr.ClaimNS = claim.Namespace
+		g.xrs.set(key, xr)
+	}
+}
+
+func (g *generation) enrichClaimCompositions() {
+	for key, claim := range g.claims.all() {
+		if claim.XRRef == "" {
+			continue
+		}
+		// XRs are cluster-scoped, so look up by name only.
+		if xr, ok := g.xrs.get(claim.XRRef); ok && claim.Composition != xr.Composition {
+			claim.Composition = xr.Composition
+			g.claims.set(key, claim)
+		}
+	}
+}
+
+func (g *generation) enrichMRClaims() {
+	for key, mr := range g.mrs.all() {
+		if mr.ClaimName != "" {
+			continue
+		}
+		if mr.XRName == "" {
+			continue
+		}
+		if xr, ok := g.lookupXR(mr.Namespace, mr.XRName); ok && (xr.ClaimName != "" || xr.ClaimNS != mr.ClaimNS) {
+			mr.ClaimName = xr.ClaimName
+			mr.ClaimNS = xr.ClaimNS
+			g.mrs.set(key, mr)
+		}
+	}
+}
+
+// lookupXR finds an XR by name, preferring a namespaced XR in the given
+// namespace over a cluster-scoped one.
+func (g *generation) lookupXR(namespace, name string) (XRInfo, bool) {
+	if namespace != "" {
+		if xr, ok := g.xrs.get(objectKey(namespace, name)); ok {
+			return xr, true
+		}
+	}
+	return g.xrs.get(name)
+}
+
+// firstKey returns the lexically smallest key in keys.
+func firstKey(keys map[string]struct{}) (string, bool) {
+	var first string
+	found := false
+	for k := range keys {
+		if !found || k < first {
+			first = k
+			found = true
+		}
+	}
+	return first, found
+}
+
+// objectKey produces a map key from a namespace and name.
+// For cluster-scoped resources (empty namespace) the key is just the name.
+// For namespaced resources the key is "namespace/name".
+func objectKey(namespace, name string) string {
+	if namespace == "" {
+		return name
+	}
+	return namespace + "/" + name
+}
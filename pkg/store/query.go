@@ -0,0 +1,665 @@
+package store
+
+import (
+	"cmp"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+)
+
+// SortField names a field that query results can be ordered by.
+type SortField string
+
+// Supported sort fields. Fields that do not apply to a resource class (e.g.
+// provider for claims) sort as empty strings, leaving the "namespace/name"
+// tiebreaker to decide the order.
+const (
+	SortByName        SortField = "name"
+	SortByNamespace   SortField = "namespace"
+	SortByKind        SortField = "kind"
+	SortByCreator     SortField = "creator"
+	SortByTeam        SortField = "team"
+	SortByComposition SortField = "composition"
+	SortByProvider    SortField = "provider"
+	SortByCreatedAt   SortField = "createdAt"
+)
+
+// ErrInvalidQuery is returned (wrapped) for malformed queries such as an
+// unknown sort field or a cursor that cannot be decoded.
+var ErrInvalidQuery = errors.New("invalid query")
+
+// Query selects, orders and paginates stored objects.
+//
+// Empty string and nil fields do not filter. Fields are resolved through
+// claim linkage where an object does not carry them directly: an XR or MR
+// takes its team, creator and (when it is cluster-scoped) namespace from its
+// claim; an MR takes its composition from its XR; claims and XRs match a
+// provider when any of their MRs is from that provider.
+type Query struct {
+	GVR         string
+	Namespace   string
+	Kind        string
+	Team        string
+	Creator     string
+	Composition string
+	Provider    string
+	Ready       *bool
+	Synced      *bool
+	Paused      *bool
+	Deleting    *bool
+
+	// Scope restricts results to one tenant's objects; nil does not restrict.
+	Scope *Scope
+
+	// SortBy orders results; the default is by "namespace/name".
+	SortBy     SortField
+	Descending bool
+
+	// Limit caps the number of items returned; zero or negative means no limit.
+	Limit int
+	// Cursor resumes after the last item of a previous page (Page.Next).
+	Cursor string
+}
+
+// Scope limits a query to the objects a tenant may see: those whose
+// namespace is in Namespaces or whose team is in Teams. Namespace and team
+// are resolved through claim linkage like the Query filters. An empty Scope
+// matches nothing.
+type Scope struct {
+	Namespaces []string
+	Teams      []string
+}
+
+func (s *Scope) allows(f fields) bool {
+	return slices.Contains(s.Namespaces, f.namespace) || (f.team != "" && slices.Contains(s.Teams, f.team))
+}
+
+// AllowsChange reports whether c concerns an object in s. A nil Scope
+// allows every change.
+func (s *Scope) AllowsChange(c Change) bool {
+	return s == nil || s.allows(fields{namespace: c.Namespace, team: c.Team})
+}
+
+// Page is one page of query results.
+type Page[T any] struct {
+	Items      []T
+	Next       string // cursor for the next page, empty on the last page
+	Total      int    // number of matches across all pages
+	Generation uint64 // store generation the page was read from
+}
+
+// Selection is the ordered result of a query over one generation. Unlike a
+// Page it holds only the matching keys; Each reads the items from the
+// generation one at a time, so large results can be streamed without being
+// copied first.
+type Selection[T any] struct {
+	objs       *cowMap[T]
+	keys       []string
+	Next       string // cursor for the next page, empty on the last page
+	Total      int    // number of matches across all pages
+	Generation uint64 // store generation the selection was read from
+}
+
+// Len returns the number of items in the selection.
+func (s Selection[T]) Len() int { return len(s.keys) }
+
+// Each calls fn for every item in order, stopping at the first error.
+func (s Selection[T]) Each(fn func(T) error) error {
+	for _, key := range s.keys {
+		obj, _ := s.objs.get(key)
+		if err := fn(obj); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s Selection[T]) page() Page[T] {
+	page := Page[T]{
+		Items:      make([]T, 0, len(s.keys)),
+		Next:       s.Next,
+		Total:      s.Total,
+		Generation: s.Generation,
+	}
+	for _, key := range s.keys {
+		obj, _ := s.objs.get(key)
+		page.Items = append(page.Items, obj)
+	}
+	return page
+}
+
+// cursor is the decoded form of Query.Cursor: the sort value and object key
+// of the last item on the previous page. Keyset pagination keeps pages
+// stable when objects are added or removed between requests.
+type cursor struct {
+	Value string `json:"v"`
+	Key   string `json:"k"`
+}
+
+func (q Query) validate() error {
+	switch q.SortBy {
+	case "", SortByName, SortByNamespace, SortByKind, SortByCreator, SortByTeam,
+		SortByComposition, SortByProvider, SortByCreatedAt:
+		return nil
+	default:
+		return fmt.Errorf("%w: unknown sort field %q", ErrInvalidQuery, q.SortBy)
+	}
+}
+
+// View is a read-only handle on one committed generation. Reads made
+// through the same View are mutually consistent even if the store commits
+// new generations in the meantime.
+type View struct {
+	g *generation
+}
+
+// View returns a handle on the current generation.
+func (s *MemoryStore) View() View {
+	return View{g: s.read()}
+}
+
+// QueryClaims returns the page of claims matching q.
+func (s *MemoryStore) QueryClaims(q Query) (Page[ClaimInfo], error) {
+	return s.View().QueryClaims(q)
+}
+
+// QueryXRs returns the page of XRs matching q.
+func (s *MemoryStore) QueryXRs(q Query) (Page[XRInfo], error) {
+	return s.View().QueryXRs(q)
+}
+
+// QueryMRs returns the page of MRs matching q.
+func (s *MemoryStore) QueryMRs(q Query) (Page[MRInfo], error) {
+	return s.View().QueryMRs(q)
+}
+
+// Generation returns the identity of the viewed generation.
+func (v View) Generation() GenerationInfo {
+	return v.g.info()
+}
+
+// QueryClaims returns the page of claims matching q.
+func (v View) QueryClaims(q Query) (Page[ClaimInfo], error) {
+	sel, err := v.SelectClaims(q)
+	return sel.page(), err
+}
+
+// QueryXRs returns the page of XRs matching q.
+func (v View) QueryXRs(q Query) (Page[XRInfo], error) {
+	sel, err := v.SelectXRs(q)
+	return sel.page(), err
+}
+
+// QueryMRs returns the page of MRs matching q.
+func (v View) QueryMRs(q Query) (Page[MRInfo], error) {
+	sel, err := v.SelectMRs(q)
+	return sel.page(), err
+}
+
+// SelectClaims returns the claims matching q without copying them.
+func (v View) SelectClaims(q Query) (Selection[ClaimInfo], error) {
+	g := v.g
+	return selectQuery(q, g, &g.claims, &g.claimsByGVR, g.claimCandidates(q), g.matchClaim, g.claimFields)
+}
+
+// SelectXRs returns the XRs matching q without copying them.
+func (v View) SelectXRs(q Query) (Selection[XRInfo], error) {
+	g := v.g
+	return selectQuery(q, g, &g.xrs, &g.xrsByGVR, nil, g.matchXR, g.xrFields)
+}
+
+// SelectMRs returns the MRs matching q without copying them.
+func (v View) SelectMRs(q Query) (Selection[MRInfo], error) {
+	g := v.g
+	return selectQuery(q, g, &g.mrs, &g.mrsByGVR, nil, g.matchMR, g.mrFields)
+}
+
+// Claim returns the claim with the given namespace and name.
+func (v View) Claim(namespace, name string) (ClaimInfo, bool) {
+	return v.g.claims.get(objectKey(namespace, name))
+}
+
+// XR returns the XR with the given name; namespace is empty for
+// cluster-scoped XRs.
+func (v View) XR(namespace, name string) (XRInfo, bool) {
+	return v.g.xrs.get(objectKey(namespace, name))
+}
+
+// LookupXR finds the XR an object in namespace refers to by name, preferring
+// a namespaced XR in that namespace over a cluster-scoped one.
+func (v View) LookupXR(namespace, name string) (XRInfo, bool) {
+	if name == "" {
+		return XRInfo{}, false
+	}
+	return v.g.lookupXR(namespace, name)
+}
+
+// MR returns the MR of the given GVR with the given namespace and name;
+// namespace is empty for cluster-scoped MRs.
+func (v View) MR(gvr, namespace, name string) (MRInfo, bool) {
+	m, ok := v.g.mrs.get(objectKey(namespace, name))
+	if !ok || m.GVR != gvr {
+		return MRInfo{}, false
+	}
+	return m, true
+}
+
+// Claims returns a copy of the viewed claims, in no particular order.
+func (v View) Claims() []ClaimInfo {
+	return values(&v.g.claims)
+}
+
+// XRs returns a copy of the viewed XRs, in no particular order.
+func (v View) XRs() []XRInfo {
+	return values(&v.g.xrs)
+}
+
+// MRs returns a copy of the viewed MRs, in no particular order.
+func (v View) MRs() []MRInfo {
+	return values(&v.g.mrs)
+}
+
+// ClaimCount returns the number of viewed claims.
+func (v View) ClaimCount() int {
+	return v.g.claims.len()
+}
+
+// XRCount returns the number of viewed XRs.
+func (v View) XRCount() int {
+	return v.g.xrs.len()
+}
+
+// MRCount returns the number of viewed MRs.
+func (v View) MRCount() int {
+	return v.g.mrs.len()
+}
+
+// AllowsClaim reports whether c is in scope, resolving its namespace and
+// team like a query. A nil scope allows every object.
+func (v View) AllowsClaim(scope *Scope, c ClaimInfo) bool {
+	return scope == nil || scope.allows(v.g.claimFields(c))
+}
+
+// AllowsXR reports whether x is in scope.
+func (v View) AllowsXR(scope *Scope, x XRInfo) bool {
+	return scope == nil || scope.allows(v.g.xrFields(x))
+}
+
+// AllowsMR reports whether m is in scope.
+func (v View) AllowsMR(scope *Scope, m MRInfo) bool {
+	return scope == nil || scope.allows(v.g.mrFields(m))
+}
+
+// MRsForXR returns the MRs whose composite label names xrName, ordered by
+// "namespace/name".
+func (v View) MRsForXR(xrName string) []MRInfo {
+	keys := v.g.mrsByXR.keys(xrName)
+	sorted := make([]string, 0, len(keys))
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+	sort.Strings(sorted)
+	out := make([]MRInfo, 0, len(sorted))
+	for _, key := range sorted {
+		m, _ := v.g.mrs.get(key)
+		out = append(out, m)
+	}
+	return out
+}
+
+// Namespaces returns the sorted namespaces of the stored objects, resolved
+// like the Namespace filter.
+func (v View) Namespaces() []string {
+	seen := make(map[string]struct{}, v.g.claimsByNamespace.len())
+	for ns := range v.g.claimsByNamespace.values() {
+		seen[ns] = struct{}{}
+	}
+	for _, x := range v.g.xrs.all() {
+		if ns := cmp.Or(x.Namespace, x.ClaimNS); ns != "" {
+			seen[ns] = struct{}{}
+		}
+	}
+	for _, m := range v.g.mrs.all() {
+		if ns := cmp.Or(m.Namespace, m.ClaimNS); ns != "" {
+			seen[ns] = struct{}{}
+		}
+	}
+	out := make([]string, 0, len(seen))
+	for ns := range seen {
+		out = append(out, ns)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// DeletedClaims returns the claim tombstones matching the filters of q, most
+// recently removed first. Sorting and pagination fields are ignored.
+func (v View) DeletedClaims(q Query) []DeletedClaim {
+	return matchDeleted(&v.g.deletedClaims, func(d DeletedClaim) (bool, time.Time) {
+		return v.g.matchClaim(q, d.ClaimInfo), d.RemovedAt
+	})
+}
+
+// DeletedXRs returns the XR tombstones matching the filters of q, most
+// recently removed first.
+func (v View) DeletedXRs(q Query) []DeletedXR {
+	return matchDeleted(&v.g.deletedXRs, func(d DeletedXR) (bool, time.Time) {
+		return v.g.matchXR(q, d.XRInfo), d.RemovedAt
+	})
+}
+
+// DeletedMRs returns the MR tombstones matching the filters of q, most
+// recently removed first.
+func (v View) DeletedMRs(q Query) []DeletedMR {
+	return matchDeleted(&v.g.deletedMRs, func(d DeletedMR) (bool, time.Time) {
+		return v.g.matchMR(q, d.MRInfo), d.RemovedAt
+	})
+}
+
+func matchDeleted[T any](tombstones *cowMap[T], match func(T) (bool, time.Time)) []T {
+	type hit struct {
+		key       string
+		item      T
+		removedAt time.Time
+	}
+	var hits []hit
+	for key, d := range tombstones.all() {
+		if ok, at := match(d); ok {
+			hits = append(hits, hit{key: key, item: d, removedAt: at})
+		}
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if !hits[i].removedAt.Equal(hits[j].removedAt) {
+			return hits[i].removedAt.After(hits[j].removedAt)
+		}
+		return hits[i].key < hits[j].key
+	})
+	out := make([]T, 0, len(hits))
+	for _, h := range hits {
+		out = append(out, h.item)
+	}
+	return out
+}
+
+// fields is the set of queryable attributes of one object, with linkage
+// already resolved.
+type fields struct {
+	namespace   string
+	name        string
+	kind        string
+	creator     string
+	team        string
+	composition string
+	provider    string
+	createdAt   time.Time
+}
+
+func (f fields) sortValue(by SortField) string {
+	switch by {
+	case SortByName:
+		return f.name
+	case SortByNamespace:
+		return f.namespace
+	case SortByKind:
+		return f.kind
+	case SortByCreator:
+		return f.creator
+	case SortByTeam:
+		return f.team
+	case SortByComposition:
+		return f.composition
+	case SortByProvider:
+		return f.provider
+	case SortByCreatedAt:
+		// Fixed-width UTC timestamps sort lexically in chronological order.
+		return f.createdAt.UTC().Format("2006-01-02T15:04:05.000000000Z")
+	default:
+		return ""
+	}
+}
+
+// selectQuery filters objs (or, when the query names a GVR, only the keys in
+// byGVR; or the narrower candidates set when non-nil), orders the matches
+// and cuts one page. Only the matching keys are collected.
+func selectQuery[T any](
+	q Query,
+	g *generation,
+	objs *cowMap[T],
+	byGVR *keySet,
+	candidates map[string]struct{},
+	match func(Query, T) bool,
+	extract func(T) fields,
+) (Selection[T], error) {
+	if err := q.validate(); err != nil {
+		return Selection[T]{}, err
+	}
+	var after *cursor
+	if q.Cursor != "" {
+		c, err := decodeCursor(q.Cursor)
+		if err != nil {
+			return Selection[T]{}, err
+		}
+		after = &c
+	}
+
+	type hit struct {
+		value, key string
+	}
+	var hits []hit
+	consider := func(key string, obj T) {
+		if !match(q, obj) {
+			return
+		}
+		var value string
+		if q.SortBy != "" {
+			value = extract(obj).sortValue(q.SortBy)
+		}
+		hits = append(hits, hit{value: value, key: key})
+	}
+
+	switch {
+	case q.GVR != "":
+		for key := range byGVR.keys(q.GVR) {
+			obj, _ := objs.get(key)
+			consider(key, obj)
+		}
+	case candidates != nil:
+		for key := range candidates {
+			obj, _ := objs.get(key)
+			consider(key, obj)
+		}
+	default:
+		for key, obj := range objs.all() {
+			consider(key, obj)
+		}
+	}
+
+	less := func(a, b hit) bool {
+		if a.value != b.value {
+			return a.value < b.value
+		}
+		return a.key < b.key
+	}
+	if q.Descending {
+		asc := less
+		less = func(a, b hit) bool { return asc(b, a) }
+	}
+	sort.Slice(hits, func(i, j int) bool { return less(hits[i], hits[j]) })
+
+	start := 0
+	if after != nil {
+		pivot := hit{value: after.Value, key: after.Key}
+		start = sort.Search(len(hits), func(i int) bool { return less(pivot, hits[i]) })
+	}
+	end := len(hits)
+	if q.Limit > 0 && start+q.Limit < end {
+		end = start + q.Limit
+	}
+
+	sel := Selection[T]{
+		objs:       objs,
+		keys:       make([]string, 0, end-start),
+		Total:      len(hits),
+		Generation: g.number,
+	}
+	for _, h := range hits[start:end] {
+		sel.keys = append(sel.keys, h.key)
+	}
+	if end < len(hits) {
+		last := hits[end-1]
+		sel.Next = encodeCursor(cursor{Value: last.value, Key: last.key})
+	}
+	return sel, nil
+}
+
+func encodeCursor(c cursor) string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(s string) (cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return cursor{}, fmt.Errorf("%w: malformed cursor", ErrInvalidQuery)
+	}
+	var c cursor
+	if err := json.Unmarshal(data, &c); err != nil {
+		return cursor{}, fmt.Errorf("%w: malformed cursor", ErrInvalidQuery)
+	}
+	return c, nil
+}
+
+// matchCommon applies the filters shared by every resource class.
+func (q Query) matchCommon(f fields, synced, ready, paused, deleting bool) bool {
+	switch {
+	case q.Namespace != "" && f.namespace != q.Namespace,
+		q.Kind != "" && f.kind != q.Kind,
+		q.Team != "" && f.team != q.Team,
+		q.Creator != "" && f.creator != q.Creator,
+		q.Composition != "" && f.composition != q.Composition,
+		q.Ready != nil && *q.Ready != ready,
+		q.Synced != nil && *q.Synced != synced,
+		q.Paused != nil && *q.Paused != paused,
+		q.Deleting != nil && *q.Deleting != deleting,
+		q.Scope != nil && !q.Scope.allows(f):
+		return false
+	}
+	return true
+}
+
+// claimCandidates narrows the claim scan to one namespace when the query
+// filters by namespace but not by GVR. It returns nil when no narrowing
+// applies.
+func (g *generation) claimCandidates(q Query) map[string]struct{} {
+	if q.Namespace == "" || q.GVR != "" {
+		return nil
+	}
+	if keys := g.claimsByNamespace.keys(q.Namespace); keys != nil {
+		return keys
+	}
+	return map[string]struct{}{}
+}
+
+func (g *generation) claimFields(c ClaimInfo) fields {
+	return fields{
+		namespace:   c.Namespace,
+		name:        c.Name,
+		kind:        c.Kind,
+		creator:     c.Creator,
+		team:        c.Team,
+		composition: c.Composition,
+		createdAt:   c.CreatedAt,
+	}
+}
+
+func (g *generation) matchClaim(q Query, c ClaimInfo) bool {
+	if q.GVR != "" && c.GVR != q.GVR {
+		return false
+	}
+	if !q.matchCommon(g.claimFields(c), c.Synced, c.Ready, c.Paused, !c.DeletedAt.IsZero()) {
+		return false
+	}
+	return q.Provider == "" || g.xrHasProvider(c.XRRef, q.Provider)
+}
+
+func (g *generation) xrFields(x XRInfo) fields {
+	f := fields{
+		namespace:   x.Namespace,
+		name:        x.Name,
+		kind:        x.Kind,
+		composition: x.Composition,
+		createdAt:   x.CreatedAt,
+	}
+	if claim, ok := g.linkedClaim(x.ClaimNS, x.ClaimName); ok {
+		f.creator = claim.Creator
+		f.team = claim.Team
+	}
+	if f.namespace == "" {
+		f.namespace = x.ClaimNS
+	}
+	return f
+}
+
+func (g *generation) matchXR(q Query, x XRInfo) bool {
+	if q.GVR != "" && x.GVR != q.GVR {
+		return false
+	}
+	if !q.matchCommon(g.xrFields(x), x.Synced, x.Ready, x.Paused, !x.DeletedAt.IsZero()) {
+		return false
+	}
+	return q.Provider == "" || g.xrHasProvider(x.Name, q.Provider)
+}
+
+func (g *generation) mrFields(m MRInfo) fields {
+	f := fields{
+		namespace: m.Namespace,
+		name:      m.Name,
+		kind:      m.Kind,
+		provider:  m.Provider,
+		createdAt: m.CreatedAt,
+	}
+	if claim, ok := g.linkedClaim(m.ClaimNS, m.ClaimName); ok {
+		f.creator = claim.Creator
+		f.team = claim.Team
+	}
+	if xr, ok := g.lookupXR(m.Namespace, m.XRName); ok {
+		f.composition = xr.Composition
+	}
+	if f.namespace == "" {
+		f.namespace = m.ClaimNS
+	}
+	return f
+}
+
+func (g *generation) matchMR(q Query, m MRInfo) bool {
+	if q.GVR != "" && m.GVR != q.GVR {
+		return false
+	}
+	if q.Provider != "" && m.Provider != q.Provider {
+		return false
+	}
+	return q.matchCommon(g.mrFields(m), m.Synced, m.Ready, m.Paused, !m.DeletedAt.IsZero())
+}
+
+// linkedClaim returns the claim identified by namespace and name, if stored.
+func (g *generation) linkedClaim(namespace, name string) (ClaimInfo, bool) {
+	if name == "" {
+		return ClaimInfo{}, false
+	}
+	return g.claims.get(objectKey(namespace, name))
+}
+
+// xrHasProvider reports whether any MR composed by the named XR comes from
+// the given provider.
+func (g *generation) xrHasProvider(xrName, provider string) bool {
+	for key := range g.mrsByXR.keys(xrName) {
+		if m, _ := g.mrs.get(key); m.Provider == provider {
+			return true
+		}
+	}
+	return false
+}
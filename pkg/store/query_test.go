@@ -0,0 +1,415 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func boolPtr(b bool) *bool { return &b }
+
+// queryFixture builds a store with two teams' claims, their XRs and MRs
+// from two providers.
+func queryFixture() *MemoryStore {
+	s := New()
+	s.BeginGeneration()
+	s.ReplaceClaims("g/v1/dbs", []ClaimInfo{
+		{GVR: "g/v1/dbs", Kind: "DB", Namespace: "team-a", Name: "db1", Team: "a", Creator: "alice", XRRef: "xr-db1", Ready: true, Synced: true},
+		{GVR: "g/v1/dbs", Kind: "DB", Namespace: "team-b", Name: "db2", Team: "b", Creator: "bob", XRRef: "xr-db2", Synced: true},
+	})
+	s.ReplaceClaims("g/v1/buckets", []ClaimInfo{
+		{GVR: "g/v1/buckets", Kind: "Bucket", Namespace: "team-a", Name: "bk1", Team: "a", Creator: "alice", XRRef: "xr-bk1", Ready: true, Paused: true},
+	})
+	s.ReplaceXRs("g/v1/xdbs", []XRInfo{
+		{GVR: "g/v1/xdbs", Kind: "XDB", Name: "xr-db1", Composition: "db-aws", Ready: true},
+		{GVR: "g/v1/xdbs", Kind: "XDB", Name: "xr-db2", Composition: "db-gcp", DeletedAt: time.Now()},
+	})
+	s.ReplaceXRs("g/v1/xbuckets", []XRInfo{
+		{GVR: "g/v1/xbuckets", Kind: "XBucket", Name: "xr-bk1", Composition: "bucket-aws"},
+	})
+	s.ReplaceMRs("rds/v1/instances", []MRInfo{
+		{GVR: "rds/v1/instances", Kind: "Instance", Name: "rds1", XRName: "xr-db1", Provider: "provider-aws", Ready: true},
+	})
+	s.ReplaceMRs("sql/v1/instances", []MRInfo{
+		{GVR: "sql/v1/instances", Kind: "Instance", Name: "sql1", XRName: "xr-db2", Provider: "provider-gcp"},
+	})
+	s.ReplaceMRs("s3/v1/buckets", []MRInfo{
+		{GVR: "s3/v1/buckets", Kind: "Bucket", Name: "s3a", XRName: "xr-bk1", Provider: "provider-aws"},
+	})
+	s.EnrichXRClaims()
+	s.EnrichClaimCompositions()
+	s.EnrichMRClaims()
+	s.CommitGeneration()
+	return s
+}
+
+func claimNames(items []ClaimInfo) []string {
+	names := make([]string, 0, len(items))
+	for _, c := range items {
+		names = append(names, c.Name)
+	}
+	return names
+}
+
+func TestQueryClaims_Filters(t *testing.T) {
+	s := queryFixture()
+
+	tests := []struct {
+		name  string
+		query Query
+		want  []string
+	}{
+		{"all", Query{}, []string{"bk1", "db1", "db2"}},
+		{"gvr", Query{GVR: "g/v1/dbs"}, []string{"db1", "db2"}},
+		{"namespace", Query{Namespace: "team-a"}, []string{"bk1", "db1"}},
+		{"namespace and gvr", Query{Namespace: "team-a", GVR: "g/v1/dbs"}, []string{"db1"}},
+		{"unknown namespace", Query{Namespace: "nope"}, []string{}},
+		{"team", Query{Team: "b"}, []string{"db2"}},
+		{"creator", Query{Creator: "alice"}, []string{"bk1", "db1"}},
+		{"kind", Query{Kind: "Bucket"}, []string{"bk1"}},
+		{"composition", Query{Composition: "db-gcp"}, []string{"db2"}},
+		{"provider via MRs", Query{Provider: "provider-aws"}, []string{"bk1", "db1"}},
+		{"ready", Query{Ready: boolPtr(true)}, []string{"bk1", "db1"}},
+		{"not synced", Query{Synced: boolPtr(false)}, []string{"bk1"}},
+		{"paused", Query{Paused: boolPtr(true)}, []string{"bk1"}},
+		{"not deleting", Query{Deleting: boolPtr(false)}, []string{"bk1", "db1", "db2"}},
+		{"scope namespace", Query{Scope: &Scope{Namespaces: []string{"team-b"}}}, []string{"db2"}},
+		{"scope team", Query{Scope: &Scope{Teams: []string{"a"}}}, []string{"bk1", "db1"}},
+		{"scope namespace or team", Query{Scope: &Scope{Namespaces: []string{"team-b"}, Teams: []string{"a"}}}, []string{"bk1", "db1", "db2"}},
+		{"scope and filter", Query{Kind: "DB", Scope: &Scope{Teams: []string{"a"}}}, []string{"db1"}},
+		{"empty scope", Query{Scope: &Scope{}}, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := s.QueryClaims(tt.query)
+			if err != nil {
+				t.Fatalf("QueryClaims: %v", err)
+			}
+			got := claimNames(page.Items)
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+			if page.Total != len(tt.want) {
+				t.Errorf("expected total %d, got %d", len(tt.want), page.Total)
+			}
+		})
+	}
+}
+
+func TestQueryXRs_LinkedFields(t *testing.T) {
+	s := queryFixture()
+
+	page, err := s.QueryXRs(Query{Team: "a", Namespace: "team-a"})
+	if err != nil {
+		t.Fatalf("QueryXRs: %v", err)
+	}
+	if len(page.Items) != 2 || page.Items[0].Name != "xr-bk1" || page.Items[1].Name != "xr-db1" {
+		t.Errorf("unexpected XRs for team a: %+v", page.Items)
+	}
+
+	page, _ = s.QueryXRs(Query{Deleting: boolPtr(true)})
+	if len(page.Items) != 1 || page.Items[0].Name != "xr-db2" {
+		t.Errorf("unexpected deleting XRs: %+v", page.Items)
+	}
+
+	page, _ = s.QueryXRs(Query{Provider: "provider-gcp"})
+	if len(page.Items) != 1 || page.Items[0].Name != "xr-db2" {
+		t.Errorf("unexpected XRs for provider-gcp: %+v", page.Items)
+	}
+}
+
+func TestQueryMRs_LinkedFields(t *testing.T) {
+	s := queryFixture()
+
+	page, err := s.QueryMRs(Query{Team: "a"})
+	if err != nil {
+		t.Fatalf("QueryMRs: %v", err)
+	}
+	if page.Total != 2 {
+		t.Errorf("expected 2 MRs for team a, got %+v", page.Items)
+	}
+
+	page, _ = s.QueryMRs(Query{Composition: "db-aws"})
+	if len(page.Items) != 1 || page.Items[0].Name != "rds1" {
+		t.Errorf("unexpected MRs for composition db-aws: %+v", page.Items)
+	}
+
+	page, _ = s.QueryMRs(Query{Provider: "provider-aws", Creator: "alice", GVR: "s3/v1/buckets"})
+	if len(page.Items) != 1 || page.Items[0].Name != "s3a" {
+		t.Errorf("unexpected MRs: %+v", page.Items)
+	}
+}
+
+func TestQuery_ScopeFollowsLinkage(t *testing.T) {
+	s := queryFixture()
+	scope := &Scope{Namespaces: []string{"team-b"}}
+
+	xrs, err := s.QueryXRs(Query{Scope: scope})
+	if err != nil {
+		t.Fatalf("QueryXRs: %v", err)
+	}
+	if xrs.Total != 1 || xrs.Items[0].Name != "xr-db2" {
+		t.Errorf("unexpected XRs in scope: %+v", xrs.Items)
+	}
+	mrs, err := s.QueryMRs(Query{Scope: scope})
+	if err != nil {
+		t.Fatalf("QueryMRs: %v", err)
+	}
+	if mrs.Total != 1 || mrs.Items[0].Name != "sql1" {
+		t.Errorf("unexpected MRs in scope: %+v", mrs.Items)
+	}
+
+	if got := s.View().Namespaces(); fmt.Sprint(got) != "[team-a team-b]" {
+		t.Errorf("unexpected namespaces %v", got)
+	}
+}
+
+func TestQuery_SortAndPaginate(t *testing.T) {
+	s := New()
+	var claims []ClaimInfo
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := range 10 {
+		claims = append(claims, ClaimInfo{
+			GVR:       "g/v1/r",
+			Namespace: "ns",
+			Name:      fmt.Sprintf("c%02d", i),
+			Team:      fmt.Sprintf("team-%d", i%3),
+			CreatedAt: base.Add(time.Duration(9-i) * time.Hour),
+		})
+	}
+	s.ReplaceClaims("g/v1/r", claims)
+
+	collect := func(q Query) []string {
+		t.Helper()
+		var names []string
+		for pages := 0; ; pages++ {
+			if pages > 10 {
+				t.Fatal("pagination did not terminate")
+			}
+			page, err := s.QueryClaims(q)
+			if err != nil {
+				t.Fatalf("QueryClaims: %v", err)
+			}
+			if page.Total != 10 {
+				t.Errorf("expected total 10, got %d", page.Total)
+			}
+			names = append(names, claimNames(page.Items)...)
+			if page.Next == "" {
+				return names
+			}
+			q.Cursor = page.Next
+		}
+	}
+
+	got := collect(Query{SortBy: SortByCreatedAt, Limit: 3})
+	want := "[c09 c08 c07 c06 c05 c04 c03 c02 c01 c00]"
+	if fmt.Sprint(got) != want {
+		t.Errorf("createdAt ascending: got %v", got)
+	}
+
+	got = collect(Query{SortBy: SortByTeam, Descending: true, Limit: 4})
+	want = "[c08 c05 c02 c07 c04 c01 c09 c06 c03 c00]"
+	if fmt.Sprint(got) != want {
+		t.Errorf("team descending: got %v", got)
+	}
+
+	got = collect(Query{Limit: 5})
+	want = "[c00 c01 c02 c03 c04 c05 c06 c07 c08 c09]"
+	if fmt.Sprint(got) != want {
+		t.Errorf("default order: got %v", got)
+	}
+}
+
+func TestQuery_CursorStableAcrossWrites(t *testing.T) {
+	s := New()
+	s.ReplaceClaims("g/v1/r", []ClaimInfo{
+		{GVR: "g/v1/r", Namespace: "ns", Name: "a"},
+		{GVR: "g/v1/r", Namespace: "ns", Name: "b"},
+		{GVR: "g/v1/r", Namespace: "ns", Name: "c"},
+	})
+
+	page, _ := s.QueryClaims(Query{Limit: 2})
+	if page.Next == "" {
+		t.Fatal("expected a next cursor")
+	}
+
+	// Removing an already returned item must not cause the next page to
+	// skip or repeat anything.
+	s.ReplaceClaims("g/v1/r", []ClaimInfo{
+		{GVR: "g/v1/r", Namespace: "ns", Name: "b"},
+		{GVR: "g/v1/r", Namespace: "ns", Name: "c"},
+		{GVR: "g/v1/r", Namespace: "ns", Name: "d"},
+	})
+	page, _ = s.QueryClaims(Query{Limit: 2, Cursor: page.Next})
+	if got := fmt.Sprint(claimNames(page.Items)); got != "[c d]" {
+		t.Errorf("unexpected second page %s", got)
+	}
+	if page.Next != "" {
+		t.Errorf("expected last page, got next %q", page.Next)
+	}
+}
+
+func TestQuery_Invalid(t *testing.T) {
+	s := queryFixture()
+
+	if _, err := s.QueryClaims(Query{SortBy: "size"}); !errors.Is(err, ErrInvalidQuery) {
+		t.Errorf("expected ErrInvalidQuery for unknown sort field, got %v", err)
+	}
+	if _, err := s.QueryMRs(Query{Cursor: "!!"}); !errors.Is(err, ErrInvalidQuery) {
+		t.Errorf("expected ErrInvalidQuery for malformed cursor, got %v", err)
+	}
+}
+
+func TestQuery_ReportsGeneration(t *testing.T) {
+	s := queryFixture()
+	page, _ := s.QueryXRs(Query{})
+	if page.Generation != s.Generation().Number {
+		t.Errorf("expected generation %d, got %d", s.Generation().Number, page.Generation)
+	}
+}
+
+func TestReplace_MaintainsQueryIndexes(t *testing.T) {
+	s := New()
+	s.ReplaceClaims("g/v1/r", []ClaimInfo{{GVR: "g/v1/r", Namespace: "ns1", Name: "a"}})
+	s.ReplaceClaims("g/v1/r", []ClaimInfo{})
+
+	if s.current.claimsByGVR.len() != 0 || s.current.claimsByNamespace.len() != 0 {
+		t.Errorf("expected claim indexes to be empty, got %v / %v", s.current.claimsByGVR, s.current.claimsByNamespace)
+	}
+
+	s.ReplaceMRs("m/v1/r", []MRInfo{{GVR: "m/v1/r", Name: "m1", XRName: "xr"}})
+	s.ReplaceMRs("m/v1/r", nil)
+	if s.current.mrsByGVR.len() != 0 || s.current.mrsByXR.len() != 0 {
+		t.Errorf("expected MR indexes to be empty, got %v / %v", s.current.mrsByGVR, s.current.mrsByXR)
+	}
+}
+
+func BenchmarkQueryMRs_100k(b *testing.B) {
+	s := New()
+	var mrs []MRInfo
+	for i := range 100_000 {
+		mrs = append(mrs, MRInfo{
+			GVR:      fmt.Sprintf("p%d/v1/r", i%20),
+			Name:     fmt.Sprintf("mr-%06d", i),
+			Provider: fmt.Sprintf("provider-%d", i%5),
+			Ready:    i%2 == 0,
+		})
+	}
+	for gvr := range 20 {
+		var batch []MRInfo
+		for _, m := range mrs {
+			if m.GVR == fmt.Sprintf("p%d/v1/r", gvr) {
+				batch = append(batch, m)
+			}
+		}
+		s.ReplaceMRs(fmt.Sprintf("p%d/v1/r", gvr), batch)
+	}
+
+	q := Query{GVR: "p3/v1/r", Ready: boolPtr(false), Limit: 100}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for b.Loop() {
+		if _, err := s.QueryMRs(q); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestView_PinsGeneration(t *testing.T) {
+	s := queryFixture()
+	view := s.View()
+	before := view.Generation()
+
+	s.ReplaceClaims("g/v1/dbs", nil)
+
+	page, err := view.QueryClaims(Query{GVR: "g/v1/dbs"})
+	if err != nil {
+		t.Fatalf("QueryClaims: %v", err)
+	}
+	if page.Total != 2 || page.Generation != before.Number {
+		t.Errorf("expected the view to keep generation %d with 2 claims, got generation %d with %d",
+			before.Number, page.Generation, page.Total)
+	}
+	if s.Generation().Number == before.Number {
+		t.Error("expected the store to have moved on to a new generation")
+	}
+}
+
+func TestView_Lookups(t *testing.T) {
+	view := queryFixture().View()
+
+	if c, ok := view.Claim("team-a", "db1"); !ok || c.XRRef != "xr-db1" {
+		t.Errorf("Claim(team-a, db1) = %+v, %v", c, ok)
+	}
+	if _, ok := view.Claim("team-b", "db1"); ok {
+		t.Error("expected no claim db1 in team-b")
+	}
+	if x, ok := view.XR("", "xr-db1"); !ok || x.ClaimName != "db1" {
+		t.Errorf("XR(xr-db1) = %+v, %v", x, ok)
+	}
+	if _, ok := view.LookupXR("team-a", ""); ok {
+		t.Error("expected LookupXR with an empty name to miss")
+	}
+	if x, ok := view.LookupXR("team-a", "xr-db1"); !ok || x.Name != "xr-db1" {
+		t.Errorf("LookupXR(team-a, xr-db1) = %+v, %v", x, ok)
+	}
+	if m, ok := view.MR("rds/v1/instances", "", "rds1"); !ok || m.ClaimName != "db1" {
+		t.Errorf("MR(rds1) = %+v, %v", m, ok)
+	}
+	if _, ok := view.MR("sql/v1/instances", "", "rds1"); ok {
+		t.Error("expected MR lookup under the wrong GVR to miss")
+	}
+	if mrs := view.MRsForXR("xr-bk1"); len(mrs) != 1 || mrs[0].Name != "s3a" {
+		t.Errorf("MRsForXR(xr-bk1) = %+v", mrs)
+	}
+	if mrs := view.MRsForXR("missing"); len(mrs) != 0 {
+		t.Errorf("MRsForXR(missing) = %+v", mrs)
+	}
+}
+
+func TestView_DeletedFiltered(t *testing.T) {
+	s := New()
+	s.SetTombstoneRetention(time.Hour)
+	s.ReplaceClaims("g/v1/r", []ClaimInfo{
+		{GVR: "g/v1/r", Namespace: "a", Name: "one", Team: "x"},
+		{GVR: "g/v1/r", Namespace: "b", Name: "two", Team: "y"},
+	})
+	s.ReplaceClaims("g/v1/r", nil)
+
+	view := s.View()
+	if got := view.DeletedClaims(Query{}); len(got) != 2 {
+		t.Errorf("expected 2 tombstones, got %d", len(got))
+	}
+	got := view.DeletedClaims(Query{Team: "y"})
+	if len(got) != 1 || got[0].Name != "two" {
+		t.Errorf("unexpected filtered tombstones %+v", got)
+	}
+}
+
+func TestSelection_EachStopsOnError(t *testing.T) {
+	s := queryFixture()
+	sel, err := s.View().SelectClaims(Query{})
+	if err != nil {
+		t.Fatalf("SelectClaims: %v", err)
+	}
+	if sel.Len() != 3 {
+		t.Fatalf("expected 3 claims, got %d", sel.Len())
+	}
+
+	stop := errors.New("stop")
+	var seen []string
+	err = sel.Each(func(c ClaimInfo) error {
+		seen = append(seen, c.Name)
+		if len(seen) == 2 {
+			return stop
+		}
+		return nil
+	})
+	if !errors.Is(err, stop) {
+		t.Errorf("expected Each to return the callback error, got %v", err)
+	}
+	if fmt.Sprint(seen) != "[bk1 db1]" {
+		t.Errorf("unexpected items visited %v", seen)
+	}
+}
@@ -3,11 +3,12 @@ package store
 import (
 	"bytes"
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"log/slog"
+	"slices"
+	"strings"
 	"sync"
 	"time"
 
@@ -26,6 +27,8 @@ const maxSnapshotSize = 100 << 20
 type S3Client interface {
 	PutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error)
 	GetObject(ctx context.Context, params *s3.GetObjectInput, optFns ...func(*s3.Options)) (*s3.GetObjectOutput, error)
+	ListObjectsV2(ctx context.Context, params *s3.ListObjectsV2Input, optFns ...func(*s3.Options)) (*s3.ListObjectsV2Output, error)
+	DeleteObject(ctx context.Context, params *s3.DeleteObjectInput, optFns ...func(*s3.Options)) (*s3.DeleteObjectOutput, error)
 }
 
 // S3Store wraps a MemoryStore and adds S3 persistence.
@@ -37,11 +40,21 @@ type S3Store struct {
 	mem    *MemoryStore
 	client S3Client
 	bucket string
+	prefix string
 	key    string
 
+	// shard is this replica's identity in sharded polling (see SetShard).
+	shard string
+
+	// keyring enables envelope encryption of snapshots when non-nil.
+	keyring *Keyring
+
 	// persistMu serialises Persist calls so concurrent poll cycles
 	// (shouldn't happen, but defensive) don't race on S3 writes.
 	persistMu sync.Mutex
+
+	statusMu sync.Mutex
+	status   PersistenceStatus
 }
 
 // NewS3Store creates an S3Store that persists snapshots to
@@ -51,54 +64,221 @@ func NewS3Store(mem *MemoryStore, client S3Client, bucket, keyPrefix string) *S3
 		mem:    mem,
 		client: client,
 		bucket: bucket,
+		prefix: keyPrefix,
 		key:    keyPrefix + "/snapshot.json",
 	}
 }
 
+// SetShard switches the store to sharded polling as the replica identity.
+// Persist and PersistShard then write
+// s3://<bucket>/<keyPrefix>/shards/<identity>.json instead of the shared
+// snapshot, and RestoreShards replaces Restore. It must be called before the
+// first Persist or restore.
+func (s *S3Store) SetShard(identity string) {
+	s.shard = identity
+	s.key = s.shardKey(identity)
+}
+
+func (s *S3Store) shardPrefix() string {
+	return s.prefix + "/shards/"
+}
+
+func (s *S3Store) shardKey(shard string) string {
+	return s.shardPrefix() + shard + ".json"
+}
+
+// SetKeyring enables client-side envelope encryption of persisted snapshots.
+// It must be called before the first Persist or Restore. Snapshots written
+// without encryption remain readable after a keyring is configured.
+func (s *S3Store) SetKeyring(k *Keyring) { s.keyring = k }
+
 // ---------------------------------------------------------------------------
 // Store interface delegation – all reads/writes go through MemoryStore.
 // ---------------------------------------------------------------------------
 
+func (s *S3Store) BeginGeneration()                            { s.mem.BeginGeneration() }
+func (s *S3Store) CommitGeneration() GenerationInfo            { return s.mem.CommitGeneration() }
+func (s *S3Store) Generation() GenerationInfo                  { return s.mem.Generation() }
 func (s *S3Store) ReplaceClaims(gvr string, items []ClaimInfo) { s.mem.ReplaceClaims(gvr, items) }
 func (s *S3Store) ReplaceXRs(gvr string, items []XRInfo)       { s.mem.ReplaceXRs(gvr, items) }
 func (s *S3Store) ReplaceMRs(gvr string, items []MRInfo)       { s.mem.ReplaceMRs(gvr, items) }
-func (s *S3Store) EnrichClaimCompositions()                    { s.mem.EnrichClaimCompositions() }
-func (s *S3Store) EnrichXRClaims()                             { s.mem.EnrichXRClaims() }
-func (s *S3Store) EnrichMRClaims()                             { s.mem.EnrichMRClaims() }
-func (s *S3Store) SnapshotClaims() []ClaimInfo                 { return s.mem.SnapshotClaims() }
-func (s *S3Store) SnapshotXRs() []XRInfo                       { return s.mem.SnapshotXRs() }
-func (s *S3Store) SnapshotMRs() []MRInfo                       { return s.mem.SnapshotMRs() }
-func (s *S3Store) ClaimCount() int                             { return s.mem.ClaimCount() }
-func (s *S3Store) XRCount() int                                { return s.mem.XRCount() }
-func (s *S3Store) MRCount() int                                { return s.mem.MRCount() }
+func (s *S3Store) ReplaceClaimsIn(gvr string, namespaces []string, items []ClaimInfo) {
+	s.mem.ReplaceClaimsIn(gvr, namespaces, items)
+}
+func (s *S3Store) ReplaceXRsIn(gvr string, namespaces []string, items []XRInfo) {
+	s.mem.ReplaceXRsIn(gvr, namespaces, items)
+}
+func (s *S3Store) ReplaceMRsIn(gvr string, namespaces []string, items []MRInfo) {
+	s.mem.ReplaceMRsIn(gvr, namespaces, items)
+}
+func (s *S3Store) PurgeClaims(gvr string, keep []string)        { s.mem.PurgeClaims(gvr, keep) }
+func (s *S3Store) PurgeXRs(gvr string, keep []string)           { s.mem.PurgeXRs(gvr, keep) }
+func (s *S3Store) PurgeMRs(gvr string, keep []string)           { s.mem.PurgeMRs(gvr, keep) }
+func (s *S3Store) EnrichClaimCompositions()                     { s.mem.EnrichClaimCompositions() }
+func (s *S3Store) EnrichXRClaims()                              { s.mem.EnrichXRClaims() }
+func (s *S3Store) EnrichMRClaims()                              { s.mem.EnrichMRClaims() }
+func (s *S3Store) MRsForXR(xrName string) []MRInfo              { return s.mem.MRsForXR(xrName) }
+func (s *S3Store) View() View                                   { return s.mem.View() }
+func (s *S3Store) QueryClaims(q Query) (Page[ClaimInfo], error) { return s.mem.QueryClaims(q) }
+func (s *S3Store) QueryXRs(q Query) (Page[XRInfo], error)       { return s.mem.QueryXRs(q) }
+func (s *S3Store) QueryMRs(q Query) (Page[MRInfo], error)       { return s.mem.QueryMRs(q) }
+func (s *S3Store) Snapshot() Snapshot                           { return s.mem.Snapshot() }
+func (s *S3Store) Changes() *ChangeFeed                         { return s.mem.Changes() }
 
 // ---------------------------------------------------------------------------
 // PersistentStore implementation
 // ---------------------------------------------------------------------------
 
-// Persist serialises the current in-memory state to S3 as JSON.
+// Persist serialises the current in-memory state to S3 as JSON, encrypted
+// when a keyring is configured.
 func (s *S3Store) Persist(ctx context.Context) error {
+	return s.persist(ctx, s.mem.Snapshot())
+}
+
+// PersistShard persists the objects and tombstones of gvrs, the GVRs this
+// replica owns, as its shard. Peers read it with LoadShard. It requires
+// SetShard.
+func (s *S3Store) PersistShard(ctx context.Context, gvrs []string) error {
+	if s.shard == "" {
+		return errors.New("persist shard: no shard identity set")
+	}
+	snap := s.mem.Snapshot().forGVRs(gvrs)
+	snap.Shard = s.shard
+	snap.GVRs = slices.Sorted(slices.Values(gvrs))
+	return s.persist(ctx, snap)
+}
+
+// LoadShard reads the shard persisted by the replica named shard. It
+// reports false, without an error, when the replica has not persisted one.
+func (s *S3Store) LoadShard(ctx context.Context, shard string) (Snapshot, bool, error) {
+	return s.get(ctx, s.shardKey(shard))
+}
+
+// MergeShard copies the objects and tombstones of gvrs from snap, a shard
+// read with LoadShard, into the store.
+func (s *S3Store) MergeShard(snap Snapshot, gvrs []string) {
+	s.mem.MergeShard(snap, gvrs)
+}
+
+// RestoreShards loads the shards of members, the current members in
+// sharded polling, on startup. Each shard contributes the GVRs it lists;
+// where shards written under an earlier membership list the same GVR, the
+// most recently persisted one wins. The shards of replicas that are no
+// longer members are not read, since their new owners poll their GVRs in
+// the first cycle. Shards that cannot be read are skipped and their errors
+// returned.
+func (s *S3Store) RestoreShards(ctx context.Context, members []string) error {
+	var snaps []Snapshot
+	var errs []error
+	for _, member := range members {
+		snap, found, err := s.get(ctx, s.shardKey(member))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("shard %s: %w", member, err))
+			continue
+		}
+		if found {
+			snaps = append(snaps, snap)
+		}
+	}
+	err := errors.Join(errs...)
+
+	var snapshotAt time.Time
+	if len(snaps) > 0 {
+		slices.SortFunc(snaps, func(a, b Snapshot) int { return a.PersistedAt.Compare(b.PersistedAt) })
+		s.mem.BeginGeneration()
+		for _, snap := range snaps {
+			s.mem.MergeShard(snap, snap.GVRs)
+		}
+		s.mem.CommitGeneration()
+		snapshotAt = snaps[len(snaps)-1].PersistedAt
+		slog.Info("restored shards from S3",
+			"bucket", s.bucket,
+			"members", members,
+			"shards", len(snaps),
+		)
+	} else if err == nil {
+		slog.Warn("no existing S3 shards found, starting with empty store",
+			"bucket", s.bucket,
+			"prefix", s.shardPrefix(),
+			"members", members,
+		)
+	}
+	s.recordRestore(snapshotAt, len(snaps) > 0, err)
+	return err
+}
+
+// ListShards lists the shards persisted under the key prefix.
+func (s *S3Store) ListShards(ctx context.Context) ([]ShardObject, error) {
+	prefix := s.shardPrefix()
+	var out []ShardObject
+	pages := s3.NewListObjectsV2Paginator(s.client, &s3.ListObjectsV2Input{
+		Bucket: &s.bucket,
+		Prefix: &prefix,
+	})
+	for pages.HasMorePages() {
+		page, err := pages.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("list shards: %w", err)
+		}
+		for _, obj := range page.Contents {
+			if obj.Key == nil {
+				continue
+			}
+			name, ok := strings.CutSuffix(strings.TrimPrefix(*obj.Key, prefix), ".json")
+			if !ok || name == "" || strings.Contains(name, "/") {
+				continue
+			}
+			shard := ShardObject{Shard: name}
+			if obj.LastModified != nil {
+				shard.LastModified = *obj.LastModified
+			}
+			out = append(out, shard)
+		}
+	}
+	return out, nil
+}
+
+// DeleteShard deletes the shard persisted by the replica named shard.
+// Deleting a shard that does not exist is not an error.
+func (s *S3Store) DeleteShard(ctx context.Context, shard string) error {
+	key := s.shardKey(shard)
+	if _, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: &s.bucket,
+		Key:    &key,
+	}); err != nil {
+		return fmt.Errorf("delete shard %s: %w", shard, err)
+	}
+	slog.Info("deleted shard of a former member", "bucket", s.bucket, "key", key)
+	return nil
+}
+
+// persist writes snap to the store's key and records the outcome.
+func (s *S3Store) persist(ctx context.Context, snap Snapshot) error {
 	s.persistMu.Lock()
 	defer s.persistMu.Unlock()
 
-	snap := Snapshot{
-		Claims:      s.mem.SnapshotClaims(),
-		XRs:         s.mem.SnapshotXRs(),
-		MRs:         s.mem.SnapshotMRs(),
-		PersistedAt: time.Now().UTC(),
+	snap.PersistedAt = time.Now().UTC()
+
+	data, err := encodeSnapshot(snap, s.keyring)
+	if err == nil {
+		_, err = s.client.PutObject(ctx, &s3.PutObjectInput{
+			Bucket:      &s.bucket,
+			Key:         &s.key,
+			Body:        bytes.NewReader(data),
+			ContentType: strPtr("application/json"),
+		})
 	}
 
-	data, err := json.Marshal(snap)
+	s.statusMu.Lock()
 	if err != nil {
-		return err
+		s.status.LastPersistError = err.Error()
+		s.status.LastPersistErrorAt = time.Now().UTC()
+	} else {
+		s.status.LastPersist = snap.PersistedAt
+		s.status.LastPersistError = ""
+		s.status.SnapshotAt = snap.PersistedAt
 	}
-
-	_, err = s.client.PutObject(ctx, &s3.PutObjectInput{
-		Bucket:      &s.bucket,
-		Key:         &s.key,
-		Body:        bytes.NewReader(data),
-		ContentType: strPtr("application/json"),
-	})
+	s.statusMu.Unlock()
 	if err != nil {
 		return err
 	}
@@ -109,6 +289,9 @@ func (s *S3Store) Persist(ctx context.Context) error {
 		"claims", len(snap.Claims),
 		"xrs", len(snap.XRs),
 		"mrs", len(snap.MRs),
+		"generation", snap.Generation,
+		"shard", snap.Shard,
+		"encrypted", s.keyring != nil,
 	)
 	return nil
 }
@@ -117,39 +300,70 @@ func (s *S3Store) Persist(ctx context.Context) error {
 // If the S3 key does not exist the store starts empty (no error).
 // Any other S3 error is returned so the caller can decide how to handle it.
 func (s *S3Store) Restore(ctx context.Context) error {
-	out, err := s.client.GetObject(ctx, &s3.GetObjectInput{
-		Bucket: &s.bucket,
-		Key:    &s.key,
-	})
-	if err != nil {
-		// NoSuchKey → start with empty store.
-		var noKey *types.NoSuchKey
-		if errors.As(err, &noKey) {
-			slog.Warn("no existing S3 snapshot found, starting with empty store",
-				"bucket", s.bucket,
-				"key", s.key,
-			)
-			return nil
+	snapshotAt, found, err := s.restore(ctx)
+	s.recordRestore(snapshotAt, found, err)
+	return err
+}
+
+// recordRestore records the outcome of Restore or RestoreShards in the
+// persistence status.
+func (s *S3Store) recordRestore(snapshotAt time.Time, found bool, err error) {
+	s.statusMu.Lock()
+	defer s.statusMu.Unlock()
+	s.status.RestoredAt = time.Now().UTC()
+	s.status.RestoreError = ""
+	switch {
+	case err != nil:
+		s.status.RestoreResult = RestoreFailed
+		s.status.RestoreError = err.Error()
+	case !found:
+		s.status.RestoreResult = RestoreNotFound
+	default:
+		s.status.RestoreResult = RestoreRestored
+		if s.status.SnapshotAt.IsZero() {
+			s.status.SnapshotAt = snapshotAt
 		}
-		return err
 	}
-	defer func() { _ = out.Body.Close() }()
+}
 
-	data, err := io.ReadAll(io.LimitReader(out.Body, maxSnapshotSize+1))
-	if err != nil {
-		return err
-	}
-	if len(data) > maxSnapshotSize {
-		return fmt.Errorf("S3 snapshot exceeds maximum allowed size of %d bytes", maxSnapshotSize)
+// PersistenceStatus reports the outcome of the last Persist and Restore
+// calls.
+func (s *S3Store) PersistenceStatus() PersistenceStatus {
+	s.statusMu.Lock()
+	st := s.status
+	s.statusMu.Unlock()
+
+	st.Backend = "s3"
+	st.Location = "s3://" + s.bucket + "/" + s.key
+	st.Encrypted = s.keyring != nil
+	if !st.SnapshotAt.IsZero() {
+		st.SnapshotAgeSeconds = time.Since(st.SnapshotAt).Seconds()
 	}
+	return st
+}
 
-	var snap Snapshot
-	if err := json.Unmarshal(data, &snap); err != nil {
-		return err
+// restore does the work of Restore. It returns when the restored snapshot
+// was persisted and whether one was found.
+func (s *S3Store) restore(ctx context.Context) (time.Time, bool, error) {
+	snap, found, err := s.get(ctx, s.key)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	if !found {
+		// NoSuchKey → start with empty store.
+		slog.Warn("no existing S3 snapshot found, starting with empty store",
+			"bucket", s.bucket,
+			"key", s.key,
+		)
+		return time.Time{}, false, nil
 	}
 
 	// Group claims by GVR and replay into MemoryStore so that
-	// per-GVR stale removal works correctly.
+	// per-GVR stale removal works correctly. The replay is staged as a
+	// single generation so readers never see a half-restored store.
+	s.mem.BeginGeneration()
+	defer s.mem.CommitGeneration()
+
 	claimsByGVR := make(map[string][]ClaimInfo)
 	for _, c := range snap.Claims {
 		claimsByGVR[c.GVR] = append(claimsByGVR[c.GVR], c)
@@ -174,15 +388,49 @@ func (s *S3Store) Restore(ctx context.Context) error {
 		s.mem.ReplaceMRs(gvr, items)
 	}
 
+	s.mem.restoreTombstones(snap)
+
 	slog.Info("restored store snapshot from S3",
 		"bucket", s.bucket,
 		"key", s.key,
 		"claims", len(snap.Claims),
 		"xrs", len(snap.XRs),
 		"mrs", len(snap.MRs),
+		"tombstones", len(snap.DeletedClaims)+len(snap.DeletedXRs)+len(snap.DeletedMRs),
 		"persistedAt", snap.PersistedAt,
 	)
-	return nil
+	return snap.PersistedAt, true, nil
+}
+
+// get reads and decodes the snapshot at key. It reports false, without an
+// error, when the key does not exist.
+func (s *S3Store) get(ctx context.Context, key string) (Snapshot, bool, error) {
+	out, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: &s.bucket,
+		Key:    &key,
+	})
+	if err != nil {
+		var noKey *types.NoSuchKey
+		if errors.As(err, &noKey) {
+			return Snapshot{}, false, nil
+		}
+		return Snapshot{}, false, err
+	}
+	defer func() { _ = out.Body.Close() }()
+
+	data, err := io.ReadAll(io.LimitReader(out.Body, maxSnapshotSize+1))
+	if err != nil {
+		return Snapshot{}, false, err
+	}
+	if len(data) > maxSnapshotSize {
+		return Snapshot{}, false, fmt.Errorf("S3 snapshot exceeds maximum allowed size of %d bytes", maxSnapshotSize)
+	}
+
+	snap, err := decodeSnapshot(data, s.keyring)
+	if err != nil {
+		return Snapshot{}, false, err
+	}
+	return snap, true, nil
 }
 
 // ---------------------------------------------------------------------------
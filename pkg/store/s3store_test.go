@@ -4,8 +4,10 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"io"
 	"sort"
+	"strings"
 	"testing"
 	"time"
 
@@ -20,6 +22,7 @@ import (
 var (
 	_ Store           = (*S3Store)(nil)
 	_ PersistentStore = (*S3Store)(nil)
+	_ ShardStore      = (*S3Store)(nil)
 )
 
 // ---------------------------------------------------------------------------
@@ -27,13 +30,14 @@ var (
 // ---------------------------------------------------------------------------
 
 type mockS3Client struct {
-	objects map[string][]byte // key → body
-	putErr  error
-	getErr  error
+	objects  map[string][]byte    // key → body
+	modified map[string]time.Time // key → last write
+	putErr   error
+	getErr   error
 }
 
 func newMockS3Client() *mockS3Client {
-	return &mockS3Client{objects: make(map[string][]byte)}
+	return &mockS3Client{objects: make(map[string][]byte), modified: make(map[string]time.Time)}
 }
 
 func (m *mockS3Client) PutObject(_ context.Context, input *s3.PutObjectInput, _ ...func(*s3.Options)) (*s3.PutObjectOutput, error) {
@@ -45,6 +49,7 @@ func (m *mockS3Client) PutObject(_ context.Context, input *s3.PutObjectInput, _
 		return nil, err
 	}
 	m.objects[*input.Key] = data
+	m.modified[*input.Key] = time.Now()
 	return &s3.PutObjectOutput{}, nil
 }
 
@@ -61,6 +66,23 @@ func (m *mockS3Client) GetObject(_ context.Context, input *s3.GetObjectInput, _
 	}, nil
 }
 
+func (m *mockS3Client) ListObjectsV2(_ context.Context, input *s3.ListObjectsV2Input, _ ...func(*s3.Options)) (*s3.ListObjectsV2Output, error) {
+	var out s3.ListObjectsV2Output
+	for key := range m.objects {
+		if strings.HasPrefix(key, *input.Prefix) {
+			modified := m.modified[key]
+			out.Contents = append(out.Contents, types.Object{Key: &key, LastModified: &modified})
+		}
+	}
+	return &out, nil
+}
+
+func (m *mockS3Client) DeleteObject(_ context.Context, input *s3.DeleteObjectInput, _ ...func(*s3.Options)) (*s3.DeleteObjectOutput, error) {
+	delete(m.objects, *input.Key)
+	delete(m.modified, *input.Key)
+	return &s3.DeleteObjectOutput{}, nil
+}
+
 // ---------------------------------------------------------------------------
 // Tests
 // ---------------------------------------------------------------------------
@@ -99,15 +121,15 @@ func TestS3Store_PersistAndRestore(t *testing.T) {
 		t.Fatalf("Restore failed: %v", err)
 	}
 
-	if ss2.ClaimCount() != 2 {
-		t.Errorf("expected 2 claims after restore, got %d", ss2.ClaimCount())
+	if ss2.View().ClaimCount() != 2 {
+		t.Errorf("expected 2 claims after restore, got %d", ss2.View().ClaimCount())
 	}
-	if ss2.XRCount() != 1 {
-		t.Errorf("expected 1 XR after restore, got %d", ss2.XRCount())
+	if ss2.View().XRCount() != 1 {
+		t.Errorf("expected 1 XR after restore, got %d", ss2.View().XRCount())
 	}
 
 	// Verify field fidelity.
-	claims := ss2.SnapshotClaims()
+	claims := ss2.View().Claims()
 	sort.Slice(claims, func(i, j int) bool { return claims[i].Name < claims[j].Name })
 	if claims[0].Namespace != "ns1" || claims[0].Name != "c1" || !claims[0].Ready {
 		t.Errorf("claim 0 field mismatch: %+v", claims[0])
@@ -116,12 +138,174 @@ func TestS3Store_PersistAndRestore(t *testing.T) {
 		t.Errorf("claim 1 field mismatch: %+v", claims[1])
 	}
 
-	xrs := ss2.SnapshotXRs()
+	xrs := ss2.View().XRs()
 	if xrs[0].Composition != "comp-a" || xrs[0].Name != "xr1" {
 		t.Errorf("XR 0 field mismatch: %+v", xrs[0])
 	}
 }
 
+func TestS3Store_PersistAndRestoreTombstones(t *testing.T) {
+	mem := New()
+	mem.SetTombstoneRetention(time.Hour)
+	mock := newMockS3Client()
+	ss := NewS3Store(mem, mock, "b", "p")
+
+	ss.ReplaceClaims("g/v1/r", []ClaimInfo{
+		{GVR: "g/v1/r", Namespace: "prod", Name: "db", Creator: "alice"},
+		{GVR: "g/v1/r", Namespace: "prod", Name: "back"},
+	})
+	ss.ReplaceClaims("g/v1/r", nil)
+
+	ctx := context.Background()
+	if err := ss.Persist(ctx); err != nil {
+		t.Fatalf("Persist: %v", err)
+	}
+
+	mem2 := New()
+	mem2.SetTombstoneRetention(time.Hour)
+	ss2 := NewS3Store(mem2, mock, "b", "p")
+	if err := ss2.Restore(ctx); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	if got := len(ss2.View().DeletedClaims(Query{})); got != 2 {
+		t.Fatalf("expected 2 restored tombstones, got %d", got)
+	}
+
+	// A restored tombstone is cleared once the object is seen again.
+	ss2.ReplaceClaims("g/v1/r", []ClaimInfo{{GVR: "g/v1/r", Namespace: "prod", Name: "back"}})
+	deleted := ss2.View().DeletedClaims(Query{})
+	if len(deleted) != 1 || deleted[0].Name != "db" || deleted[0].Creator != "alice" {
+		t.Errorf("unexpected tombstones after reappearance: %+v", deleted)
+	}
+}
+
+func TestS3Store_PersistAndLoadShard(t *testing.T) {
+	mock := newMockS3Client()
+	ss := NewS3Store(New(), mock, "my-bucket", "prefix")
+	ss.SetShard("exporter-0")
+
+	ss.ReplaceClaims("g1/v1/claims", []ClaimInfo{{GVR: "g1/v1/claims", Namespace: "ns1", Name: "c1"}})
+	ss.ReplaceXRs("g1/v1/xrs", []XRInfo{{GVR: "g1/v1/xrs", Name: "xr1"}})
+	ss.ReplaceMRs("aws/v1/buckets", []MRInfo{{GVR: "aws/v1/buckets", Name: "b1", XRName: "xr1"}})
+
+	if err := ss.PersistShard(context.Background(), []string{"g1/v1/xrs", "g1/v1/claims"}); err != nil {
+		t.Fatalf("PersistShard: %v", err)
+	}
+	if _, ok := mock.objects["prefix/shards/exporter-0.json"]; !ok {
+		t.Fatalf("expected the shard object, got keys %v", mock.objects)
+	}
+	if _, ok := mock.objects["prefix/snapshot.json"]; ok {
+		t.Error("expected the shared snapshot not to be written")
+	}
+
+	peer := NewS3Store(New(), mock, "my-bucket", "prefix")
+	peer.SetShard("exporter-1")
+	snap, found, err := peer.LoadShard(context.Background(), "exporter-0")
+	if err != nil || !found {
+		t.Fatalf("LoadShard: found=%v err=%v", found, err)
+	}
+	if snap.Shard != "exporter-0" || len(snap.GVRs) != 2 || snap.GVRs[0] != "g1/v1/claims" {
+		t.Errorf("unexpected shard header: shard=%q gvrs=%v", snap.Shard, snap.GVRs)
+	}
+	if len(snap.Claims) != 1 || len(snap.XRs) != 1 || len(snap.MRs) != 0 {
+		t.Errorf("expected only the owned GVRs, got claims=%d xrs=%d mrs=%d", len(snap.Claims), len(snap.XRs), len(snap.MRs))
+	}
+
+	if _, found, err := peer.LoadShard(context.Background(), "exporter-2"); err != nil || found {
+		t.Errorf("expected a missing shard to be reported as not found, got found=%v err=%v", found, err)
+	}
+
+}
+
+func TestS3Store_RestoreShards(t *testing.T) {
+	ctx := context.Background()
+	mock := newMockS3Client()
+
+	// exporter-0 owned both GVRs, then exporter-1 took over the XRs.
+	e0 := NewS3Store(New(), mock, "my-bucket", "prefix")
+	e0.SetShard("exporter-0")
+	e0.ReplaceClaims("g/v1/claims", []ClaimInfo{{GVR: "g/v1/claims", Namespace: "ns1", Name: "c1"}})
+	e0.ReplaceXRs("g/v1/xrs", []XRInfo{{GVR: "g/v1/xrs", Name: "old"}})
+	if err := e0.PersistShard(ctx, []string{"g/v1/claims", "g/v1/xrs"}); err != nil {
+		t.Fatalf("PersistShard: %v", err)
+	}
+	time.Sleep(time.Millisecond)
+	e1 := NewS3Store(New(), mock, "my-bucket", "prefix")
+	e1.SetShard("exporter-1")
+	e1.ReplaceXRs("g/v1/xrs", []XRInfo{{GVR: "g/v1/xrs", Name: "new"}})
+	if err := e1.PersistShard(ctx, []string{"g/v1/xrs"}); err != nil {
+		t.Fatalf("PersistShard: %v", err)
+	}
+	// exporter-2 left; its shard is not restored.
+	e2 := NewS3Store(New(), mock, "my-bucket", "prefix")
+	e2.SetShard("exporter-2")
+	e2.ReplaceMRs("aws/v1/buckets", []MRInfo{{GVR: "aws/v1/buckets", Name: "b1"}})
+	if err := e2.PersistShard(ctx, []string{"aws/v1/buckets"}); err != nil {
+		t.Fatalf("PersistShard: %v", err)
+	}
+
+	// exporter-3 starts under a new identity and restores the members'
+	// shards.
+	restarted := NewS3Store(New(), mock, "my-bucket", "prefix")
+	restarted.SetShard("exporter-3")
+	if err := restarted.RestoreShards(ctx, []string{"exporter-0", "exporter-1", "exporter-3"}); err != nil {
+		t.Fatalf("RestoreShards: %v", err)
+	}
+	if restarted.View().ClaimCount() != 1 || restarted.View().MRCount() != 0 {
+		t.Errorf("expected the members' shards only, got claims=%d mrs=%d", restarted.View().ClaimCount(), restarted.View().MRCount())
+	}
+	if xrs := restarted.View().XRs(); len(xrs) != 1 || xrs[0].Name != "new" {
+		t.Errorf("expected the newest shard to win, got %+v", xrs)
+	}
+	if st := restarted.PersistenceStatus(); st.RestoreResult != RestoreRestored || st.SnapshotAt.IsZero() {
+		t.Errorf("unexpected restore status: %+v", st)
+	}
+
+	empty := NewS3Store(New(), mock, "my-bucket", "other")
+	empty.SetShard("exporter-0")
+	if err := empty.RestoreShards(ctx, []string{"exporter-0"}); err != nil {
+		t.Fatalf("RestoreShards: %v", err)
+	}
+	if st := empty.PersistenceStatus(); st.RestoreResult != RestoreNotFound {
+		t.Errorf("expected not_found, got %q", st.RestoreResult)
+	}
+}
+
+func TestS3Store_ListAndDeleteShards(t *testing.T) {
+	ctx := context.Background()
+	mock := newMockS3Client()
+	mock.objects["prefix/snapshot.json"] = []byte("{}")
+	mock.objects["prefix/shards/nested/x.json"] = []byte("{}")
+	for _, id := range []string{"exporter-0", "exporter-1"} {
+		ss := NewS3Store(New(), mock, "my-bucket", "prefix")
+		ss.SetShard(id)
+		if err := ss.PersistShard(ctx, nil); err != nil {
+			t.Fatalf("PersistShard: %v", err)
+		}
+	}
+
+	ss := NewS3Store(New(), mock, "my-bucket", "prefix")
+	ss.SetShard("exporter-0")
+	objs, err := ss.ListShards(ctx)
+	if err != nil {
+		t.Fatalf("ListShards: %v", err)
+	}
+	sort.Slice(objs, func(i, j int) bool { return objs[i].Shard < objs[j].Shard })
+	if len(objs) != 2 || objs[0].Shard != "exporter-0" || objs[1].Shard != "exporter-1" || objs[1].LastModified.IsZero() {
+		t.Fatalf("unexpected shards: %+v", objs)
+	}
+
+	if err := ss.DeleteShard(ctx, "exporter-1"); err != nil {
+		t.Fatalf("DeleteShard: %v", err)
+	}
+	if _, ok := mock.objects["prefix/shards/exporter-1.json"]; ok {
+		t.Error("expected the shard to be deleted")
+	}
+	if _, ok := mock.objects["prefix/shards/exporter-0.json"]; !ok {
+		t.Error("expected the other shard to be kept")
+	}
+}
+
 func TestS3Store_RestoreEmpty(t *testing.T) {
 	mem := New()
 	mock := newMockS3Client() // no objects stored
@@ -131,11 +315,62 @@ func TestS3Store_RestoreEmpty(t *testing.T) {
 	if err := ss.Restore(ctx); err != nil {
 		t.Fatalf("Restore on missing key should not error, got: %v", err)
 	}
-	if ss.ClaimCount() != 0 {
-		t.Errorf("expected 0 claims, got %d", ss.ClaimCount())
+	if ss.View().ClaimCount() != 0 {
+		t.Errorf("expected 0 claims, got %d", ss.View().ClaimCount())
+	}
+	if ss.View().XRCount() != 0 {
+		t.Errorf("expected 0 XRs, got %d", ss.View().XRCount())
+	}
+}
+
+func TestS3Store_PersistenceStatus(t *testing.T) {
+	mock := newMockS3Client()
+	ss := NewS3Store(New(), mock, "my-bucket", "prefix")
+	ctx := context.Background()
+
+	if err := ss.Restore(ctx); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	st := ss.PersistenceStatus()
+	if st.Backend != "s3" || st.Location != "s3://my-bucket/prefix/snapshot.json" || st.RestoreResult != RestoreNotFound {
+		t.Errorf("unexpected status after restoring nothing: %+v", st)
+	}
+	if !st.SnapshotAt.IsZero() || !st.LastPersist.IsZero() {
+		t.Errorf("expected no snapshot yet: %+v", st)
+	}
+
+	mock.putErr = errors.New("access denied")
+	if err := ss.Persist(ctx); err == nil {
+		t.Fatal("expected Persist to fail")
+	}
+	if st := ss.PersistenceStatus(); st.LastPersistError != "access denied" || st.LastPersistErrorAt.IsZero() {
+		t.Errorf("expected the persist error to be reported: %+v", st)
+	}
+
+	mock.putErr = nil
+	if err := ss.Persist(ctx); err != nil {
+		t.Fatalf("Persist: %v", err)
+	}
+	st = ss.PersistenceStatus()
+	if st.LastPersistError != "" || st.LastPersist.IsZero() || !st.SnapshotAt.Equal(st.LastPersist) {
+		t.Errorf("unexpected status after persisting: %+v", st)
+	}
+
+	ss2 := NewS3Store(New(), mock, "my-bucket", "prefix")
+	if err := ss2.Restore(ctx); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	if st2 := ss2.PersistenceStatus(); st2.RestoreResult != RestoreRestored || !st2.SnapshotAt.Equal(st.SnapshotAt) {
+		t.Errorf("expected the restored snapshot time %v, got %+v", st.SnapshotAt, st2)
+	}
+
+	mock.getErr = errors.New("timeout")
+	ss3 := NewS3Store(New(), mock, "my-bucket", "prefix")
+	if err := ss3.Restore(ctx); err == nil {
+		t.Fatal("expected Restore to fail")
 	}
-	if ss.XRCount() != 0 {
-		t.Errorf("expected 0 XRs, got %d", ss.XRCount())
+	if st3 := ss3.PersistenceStatus(); st3.RestoreResult != RestoreFailed || st3.RestoreError != "timeout" {
+		t.Errorf("expected a failed restore, got %+v", st3)
 	}
 }
 
@@ -152,23 +387,23 @@ func TestS3Store_DelegatesAllMethods(t *testing.T) {
 		{GVR: "g/v/xr", Group: "g", Kind: "XK", Name: "xr1", Composition: "comp", CreatedAt: now},
 	})
 
-	if ss.ClaimCount() != 1 {
-		t.Errorf("ClaimCount: expected 1, got %d", ss.ClaimCount())
+	if ss.View().ClaimCount() != 1 {
+		t.Errorf("ClaimCount: expected 1, got %d", ss.View().ClaimCount())
 	}
-	if ss.XRCount() != 1 {
-		t.Errorf("XRCount: expected 1, got %d", ss.XRCount())
+	if ss.View().XRCount() != 1 {
+		t.Errorf("XRCount: expected 1, got %d", ss.View().XRCount())
 	}
 
 	// EnrichClaimCompositions should work through delegation.
 	ss.EnrichClaimCompositions()
-	claims := ss.SnapshotClaims()
+	claims := ss.View().Claims()
 	if claims[0].Composition != "comp" {
 		t.Errorf("EnrichClaimCompositions via delegation failed: got composition %q", claims[0].Composition)
 	}
 
 	// EnrichXRClaims should work through delegation.
 	ss.EnrichXRClaims()
-	xrs := ss.SnapshotXRs()
+	xrs := ss.View().XRs()
 	if xrs[0].ClaimName != "a" {
 		t.Errorf("EnrichXRClaims via delegation failed: got claim name %q", xrs[0].ClaimName)
 	}
@@ -243,20 +478,20 @@ func TestS3Store_RestoreMultiGVR(t *testing.T) {
 		t.Fatalf("Restore: %v", err)
 	}
 
-	if ss2.ClaimCount() != 2 {
-		t.Errorf("expected 2 claims, got %d", ss2.ClaimCount())
+	if ss2.View().ClaimCount() != 2 {
+		t.Errorf("expected 2 claims, got %d", ss2.View().ClaimCount())
 	}
-	if ss2.XRCount() != 2 {
-		t.Errorf("expected 2 XRs, got %d", ss2.XRCount())
+	if ss2.View().XRCount() != 2 {
+		t.Errorf("expected 2 XRs, got %d", ss2.View().XRCount())
 	}
 
 	// Now replace one GVR with empty — only that GVR's entries should be removed.
 	ss2.ReplaceClaims("g1/v1/r1", nil)
-	if ss2.ClaimCount() != 1 {
-		t.Errorf("expected 1 claim after removing g1/v1/r1, got %d", ss2.ClaimCount())
+	if ss2.View().ClaimCount() != 1 {
+		t.Errorf("expected 1 claim after removing g1/v1/r1, got %d", ss2.View().ClaimCount())
 	}
 
-	claims := ss2.SnapshotClaims()
+	claims := ss2.View().Claims()
 	if claims[0].GVR != "g2/v1/r2" {
 		t.Errorf("expected remaining claim to be g2/v1/r2, got %s", claims[0].GVR)
 	}
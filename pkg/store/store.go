@@ -69,21 +69,61 @@ type MRInfo struct {
 	DeletedAt          time.Time `json:"deletedAt,omitempty"` // metadata.deletionTimestamp, zero when not deleting
 }
 
+// DeletedClaim is a tombstone: the last known state of a claim that
+// disappeared from the cluster, and when the exporter noticed.
+type DeletedClaim struct {
+	ClaimInfo
+	RemovedAt time.Time `json:"removedAt"`
+}
+
+// DeletedXR is a tombstone for a composite resource.
+type DeletedXR struct {
+	XRInfo
+	RemovedAt time.Time `json:"removedAt"`
+}
+
+// DeletedMR is a tombstone for a managed resource.
+type DeletedMR struct {
+	MRInfo
+	RemovedAt time.Time `json:"removedAt"`
+}
+
 // Store is the interface for claim and XR metadata storage.
 // Implementations must be safe for concurrent use.
+//
+// The Replace methods replace a GVR's objects with a complete, successful
+// list and keep tombstones of the objects missing from it, which were
+// deleted. Objects that only left the polled scope, for example because
+// their namespace is no longer selected or their GVR is no longer tracked,
+// are removed with the Purge methods instead, which keep no tombstones.
+//
+// Writes made between BeginGeneration and CommitGeneration are staged in a
+// new generation that readers cannot see until it is committed, so every
+// read observes one consistent, fully enriched generation. Writes made
+// outside a generation are committed immediately.
 type Store interface {
+	BeginGeneration()
+	CommitGeneration() GenerationInfo
+	Generation() GenerationInfo
 	ReplaceClaims(gvr string, items []ClaimInfo)
 	ReplaceXRs(gvr string, items []XRInfo)
 	ReplaceMRs(gvr string, items []MRInfo)
+	ReplaceClaimsIn(gvr string, namespaces []string, items []ClaimInfo)
+	ReplaceXRsIn(gvr string, namespaces []string, items []XRInfo)
+	ReplaceMRsIn(gvr string, namespaces []string, items []MRInfo)
+	PurgeClaims(gvr string, keep []string)
+	PurgeXRs(gvr string, keep []string)
+	PurgeMRs(gvr string, keep []string)
 	EnrichClaimCompositions()
 	EnrichXRClaims()
 	EnrichMRClaims()
-	SnapshotClaims() []ClaimInfo
-	SnapshotXRs() []XRInfo
-	SnapshotMRs() []MRInfo
-	ClaimCount() int
-	XRCount() int
-	MRCount() int
+	MRsForXR(xrName string) []MRInfo
+	View() View
+	QueryClaims(q Query) (Page[ClaimInfo], error)
+	QueryXRs(q Query) (Page[XRInfo], error)
+	QueryMRs(q Query) (Page[MRInfo], error)
+	Snapshot() Snapshot
+	Changes() *ChangeFeed
 }
 
 // PersistentStore extends Store with durable persistence capabilities.
@@ -93,232 +133,348 @@ type PersistentStore interface {
 	Store
 	Persist(ctx context.Context) error
 	Restore(ctx context.Context) error
+	PersistenceStatus() PersistenceStatus
+}
+
+// ShardStore extends PersistentStore for sharded polling, where each replica
+// polls a share of the GVRs. A replica persists the GVRs it owns as its own
+// shard and loads its peers' shards to serve the complete inventory.
+// RestoreShards replaces Restore on startup, and the shards of replicas that
+// left are found with ListShards and removed with DeleteShard.
+type ShardStore interface {
+	PersistentStore
+	PersistShard(ctx context.Context, gvrs []string) error
+	LoadShard(ctx context.Context, shard string) (Snapshot, bool, error)
+	MergeShard(snap Snapshot, gvrs []string)
+	RestoreShards(ctx context.Context, members []string) error
+	ListShards(ctx context.Context) ([]ShardObject, error)
+	DeleteShard(ctx context.Context, shard string) error
+}
+
+// ShardObject describes a persisted shard.
+type ShardObject struct {
+	Shard        string    // identity of the replica that wrote it
+	LastModified time.Time // when it was last written
+}
+
+// Restore results reported in PersistenceStatus.
+const (
+	RestoreRestored = "restored"  // a snapshot was loaded
+	RestoreNotFound = "not_found" // no snapshot existed; started empty
+	RestoreFailed   = "failed"    // the snapshot could not be loaded; started empty
+)
+
+// PersistenceStatus describes the recent persist and restore operations of
+// a PersistentStore.
+type PersistenceStatus struct {
+	Backend            string    `json:"backend"`  // e.g. "s3"
+	Location           string    `json:"location"` // e.g. "s3://bucket/key"
+	Encrypted          bool      `json:"encrypted"`
+	LastPersist        time.Time `json:"lastPersist,omitzero"` // last successful persist
+	LastPersistError   string    `json:"lastPersistError,omitempty"`
+	LastPersistErrorAt time.Time `json:"lastPersistErrorAt,omitzero"`
+	RestoredAt         time.Time `json:"restoredAt,omitzero"`
+	RestoreResult      string    `json:"restoreResult,omitempty"` // RestoreRestored, RestoreNotFound or RestoreFailed
+	RestoreError       string    `json:"restoreError,omitempty"`
+	// SnapshotAt is when the newest snapshot written or restored was
+	// persisted, and SnapshotAgeSeconds how long ago that was.
+	SnapshotAt         time.Time `json:"snapshotAt,omitzero"`
+	SnapshotAgeSeconds float64   `json:"snapshotAgeSeconds,omitempty"`
+}
+
+// GenerationInfo identifies a committed store generation.
+type GenerationInfo struct {
+	Number      uint64    `json:"number"`      // increases by one with every commit
+	CommittedAt time.Time `json:"committedAt"` // zero until the first commit
 }
 
 // Snapshot is the serialisation envelope for persisting store state.
 // All PersistentStore implementations should use this struct to ensure
 // a consistent format across backends.
 type Snapshot struct {
-	Claims      []ClaimInfo `json:"claims"`
-	XRs         []XRInfo    `json:"xrs"`
-	MRs         []MRInfo    `json:"mrs,omitempty"`
-	PersistedAt time.Time   `json:"persistedAt"`
+	Claims        []ClaimInfo    `json:"claims"`
+	XRs           []XRInfo       `json:"xrs"`
+	MRs           []MRInfo       `json:"mrs,omitempty"`
+	DeletedClaims []DeletedClaim `json:"deletedClaims,omitempty"`
+	DeletedXRs    []DeletedXR    `json:"deletedXrs,omitempty"`
+	DeletedMRs    []DeletedMR    `json:"deletedMrs,omitempty"`
+	Generation    uint64         `json:"generation,omitempty"`
+	CommittedAt   time.Time      `json:"committedAt,omitempty"`
+	PersistedAt   time.Time      `json:"persistedAt"`
+
+	// Shard and GVRs are set on a shard snapshot: the identity of the
+	// replica that wrote it and the GVRs it owned.
+	Shard string   `json:"shard,omitempty"`
+	GVRs  []string `json:"gvrs,omitempty"`
+}
+
+// forGVRs returns the part of snap, objects and tombstones, that belongs to
+// the given GVRs.
+func (snap Snapshot) forGVRs(gvrs []string) Snapshot {
+	keep := make(map[string]bool, len(gvrs))
+	for _, gvr := range gvrs {
+		keep[gvr] = true
+	}
+	out := snap
+	out.Claims = filterGVR(snap.Claims, func(c ClaimInfo) string { return c.GVR }, keep)
+	out.XRs = filterGVR(snap.XRs, func(x XRInfo) string { return x.GVR }, keep)
+	out.MRs = filterGVR(snap.MRs, func(m MRInfo) string { return m.GVR }, keep)
+	out.DeletedClaims = filterGVR(snap.DeletedClaims, func(d DeletedClaim) string { return d.GVR }, keep)
+	out.DeletedXRs = filterGVR(snap.DeletedXRs, func(d DeletedXR) string { return d.GVR }, keep)
+	out.DeletedMRs = filterGVR(snap.DeletedMRs, func(d DeletedMR) string { return d.GVR }, keep)
+	return out
+}
+
+func filterGVR[T any](items []T, gvr func(T) string, keep map[string]bool) []T {
+	out := make([]T, 0, len(items))
+	for _, item := range items {
+		if keep[gvr(item)] {
+			out = append(out, item)
+		}
+	}
+	return out
 }
 
 // MemoryStore is a thread-safe in-memory implementation of Store.
 // All public methods are safe for concurrent use.
+//
+// State is held in immutable generations. Readers take the current
+// generation under a brief read lock and never observe a partially applied
+// poll cycle; writers mutate a private staging copy that is swapped in by
+// CommitGeneration. The copy is made on write: it shares the objects it
+// does not change with the current generation.
+//
+// Objects removed by a Replace call are kept as tombstones until they are
+// older than the tombstone retention (see SetTombstoneRetention). Purged
+// objects are not.
+//
+// When a change buffer is configured (see SetChangeBufferSize), every
+// published generation is diffed against its predecessor and the resulting
+// changes are appended to the store's ChangeFeed.
 type MemoryStore struct {
-	mu     sync.RWMutex
-	claims map[string]ClaimInfo // keyed by "namespace/name"
-	xrs    map[string]XRInfo    // keyed by "namespace/name" (or just "name" for cluster-scoped)
-	mrs    map[string]MRInfo    // keyed by "namespace/name"
+	mu        sync.RWMutex
+	current   *generation // published; never mutated
+	next      *generation // staging; nil outside BeginGeneration/CommitGeneration
+	retention time.Duration
+	changes   *ChangeFeed
 }
 
 // New creates a new empty MemoryStore.
 func New() *MemoryStore {
-	return &MemoryStore{
-		claims: make(map[string]ClaimInfo),
-		xrs:    make(map[string]XRInfo),
-		mrs:    make(map[string]MRInfo),
-	}
+	return &MemoryStore{current: newGeneration(), changes: NewChangeFeed(0)}
 }
 
-// ReplaceClaims atomically replaces the stored claims for a given GVR.
-// The gvr string identifies which GVR produced these items (e.g. "group/version/resource").
-// Items belonging to this GVR that are no longer present are removed.
-// Items from other GVRs are left untouched.
-func (s *MemoryStore) ReplaceClaims(gvr string, items []ClaimInfo) {
-	newKeys := make(map[string]struct{}, len(items))
+// SetTombstoneRetention sets how long removed objects are kept as
+// tombstones. Expired tombstones are pruned whenever a generation is
+// published. Zero (the default) disables tombstones.
+func (s *MemoryStore) SetTombstoneRetention(d time.Duration) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	s.retention = d
+}
 
-	// Add/update incoming items.
-	for _, c := range items {
-		key := objectKey(c.Namespace, c.Name)
-		newKeys[key] = struct{}{}
-		s.claims[key] = c
-	}
-
-	// Remove stale entries belonging to this GVR.
-	for key, existing := range s.claims {
-		if existing.GVR != gvr {
-			continue
-		}
-		if _, ok := newKeys[key]; !ok {
-			delete(s.claims, key)
-		}
-	}
+// SetChangeBufferSize sets how many changes the change feed keeps. Zero (the
+// default) disables the feed. It replaces the feed, so call it before
+// handing out Changes.
+func (s *MemoryStore) SetChangeBufferSize(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.changes = NewChangeFeed(n)
 }
 
-// ReplaceXRs atomically replaces the stored XRs for a given GVR.
-func (s *MemoryStore) ReplaceXRs(gvr string, items []XRInfo) {
-	newKeys := make(map[string]struct{}, len(items))
+// BeginGeneration starts staging a new generation as a copy of the current
+// one. Subsequent writes go to the staged generation until CommitGeneration.
+// Calling BeginGeneration again before committing discards the staged writes.
+func (s *MemoryStore) BeginGeneration() {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	s.next = s.current.clone()
+}
 
-	for _, x := range items {
-		key := objectKey(x.Namespace, x.Name)
-		newKeys[key] = struct{}{}
-		s.xrs[key] = x
+// CommitGeneration atomically publishes the staged generation and returns its
+// identity. Without a preceding BeginGeneration it is a no-op that returns
+// the current generation.
+func (s *MemoryStore) CommitGeneration() GenerationInfo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.next == nil {
+		return s.current.info()
 	}
+	s.publish(s.next)
+	s.next = nil
+	return s.current.info()
+}
 
-	for key, existing := range s.xrs {
-		if existing.GVR != gvr {
-			continue
-		}
-		if _, ok := newKeys[key]; !ok {
-			delete(s.xrs, key)
-		}
-	}
+// Generation returns the identity of the currently published generation.
+func (s *MemoryStore) Generation() GenerationInfo {
+	return s.read().info()
+}
+
+// ReplaceClaims atomically replaces the stored claims for a given GVR.
+// The gvr string identifies which GVR produced these items (e.g. "group/version/resource").
+// Items belonging to this GVR that are no longer present are removed.
+// Items from other GVRs are left untouched.
+func (s *MemoryStore) ReplaceClaims(gvr string, items []ClaimInfo) {
+	s.write(func(g *generation) { g.replaceClaims(gvr, items, listed) })
+}
+
+// ReplaceXRs atomically replaces the stored XRs for a given GVR.
+func (s *MemoryStore) ReplaceXRs(gvr string, items []XRInfo) {
+	s.write(func(g *generation) { g.replaceXRs(gvr, items, listed) })
 }
 
 // ReplaceMRs atomically replaces the stored MRs for a given GVR.
 func (s *MemoryStore) ReplaceMRs(gvr string, items []MRInfo) {
-	newKeys := make(map[string]struct{}, len(items))
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	s.write(func(g *generation) { g.replaceMRs(gvr, items, listed) })
+}
 
-	for _, m := range items {
-		key := objectKey(m.Namespace, m.Name)
-		newKeys[key] = struct{}{}
-		s.mrs[key] = m
-	}
+// ReplaceClaimsIn replaces the stored claims of a GVR in the given
+// namespaces with items, listed from those namespaces. Claims in other
+// namespaces are left untouched.
+func (s *MemoryStore) ReplaceClaimsIn(gvr string, namespaces []string, items []ClaimInfo) {
+	s.write(func(g *generation) { g.replaceClaims(gvr, items, listedIn(namespaces)) })
+}
 
-	for key, existing := range s.mrs {
-		if existing.GVR != gvr {
-			continue
-		}
-		if _, ok := newKeys[key]; !ok {
-			delete(s.mrs, key)
-		}
-	}
+// ReplaceXRsIn replaces the stored XRs of a GVR in the given namespaces.
+func (s *MemoryStore) ReplaceXRsIn(gvr string, namespaces []string, items []XRInfo) {
+	s.write(func(g *generation) { g.replaceXRs(gvr, items, listedIn(namespaces)) })
+}
+
+// ReplaceMRsIn replaces the stored MRs of a GVR in the given namespaces.
+func (s *MemoryStore) ReplaceMRsIn(gvr string, namespaces []string, items []MRInfo) {
+	s.write(func(g *generation) { g.replaceMRs(gvr, items, listedIn(namespaces)) })
+}
+
+// PurgeClaims removes the stored claims of a GVR outside the namespaces in
+// keep, or all of them when keep is empty, without keeping tombstones.
+func (s *MemoryStore) PurgeClaims(gvr string, keep []string) {
+	s.write(func(g *generation) { g.replaceClaims(gvr, nil, outside(keep)) })
+}
+
+// PurgeXRs removes the stored XRs of a GVR outside the namespaces in keep.
+func (s *MemoryStore) PurgeXRs(gvr string, keep []string) {
+	s.write(func(g *generation) { g.replaceXRs(gvr, nil, outside(keep)) })
+}
+
+// PurgeMRs removes the stored MRs of a GVR outside the namespaces in keep.
+func (s *MemoryStore) PurgeMRs(gvr string, keep []string) {
+	s.write(func(g *generation) { g.replaceMRs(gvr, nil, outside(keep)) })
 }
 
 // EnrichXRClaims looks up each XR without claim labels in the claim store and
 // copies ClaimName and ClaimNS from the claim whose spec.resourceRef.name
 // matches the XR name. Label-derived values are not overwritten. Must be
 // called after both claims and XRs have been replaced for the current polling
-// cycle. If multiple claims reference the same XR, the first match wins.
+// cycle. If multiple claims reference the same XR, the claim with the
+// lexically smallest "namespace/name" key wins.
 func (s *MemoryStore) EnrichXRClaims() {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-
-	for key, xr := range s.xrs {
-		if xr.ClaimName != "" {
-			continue
-		}
-		for _, claim := range s.claims {
-			if claim.XRRef == xr.Name {
-				xr.ClaimName = claim.Name
-				xr.ClaimNS = claim.Namespace
-				s.xrs[key] = xr
-				break
-			}
-		}
-	}
+	s.write(func(g *generation) { g.enrichXRClaims() })
 }
 
 // EnrichClaimCompositions looks up each claim's XRRef in the XR store and
 // copies the Composition value. Must be called after both claims and XRs
 // have been replaced for the current polling cycle.
 func (s *MemoryStore) EnrichClaimCompositions() {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-
-	for key, claim := range s.claims {
-		if claim.XRRef == "" {
-			continue
-		}
-		// XRs are cluster-scoped, so look up by name only.
-		if xr, ok := s.xrs[claim.XRRef]; ok {
-			claim.Composition = xr.Composition
-			s.claims[key] = claim
-		}
-	}
+	s.write(func(g *generation) { g.enrichClaimCompositions() })
 }
 
 // EnrichMRClaims copies claim linkage onto MRs from the backing XR store when
 // claim fields are not already set from MR labels. Must be called after
 // claims, XRs, and MRs have been replaced for the current polling cycle.
 func (s *MemoryStore) EnrichMRClaims() {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-
-	for key, mr := range s.mrs {
-		if mr.ClaimName != "" {
-			continue
-		}
-		if mr.XRName == "" {
-			continue
-		}
-		if xr, ok := s.xrs[mr.XRName]; ok {
-			mr.ClaimName = xr.ClaimName
-			mr.ClaimNS = xr.ClaimNS
-			s.mrs[key] = mr
-		}
-	}
+	s.write(func(g *generation) { g.enrichMRClaims() })
 }
 
-// SnapshotClaims returns a copy of all stored claims.
-func (s *MemoryStore) SnapshotClaims() []ClaimInfo {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-	out := make([]ClaimInfo, 0, len(s.claims))
-	for _, c := range s.claims {
-		out = append(out, c)
+// MRsForXR returns a copy of all MRs whose composite label names xrName.
+func (s *MemoryStore) MRsForXR(xrName string) []MRInfo {
+	g := s.read()
+	keys := g.mrsByXR.keys(xrName)
+	out := make([]MRInfo, 0, len(keys))
+	for key := range keys {
+		m, _ := g.mrs.get(key)
+		out = append(out, m)
 	}
 	return out
 }
 
-// SnapshotXRs returns a copy of all stored XRs.
-func (s *MemoryStore) SnapshotXRs() []XRInfo {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-	out := make([]XRInfo, 0, len(s.xrs))
-	for _, x := range s.xrs {
-		out = append(out, x)
+// Snapshot returns a copy of all claims, XRs and MRs from a single
+// generation, together with that generation's identity.
+func (s *MemoryStore) Snapshot() Snapshot {
+	g := s.read()
+	return Snapshot{
+		Claims:        values(&g.claims),
+		XRs:           values(&g.xrs),
+		MRs:           values(&g.mrs),
+		DeletedClaims: values(&g.deletedClaims),
+		DeletedXRs:    values(&g.deletedXRs),
+		DeletedMRs:    values(&g.deletedMRs),
+		Generation:    g.number,
+		CommittedAt:   g.committedAt,
 	}
-	return out
 }
 
-// SnapshotMRs returns a copy of all stored MRs.
-func (s *MemoryStore) SnapshotMRs() []MRInfo {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-	out := make([]MRInfo, 0, len(s.mrs))
-	for _, m := range s.mrs {
-		out = append(out, m)
-	}
-	return out
+// restoreTombstones loads the tombstones of a persisted snapshot. Tombstones
+// for objects that are present again are ignored.
+func (s *MemoryStore) restoreTombstones(snap Snapshot) {
+	s.write(func(g *generation) { g.restoreTombstones(snap) })
 }
 
-// ClaimCount returns the total number of stored claims.
-func (s *MemoryStore) ClaimCount() int {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-	return len(s.claims)
+// MergeShard copies the objects and tombstones of gvrs from snap, a peer's
+// shard in sharded polling. Objects of gvrs missing from snap are removed
+// without a tombstone, since the peer keeps tombstones of the ones that
+// were deleted.
+func (s *MemoryStore) MergeShard(snap Snapshot, gvrs []string) {
+	s.write(func(g *generation) { g.mergeShard(snap, gvrs) })
 }
 
-// XRCount returns the total number of stored XRs.
-func (s *MemoryStore) XRCount() int {
+// Changes returns the change feed.
+func (s *MemoryStore) Changes() *ChangeFeed {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
-	return len(s.xrs)
+	return s.changes
 }
 
-// MRCount returns the total number of stored MRs.
-func (s *MemoryStore) MRCount() int {
+// read returns the current published generation. The returned generation is
+// immutable, so callers may use it without holding the lock.
+func (s *MemoryStore) read() *generation {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
-	return len(s.mrs)
+	return s.current
+}
+
+// write applies fn to the staged generation, or — outside
+// BeginGeneration/CommitGeneration — to a copy of the current generation
+// that is published immediately. Copies share everything fn does not
+// change with the current generation.
+func (s *MemoryStore) write(fn func(g *generation)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.next != nil {
+		fn(s.next)
+		return
+	}
+	g := s.current.clone()
+	fn(g)
+	s.publish(g)
 }
 
-// objectKey produces a map key from a namespace and name.
-// For cluster-scoped resources (empty namespace) the key is just the name.
-// For namespaced resources the key is "namespace/name".
-func objectKey(namespace, name string) string {
-	if namespace == "" {
-		return name
+// publish makes g the current generation, pruning expired tombstones and
+// recording the changes from the previous generation. Callers must hold s.mu.
+func (s *MemoryStore) publish(g *generation) {
+	g.number = s.current.number + 1
+	g.committedAt = time.Now().UTC()
+	g.pruneTombstones(g.committedAt.Add(-s.retention))
+	if s.changes.Enabled() {
+		s.changes.append(diffGenerations(s.current, g))
 	}
-	return namespace + "/" + name
+	s.current = g
+}
+
+// values returns the values of m as a newly allocated slice.
+func values[T any](m *cowMap[T]) []T {
+	out := make([]T, 0, m.len())
+	for _, v := range m.all() {
+		out = append(out, v)
+	}
+	return out
 }
@@ -1,6 +1,7 @@
 package store
 
 import (
+	"fmt"
 	"sort"
 	"sync"
 	"testing"
@@ -9,11 +10,11 @@ import (
 
 func TestNew(t *testing.T) {
 	s := New()
-	if s.ClaimCount() != 0 {
-		t.Errorf("expected 0 claims, got %d", s.ClaimCount())
+	if s.View().ClaimCount() != 0 {
+		t.Errorf("expected 0 claims, got %d", s.View().ClaimCount())
 	}
-	if s.XRCount() != 0 {
-		t.Errorf("expected 0 XRs, got %d", s.XRCount())
+	if s.View().XRCount() != 0 {
+		t.Errorf("expected 0 XRs, got %d", s.View().XRCount())
 	}
 }
 
@@ -27,11 +28,11 @@ func TestReplaceClaims(t *testing.T) {
 	}
 	s.ReplaceClaims("g1/v1/k1s", claims)
 
-	if s.ClaimCount() != 3 {
-		t.Fatalf("expected 3 claims, got %d", s.ClaimCount())
+	if s.View().ClaimCount() != 3 {
+		t.Fatalf("expected 3 claims, got %d", s.View().ClaimCount())
 	}
 
-	snap := s.SnapshotClaims()
+	snap := s.View().Claims()
 	if len(snap) != 3 {
 		t.Fatalf("expected 3 in snapshot, got %d", len(snap))
 	}
@@ -44,16 +45,16 @@ func TestReplaceClaims_RemovesStale(t *testing.T) {
 		{GVR: "g1/v1/k1s", Group: "g1", Kind: "K1", Namespace: "ns1", Name: "a"},
 		{GVR: "g1/v1/k1s", Group: "g1", Kind: "K1", Namespace: "ns1", Name: "b"},
 	})
-	if s.ClaimCount() != 2 {
-		t.Fatalf("expected 2, got %d", s.ClaimCount())
+	if s.View().ClaimCount() != 2 {
+		t.Fatalf("expected 2, got %d", s.View().ClaimCount())
 	}
 
 	// Replace with smaller set — "b" should be removed.
 	s.ReplaceClaims("g1/v1/k1s", []ClaimInfo{
 		{GVR: "g1/v1/k1s", Group: "g1", Kind: "K1", Namespace: "ns1", Name: "a"},
 	})
-	if s.ClaimCount() != 1 {
-		t.Fatalf("expected 1 after replace, got %d", s.ClaimCount())
+	if s.View().ClaimCount() != 1 {
+		t.Fatalf("expected 1 after replace, got %d", s.View().ClaimCount())
 	}
 }
 
@@ -67,17 +68,17 @@ func TestReplaceClaims_DifferentGVRs(t *testing.T) {
 		{GVR: "g1/v1/k2s", Group: "g1", Kind: "K2", Namespace: "ns1", Name: "x"},
 	})
 
-	if s.ClaimCount() != 2 {
-		t.Fatalf("expected 2 claims across GVRs, got %d", s.ClaimCount())
+	if s.View().ClaimCount() != 2 {
+		t.Fatalf("expected 2 claims across GVRs, got %d", s.View().ClaimCount())
 	}
 
 	// Replacing one GVR with empty should only remove that GVR's entries.
 	s.ReplaceClaims("g1/v1/k1s", nil)
-	if s.ClaimCount() != 1 {
-		t.Fatalf("expected 1 claim after removing g1/v1/k1s, got %d", s.ClaimCount())
+	if s.View().ClaimCount() != 1 {
+		t.Fatalf("expected 1 claim after removing g1/v1/k1s, got %d", s.View().ClaimCount())
 	}
 
-	snap := s.SnapshotClaims()
+	snap := s.View().Claims()
 	if snap[0].Kind != "K2" {
 		t.Errorf("expected K2, got %s", snap[0].Kind)
 	}
@@ -92,11 +93,11 @@ func TestReplaceXRs(t *testing.T) {
 	}
 	s.ReplaceXRs("g1/v1/xk1s", xrs)
 
-	if s.XRCount() != 2 {
-		t.Fatalf("expected 2 XRs, got %d", s.XRCount())
+	if s.View().XRCount() != 2 {
+		t.Fatalf("expected 2 XRs, got %d", s.View().XRCount())
 	}
 
-	snap := s.SnapshotXRs()
+	snap := s.View().XRs()
 	if len(snap) != 2 {
 		t.Fatalf("expected 2 in snapshot, got %d", len(snap))
 	}
@@ -113,8 +114,8 @@ func TestReplaceXRs_RemovesStale(t *testing.T) {
 		{GVR: "g1/v1/xk1s", Group: "g1", Kind: "XK1", Name: "xr1"},
 	})
 
-	if s.XRCount() != 1 {
-		t.Fatalf("expected 1 XR after replace, got %d", s.XRCount())
+	if s.View().XRCount() != 1 {
+		t.Fatalf("expected 1 XR after replace, got %d", s.View().XRCount())
 	}
 }
 
@@ -137,7 +138,7 @@ func TestEnrichClaimCompositions(t *testing.T) {
 
 	s.EnrichClaimCompositions()
 
-	snap := s.SnapshotClaims()
+	snap := s.View().Claims()
 	byName := make(map[string]ClaimInfo)
 	for _, c := range snap {
 		byName[c.Name] = c
@@ -175,7 +176,7 @@ func TestEnrichXRClaims(t *testing.T) {
 
 	s.EnrichXRClaims()
 
-	snap := s.SnapshotXRs()
+	snap := s.View().XRs()
 	byName := make(map[string]XRInfo)
 	for _, x := range snap {
 		byName[x.Name] = x
@@ -203,8 +204,8 @@ func TestReplaceMRs(t *testing.T) {
 	s.ReplaceMRs("nop.crossplane.io/v1alpha1/nopresources", []MRInfo{
 		{GVR: "nop.crossplane.io/v1alpha1/nopresources", Group: "nop.crossplane.io", Kind: "NopResource", Namespace: "default", Name: "nop-1", XRName: "xr-1"},
 	})
-	if s.MRCount() != 1 {
-		t.Fatalf("expected 1 MR, got %d", s.MRCount())
+	if s.View().MRCount() != 1 {
+		t.Fatalf("expected 1 MR, got %d", s.View().MRCount())
 	}
 }
 
@@ -224,7 +225,7 @@ func TestEnrichMRClaims(t *testing.T) {
 
 	s.EnrichMRClaims()
 
-	snap := s.SnapshotMRs()
+	snap := s.View().MRs()
 	byName := make(map[string]MRInfo)
 	for _, m := range snap {
 		byName[m.Name] = m
@@ -241,16 +242,440 @@ func TestEnrichMRClaims(t *testing.T) {
 	}
 }
 
+func TestEnrichXRClaims_IndexFollowsReplace(t *testing.T) {
+	s := New()
+
+	s.ReplaceXRs("g1/v1/xwidgets", []XRInfo{
+		{GVR: "g1/v1/xwidgets", Group: "g1", Kind: "XWidget", Name: "xr-1"},
+		{GVR: "g1/v1/xwidgets", Group: "g1", Kind: "XWidget", Name: "xr-2"},
+	})
+	s.ReplaceClaims("g1/v1/widgets", []ClaimInfo{
+		{GVR: "g1/v1/widgets", Group: "g1", Kind: "Widget", Namespace: "ns1", Name: "w", XRRef: "xr-1"},
+	})
+
+	// Re-point the claim at a different XR; the old index entry must go.
+	s.ReplaceClaims("g1/v1/widgets", []ClaimInfo{
+		{GVR: "g1/v1/widgets", Group: "g1", Kind: "Widget", Namespace: "ns1", Name: "w", XRRef: "xr-2"},
+	})
+	s.EnrichXRClaims()
+
+	byName := make(map[string]XRInfo)
+	for _, x := range s.View().XRs() {
+		byName[x.Name] = x
+	}
+	if byName["xr-1"].ClaimName != "" {
+		t.Errorf("xr-1: expected no claim after re-pointing, got %q", byName["xr-1"].ClaimName)
+	}
+	if byName["xr-2"].ClaimName != "w" {
+		t.Errorf("xr-2: expected claim w, got %q", byName["xr-2"].ClaimName)
+	}
+
+	// Removing the claim must also drop it from the index.
+	s.ReplaceClaims("g1/v1/widgets", nil)
+	if s.current.claimsByXRRef.len() != 0 {
+		t.Errorf("expected empty claim index after removal, got %v", s.current.claimsByXRRef)
+	}
+}
+
+func TestEnrichXRClaims_DeterministicWhenShared(t *testing.T) {
+	s := New()
+	s.ReplaceXRs("g1/v1/xwidgets", []XRInfo{
+		{GVR: "g1/v1/xwidgets", Group: "g1", Kind: "XWidget", Name: "xr-shared"},
+	})
+	s.ReplaceClaims("g1/v1/widgets", []ClaimInfo{
+		{GVR: "g1/v1/widgets", Group: "g1", Kind: "Widget", Namespace: "ns-b", Name: "w", XRRef: "xr-shared"},
+		{GVR: "g1/v1/widgets", Group: "g1", Kind: "Widget", Namespace: "ns-a", Name: "w", XRRef: "xr-shared"},
+	})
+	s.EnrichXRClaims()
+
+	xr := s.View().XRs()[0]
+	if xr.ClaimNS != "ns-a" {
+		t.Errorf("expected lexically first claim ns-a/w to win, got %s/%s", xr.ClaimNS, xr.ClaimName)
+	}
+}
+
+func TestEnrichMRClaims_NamespacedXR(t *testing.T) {
+	s := New()
+	s.ReplaceXRs("g1/v2/xwidgets", []XRInfo{
+		{GVR: "g1/v2/xwidgets", Group: "g1", Kind: "XWidget", Namespace: "team-a", Name: "xr", ClaimName: "claim-a", ClaimNS: "team-a"},
+		{GVR: "g1/v2/xwidgets", Group: "g1", Kind: "XWidget", Namespace: "team-b", Name: "xr", ClaimName: "claim-b", ClaimNS: "team-b"},
+	})
+	s.ReplaceMRs("nop.crossplane.io/v1alpha1/nopresources", []MRInfo{
+		{GVR: "nop.crossplane.io/v1alpha1/nopresources", Group: "nop.crossplane.io", Kind: "NopResource", Namespace: "team-b", Name: "nop", XRName: "xr"},
+	})
+	s.EnrichMRClaims()
+
+	mr := s.View().MRs()[0]
+	if mr.ClaimName != "claim-b" {
+		t.Errorf("expected MR linked to XR in its own namespace, got claim %q", mr.ClaimName)
+	}
+}
+
+func TestMRsForXR(t *testing.T) {
+	s := New()
+	s.ReplaceMRs("nop.crossplane.io/v1alpha1/nopresources", []MRInfo{
+		{GVR: "nop.crossplane.io/v1alpha1/nopresources", Name: "nop-1", XRName: "xr-a"},
+		{GVR: "nop.crossplane.io/v1alpha1/nopresources", Name: "nop-2", XRName: "xr-a"},
+		{GVR: "nop.crossplane.io/v1alpha1/nopresources", Name: "nop-3", XRName: "xr-b"},
+	})
+	if got := len(s.MRsForXR("xr-a")); got != 2 {
+		t.Errorf("expected 2 MRs for xr-a, got %d", got)
+	}
+
+	// Moving an MR to another XR updates the index.
+	s.ReplaceMRs("nop.crossplane.io/v1alpha1/nopresources", []MRInfo{
+		{GVR: "nop.crossplane.io/v1alpha1/nopresources", Name: "nop-1", XRName: "xr-a"},
+		{GVR: "nop.crossplane.io/v1alpha1/nopresources", Name: "nop-2", XRName: "xr-b"},
+	})
+	if got := len(s.MRsForXR("xr-a")); got != 1 {
+		t.Errorf("expected 1 MR for xr-a after move, got %d", got)
+	}
+	if got := len(s.MRsForXR("xr-b")); got != 1 {
+		t.Errorf("expected 1 MR for xr-b after move and removal, got %d", got)
+	}
+	if got := len(s.MRsForXR("missing")); got != 0 {
+		t.Errorf("expected no MRs for unknown XR, got %d", got)
+	}
+}
+
+func TestGeneration_StagedWritesInvisibleUntilCommit(t *testing.T) {
+	s := New()
+	s.ReplaceXRs("g1/v1/xwidgets", []XRInfo{
+		{GVR: "g1/v1/xwidgets", Group: "g1", Kind: "XWidget", Name: "xr-old", Composition: "comp-old"},
+	})
+	before := s.Generation()
+
+	s.BeginGeneration()
+	s.ReplaceXRs("g1/v1/xwidgets", []XRInfo{
+		{GVR: "g1/v1/xwidgets", Group: "g1", Kind: "XWidget", Name: "xr-new", Composition: "comp-new"},
+	})
+	s.ReplaceClaims("g1/v1/widgets", []ClaimInfo{
+		{GVR: "g1/v1/widgets", Group: "g1", Kind: "Widget", Namespace: "ns", Name: "w", XRRef: "xr-new"},
+	})
+	s.EnrichClaimCompositions()
+
+	// Mid-cycle readers still see the previous generation in full.
+	if got := s.Generation(); got != before {
+		t.Errorf("generation changed before commit: %+v -> %+v", before, got)
+	}
+	if s.View().ClaimCount() != 0 {
+		t.Errorf("expected staged claim to be invisible, got %d claims", s.View().ClaimCount())
+	}
+	if xrs := s.View().XRs(); len(xrs) != 1 || xrs[0].Name != "xr-old" {
+		t.Errorf("expected previous XR set, got %+v", xrs)
+	}
+
+	info := s.CommitGeneration()
+	if info.Number != before.Number+1 {
+		t.Errorf("expected generation %d, got %d", before.Number+1, info.Number)
+	}
+	if info.CommittedAt.IsZero() {
+		t.Error("expected non-zero commit time")
+	}
+
+	snap := s.Snapshot()
+	if snap.Generation != info.Number {
+		t.Errorf("snapshot generation %d, want %d", snap.Generation, info.Number)
+	}
+	if len(snap.Claims) != 1 || snap.Claims[0].Composition != "comp-new" {
+		t.Errorf("expected committed claim to be enriched, got %+v", snap.Claims)
+	}
+	if len(snap.XRs) != 1 || snap.XRs[0].Name != "xr-new" {
+		t.Errorf("expected committed XR set, got %+v", snap.XRs)
+	}
+}
+
+func TestGeneration_WritesOutsideCycleCommitImmediately(t *testing.T) {
+	s := New()
+	if s.Generation().Number != 0 {
+		t.Fatalf("expected generation 0 for new store, got %d", s.Generation().Number)
+	}
+	s.ReplaceClaims("g1/v1/k1s", []ClaimInfo{{GVR: "g1/v1/k1s", Namespace: "ns", Name: "a"}})
+	s.ReplaceClaims("g1/v1/k1s", []ClaimInfo{{GVR: "g1/v1/k1s", Namespace: "ns", Name: "b"}})
+	if s.Generation().Number != 2 {
+		t.Errorf("expected generation 2 after two direct writes, got %d", s.Generation().Number)
+	}
+
+	// Commit without Begin is a no-op.
+	if info := s.CommitGeneration(); info.Number != 2 {
+		t.Errorf("expected no-op commit to keep generation 2, got %d", info.Number)
+	}
+}
+
+func TestGeneration_BeginDiscardsUncommitted(t *testing.T) {
+	s := New()
+	s.BeginGeneration()
+	s.ReplaceClaims("g1/v1/k1s", []ClaimInfo{{GVR: "g1/v1/k1s", Namespace: "ns", Name: "discarded"}})
+	s.BeginGeneration()
+	s.ReplaceClaims("g1/v1/k2s", []ClaimInfo{{GVR: "g1/v1/k2s", Namespace: "ns", Name: "kept"}})
+	s.CommitGeneration()
+
+	claims := s.View().Claims()
+	if len(claims) != 1 || claims[0].Name != "kept" {
+		t.Errorf("expected only the second cycle's writes, got %+v", claims)
+	}
+}
+
+func TestGeneration_CopyOnWrite(t *testing.T) {
+	mrs := func(gvr string, n int, ready bool) []MRInfo {
+		out := make([]MRInfo, n)
+		for i := range out {
+			out[i] = MRInfo{GVR: gvr, Namespace: "ns", Name: fmt.Sprintf("%s-%d", gvr, i), XRName: "xr", Ready: ready}
+		}
+		return out
+	}
+	changed := func(prev, next *generation) int {
+		n := 0
+		for range next.mrs.changedBuckets(&prev.mrs) {
+			n++
+		}
+		return n
+	}
+
+	s := New()
+	s.ReplaceMRs("big", mrs("big", 5000, true))
+	s.ReplaceMRs("small", mrs("small", 3, true))
+	prev := s.read()
+
+	// Listing the same objects again shares the whole generation.
+	s.BeginGeneration()
+	s.ReplaceMRs("big", mrs("big", 5000, true))
+	s.EnrichMRClaims()
+	s.CommitGeneration()
+	if n := changed(prev, s.read()); n != 0 {
+		t.Errorf("expected no copied buckets for unchanged objects, got %d", n)
+	}
+
+	// Changing a few objects copies only their buckets.
+	prev = s.read()
+	s.ReplaceMRs("small", mrs("small", 3, false))
+	if n := changed(prev, s.read()); n == 0 || n > 3 {
+		t.Errorf("expected 1 to 3 copied buckets, got %d", n)
+	}
+	if m, _ := prev.mrs.get("ns/small-0"); !m.Ready {
+		t.Error("expected the previous generation to be unchanged")
+	}
+	if m, _ := s.read().mrs.get("ns/small-0"); m.Ready {
+		t.Error("expected the new generation to hold the change")
+	}
+	if got := s.View().MRCount(); got != 5003 {
+		t.Errorf("expected 5003 MRs, got %d", got)
+	}
+}
+
+func TestGeneration_ConcurrentReadersSeeConsistentGeneration(t *testing.T) {
+	s := New()
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+
+	// Each cycle writes N claims and N XRs tagged with the cycle number. A
+	// reader must never see claims and XRs from different cycles.
+	const n = 50
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for cycle := 0; cycle < 200; cycle++ {
+			tag := time.Unix(int64(cycle), 0)
+			claims := make([]ClaimInfo, n)
+			xrs := make([]XRInfo, n)
+			for i := range n {
+				claims[i] = ClaimInfo{GVR: "g/v1/c", Namespace: "ns", Name: fmt.Sprintf("c-%d", i), CreatedAt: tag}
+				xrs[i] = XRInfo{GVR: "g/v1/x", Name: fmt.Sprintf("x-%d", i), CreatedAt: tag}
+			}
+			s.BeginGeneration()
+			s.ReplaceClaims("g/v1/c", claims)
+			s.ReplaceXRs("g/v1/x", xrs)
+			s.CommitGeneration()
+		}
+		close(stop)
+	}()
+
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				snap := s.Snapshot()
+				if len(snap.Claims) == 0 {
+					continue
+				}
+				tag := snap.Claims[0].CreatedAt
+				for _, c := range snap.Claims {
+					if !c.CreatedAt.Equal(tag) {
+						t.Errorf("generation %d mixes claim cycles", snap.Generation)
+						return
+					}
+				}
+				for _, x := range snap.XRs {
+					if !x.CreatedAt.Equal(tag) {
+						t.Errorf("generation %d mixes claim and XR cycles", snap.Generation)
+						return
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestTombstones_RecordRemovedObjects(t *testing.T) {
+	s := New()
+	s.SetTombstoneRetention(time.Hour)
+	created := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	s.ReplaceClaims("g/v1/r", []ClaimInfo{
+		{GVR: "g/v1/r", Namespace: "prod", Name: "db", Creator: "alice", CreatedAt: created},
+		{GVR: "g/v1/r", Namespace: "prod", Name: "cache"},
+	})
+	s.ReplaceXRs("g/v1/xr", []XRInfo{{GVR: "g/v1/xr", Name: "xr-db"}})
+	s.ReplaceMRs("m/v1/r", []MRInfo{{GVR: "m/v1/r", Name: "rds", XRName: "xr-db"}})
+
+	before := time.Now().UTC()
+	s.ReplaceClaims("g/v1/r", []ClaimInfo{{GVR: "g/v1/r", Namespace: "prod", Name: "cache"}})
+	s.ReplaceXRs("g/v1/xr", nil)
+	s.ReplaceMRs("m/v1/r", nil)
+
+	deleted := s.View().DeletedClaims(Query{})
+	if len(deleted) != 1 {
+		t.Fatalf("expected 1 claim tombstone, got %d", len(deleted))
+	}
+	d := deleted[0]
+	if d.Name != "db" || d.Creator != "alice" || !d.CreatedAt.Equal(created) {
+		t.Errorf("tombstone lost last known state: %+v", d)
+	}
+	if d.RemovedAt.Before(before) {
+		t.Errorf("expected RemovedAt >= %s, got %s", before, d.RemovedAt)
+	}
+	if len(s.View().DeletedXRs(Query{})) != 1 || len(s.View().DeletedMRs(Query{})) != 1 {
+		t.Errorf("expected 1 XR and 1 MR tombstone, got %d and %d", len(s.View().DeletedXRs(Query{})), len(s.View().DeletedMRs(Query{})))
+	}
+	if s.View().ClaimCount() != 1 {
+		t.Errorf("tombstones must not count as live claims, got %d", s.View().ClaimCount())
+	}
+}
+
+func TestTombstones_ClearedWhenObjectReappears(t *testing.T) {
+	s := New()
+	s.SetTombstoneRetention(time.Hour)
+	claim := ClaimInfo{GVR: "g/v1/r", Namespace: "prod", Name: "db"}
+	s.ReplaceClaims("g/v1/r", []ClaimInfo{claim})
+	s.ReplaceClaims("g/v1/r", nil)
+	if len(s.View().DeletedClaims(Query{})) != 1 {
+		t.Fatal("expected a tombstone after removal")
+	}
+
+	s.ReplaceClaims("g/v1/r", []ClaimInfo{claim})
+	if len(s.View().DeletedClaims(Query{})) != 0 {
+		t.Errorf("expected tombstone to be cleared, got %+v", s.View().DeletedClaims(Query{}))
+	}
+}
+
+func TestTombstones_OnlyForDeletedObjects(t *testing.T) {
+	s := New()
+	s.SetTombstoneRetention(time.Hour)
+	claims := []ClaimInfo{
+		{GVR: "g/v1/r", Namespace: "a", Name: "db"},
+		{GVR: "g/v1/r", Namespace: "b", Name: "db"},
+		{GVR: "g/v1/r", Namespace: "c", Name: "db"},
+	}
+	s.ReplaceClaims("g/v1/r", claims)
+
+	// A list of namespace a without its claim leaves b and c untouched.
+	s.ReplaceClaimsIn("g/v1/r", []string{"a"}, nil)
+	if s.View().ClaimCount() != 2 || len(s.View().DeletedClaims(Query{})) != 1 {
+		t.Fatalf("expected 2 claims and 1 tombstone, got %d and %d", s.View().ClaimCount(), len(s.View().DeletedClaims(Query{})))
+	}
+
+	// Namespace c leaves the scope: purged without a tombstone.
+	s.PurgeClaims("g/v1/r", []string{"a", "b"})
+	if claims := s.View().Claims(); len(claims) != 1 || claims[0].Namespace != "b" {
+		t.Errorf("expected only the claim in b, got %+v", claims)
+	}
+	if len(s.View().DeletedClaims(Query{})) != 1 {
+		t.Errorf("expected no tombstone for the purged claim, got %+v", s.View().DeletedClaims(Query{}))
+	}
+
+	// The GVR is no longer tracked.
+	s.ReplaceXRs("g/v1/xr", []XRInfo{{GVR: "g/v1/xr", Name: "xr"}})
+	s.ReplaceMRs("m/v1/r", []MRInfo{{GVR: "m/v1/r", Name: "rds", XRName: "xr"}})
+	s.PurgeClaims("g/v1/r", nil)
+	s.PurgeXRs("g/v1/xr", nil)
+	s.PurgeMRs("m/v1/r", nil)
+	if s.View().ClaimCount()+s.View().XRCount()+s.View().MRCount() != 0 {
+		t.Errorf("expected an empty store, got %d claims, %d XRs, %d MRs", s.View().ClaimCount(), s.View().XRCount(), s.View().MRCount())
+	}
+	if len(s.View().DeletedClaims(Query{})) != 1 || len(s.View().DeletedXRs(Query{})) != 0 || len(s.View().DeletedMRs(Query{})) != 0 {
+		t.Errorf("expected no tombstones for purged objects")
+	}
+}
+
+func TestMergeShard(t *testing.T) {
+	s := New()
+	s.SetTombstoneRetention(time.Hour)
+	removedAt := time.Now().UTC().Add(-time.Minute)
+	s.MergeShard(Snapshot{
+		Claims:        []ClaimInfo{{GVR: "g/v1/r", Namespace: "ns", Name: "a"}, {GVR: "g/v1/other", Namespace: "ns", Name: "x"}},
+		MRs:           []MRInfo{{GVR: "m/v1/r", Name: "rds", XRName: "xr"}},
+		DeletedClaims: []DeletedClaim{{ClaimInfo: ClaimInfo{GVR: "g/v1/r", Namespace: "ns", Name: "b"}, RemovedAt: removedAt}},
+	}, []string{"g/v1/r", "m/v1/r"})
+	if s.View().ClaimCount() != 1 || s.View().MRCount() != 1 || len(s.View().DeletedClaims(Query{})) != 1 {
+		t.Fatalf("expected the shard's objects and tombstones of its GVRs, got %d claims, %d MRs, %d tombstones",
+			s.View().ClaimCount(), s.View().MRCount(), len(s.View().DeletedClaims(Query{})))
+	}
+
+	// The peer purged its MR: no tombstone here.
+	s.MergeShard(Snapshot{Claims: []ClaimInfo{{GVR: "g/v1/r", Namespace: "ns", Name: "a"}}}, []string{"g/v1/r", "m/v1/r"})
+	if s.View().MRCount() != 0 || len(s.View().DeletedMRs(Query{})) != 0 {
+		t.Errorf("expected the MR removed without a tombstone, got %d MRs and %d tombstones", s.View().MRCount(), len(s.View().DeletedMRs(Query{})))
+	}
+}
+
+func TestTombstones_PrunedAfterRetention(t *testing.T) {
+	s := New()
+	s.SetTombstoneRetention(time.Hour)
+	now := time.Now().UTC()
+	s.restoreTombstones(Snapshot{
+		DeletedClaims: []DeletedClaim{
+			{ClaimInfo: ClaimInfo{Namespace: "ns", Name: "old"}, RemovedAt: now.Add(-2 * time.Hour)},
+			{ClaimInfo: ClaimInfo{Namespace: "ns", Name: "recent"}, RemovedAt: now.Add(-time.Minute)},
+		},
+		DeletedMRs: []DeletedMR{
+			{MRInfo: MRInfo{Name: "old-mr"}, RemovedAt: now.Add(-2 * time.Hour)},
+		},
+	})
+
+	deleted := s.View().DeletedClaims(Query{})
+	if len(deleted) != 1 || deleted[0].Name != "recent" {
+		t.Errorf("expected only the recent tombstone to survive, got %+v", deleted)
+	}
+	if len(s.View().DeletedMRs(Query{})) != 0 {
+		t.Errorf("expected expired MR tombstone to be pruned, got %+v", s.View().DeletedMRs(Query{}))
+	}
+}
+
+func TestTombstones_DisabledByDefault(t *testing.T) {
+	s := New()
+	s.ReplaceClaims("g/v1/r", []ClaimInfo{{GVR: "g/v1/r", Namespace: "ns", Name: "a"}})
+	s.ReplaceClaims("g/v1/r", nil)
+	if len(s.View().DeletedClaims(Query{})) != 0 {
+		t.Errorf("expected no tombstones without retention, got %+v", s.View().DeletedClaims(Query{}))
+	}
+}
+
 func TestSnapshotClaims_IsCopy(t *testing.T) {
 	s := New()
 	s.ReplaceClaims("g1/v1/k1s", []ClaimInfo{
 		{GVR: "g1/v1/k1s", Group: "g1", Kind: "K1", Namespace: "ns1", Name: "a"},
 	})
 
-	snap := s.SnapshotClaims()
+	snap := s.View().Claims()
 	snap[0].Name = "mutated"
 
-	snap2 := s.SnapshotClaims()
+	snap2 := s.View().Claims()
 	if snap2[0].Name != "a" {
 		t.Errorf("snapshot mutation leaked: got %s", snap2[0].Name)
 	}
@@ -285,8 +710,8 @@ func TestConcurrentAccess(t *testing.T) {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			_ = s.SnapshotClaims()
-			_ = s.ClaimCount()
+			_ = s.View().Claims()
+			_ = s.View().ClaimCount()
 		}()
 	}
 
@@ -301,7 +726,7 @@ func TestSnapshotClaims_Deterministic(t *testing.T) {
 		{GVR: "g1/v1/k1s", Group: "g1", Kind: "K1", Namespace: "ns1", Name: "b"},
 	})
 
-	snap := s.SnapshotClaims()
+	snap := s.View().Claims()
 	sort.Slice(snap, func(i, j int) bool {
 		return snap[i].Name < snap[j].Name
 	})